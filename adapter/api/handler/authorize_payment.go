@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+type (
+	// Request data
+	AuthorizePaymentRequest struct {
+		PayerID string `json:"payer_id"`
+		PayeeID string `json:"payee_id"`
+		Value   int64  `json:"value"`
+	}
+
+	// AuthorizePaymentHandler define the dependencies of the HTTP handler for the use case
+	AuthorizePaymentHandler struct {
+		uc     usecase.AuthorizePaymentUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewAuthorizePaymentHandler create new AuthorizePaymentHandler with its dependencies
+func NewAuthorizePaymentHandler(uc usecase.AuthorizePaymentUseCase, l logger.Logger) AuthorizePaymentHandler {
+	return AuthorizePaymentHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "authorize_payment",
+	}
+}
+
+// Handle handle http request
+func (a AuthorizePaymentHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	a.log = a.log.WithContext(r.Context())
+
+	var reqData AuthorizePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		a.log.WithFields(logger.Fields{
+			"key":         a.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input, errs := a.validate(reqData)
+	if len(errs) > 0 {
+		a.log.WithFields(logger.Fields{
+			"key":         a.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := paymentErrorStatus(err)
+
+		a.log.WithFields(logger.Fields{
+			"key":         a.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error authorizing payment")
+
+		setRetryAfter(w, err)
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	a.log.WithFields(logger.Fields{
+		"key":         a.logKey,
+		"http_status": http.StatusCreated,
+	}).Infof("success authorizing payment")
+
+	response.NewSuccess(http.StatusCreated, output).Send(w)
+}
+
+func (a AuthorizePaymentHandler) validate(i AuthorizePaymentRequest) (usecase.AuthorizePaymentInput, []error) {
+	var errs []error
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payerID, err := vo.NewUuid(i.PayerID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payeeID, err := vo.NewUuid(i.PayeeID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	amount, err := vo.NewAmount(i.Value)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return usecase.AuthorizePaymentInput{
+		ID:       id,
+		PayerID:  payerID,
+		PayeeID:  payeeID,
+		Value:    vo.NewMoneyBRL(amount),
+		CreateAt: time.Now(),
+	}, errs
+}
+
+// paymentErrorStatus returns the HTTP status of the errors of the payment use cases
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFoundPayment):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrPaymentStatusTransition),
+		errors.Is(err, entity.ErrPaymentExpired):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidCaptureValue),
+		errors.Is(err, entity.ErrPayeeNotMerchant),
+		errors.Is(err, entity.ErrSamePayerAndPayee),
+		errors.Is(err, entity.ErrNotFoundUser),
+		errors.Is(err, entity.ErrUserInsufficientBalance),
+		errors.Is(err, entity.ErrNotFoundWallet),
+		errors.Is(err, entity.ErrLimitExceeded),
+		errors.Is(err, entity.ErrUnauthorizedTransfer):
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
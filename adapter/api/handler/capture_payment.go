@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+type (
+	// Request data. Without value the whole authorized value is captured.
+	CapturePaymentRequest struct {
+		Value int64 `json:"value"`
+	}
+
+	// CapturePaymentHandler define the dependencies of the HTTP handler for the use case
+	CapturePaymentHandler struct {
+		uc     usecase.CapturePaymentUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewCapturePaymentHandler create new CapturePaymentHandler with its dependencies
+func NewCapturePaymentHandler(uc usecase.CapturePaymentUseCase, l logger.Logger) CapturePaymentHandler {
+	return CapturePaymentHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "capture_payment",
+	}
+}
+
+// Handle handle http request
+func (c CapturePaymentHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	c.log = c.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["payment_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	// the body is optional, an empty one captures the whole payment
+	var reqData CapturePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil && !errors.Is(err, io.EOF) {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	value, err := vo.NewAmount(reqData.Value)
+	if err != nil {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := c.uc.Execute(r.Context(), usecase.CapturePaymentInput{
+		ID:    ID,
+		Value: value,
+		At:    time.Now(),
+	})
+	if err != nil {
+		status := paymentErrorStatus(err)
+
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error capturing payment")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	c.log.WithFields(logger.Fields{
+		"key":         c.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success capturing payment")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+// ChangeScheduleStatusHandler define the dependencies of the HTTP handler for the use case
+type ChangeScheduleStatusHandler struct {
+	uc     usecase.ChangeScheduleStatusUseCase
+	action usecase.ScheduleAction
+	log    logger.Logger
+	logKey string
+}
+
+// NewChangeScheduleStatusHandler create new ChangeScheduleStatusHandler applying the action
+func NewChangeScheduleStatusHandler(
+	uc usecase.ChangeScheduleStatusUseCase,
+	action usecase.ScheduleAction,
+	l logger.Logger,
+) ChangeScheduleStatusHandler {
+	return ChangeScheduleStatusHandler{
+		uc:     uc,
+		action: action,
+		log:    l,
+		logKey: string(action) + "_schedule",
+	}
+}
+
+// Handle handle http request
+func (c ChangeScheduleStatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	c.log = c.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["schedule_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := c.uc.Execute(r.Context(), usecase.ChangeScheduleStatusInput{
+		ID:     ID,
+		Action: c.action,
+		At:     time.Now(),
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrNotFoundSchedule):
+			status = http.StatusNotFound
+		case errors.Is(err, entity.ErrScheduleStatusTransition):
+			status = http.StatusConflict
+		}
+
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error changing schedule status")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	c.log.WithFields(logger.Fields{
+		"key":         c.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success changing schedule status")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
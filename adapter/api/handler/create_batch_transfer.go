@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+// maxBatchBodySize bounds the size of the JSON or CSV body of a batch
+const maxBatchBodySize = 1 << 20
+
+type (
+	// Request data
+	CreateBatchTransferRequest struct {
+		PayerID   string                     `json:"payer_id"`
+		Mode      string                     `json:"mode"`
+		Transfers []BatchTransferItemRequest `json:"transfers"`
+	}
+
+	// Request data
+	BatchTransferItemRequest struct {
+		PayeeID string `json:"payee_id"`
+		Value   int64  `json:"value"`
+	}
+
+	// CreateBatchTransferHandler define the dependencies of the HTTP handler for the use case
+	CreateBatchTransferHandler struct {
+		uc     usecase.CreateBatchTransferUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewCreateBatchTransferHandler create new CreateBatchTransferHandler with its dependencies
+func NewCreateBatchTransferHandler(uc usecase.CreateBatchTransferUseCase, l logger.Logger) CreateBatchTransferHandler {
+	return CreateBatchTransferHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "create_batch_transfer",
+	}
+}
+
+// Handle handle http request. The body is either JSON or, with the text/csv
+// content type, one "payee_id,value" line per transfer with the payer_id and
+// mode given in the query string.
+func (c CreateBatchTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	c.log = c.log.WithContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodySize)
+	defer r.Body.Close()
+
+	reqData, err := c.decode(r)
+	if err != nil {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	input, errs := c.validate(reqData)
+	if len(errs) > 0 {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to validate data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := c.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrEmptyBatch), errors.Is(err, entity.ErrBatchTooLarge):
+			status = http.StatusBadRequest
+		case errors.Is(err, entity.ErrNotFoundUser):
+			status = http.StatusNotFound
+		case errors.Is(err, entity.ErrSamePayerAndPayee),
+			errors.Is(err, entity.ErrUserInsufficientBalance),
+			errors.Is(err, vo.ErrNotAllowedTypeUser):
+			status = http.StatusUnprocessableEntity
+		}
+
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error when creating a batch of transfers")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	c.log.WithFields(logger.Fields{
+		"key":         c.logKey,
+		"http_status": http.StatusAccepted,
+	}).Infof("success creating batch of transfers")
+
+	response.NewSuccess(http.StatusAccepted, output).Send(w)
+}
+
+func (c CreateBatchTransferHandler) decode(r *http.Request) (CreateBatchTransferRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return decodeBatchCSV(r)
+	}
+
+	var reqData CreateBatchTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		return CreateBatchTransferRequest{}, err
+	}
+
+	return reqData, nil
+}
+
+// decodeBatchCSV reads the transfers of a CSV body, skipping the header line if any
+func decodeBatchCSV(r *http.Request) (CreateBatchTransferRequest, error) {
+	reqData := CreateBatchTransferRequest{
+		PayerID: r.URL.Query().Get("payer_id"),
+		Mode:    r.URL.Query().Get("mode"),
+	}
+
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return CreateBatchTransferRequest{}, err
+		}
+
+		value, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return CreateBatchTransferRequest{}, fmt.Errorf("line %d: invalid value %q", line, record[1])
+		}
+
+		reqData.Transfers = append(reqData.Transfers, BatchTransferItemRequest{
+			PayeeID: strings.TrimSpace(record[0]),
+			Value:   value,
+		})
+	}
+
+	return reqData, nil
+}
+
+func (c CreateBatchTransferHandler) validate(i CreateBatchTransferRequest) (usecase.CreateBatchTransferInput, []error) {
+	var errs []error
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payerID, err := vo.NewUuid(i.PayerID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	mode := vo.BatchBestEffort
+	if i.Mode != "" {
+		mode, err = vo.NewBatchMode(i.Mode)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	items := make([]usecase.CreateBatchTransferItemInput, 0, len(i.Transfers))
+	for n, t := range i.Transfers {
+		transferID, err := vo.NewUuid(uuid.New().String())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		payeeID, err := vo.NewUuid(t.PayeeID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transfers[%d]: %w", n, err))
+		}
+		amount, err := vo.NewAmount(t.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transfers[%d]: %w", n, err))
+		}
+
+		items = append(items, usecase.CreateBatchTransferItemInput{
+			TransferID: transferID,
+			PayeeID:    payeeID,
+			Value:      vo.NewMoneyBRL(amount),
+		})
+	}
+
+	return usecase.CreateBatchTransferInput{
+		ID:        id,
+		PayerID:   payerID,
+		Mode:      mode,
+		Items:     items,
+		CreatedAt: time.Now(),
+	}, errs
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+type (
+	// Request data
+	CreateSplitTransferRequest struct {
+		PayerID string                    `json:"payer_id"`
+		Value   int64                     `json:"value"`
+		Legs    []SplitTransferLegRequest `json:"legs"`
+	}
+
+	// Request data, a leg has either an amount or a percentage
+	SplitTransferLegRequest struct {
+		PayeeID    string   `json:"payee_id"`
+		Amount     *int64   `json:"amount"`
+		Percentage *float64 `json:"percentage"`
+	}
+
+	// CreateSplitTransferHandler define the dependencies of the HTTP handler for the use case
+	CreateSplitTransferHandler struct {
+		uc     usecase.CreateSplitTransferUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewCreateSplitTransferHandler create new CreateSplitTransferHandler with its dependencies
+func NewCreateSplitTransferHandler(uc usecase.CreateSplitTransferUseCase, l logger.Logger) CreateSplitTransferHandler {
+	return CreateSplitTransferHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "create_split_transfer",
+	}
+}
+
+// Handle handle http request
+func (c CreateSplitTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	c.log = c.log.WithContext(r.Context())
+
+	var reqData CreateSplitTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input, errs := c.validate(reqData)
+	if len(errs) > 0 {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to validate data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := c.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrNotFoundUser):
+			status = http.StatusNotFound
+		case errors.Is(err, vo.ErrSplitMismatch),
+			errors.Is(err, vo.ErrEmptySplitShare),
+			errors.Is(err, entity.ErrNoTransferLegs),
+			errors.Is(err, entity.ErrDuplicatePayee),
+			errors.Is(err, entity.ErrEmptyTransferLeg),
+			errors.Is(err, entity.ErrSamePayerAndPayee),
+			errors.Is(err, entity.ErrUserInsufficientBalance),
+			errors.Is(err, entity.ErrLimitExceeded),
+			errors.Is(err, entity.ErrUnauthorizedTransfer),
+			errors.Is(err, vo.ErrNotAllowedTypeUser):
+			status = http.StatusUnprocessableEntity
+		}
+
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error when creating a new split transfer")
+
+		setRetryAfter(w, err)
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	status := createdStatus(output)
+	c.log.WithFields(logger.Fields{
+		"key":         c.logKey,
+		"http_status": status,
+	}).Infof("success creating split transfer")
+
+	response.NewSuccess(status, output).Send(w)
+}
+
+func (c CreateSplitTransferHandler) validate(i CreateSplitTransferRequest) (usecase.CreateSplitTransferInput, []error) {
+	var errs []error
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payerID, err := vo.NewUuid(i.PayerID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	var (
+		legs       = make([]usecase.CreateSplitTransferLegInput, 0, len(i.Legs))
+		sum        int64
+		percentage bool
+	)
+	for n, l := range i.Legs {
+		payeeID, err := vo.NewUuid(l.PayeeID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("legs[%d]: %w", n, err))
+		}
+
+		var share vo.SplitShare
+		switch {
+		case (l.Amount == nil) == (l.Percentage == nil):
+			errs = append(errs, fmt.Errorf("legs[%d]: either amount or percentage is required", n))
+		case l.Amount != nil:
+			amount, err := vo.NewAmount(*l.Amount)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("legs[%d]: %w", n, err))
+			}
+			share = vo.NewAmountShare(amount)
+			sum += amount.Value()
+		default:
+			share, err = vo.NewPercentageShare(int64(math.Round(*l.Percentage * 100)))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("legs[%d]: %w", n, err))
+			}
+			percentage = true
+		}
+
+		legs = append(legs, usecase.CreateSplitTransferLegInput{
+			PayeeID: payeeID,
+			Share:   share,
+		})
+	}
+
+	// the value of a split in amounts only is their sum unless given
+	value := i.Value
+	if value == 0 && !percentage {
+		value = sum
+	}
+	amount, err := vo.NewAmount(value)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return usecase.CreateSplitTransferInput{
+		ID:       id,
+		PayerID:  payerID,
+		Value:    vo.NewMoneyBRL(amount),
+		Legs:     legs,
+		CreateAt: time.Now(),
+	}, errs
+}
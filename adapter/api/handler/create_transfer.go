@@ -2,22 +2,30 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/adapter/api/response"
 	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
 	"github.com/dungnguyen/clean-architecture/usecase"
 	"github.com/google/uuid"
 )
 
 type (
-	// Request data
+	// Request data. The transfer is made in BRL between the main wallets
+	// unless a currency or the purposes of the wallets are given.
 	CreateTransferRequest struct {
-		PayerID string `json:"payser_id"`
-		PayeeID string `json:"payee_id"`
-		Value   int64  `json:"value"`
+		PayerID           string `json:"payser_id"`
+		PayeeID           string `json:"payee_id"`
+		Value             int64  `json:"value"`
+		Currency          string `json:"currency"`
+		SourceWallet      string `json:"source_wallet"`
+		DestinationWallet string `json:"destination_wallet"`
 	}
 
 	// CreateTransferHandler define the dependencies of the HTTP handler for the use case
@@ -39,9 +47,7 @@ func NewCreateTransferHandler(uc usecase.CreateTransferUseCase, l logger.Logger)
 
 // Handle handle http request
 func (c CreateTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	c.log = c.log.WithFields(logger.Fields{
-		"correlation_id": r.Context().Value("correlation_id"),
-	})
+	c.log = c.log.WithContext(r.Context())
 
 	var reqData CreateTransferRequest
 	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
@@ -70,22 +76,31 @@ func (c CreateTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
 
 	output, err := c.uc.Execute(r.Context(), input)
 	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrLimitExceeded) ||
+			errors.Is(err, entity.ErrUnauthorizedTransfer) ||
+			errors.Is(err, entity.ErrNotFoundWallet) {
+			status = http.StatusUnprocessableEntity
+		}
+
 		c.log.WithFields(logger.Fields{
 			"key":         c.logKey,
 			"error":       err.Error(),
-			"http_status": http.StatusInternalServerError,
+			"http_status": status,
 		}).Errorf("error when creating a new transfer")
 
-		response.NewError(err, http.StatusInternalServerError).Send(w)
+		setRetryAfter(w, err)
+		response.NewError(err, status).Send(w)
 		return
 	}
 
+	status := createdStatus(output)
 	c.log.WithFields(logger.Fields{
 		"key":         c.logKey,
-		"http_status": http.StatusCreated,
+		"http_status": status,
 	}).Infof("success creating transfer")
 
-	response.NewSuccess(http.StatusCreated, output).Send(w)
+	response.NewSuccess(status, output).Send(w)
 }
 
 func (c CreateTransferHandler) validate(i CreateTransferRequest) (usecase.CreateTransferInput, []error) {
@@ -106,12 +121,50 @@ func (c CreateTransferHandler) validate(i CreateTransferRequest) (usecase.Create
 	if err != nil {
 		errs = append(errs, err)
 	}
+	currency, err := vo.NewCurrency(vo.BRL.String())
+	if i.Currency != "" {
+		currency, err = vo.NewCurrency(i.Currency)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	source, err := vo.NewWalletPurpose(i.SourceWallet)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	destination, err := vo.NewWalletPurpose(i.DestinationWallet)
+	if err != nil {
+		errs = append(errs, err)
+	}
 
 	return usecase.CreateTransferInput{
-		ID:       id,
-		PayerID:  payerID,
-		PayeeID:  payeeID,
-		Value:    vo.NewMoneyBRL(amount),
-		CreateAt: time.Now(),
+		ID:          id,
+		PayerID:     payerID,
+		PayeeID:     payeeID,
+		Value:       vo.NewMoney(currency, amount),
+		Source:      source,
+		Destination: destination,
+		CreateAt:    time.Now(),
 	}, errs
 }
+
+// createdStatus returns 202 for the transfers held for a review, 201 otherwise
+func createdStatus(output usecase.CreateTransferOutput) int {
+	if output.Status == vo.TransferHeld.String() {
+		return http.StatusAccepted
+	}
+
+	return http.StatusCreated
+}
+
+// setRetryAfter sets the Retry-After header to the seconds left until the
+// exceeded limit of err resets, when it does
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var limitErr *entity.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.ResetAt.IsZero() {
+		return
+	}
+
+	seconds := math.Ceil(time.Until(limitErr.ResetAt).Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(seconds, 0))))
+}
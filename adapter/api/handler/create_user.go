@@ -2,16 +2,22 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/adapter/api/response"
 	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
 	"github.com/dungnguyen/clean-architecture/usecase"
 	"github.com/google/uuid"
 )
 
+// adminActor is who creates the users of the admin API in the audit log, the
+// API not identifying its callers
+const adminActor = "admin"
+
 type (
 	// Request data
 	CreateUserRequest struct {
@@ -38,15 +44,18 @@ type (
 	// CreateUserHandler define the dependencies of the HTTP handler for the use case
 	CreateUserHandler struct {
 		uc     usecase.CreateUserUseCase
+		admin  bool
 		log    logger.Logger
 		logKey string
 	}
 )
 
-// NewCreateUserHandler create new CreateUserHandler with its dependencies
-func NewCreateUserHandler(uc usecase.CreateUserUseCase, l logger.Logger) CreateUserHandler {
+// NewCreateUserHandler create new CreateUserHandler with its dependencies.
+// Only the admin handler creates the users with an initial balance.
+func NewCreateUserHandler(uc usecase.CreateUserUseCase, admin bool, l logger.Logger) CreateUserHandler {
 	return CreateUserHandler{
 		uc:     uc,
+		admin:  admin,
 		log:    l,
 		logKey: "create_user",
 	}
@@ -54,9 +63,7 @@ func NewCreateUserHandler(uc usecase.CreateUserUseCase, l logger.Logger) CreateU
 
 // Handle handles http request
 func (c CreateUserHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	c.log = c.log.WithFields(logger.Fields{
-		"correlation_id": r.Context().Value("correlation_id"),
-	})
+	c.log = c.log.WithContext(r.Context())
 
 	var reqData CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
@@ -85,13 +92,18 @@ func (c CreateUserHandler) Handle(w http.ResponseWriter, r *http.Request) {
 
 	output, err := c.uc.Execute(r.Context(), input)
 	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrInitialBalanceRestricted) {
+			status = http.StatusForbidden
+		}
+
 		c.log.WithFields(logger.Fields{
 			"key":         c.logKey,
 			"error":       err.Error(),
-			"http_status": http.StatusInternalServerError,
+			"http_status": status,
 		}).Errorf("error while creating a new user")
 
-		response.NewError(err, http.StatusInternalServerError).Send(w)
+		response.NewError(err, status).Send(w)
 		return
 	}
 
@@ -132,6 +144,11 @@ func (c CreateUserHandler) validate(i CreateUserRequest) (usecase.CreateUserInpu
 		errs = append(errs, err)
 	}
 
+	var actor string
+	if c.admin {
+		actor = adminActor
+	}
+
 	return usecase.CreateUserInput{
 		ID:        id,
 		FullName:  vo.NewFullName(i.FullName),
@@ -141,5 +158,7 @@ func (c CreateUserHandler) validate(i CreateUserRequest) (usecase.CreateUserInpu
 		Wallet:    wallet,
 		Type:      typeUser,
 		CreatedAt: time.Now(),
+		Admin:     c.admin,
+		Actor:     actor,
 	}, errs
 }
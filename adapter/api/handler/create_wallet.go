@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+type (
+	// Request data. Without purpose the main wallet of the currency is created.
+	CreateWalletRequest struct {
+		Currency string `json:"currency"`
+		Purpose  string `json:"purpose"`
+	}
+
+	// CreateWalletHandler define the dependencies of the HTTP handler for the use case
+	CreateWalletHandler struct {
+		uc     usecase.CreateWalletUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewCreateWalletHandler create new CreateWalletHandler with its dependencies
+func NewCreateWalletHandler(uc usecase.CreateWalletUseCase, l logger.Logger) CreateWalletHandler {
+	return CreateWalletHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "create_wallet",
+	}
+}
+
+// Handle handle http request
+func (c CreateWalletHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	c.log = c.log.WithContext(r.Context())
+
+	userID, err := vo.NewUuid(mux.Vars(r)["user_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	var reqData CreateWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input, errs := c.validate(userID, reqData)
+	if len(errs) > 0 {
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := c.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrNotFoundUser):
+			status = http.StatusNotFound
+		case errors.Is(err, entity.ErrWalletAlreadyExists):
+			status = http.StatusConflict
+		}
+
+		c.log.WithFields(logger.Fields{
+			"key":         c.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error creating wallet")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	c.log.WithFields(logger.Fields{
+		"key":         c.logKey,
+		"http_status": http.StatusCreated,
+	}).Infof("success creating wallet")
+
+	response.NewSuccess(http.StatusCreated, output).Send(w)
+}
+
+func (c CreateWalletHandler) validate(userID vo.Uuid, i CreateWalletRequest) (usecase.CreateWalletInput, []error) {
+	var errs []error
+	currency, err := vo.NewCurrency(i.Currency)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	purpose, err := vo.NewWalletPurpose(i.Purpose)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return usecase.CreateWalletInput{
+		UserID:   userID,
+		Currency: currency,
+		Purpose:  purpose,
+	}, errs
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+var errRequiredReviewer = errors.New("reviewer is required")
+
+type (
+	// Request data
+	DecideReviewRequest struct {
+		Reviewer string `json:"reviewer"`
+		Note     string `json:"note"`
+	}
+
+	// DecideReviewHandler define the dependencies of the HTTP handler for the use case
+	DecideReviewHandler struct {
+		uc     usecase.DecideReviewUseCase
+		action usecase.ReviewAction
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewDecideReviewHandler create new DecideReviewHandler applying the action
+func NewDecideReviewHandler(
+	uc usecase.DecideReviewUseCase,
+	action usecase.ReviewAction,
+	l logger.Logger,
+) DecideReviewHandler {
+	return DecideReviewHandler{
+		uc:     uc,
+		action: action,
+		log:    l,
+		logKey: string(action) + "_review",
+	}
+}
+
+// Handle handle http request
+func (d DecideReviewHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	d.log = d.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["review_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	var reqData DecideReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	reviewer := strings.TrimSpace(reqData.Reviewer)
+	if reviewer == "" {
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       errRequiredReviewer.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewError(errRequiredReviewer, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := d.uc.Execute(r.Context(), usecase.DecideReviewInput{
+		ID:       ID,
+		Action:   d.action,
+		Reviewer: reviewer,
+		Note:     reqData.Note,
+		At:       time.Now(),
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrNotFoundReview):
+			status = http.StatusNotFound
+		case errors.Is(err, entity.ErrReviewStatusTransition):
+			status = http.StatusConflict
+		}
+
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error deciding review")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	d.log.WithFields(logger.Fields{
+		"key":         d.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success deciding review")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
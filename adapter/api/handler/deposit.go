@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+type (
+	// Request data of the deposits and the withdrawals. The value is in BRL
+	// on the main wallet unless a currency or the purpose of the wallet is given.
+	FundingRequest struct {
+		UserID   string `json:"user_id"`
+		Value    int64  `json:"value"`
+		Currency string `json:"currency"`
+		Wallet   string `json:"wallet"`
+		Method   string `json:"method"`
+	}
+
+	// DepositHandler define the dependencies of the HTTP handler for the use case
+	DepositHandler struct {
+		uc     usecase.DepositUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewDepositHandler create new DepositHandler with its dependencies
+func NewDepositHandler(uc usecase.DepositUseCase, l logger.Logger) DepositHandler {
+	return DepositHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "deposit",
+	}
+}
+
+// Handle handle http request
+func (d DepositHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	d.log = d.log.WithContext(r.Context())
+
+	var reqData FundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	funding, errs := reqData.validate()
+	if len(errs) > 0 {
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := d.uc.Execute(r.Context(), usecase.DepositInput(funding))
+	if err != nil {
+		status := fundingErrorStatus(err)
+
+		d.log.WithFields(logger.Fields{
+			"key":         d.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error depositing")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	d.log.WithFields(logger.Fields{
+		"key":         d.logKey,
+		"http_status": http.StatusCreated,
+	}).Infof("success depositing")
+
+	response.NewSuccess(http.StatusCreated, output).Send(w)
+}
+
+func (i FundingRequest) validate() (usecase.WithdrawalInput, []error) {
+	var errs []error
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	userID, err := vo.NewUuid(i.UserID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	amount, err := vo.NewAmount(i.Value)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	currency, err := vo.NewCurrency(vo.BRL.String())
+	if i.Currency != "" {
+		currency, err = vo.NewCurrency(i.Currency)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	wallet, err := vo.NewWalletPurpose(i.Wallet)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	method, err := vo.NewFundingMethod(i.Method)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return usecase.WithdrawalInput{
+		ID:       id,
+		UserID:   userID,
+		Value:    vo.NewMoney(currency, amount),
+		Method:   method,
+		Wallet:   wallet,
+		CreateAt: time.Now(),
+	}, errs
+}
+
+// fundingErrorStatus returns the HTTP status of the errors of the funding use cases
+func fundingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFoundFunding):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrFundingStatusTransition):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidFundingValue),
+		errors.Is(err, entity.ErrFundingFailed),
+		errors.Is(err, entity.ErrNotFoundUser),
+		errors.Is(err, entity.ErrNotFoundWallet),
+		errors.Is(err, entity.ErrUserInsufficientBalance),
+		errors.Is(err, entity.ErrLimitExceeded),
+		errors.Is(err, entity.ErrUnauthorizedTransfer),
+		errors.Is(err, entity.ErrReviewRequired):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, usecase.ErrProviderUnavailable):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
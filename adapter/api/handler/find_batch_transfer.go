@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+// FindBatchTransferHandler define the dependencies of the HTTP handler for the use case
+type FindBatchTransferHandler struct {
+	uc     usecase.FindBatchTransferUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewFindBatchTransferHandler create new FindBatchTransferHandler with its dependencies
+func NewFindBatchTransferHandler(uc usecase.FindBatchTransferUseCase, l logger.Logger) FindBatchTransferHandler {
+	return FindBatchTransferHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "find_batch_transfer",
+	}
+}
+
+// Handle handle http request
+func (f FindBatchTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	f.log = f.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["batch_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		f.log.WithFields(logger.Fields{
+			"key":         f.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := f.uc.Execute(r.Context(), usecase.FindBatchTransferInput{ID: ID})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrNotFoundBatch) {
+			status = http.StatusNotFound
+		}
+
+		f.log.WithFields(logger.Fields{
+			"key":         f.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error fetching batch of transfers")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	f.log.WithFields(logger.Fields{
+		"key":         f.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success fetching batch of transfers")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
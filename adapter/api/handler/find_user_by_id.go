@@ -30,9 +30,7 @@ func NewFindUserByIDHandler(uc usecase.FindUserByIDUseCase, l logger.Logger) Fin
 
 // Handler handle http request
 func (f FindUserByIDHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	f.log = f.log.WithFields(logger.Fields{
-		"correlation_id": r.Context().Value("correlation_id"),
-	})
+	f.log = f.log.WithContext(r.Context())
 
 	reqID := mux.Vars(r)["user_id"]
 	if reqID == "" {
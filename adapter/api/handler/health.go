@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/health"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+)
+
+// HealthHandler define the dependencies of the HTTP handler for the health probes
+type HealthHandler struct {
+	health *health.Health
+	log    logger.Logger
+	logKey string
+}
+
+// NewHealthHandler create new HealthHandler with its dependencies
+func NewHealthHandler(h *health.Health, l logger.Logger) HealthHandler {
+	return HealthHandler{
+		health: h,
+		log:    l,
+		logKey: "health",
+	}
+}
+
+// Live handle the liveness probe
+func (h HealthHandler) Live(w http.ResponseWriter, _ *http.Request) {
+	response.NewSuccess(http.StatusOK, h.health.Live()).Send(w)
+}
+
+// Ready handle the readiness probe
+func (h HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.health.Ready(r.Context())
+	if report.Status != health.StatusUp {
+		h.log.WithFields(logger.Fields{
+			"key":         h.logKey,
+			"checks":      report.Checks,
+			"http_status": http.StatusServiceUnavailable,
+		}).Warnf("service is not ready")
+
+		response.NewSuccess(http.StatusServiceUnavailable, report).Send(w)
+		return
+	}
+
+	response.NewSuccess(http.StatusOK, report).Send(w)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var errInvalidOffset = errors.New("offset must be a non-negative integer")
+
+// ListAuditEntriesHandler define the dependencies of the HTTP handler for the use case
+type ListAuditEntriesHandler struct {
+	uc     usecase.ListAuditEntriesUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewListAuditEntriesHandler create new ListAuditEntriesHandler with its dependencies
+func NewListAuditEntriesHandler(uc usecase.ListAuditEntriesUseCase, l logger.Logger) ListAuditEntriesHandler {
+	return ListAuditEntriesHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "list_audit_entries",
+	}
+}
+
+// Handle handle http request. The filters of the entries are given in the
+// query string: actor, action, target, subject_id, correlation_id, from and
+// to as RFC 3339 times, offset and limit.
+func (l ListAuditEntriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	l.log = l.log.WithContext(r.Context())
+
+	input, errs := l.validate(r)
+	if len(errs) > 0 {
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to validate data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := l.uc.Execute(r.Context(), input)
+	if err != nil {
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusInternalServerError,
+		}).Errorf("error listing audit entries")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	l.log.WithFields(logger.Fields{
+		"key":         l.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success listing audit entries")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
+
+func (l ListAuditEntriesHandler) validate(r *http.Request) (usecase.ListAuditEntriesInput, []error) {
+	var (
+		errs  []error
+		query = r.URL.Query()
+		input = usecase.ListAuditEntriesInput{
+			Actor:         query.Get("actor"),
+			CorrelationID: query.Get("correlation_id"),
+		}
+	)
+
+	if v := query.Get("action"); v != "" {
+		action, err := vo.NewAuditAction(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		input.Action = action
+	}
+
+	if v := query.Get("target"); v != "" {
+		target, err := vo.NewAuditTarget(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		input.Target = target
+	}
+
+	if v := query.Get("subject_id"); v != "" {
+		id, err := vo.NewUuid(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		input.SubjectID = id
+	}
+
+	for _, param := range []struct {
+		name string
+		at   *time.Time
+	}{{"from", &input.From}, {"to", &input.To}} {
+		if v := query.Get(param.name); v != "" {
+			at, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be an RFC 3339 time", param.name))
+			}
+			*param.at = at
+		}
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			errs = append(errs, errInvalidOffset)
+		}
+		input.Offset = offset
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			errs = append(errs, errInvalidLimit)
+		}
+		input.Limit = limit
+	}
+
+	return input, errs
+}
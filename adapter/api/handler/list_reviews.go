@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var errInvalidLimit = errors.New("limit must be a positive integer")
+
+// ListReviewsHandler define the dependencies of the HTTP handler for the use case
+type ListReviewsHandler struct {
+	uc     usecase.ListReviewsUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewListReviewsHandler create new ListReviewsHandler with its dependencies
+func NewListReviewsHandler(uc usecase.ListReviewsUseCase, l logger.Logger) ListReviewsHandler {
+	return ListReviewsHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "list_reviews",
+	}
+}
+
+// Handle handle http request. The status and limit of the reviews are given
+// in the query string, the pending ones being listed by default.
+func (l ListReviewsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	l.log = l.log.WithContext(r.Context())
+
+	input, errs := l.validate(r)
+	if len(errs) > 0 {
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := l.uc.Execute(r.Context(), input)
+	if err != nil {
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusInternalServerError,
+		}).Errorf("error listing reviews")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	l.log.WithFields(logger.Fields{
+		"key":         l.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success listing reviews")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
+
+func (l ListReviewsHandler) validate(r *http.Request) (usecase.ListReviewsInput, []error) {
+	var (
+		input usecase.ListReviewsInput
+		errs  []error
+		query = r.URL.Query()
+	)
+
+	if v := query.Get("status"); v != "" {
+		status, err := vo.NewReviewStatus(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		input.Status = status
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			errs = append(errs, errInvalidLimit)
+		}
+		input.Limit = limit
+	}
+
+	return input, errs
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+// ListSchedulesHandler define the dependencies of the HTTP handler for the use case
+type ListSchedulesHandler struct {
+	uc     usecase.ListSchedulesUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewListSchedulesHandler create new ListSchedulesHandler with its dependencies
+func NewListSchedulesHandler(uc usecase.ListSchedulesUseCase, l logger.Logger) ListSchedulesHandler {
+	return ListSchedulesHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "list_schedules",
+	}
+}
+
+// Handle handle http request
+func (l ListSchedulesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	l.log = l.log.WithContext(r.Context())
+
+	payerID, err := vo.NewUuid(mux.Vars(r)["user_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := l.uc.Execute(r.Context(), usecase.ListSchedulesInput{PayerID: payerID})
+	if err != nil {
+		l.log.WithFields(logger.Fields{
+			"key":         l.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusInternalServerError,
+		}).Errorf("error listing schedules")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	l.log.WithFields(logger.Fields{
+		"key":         l.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success listing schedules")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
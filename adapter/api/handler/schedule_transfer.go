@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+type (
+	// Request data
+	ScheduleTransferRequest struct {
+		PayerID    string                    `json:"payer_id"`
+		PayeeID    string                    `json:"payee_id"`
+		Value      int64                     `json:"value"`
+		StartAt    time.Time                 `json:"start_at"`
+		Recurrence ScheduleRecurrenceRequest `json:"recurrence"`
+	}
+
+	// Request data
+	ScheduleRecurrenceRequest struct {
+		Frequency  string     `json:"frequency"`
+		DayOfMonth int        `json:"day_of_month"`
+		EndAt      *time.Time `json:"end_at"`
+		Count      int        `json:"count"`
+	}
+
+	// ScheduleTransferHandler define the dependencies of the HTTP handler for the use case
+	ScheduleTransferHandler struct {
+		uc     usecase.ScheduleTransferUseCase
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewScheduleTransferHandler create new ScheduleTransferHandler with its dependencies
+func NewScheduleTransferHandler(uc usecase.ScheduleTransferUseCase, l logger.Logger) ScheduleTransferHandler {
+	return ScheduleTransferHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "schedule_transfer",
+	}
+}
+
+// Handle handle http request
+func (s ScheduleTransferHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	s.log = s.log.WithContext(r.Context())
+
+	var reqData ScheduleTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input, errs := s.validate(reqData)
+	if len(errs) > 0 {
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to validate data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := s.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrNotFoundUser):
+			status = http.StatusNotFound
+		case errors.Is(err, entity.ErrScheduleInPast),
+			errors.Is(err, entity.ErrSamePayerAndPayee),
+			errors.Is(err, vo.ErrNotAllowedTypeUser):
+			status = http.StatusUnprocessableEntity
+		}
+
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error when scheduling a transfer")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	s.log.WithFields(logger.Fields{
+		"key":         s.logKey,
+		"http_status": http.StatusCreated,
+	}).Infof("success scheduling transfer")
+
+	response.NewSuccess(http.StatusCreated, output).Send(w)
+}
+
+func (s ScheduleTransferHandler) validate(i ScheduleTransferRequest) (usecase.ScheduleTransferInput, []error) {
+	var errs []error
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payerID, err := vo.NewUuid(i.PayerID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	payeeID, err := vo.NewUuid(i.PayeeID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	amount, err := vo.NewAmount(i.Value)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if i.StartAt.IsZero() {
+		errs = append(errs, errors.New("start_at is required"))
+	}
+
+	frequency := vo.ONCE
+	if i.Recurrence.Frequency != "" {
+		frequency, err = vo.NewFrequency(i.Recurrence.Frequency)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// a monthly schedule runs on the day it starts unless told otherwise
+	dayOfMonth := i.Recurrence.DayOfMonth
+	if frequency == vo.MONTHLY && dayOfMonth == 0 {
+		dayOfMonth = i.StartAt.Day()
+	}
+
+	var endAt time.Time
+	if i.Recurrence.EndAt != nil {
+		endAt = *i.Recurrence.EndAt
+	}
+
+	recurrence, err := vo.NewRecurrence(frequency, dayOfMonth, endAt, i.Recurrence.Count)
+	if err != nil && frequency != "" {
+		errs = append(errs, err)
+	}
+
+	return usecase.ScheduleTransferInput{
+		ID:         id,
+		PayerID:    payerID,
+		PayeeID:    payeeID,
+		Value:      vo.NewMoneyBRL(amount),
+		Recurrence: recurrence,
+		StartAt:    i.StartAt,
+		CreatedAt:  time.Now(),
+	}, errs
+}
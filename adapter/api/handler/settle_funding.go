@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+type (
+	// Request data sent by the payment provider, the body is optional
+	SettleFundingRequest struct {
+		Reference string `json:"reference"`
+		Reason    string `json:"reason"`
+	}
+
+	// SettleFundingHandler define the dependencies of the HTTP handler for the use case
+	SettleFundingHandler struct {
+		uc     usecase.SettleFundingUseCase
+		action usecase.FundingAction
+		log    logger.Logger
+		logKey string
+	}
+)
+
+// NewSettleFundingHandler create new SettleFundingHandler applying the action
+func NewSettleFundingHandler(
+	uc usecase.SettleFundingUseCase,
+	action usecase.FundingAction,
+	l logger.Logger,
+) SettleFundingHandler {
+	return SettleFundingHandler{
+		uc:     uc,
+		action: action,
+		log:    l,
+		logKey: string(action) + "_funding",
+	}
+}
+
+// Handle handle http request
+func (s SettleFundingHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	s.log = s.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["funding_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	var reqData SettleFundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil && !errors.Is(err, io.EOF) {
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	output, err := s.uc.Execute(r.Context(), usecase.SettleFundingInput{
+		ID:        ID,
+		Action:    s.action,
+		Reference: reqData.Reference,
+		Reason:    reqData.Reason,
+		At:        time.Now(),
+	})
+	if err != nil {
+		status := fundingErrorStatus(err)
+
+		s.log.WithFields(logger.Fields{
+			"key":         s.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error settling funding")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	s.log.WithFields(logger.Fields{
+		"key":         s.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success settling funding")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var errInvalidFromSequence = errors.New("from_sequence must be a positive integer")
+
+// VerifyAuditHandler define the dependencies of the HTTP handler for the use case
+type VerifyAuditHandler struct {
+	uc     usecase.VerifyAuditUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewVerifyAuditHandler create new VerifyAuditHandler with its dependencies
+func NewVerifyAuditHandler(uc usecase.VerifyAuditUseCase, l logger.Logger) VerifyAuditHandler {
+	return VerifyAuditHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "verify_audit",
+	}
+}
+
+// Handle handle http request. The chain is verified from the from_sequence
+// of the query string, from its first entry when absent. A broken chain is
+// reported as not valid with the sequence it breaks at.
+func (v VerifyAuditHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	v.log = v.log.WithContext(r.Context())
+
+	var input usecase.VerifyAuditInput
+	if s := r.URL.Query().Get("from_sequence"); s != "" {
+		from, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || from <= 0 {
+			v.log.WithFields(logger.Fields{
+				"key":         v.logKey,
+				"error":       errInvalidFromSequence.Error(),
+				"http_status": http.StatusBadRequest,
+			}).Errorf("failed to validate data")
+
+			response.NewError(errInvalidFromSequence, http.StatusBadRequest).Send(w)
+			return
+		}
+		input.FromSequence = from
+	}
+
+	output, err := v.uc.Execute(r.Context(), input)
+	if err != nil {
+		v.log.WithFields(logger.Fields{
+			"key":         v.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusInternalServerError,
+		}).Errorf("error verifying the audit log")
+
+		response.NewError(err, http.StatusInternalServerError).Send(w)
+		return
+	}
+
+	fields := logger.Fields{
+		"key":         v.logKey,
+		"http_status": http.StatusOK,
+		"verified":    output.Verified,
+	}
+	if !output.Valid {
+		fields["broken_at"] = output.BrokenAt
+		v.log.WithFields(fields).Errorf("audit log chain is broken")
+	} else {
+		v.log.WithFields(fields).Infof("success verifying the audit log")
+	}
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
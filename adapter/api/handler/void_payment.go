@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/gorilla/mux"
+)
+
+// VoidPaymentHandler define the dependencies of the HTTP handler for the use case
+type VoidPaymentHandler struct {
+	uc     usecase.VoidPaymentUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewVoidPaymentHandler create new VoidPaymentHandler with its dependencies
+func NewVoidPaymentHandler(uc usecase.VoidPaymentUseCase, l logger.Logger) VoidPaymentHandler {
+	return VoidPaymentHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "void_payment",
+	}
+}
+
+// Handle handle http request
+func (v VoidPaymentHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	v.log = v.log.WithContext(r.Context())
+
+	ID, err := vo.NewUuid(mux.Vars(r)["payment_id"])
+	if err != nil {
+		err := errors.New("invalid uuid")
+		v.log.WithFields(logger.Fields{
+			"key":         v.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("invalid uuid")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := v.uc.Execute(r.Context(), usecase.VoidPaymentInput{
+		ID: ID,
+		At: time.Now(),
+	})
+	if err != nil {
+		status := paymentErrorStatus(err)
+
+		v.log.WithFields(logger.Fields{
+			"key":         v.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error voiding payment")
+
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	v.log.WithFields(logger.Fields{
+		"key":         v.logKey,
+		"http_status": http.StatusOK,
+	}).Infof("success voiding payment")
+
+	response.NewSuccess(http.StatusOK, output).Send(w)
+}
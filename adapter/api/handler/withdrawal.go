@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// WithdrawalHandler define the dependencies of the HTTP handler for the use case
+type WithdrawalHandler struct {
+	uc     usecase.WithdrawalUseCase
+	log    logger.Logger
+	logKey string
+}
+
+// NewWithdrawalHandler create new WithdrawalHandler with its dependencies
+func NewWithdrawalHandler(uc usecase.WithdrawalUseCase, l logger.Logger) WithdrawalHandler {
+	return WithdrawalHandler{
+		uc:     uc,
+		log:    l,
+		logKey: "withdrawal",
+	}
+}
+
+// Handle handle http request
+func (h WithdrawalHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	h.log = h.log.WithContext(r.Context())
+
+	var reqData FundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		h.log.WithFields(logger.Fields{
+			"key":         h.logKey,
+			"error":       err.Error(),
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to marshal message")
+
+		response.NewError(err, http.StatusBadRequest).Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input, errs := reqData.validate()
+	if len(errs) > 0 {
+		h.log.WithFields(logger.Fields{
+			"key":         h.logKey,
+			"error":       "invalid input",
+			"http_status": http.StatusBadRequest,
+		}).Errorf("failed to data")
+
+		response.NewErrors(errs, http.StatusBadRequest).Send(w)
+		return
+	}
+
+	output, err := h.uc.Execute(r.Context(), input)
+	if err != nil {
+		status := fundingErrorStatus(err)
+
+		h.log.WithFields(logger.Fields{
+			"key":         h.logKey,
+			"error":       err.Error(),
+			"http_status": status,
+		}).Errorf("error withdrawing")
+
+		setRetryAfter(w, err)
+		response.NewError(err, status).Send(w)
+		return
+	}
+
+	h.log.WithFields(logger.Fields{
+		"key":         h.logKey,
+		"http_status": http.StatusCreated,
+	}).Infof("success withdrawing")
+
+	response.NewSuccess(http.StatusCreated, output).Send(w)
+}
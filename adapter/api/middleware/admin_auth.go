@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+)
+
+var ErrUnauthorizedAdmin = errors.New("admin authentication required")
+
+// AdminAuth restricts the admin routes to the requests carrying the admin
+// token as a bearer token. Every request is rejected when the token is empty.
+type AdminAuth struct {
+	token []byte
+}
+
+// NewAdminAuth create new AdminAuth middleware accepting the token
+func NewAdminAuth(token string) *AdminAuth {
+	return &AdminAuth{token: []byte(token)}
+}
+
+func (a AdminAuth) Execute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			response.NewError(ErrUnauthorizedAdmin, http.StatusUnauthorized).Send(w)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a AdminAuth) authorized(r *http.Request) bool {
+	if len(a.token) == 0 {
+		return false
+	}
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, "Bearer ")), a.token) == 1
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// AuditOrigin passes the correlation ID, the IP and the user agent of each
+// request to the use cases, recorded with the actions they append to the audit
+// log. It runs after CorrelationID. X-Forwarded-For is only read from the
+// requests of the trusted proxies, the client setting it otherwise.
+type AuditOrigin struct {
+	trusted []*net.IPNet
+}
+
+// NewAuditOrigin create new AuditOrigin middleware trusting the proxies of the networks
+func NewAuditOrigin(trusted []*net.IPNet) *AuditOrigin {
+	return &AuditOrigin{trusted: trusted}
+}
+
+func (a AuditOrigin) Execute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := usecase.ContextWithAuditOrigin(r.Context(), entity.AuditOrigin{
+			CorrelationID: logger.CorrelationIDFromContext(r.Context()),
+			IP:            a.clientIP(r),
+			UserAgent:     r.UserAgent(),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// clientIP returns the IP the request comes from. Through trusted proxies it is
+// the rightmost hop of X-Forwarded-For which is not a trusted proxy, the hops on
+// its left being set by the client.
+func (a AuditOrigin) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	if !a.trustedIP(ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		// a malformed hop was not added by a trusted proxy, the last one is the origin known
+		if net.ParseIP(hop) == nil {
+			return ip
+		}
+
+		ip = hop
+		if !a.trustedIP(hop) {
+			return ip
+		}
+	}
+
+	return ip
+}
+
+func (a AuditOrigin) trustedIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range a.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
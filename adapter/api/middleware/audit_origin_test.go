@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuditOriginClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		trusted    []*net.IPNet
+		remoteAddr string
+		forwarded  []string
+		ip         string
+	}{
+		{name: "direct", remoteAddr: "203.0.113.7:4321", ip: "203.0.113.7"},
+		{name: "forged without trusted proxy", remoteAddr: "203.0.113.7:4321", forwarded: []string{"198.51.100.1"}, ip: "203.0.113.7"},
+		{name: "forged by untrusted peer", trusted: []*net.IPNet{proxies}, remoteAddr: "203.0.113.7:4321", forwarded: []string{"198.51.100.1"}, ip: "203.0.113.7"},
+		{name: "through trusted proxy", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", forwarded: []string{"203.0.113.7"}, ip: "203.0.113.7"},
+		{name: "forged hop before client", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", forwarded: []string{"198.51.100.1, 203.0.113.7, 10.0.0.3"}, ip: "203.0.113.7"},
+		{name: "several headers", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", forwarded: []string{"198.51.100.1", "203.0.113.7"}, ip: "203.0.113.7"},
+		{name: "every hop trusted", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", forwarded: []string{"10.0.0.4, 10.0.0.3"}, ip: "10.0.0.4"},
+		{name: "malformed hop", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", forwarded: []string{"203.0.113.7, unknown"}, ip: "10.0.0.2"},
+		{name: "trusted proxy without header", trusted: []*net.IPNet{proxies}, remoteAddr: "10.0.0.2:4321", ip: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			if got := NewAuditOrigin(tt.trusted).clientIP(r); got != tt.ip {
+				t.Errorf("client IP = %q, want %q", got, tt.ip)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/metrics"
+	"github.com/gorilla/mux"
+)
+
+type (
+	// Metrics records the count and latency of the HTTP requests per route and status
+	Metrics struct {
+		m metrics.Metrics
+	}
+
+	statusRecorder struct {
+		http.ResponseWriter
+		status int
+	}
+)
+
+// NewMetrics create new Metrics middleware with its dependencies
+func NewMetrics(m metrics.Metrics) *Metrics {
+	return &Metrics{m: m}
+}
+
+func (m Metrics) Execute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			start = time.Now()
+			rec   = &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		)
+
+		next.ServeHTTP(rec, r)
+
+		m.m.ObserveHTTPRequest(r.Method, routeTemplate(r), rec.status, time.Since(start))
+	})
+}
+
+// routeTemplate returns the matched route template to keep the label cardinality bounded
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+
+	return tpl
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/response"
+)
+
+const (
+	// ProviderTimestampHeader carries the unix time the payment provider signed the callback at
+	ProviderTimestampHeader = "X-Provider-Timestamp"
+	// ProviderSignatureHeader carries the hex HMAC-SHA256 of the callback
+	ProviderSignatureHeader = "X-Provider-Signature"
+
+	// maxCallbackBody is the largest callback body read
+	maxCallbackBody = 1 << 20
+)
+
+var ErrInvalidProviderSignature = errors.New("invalid payment provider signature")
+
+// ProviderSignature restricts the routes to the callbacks signed by the
+// payment provider with the shared secret: the signature is the HMAC-SHA256
+// of "timestamp.path.body", and the timestamp must be within the tolerance.
+// Every request is rejected when the secret is empty.
+type ProviderSignature struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewProviderSignature create new ProviderSignature middleware checking the
+// callbacks with the secret
+func NewProviderSignature(secret string, tolerance time.Duration) *ProviderSignature {
+	return &ProviderSignature{
+		secret:    []byte(secret),
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+func (p ProviderSignature) Execute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+		if err != nil {
+			response.NewError(err, http.StatusBadRequest).Send(w)
+			return
+		}
+		r.Body.Close()
+
+		if !p.verified(r, body) {
+			response.NewError(ErrInvalidProviderSignature, http.StatusUnauthorized).Send(w)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (p ProviderSignature) verified(r *http.Request, body []byte) bool {
+	if len(p.secret) == 0 {
+		return false
+	}
+
+	timestamp := r.Header.Get(ProviderTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := p.now().Sub(time.Unix(unix, 0)); age > p.tolerance || age < -p.tolerance {
+		return false
+	}
+
+	signature, err := hex.DecodeString(r.Header.Get(ProviderSignatureHeader))
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(signature, SignProviderCallback(p.secret, timestamp, r.URL.Path, body))
+}
+
+// SignProviderCallback returns the signature of a callback of the payment provider
+func SignProviderCallback(secret []byte, timestamp, path string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "." + path + "."))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProviderSignature(t *testing.T) {
+	var (
+		now    = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		secret = "secret"
+		path   = "/fundings/4b6e1f0e-5a3c-4f2a-9d3e-2f1c0b9a8d7e/settle"
+		body   = `{"reference":"ref-1"}`
+	)
+	sign := func(secret string, at time.Time, path, body string) (string, string) {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		return timestamp, hex.EncodeToString(SignProviderCallback([]byte(secret), timestamp, path, []byte(body)))
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		signedAt  time.Time
+		signedFor string
+		signedBy  string
+		status    int
+	}{
+		{name: "valid signature", secret: secret, signedAt: now, signedFor: path, signedBy: secret, status: http.StatusOK},
+		{name: "other secret", secret: secret, signedAt: now, signedFor: path, signedBy: "other", status: http.StatusUnauthorized},
+		{name: "other funding", secret: secret, signedAt: now, signedFor: "/fundings/x/settle", signedBy: secret, status: http.StatusUnauthorized},
+		{name: "expired", secret: secret, signedAt: now.Add(-10 * time.Minute), signedFor: path, signedBy: secret, status: http.StatusUnauthorized},
+		{name: "no secret configured", signedAt: now, signedFor: path, status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				got = string(b)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			timestamp, signature := sign(tt.signedBy, tt.signedAt, tt.signedFor, body)
+			r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			r.Header.Set(ProviderTimestampHeader, timestamp)
+			r.Header.Set(ProviderSignatureHeader, signature)
+			w := httptest.NewRecorder()
+
+			m := NewProviderSignature(tt.secret, 5*time.Minute)
+			m.now = func() time.Time { return now }
+			m.Execute(next).ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && got != body {
+				t.Errorf("body = %q, want %q", got, body)
+			}
+		})
+	}
+}
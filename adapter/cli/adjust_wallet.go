@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+// NewAdjustWalletCommand create new credit or debit Command, the adjustments
+// being recorded in the audit log as made by the actor
+func NewAdjustWalletCommand(uc usecase.AdjustWalletUseCase, adjustment usecase.WalletAdjustment, actor string) Command {
+	name := string(adjustment)
+
+	return Command{
+		Name:    name,
+		Usage:   "-user USER_ID -amount CENTS -reason REASON [-currency BRL] [-wallet main]",
+		Summary: name + " a wallet of a user outside of any transfer, with the reason",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet(name)
+			var (
+				user     = fs.String("user", "", "ID of the user")
+				amount   = fs.Int64("amount", 0, "value of the adjustment, in cents")
+				reason   = fs.String("reason", "", "why the wallet is adjusted")
+				currency = fs.String("currency", string(vo.BRL), "currency of the wallet")
+				wallet   = fs.String("wallet", string(vo.MainWallet), "purpose of the wallet")
+			)
+			if err := parse(fs, args); err != nil {
+				return err
+			}
+
+			id, err := parseUuid("-user", *user, true)
+			if err != nil {
+				return err
+			}
+
+			money, err := parseMoney(*currency, *amount)
+			if err != nil {
+				return err
+			}
+
+			purpose, err := vo.NewWalletPurpose(*wallet)
+			if err != nil {
+				return errors.Wrap(ErrUsage, "-wallet: "+err.Error())
+			}
+
+			output, err := uc.Execute(ctx, usecase.AdjustWalletInput{
+				UserID:     id,
+				Adjustment: adjustment,
+				Value:      money,
+				Wallet:     purpose,
+				Reason:     *reason,
+				Actor:      actor,
+				At:         time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return p.Print(output, Table{
+				Header: []string{"ID", "USER ID", "ACTION", "ACTOR", "DETAIL", "CURRENCY", "PURPOSE", "AMOUNT", "AVAILABLE"},
+				Rows: [][]string{{
+					output.ID,
+					output.UserID,
+					output.Action,
+					output.Actor,
+					output.Detail,
+					output.Wallet.Currency,
+					output.Wallet.Purpose,
+					strconv.FormatInt(output.Wallet.Amount, 10),
+					strconv.FormatInt(output.Wallet.Available, 10),
+				}},
+			})
+		},
+	}
+}
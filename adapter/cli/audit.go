@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+// NewAuditCommand create new audit Command, failing when the verified chain is broken
+func NewAuditCommand(verify usecase.VerifyAuditUseCase) Command {
+	return Command{
+		Name:    "audit",
+		Usage:   "[-from sequence] verify",
+		Summary: "verify the hash chain of the audit log",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("audit")
+			from := fs.Int64("from", 0, "first sequence verified, the first entry of the chain when zero")
+			if err := fs.Parse(args); err != nil {
+				return errors.Wrap(ErrUsage, err.Error())
+			}
+
+			if fs.NArg() != 1 || fs.Arg(0) != "verify" {
+				return errors.Wrap(ErrUsage, "verify is required")
+			}
+			if *from < 0 {
+				return errors.Wrap(ErrUsage, "-from must not be negative")
+			}
+
+			output, err := verify.Execute(ctx, usecase.VerifyAuditInput{FromSequence: *from})
+			if err != nil {
+				return err
+			}
+
+			t := Table{
+				Header: []string{"VALID", "VERIFIED", "LAST SEQUENCE", "BROKEN AT"},
+				Rows: [][]string{{
+					strconv.FormatBool(output.Valid),
+					strconv.Itoa(output.Verified),
+					strconv.FormatInt(output.LastSequence, 10),
+					brokenAt(output),
+				}},
+			}
+			if err := p.Print(output, t); err != nil {
+				return err
+			}
+
+			if !output.Valid {
+				return errors.Wrap(entity.ErrAuditChainBroken, fmt.Sprintf("at sequence %d", output.BrokenAt))
+			}
+
+			return nil
+		},
+	}
+}
+
+func brokenAt(output usecase.VerifyAuditOutput) string {
+	if output.Valid {
+		return ""
+	}
+
+	return strconv.FormatInt(output.BrokenAt, 10)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/pkg/errors"
+)
+
+var ErrUsage = errors.New("invalid arguments")
+
+// Command define a subcommand of the admin tool
+type Command struct {
+	Name string
+	// Usage is the synopsis of the arguments of the command
+	Usage string
+	// Summary is what the command does, in a line
+	Summary string
+	Run     func(ctx context.Context, args []string, p Printer) error
+}
+
+// newFlagSet returns the flags of the command, writing their defaults to stderr on errors
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	return fs
+}
+
+// parse parses the flags of the command, the errors being wrapped in ErrUsage
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errors.Wrap(ErrUsage, err.Error())
+	}
+
+	if fs.NArg() > 0 {
+		return errors.Wrap(ErrUsage, fmt.Sprintf("unexpected argument %q", fs.Arg(0)))
+	}
+
+	return nil
+}
+
+// parseUuid parses the ID of the argument named, an empty ID when it is not required and empty
+func parseUuid(name, value string, required bool) (vo.Uuid, error) {
+	if value == "" && !required {
+		return vo.Uuid{}, nil
+	}
+
+	id, err := vo.NewUuid(value)
+	if err != nil {
+		return vo.Uuid{}, errors.Wrap(ErrUsage, fmt.Sprintf("%s: %s", name, err))
+	}
+
+	return id, nil
+}
+
+// parseTime parses the time of the argument named, as RFC 3339 or as a UTC date, zero when empty
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.Wrap(ErrUsage, fmt.Sprintf("%s: invalid time %q, RFC 3339 or YYYY-MM-DD", name, value))
+}
+
+// parseMoney parses the money of the flags
+func parseMoney(currency string, amount int64) (vo.Money, error) {
+	c, err := vo.NewCurrency(currency)
+	if err != nil {
+		return vo.Money{}, errors.Wrap(ErrUsage, "-currency: "+err.Error())
+	}
+
+	a, err := vo.NewAmount(amount)
+	if err != nil {
+		return vo.Money{}, errors.Wrap(ErrUsage, "-amount: "+err.Error())
+	}
+
+	return vo.NewMoney(c, a), nil
+}
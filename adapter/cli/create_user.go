@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// NewCreateUserCommand create new create-user Command, the users being created
+// as the admins do with their initial balance, by the actor in the audit log
+func NewCreateUserCommand(uc usecase.CreateUserUseCase, actor string) Command {
+	return Command{
+		Name:    "create-user",
+		Usage:   "-name NAME -email EMAIL -password PASSWORD -document VALUE [-document-type CPF] [-type COMMON] [-currency BRL] [-amount 0]",
+		Summary: "create a user with a wallet, funded with the amount",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("create-user")
+			var (
+				name         = fs.String("name", "", "full name of the user")
+				email        = fs.String("email", "", "email of the user")
+				password     = fs.String("password", "", "password of the user")
+				document     = fs.String("document", "", "document number of the user")
+				documentType = fs.String("document-type", string(vo.CPF), "CPF or CNPJ")
+				typeUser     = fs.String("type", string(vo.COMMON), "COMMON or MERCHANT")
+				currency     = fs.String("currency", string(vo.BRL), "currency of the wallet")
+				amount       = fs.Int64("amount", 0, "initial balance of the wallet, in cents")
+			)
+			if err := parse(fs, args); err != nil {
+				return err
+			}
+
+			input, err := createUserInput(*name, *email, *password, *document, *documentType, *typeUser, *currency, *amount)
+			if err != nil {
+				return err
+			}
+			input.Actor = actor
+
+			output, err := uc.Execute(ctx, input)
+			if err != nil {
+				return err
+			}
+
+			return p.Print(output, Table{
+				Header: []string{"ID", "FULL NAME", "EMAIL", "TYPE", "CURRENCY", "AMOUNT", "CREATED AT"},
+				Rows: [][]string{{
+					output.ID,
+					output.FullName,
+					output.Email,
+					output.Type,
+					output.Wallet.Currency,
+					strconv.FormatInt(output.Wallet.Amount, 10),
+					output.CreatedAt,
+				}},
+			})
+		},
+	}
+}
+
+func createUserInput(
+	name, email, password, document, documentType, typeUser, currency string,
+	amount int64,
+) (usecase.CreateUserInput, error) {
+	if name == "" || email == "" || password == "" || document == "" {
+		return usecase.CreateUserInput{}, errors.Wrap(ErrUsage, "-name, -email, -password and -document are required")
+	}
+
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		return usecase.CreateUserInput{}, err
+	}
+
+	doc, err := vo.NewDocument(vo.TypeDocument(documentType), document)
+	if err != nil {
+		return usecase.CreateUserInput{}, errors.Wrap(ErrUsage, "-document: "+err.Error())
+	}
+
+	e, err := vo.NewEmail(email)
+	if err != nil {
+		return usecase.CreateUserInput{}, errors.Wrap(ErrUsage, "-email: "+err.Error())
+	}
+
+	t, err := vo.NewTypeUser(typeUser)
+	if err != nil {
+		return usecase.CreateUserInput{}, errors.Wrap(ErrUsage, "-type: "+err.Error())
+	}
+
+	money, err := parseMoney(currency, amount)
+	if err != nil {
+		return usecase.CreateUserInput{}, err
+	}
+
+	return usecase.CreateUserInput{
+		ID:        id,
+		FullName:  vo.NewFullName(name),
+		Document:  doc,
+		Email:     e,
+		Password:  vo.NewPassword(password),
+		Wallet:    vo.NewWallet(money),
+		Type:      t,
+		CreatedAt: time.Now(),
+		Admin:     true,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+// exportPageSize is the number of entities read at once while exporting
+const exportPageSize = 100
+
+// NewExportCommand create new export Command, writing every user or every transfer
+func NewExportCommand(users usecase.ListUsersUseCase, transfers usecase.ListTransfersUseCase) Command {
+	return Command{
+		Name:    "export",
+		Usage:   "users|transfers",
+		Summary: "export every user with its wallets, or every transfer, the oldest first",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			if len(args) != 1 {
+				return errors.Wrap(ErrUsage, "users or transfers is required")
+			}
+
+			switch args[0] {
+			case "users":
+				output, err := exportUsers(ctx, users)
+				if err != nil {
+					return err
+				}
+
+				return p.Print(output, userTable(output))
+			case "transfers":
+				output, err := exportTransfers(ctx, transfers)
+				if err != nil {
+					return err
+				}
+
+				return p.Print(output, transferTable(output))
+			}
+
+			return errors.Wrap(ErrUsage, "unknown export "+args[0])
+		},
+	}
+}
+
+func exportUsers(ctx context.Context, uc usecase.ListUsersUseCase) ([]usecase.FindUserByIDOutput, error) {
+	output := []usecase.FindUserByIDOutput{}
+	for offset := 0; ; offset += exportPageSize {
+		page, err := uc.Execute(ctx, usecase.ListUsersInput{Offset: offset, Limit: exportPageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, page...)
+		if len(page) < exportPageSize {
+			return output, nil
+		}
+	}
+}
+
+func exportTransfers(ctx context.Context, uc usecase.ListTransfersUseCase) ([]usecase.CreateTransferOutput, error) {
+	output := []usecase.CreateTransferOutput{}
+	for offset := 0; ; offset += exportPageSize {
+		page, err := uc.Execute(ctx, usecase.ListTransfersInput{Offset: offset, Limit: exportPageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, page...)
+		if len(page) < exportPageSize {
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// NewListTransfersCommand create new list-transfers Command
+func NewListTransfersCommand(uc usecase.ListTransfersUseCase) Command {
+	return Command{
+		Name:    "list-transfers",
+		Usage:   "[-user USER_ID] [-from TIME] [-to TIME] [-offset 0] [-limit 100]",
+		Summary: "list the transfers made or received by a user, the oldest first",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("list-transfers")
+			var (
+				user   = fs.String("user", "", "ID of the payer or payee, every user when empty")
+				from   = fs.String("from", "", "first creation time listed, RFC 3339 or YYYY-MM-DD")
+				to     = fs.String("to", "", "creation time listed until, excluded")
+				offset = fs.Int("offset", 0, "number of transfers skipped")
+				limit  = fs.Int("limit", 100, "number of transfers listed")
+			)
+			if err := parse(fs, args); err != nil {
+				return err
+			}
+
+			input, err := listTransfersInput(*user, *from, *to)
+			if err != nil {
+				return err
+			}
+			input.Offset, input.Limit = *offset, *limit
+
+			output, err := uc.Execute(ctx, input)
+			if err != nil {
+				return err
+			}
+
+			return p.Print(output, transferTable(output))
+		},
+	}
+}
+
+func listTransfersInput(user, from, to string) (usecase.ListTransfersInput, error) {
+	id, err := parseUuid("-user", user, false)
+	if err != nil {
+		return usecase.ListTransfersInput{}, err
+	}
+
+	f, err := parseTime("-from", from)
+	if err != nil {
+		return usecase.ListTransfersInput{}, err
+	}
+
+	t, err := parseTime("-to", to)
+	if err != nil {
+		return usecase.ListTransfersInput{}, err
+	}
+
+	return usecase.ListTransfersInput{UserID: id, From: f, To: t}, nil
+}
+
+// transferTable returns a row for each transfer, the payees of a split transfer counted
+func transferTable(transfers []usecase.CreateTransferOutput) Table {
+	t := Table{Header: []string{
+		"ID", "PAYER", "PAYEE", "TYPE", "STATUS", "CURRENCY", "VALUE", "FEE", "CREATED AT",
+	}}
+	for _, tr := range transfers {
+		payee := tr.PayeeID
+		if len(tr.Legs) > 0 {
+			payee = strconv.Itoa(len(tr.Legs)) + " payees"
+		}
+
+		t.Rows = append(t.Rows, []string{
+			tr.ID,
+			tr.PayerID,
+			payee,
+			tr.Type,
+			tr.Status,
+			tr.Currency,
+			strconv.FormatInt(tr.Value, 10),
+			strconv.FormatInt(tr.Fee, 10),
+			tr.CreatedAt,
+		})
+	}
+
+	return t
+}
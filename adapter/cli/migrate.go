@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+// ErrMigrateUnsupported is returned when the storage has no migrations to run from the CLI
+var ErrMigrateUnsupported = errors.New("migrate is only supported by the mongodb storage")
+
+// Migrator applies and reverts the migrations of the storage
+type Migrator interface {
+	Migrate(context.Context) ([]string, error)
+	Rollback(context.Context, int) ([]string, error)
+	Migrations(context.Context) ([]database.MigrationStatus, error)
+}
+
+// NewMigrateCommand create new migrate Command, migrator being nil when the storage has no migrations to run.
+// The pending migrations are also applied when the HTTP server starts, unless MIGRATE_ON_START is false.
+func NewMigrateCommand(migrator Migrator) Command {
+	return Command{
+		Name:    "migrate",
+		Usage:   "[-steps n] up|down|status",
+		Summary: "apply the pending MongoDB migrations, revert the last ones or list them",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("migrate")
+			steps := fs.Int("steps", 1, "number of migrations reverted by down")
+			if err := fs.Parse(args); err != nil {
+				return errors.Wrap(ErrUsage, err.Error())
+			}
+
+			if fs.NArg() != 1 {
+				return errors.Wrap(ErrUsage, "up, down or status is required")
+			}
+
+			if migrator == nil {
+				return ErrMigrateUnsupported
+			}
+
+			switch fs.Arg(0) {
+			case "up":
+				versions, err := migrator.Migrate(ctx)
+				if err != nil {
+					return err
+				}
+
+				return p.Print(nonNil(versions), versionTable(versions))
+			case "down":
+				if *steps < 1 {
+					return errors.Wrap(ErrUsage, "-steps must be positive")
+				}
+
+				versions, err := migrator.Rollback(ctx, *steps)
+				if err != nil {
+					return err
+				}
+
+				return p.Print(nonNil(versions), versionTable(versions))
+			case "status":
+				statuses, err := migrator.Migrations(ctx)
+				if err != nil {
+					return err
+				}
+
+				t := Table{Header: []string{"VERSION", "APPLIED AT", "DESCRIPTION"}}
+				for _, s := range statuses {
+					appliedAt := "pending"
+					if s.AppliedAt != nil {
+						appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+					}
+					t.Rows = append(t.Rows, []string{s.Version, appliedAt, s.Description})
+				}
+
+				return p.Print(statuses, t)
+			}
+
+			return errors.Wrap(ErrUsage, "unknown migrate "+fs.Arg(0))
+		},
+	}
+}
+
+func versionTable(versions []string) Table {
+	t := Table{Header: []string{"VERSION"}}
+	for _, v := range versions {
+		t.Rows = append(t.Rows, []string{v})
+	}
+
+	return t
+}
+
+// nonNil returns versions, empty rather than nil to be printed as an empty JSON array
+func nonNil(versions []string) []string {
+	if versions == nil {
+		return []string{}
+	}
+
+	return versions
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Output formats
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+var ErrInvalidFormat = errors.New("invalid output format, table, json or csv")
+
+type (
+	// Table define the rows printed under the header in the table format
+	Table struct {
+		Header []string
+		Rows   [][]string
+	}
+
+	// Printer writes the output of the commands in the format selected
+	Printer struct {
+		w      io.Writer
+		format string
+	}
+)
+
+// NewPrinter create new Printer writing to w, the table format when the format is empty
+func NewPrinter(w io.Writer, format string) (Printer, error) {
+	switch f := strings.ToLower(format); f {
+	case "":
+		return Printer{w: w, format: FormatTable}, nil
+	case FormatTable, FormatJSON, FormatCSV:
+		return Printer{w: w, format: f}, nil
+	}
+
+	return Printer{}, ErrInvalidFormat
+}
+
+// Print writes the output as indented JSON, or the table of it, aligned or as CSV
+func (p Printer) Print(output interface{}, t Table) error {
+	switch p.format {
+	case FormatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(output)
+	case FormatCSV:
+		w := csv.NewWriter(p.w)
+		if err := w.Write(t.Header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.Rows); err != nil {
+			return err
+		}
+
+		return w.Error()
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
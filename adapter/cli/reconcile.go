@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+var ErrDiscrepancies = errors.New("reconciliation found discrepancies")
+
+// NewReconcileCommand create new reconcile Command, failing when the reconciliation finds discrepancies
+func NewReconcileCommand(uc usecase.ReconcileUseCase) Command {
+	return Command{
+		Name:    "reconcile",
+		Usage:   "[-day YYYY-MM-DD]",
+		Summary: "compare the balance of every wallet to its movements and check the transfers of a day",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("reconcile")
+			day := fs.String("day", "", "UTC day reconciled, yesterday when empty")
+			if err := parse(fs, args); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			d, err := parseTime("-day", *day)
+			if err != nil {
+				return err
+			}
+			if d.IsZero() {
+				d = now.UTC().AddDate(0, 0, -1)
+			}
+
+			output, err := uc.Execute(ctx, usecase.ReconcileInput{Day: d, At: now})
+			if err != nil {
+				return err
+			}
+
+			if err := p.Print(output, reconciliationTable(output)); err != nil {
+				return err
+			}
+
+			if n := len(output.Discrepancies); n > 0 {
+				return errors.Wrap(ErrDiscrepancies, fmt.Sprintf("%d on %s", n, output.Day))
+			}
+
+			return nil
+		},
+	}
+}
+
+// WriteReconciliationReport writes the report of the reconciliation in the format
+func WriteReconciliationReport(w io.Writer, format string, output usecase.ReconcileOutput) error {
+	p, err := NewPrinter(w, format)
+	if err != nil {
+		return err
+	}
+
+	return p.Print(output, reconciliationTable(output))
+}
+
+// reconciliationTable returns a row for each wallet of the report, then for
+// each discrepancy other than a balance mismatch, shown on its wallet
+func reconciliationTable(output usecase.ReconcileOutput) Table {
+	t := Table{Header: []string{
+		"ISSUE", "USER", "CURRENCY", "PURPOSE", "REFERENCE",
+		"OPENING", "CREDITS", "DEBITS", "CLOSING", "EXPECTED", "ACTUAL", "DIFFERENCE", "DETAIL",
+	}}
+
+	mismatches := map[[3]string]usecase.DiscrepancyOutput{}
+	for _, d := range output.Discrepancies {
+		if d.Issue == usecase.BalanceMismatch {
+			mismatches[[3]string{d.UserID, d.Currency, d.Purpose}] = d
+		}
+	}
+
+	for _, b := range output.Balances {
+		d := mismatches[[3]string{b.UserID, b.Currency, b.Purpose}]
+		t.Rows = append(t.Rows, []string{
+			string(d.Issue),
+			b.UserID,
+			b.Currency,
+			b.Purpose,
+			"",
+			strconv.FormatInt(b.Opening, 10),
+			strconv.FormatInt(b.Credits, 10),
+			strconv.FormatInt(b.Debits, 10),
+			strconv.FormatInt(b.Closing, 10),
+			strconv.FormatInt(b.Expected, 10),
+			strconv.FormatInt(b.Actual, 10),
+			strconv.FormatInt(b.Actual-b.Expected, 10),
+			d.Detail,
+		})
+	}
+
+	for _, d := range output.Discrepancies {
+		if d.Issue == usecase.BalanceMismatch {
+			continue
+		}
+
+		reference := d.TransferID
+		if reference == "" {
+			reference = d.FundingID
+		}
+
+		t.Rows = append(t.Rows, []string{
+			string(d.Issue),
+			d.UserID,
+			d.Currency,
+			d.Purpose,
+			reference,
+			"", "", "", "", "", "",
+			strconv.FormatInt(d.Difference, 10),
+			d.Detail,
+		})
+	}
+
+	return t
+}
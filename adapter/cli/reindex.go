@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrReindexUnsupported is returned when the storage has no indexes to create
+var ErrReindexUnsupported = errors.New("reindex is only supported by the mongodb storage")
+
+// Reindexer creates the missing indexes of the storage, returning the names of all of them by collection
+type Reindexer func(context.Context) (map[string][]string, error)
+
+// NewReindexCommand create new reindex Command, reindex being nil when the storage has no indexes to create
+func NewReindexCommand(reindex Reindexer) Command {
+	return Command{
+		Name:    "reindex",
+		Summary: "create the MongoDB indexes of the applied migrations missing from the collections",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			if err := parse(newFlagSet("reindex"), args); err != nil {
+				return err
+			}
+
+			if reindex == nil {
+				return ErrReindexUnsupported
+			}
+
+			indexes, err := reindex(ctx)
+			if err != nil {
+				return err
+			}
+
+			collections := make([]string, 0, len(indexes))
+			for c := range indexes {
+				collections = append(collections, c)
+			}
+			sort.Strings(collections)
+
+			t := Table{Header: []string{"COLLECTION", "INDEXES"}}
+			for _, c := range collections {
+				t.Rows = append(t.Rows, []string{c, strings.Join(indexes[c], ", ")})
+			}
+
+			return p.Print(indexes, t)
+		},
+	}
+}
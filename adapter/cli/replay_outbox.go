@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// NewReplayOutboxCommand create new replay-outbox Command. The notifications
+// are not stored, the completed transfers selected are all notified again.
+func NewReplayOutboxCommand(uc usecase.ReplayNotificationsUseCase) Command {
+	return Command{
+		Name:    "replay-outbox",
+		Usage:   "[-user USER_ID] [-from TIME] [-to TIME]",
+		Summary: "notify the completed transfers again, e.g. after the notifier was down",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			fs := newFlagSet("replay-outbox")
+			var (
+				user = fs.String("user", "", "ID of the payer or payee, every user when empty")
+				from = fs.String("from", "", "first creation time replayed, RFC 3339 or YYYY-MM-DD")
+				to   = fs.String("to", "", "creation time replayed until, excluded")
+			)
+			if err := parse(fs, args); err != nil {
+				return err
+			}
+
+			filter, err := listTransfersInput(*user, *from, *to)
+			if err != nil {
+				return err
+			}
+
+			output, err := uc.Execute(ctx, usecase.ReplayNotificationsInput{
+				UserID: filter.UserID,
+				From:   filter.From,
+				To:     filter.To,
+			})
+			if err != nil {
+				return err
+			}
+
+			return p.Print(output, Table{
+				Header: []string{"NOTIFIED"},
+				Rows:   [][]string{{strconv.Itoa(output.Notified)}},
+			})
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+// NewShowUserCommand create new show-user Command
+func NewShowUserCommand(uc usecase.FindUserByIDUseCase) Command {
+	return Command{
+		Name:    "show-user",
+		Usage:   "USER_ID",
+		Summary: "show a user and the balances of its wallets",
+		Run: func(ctx context.Context, args []string, p Printer) error {
+			if len(args) != 1 {
+				return errors.Wrap(ErrUsage, "the user ID is required")
+			}
+
+			id, err := parseUuid("USER_ID", args[0], true)
+			if err != nil {
+				return err
+			}
+
+			output, err := uc.Execute(ctx, usecase.FindUserByIDInput{ID: id})
+			if err != nil {
+				return err
+			}
+
+			return p.Print(output, userTable([]usecase.FindUserByIDOutput{output}))
+		},
+	}
+}
+
+// userTable returns a row for each wallet of the users
+func userTable(users []usecase.FindUserByIDOutput) Table {
+	t := Table{Header: []string{
+		"ID", "FULL NAME", "EMAIL", "TYPE", "CURRENCY", "PURPOSE", "AMOUNT", "AVAILABLE", "HELD", "CREATED AT",
+	}}
+	for _, u := range users {
+		for _, w := range u.Wallets {
+			t.Rows = append(t.Rows, []string{
+				u.ID,
+				u.FullName,
+				u.Email,
+				u.Type,
+				w.Currency,
+				w.Purpose,
+				strconv.FormatInt(w.Amount, 10),
+				strconv.FormatInt(w.Available, 10),
+				strconv.FormatInt(w.Held, 10),
+				u.CreatedAt,
+			})
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// Check status
+	StatusUp   Status = "UP"
+	StatusDown Status = "DOWN"
+)
+
+var (
+	defaultCheckTimeout = 2 * time.Second
+	defaultCacheTTL     = 5 * time.Second
+)
+
+type (
+	// Status define the state of a check
+	Status string
+
+	// Checker port
+	Checker interface {
+		Name() string
+		Check(context.Context) error
+	}
+
+	// CheckerFunc adapts a function to the Checker port
+	CheckerFunc struct {
+		name string
+		fn   func(context.Context) error
+	}
+
+	// Option is the Health options
+	Option func(*Health)
+
+	// Output data
+	Report struct {
+		Status    Status        `json:"status"`
+		Checks    []CheckReport `json:"checks,omitempty"`
+		CheckedAt string        `json:"checked_at"`
+	}
+
+	// Output data
+	CheckReport struct {
+		Name      string `json:"name"`
+		Status    Status `json:"status"`
+		LatencyMS int64  `json:"latency_ms"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	// Health runs the readiness checkers and caches the last report to avoid
+	// hammering the dependencies when the probes are frequent
+	Health struct {
+		checkers []Checker
+		timeout  time.Duration
+		ttl      time.Duration
+		now      func() time.Time
+
+		mu        sync.Mutex
+		cached    Report
+		expiresAt time.Time
+	}
+)
+
+// NewCheckerFunc create new CheckerFunc
+func NewCheckerFunc(name string, fn func(context.Context) error) CheckerFunc {
+	return CheckerFunc{name: name, fn: fn}
+}
+
+// Name return the name property
+func (c CheckerFunc) Name() string {
+	return c.name
+}
+
+// Check run the function
+func (c CheckerFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// NewHealth create new Health with its checkers
+func NewHealth(checkers []Checker, opts ...Option) *Health {
+	h := &Health{
+		checkers: checkers,
+		timeout:  defaultCheckTimeout,
+		ttl:      defaultCacheTTL,
+		now:      time.Now,
+	}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
+}
+
+// WithCheckTimeout defines the deadline of each checker
+func WithCheckTimeout(t time.Duration) Option {
+	return func(h *Health) {
+		h.timeout = t
+	}
+}
+
+// WithCacheTTL defines for how long a readiness report is reused
+func WithCacheTTL(t time.Duration) Option {
+	return func(h *Health) {
+		h.ttl = t
+	}
+}
+
+// Live reports whether the process is able to serve requests, it does not touch the dependencies
+func (h *Health) Live() Report {
+	return Report{
+		Status:    StatusUp,
+		CheckedAt: h.now().Format(time.RFC3339),
+	}
+}
+
+// Ready runs every checker concurrently, the report is down if any of them fails
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.now().Before(h.expiresAt) {
+		return h.cached
+	}
+
+	var (
+		checks = make([]CheckReport, len(h.checkers))
+		wg     sync.WaitGroup
+	)
+	for i, c := range h.checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			checks[i] = h.check(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusUp,
+		Checks:    checks,
+		CheckedAt: h.now().Format(time.RFC3339),
+	}
+	for _, c := range checks {
+		if c.Status != StatusUp {
+			report.Status = StatusDown
+			break
+		}
+	}
+
+	h.cached = report
+	h.expiresAt = h.now().Add(h.ttl)
+
+	return report
+}
+
+func (h *Health) check(ctx context.Context, c Checker) CheckReport {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		start = h.now()
+		err   = c.Check(ctx)
+		r     = CheckReport{
+			Name:      c.Name(),
+			Status:    StatusUp,
+			LatencyMS: h.now().Sub(start).Milliseconds(),
+		}
+	)
+	if err != nil {
+		r.Status = StatusDown
+		r.Error = err.Error()
+	}
+
+	return r
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthReadyCache(t *testing.T) {
+	var (
+		now   = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		calls int
+		fail  bool
+	)
+
+	db := NewCheckerFunc("database", func(context.Context) error {
+		calls++
+		if fail {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	h := NewHealth([]Checker{db}, WithCacheTTL(5*time.Second))
+	h.now = func() time.Time { return now }
+
+	tests := []struct {
+		name   string
+		after  time.Duration
+		fail   bool
+		status Status
+		calls  int
+	}{
+		{name: "first probe checks", status: StatusUp, calls: 1},
+		{name: "cached within the ttl", after: 4 * time.Second, fail: true, status: StatusUp, calls: 1},
+		{name: "checked again once expired", after: time.Second, fail: true, status: StatusDown, calls: 2},
+		{name: "failure cached too", after: time.Second, status: StatusDown, calls: 2},
+		{name: "recovered once expired", after: 4 * time.Second, status: StatusUp, calls: 3},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.after)
+		fail = tt.fail
+
+		report := h.Ready(context.Background())
+		if report.Status != tt.status {
+			t.Errorf("[%s] status = %s, want %s", tt.name, report.Status, tt.status)
+		}
+		if calls != tt.calls {
+			t.Errorf("[%s] checks = %d, want %d", tt.name, calls, tt.calls)
+		}
+	}
+}
+
+func TestHealthReadyChecks(t *testing.T) {
+	up := NewCheckerFunc("database", func(context.Context) error { return nil })
+	down := NewCheckerFunc("queue", func(context.Context) error { return errors.New("channel closed") })
+	slow := NewCheckerFunc("authorizer", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	tests := []struct {
+		name     string
+		checkers []Checker
+		status   Status
+		checks   []Status
+		errors   []string
+	}{
+		{name: "all up", checkers: []Checker{up}, status: StatusUp, checks: []Status{StatusUp}, errors: []string{""}},
+		{name: "one down", checkers: []Checker{up, down}, status: StatusDown, checks: []Status{StatusUp, StatusDown}, errors: []string{"", "channel closed"}},
+		{name: "timed out", checkers: []Checker{slow, up}, status: StatusDown, checks: []Status{StatusDown, StatusUp}, errors: []string{context.DeadlineExceeded.Error(), ""}},
+		{name: "no checker", status: StatusUp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewHealth(tt.checkers, WithCheckTimeout(10*time.Millisecond)).Ready(context.Background())
+			if report.Status != tt.status {
+				t.Errorf("status = %s, want %s", report.Status, tt.status)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Fatalf("checks = %d, want %d", len(report.Checks), len(tt.checks))
+			}
+			for i, c := range report.Checks {
+				if c.Name != tt.checkers[i].Name() || c.Status != tt.checks[i] || c.Error != tt.errors[i] {
+					t.Errorf("check %d = %+v, want %s %s %q", i, c, tt.checkers[i].Name(), tt.checks[i], tt.errors[i])
+				}
+			}
+		})
+	}
+}
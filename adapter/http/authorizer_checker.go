@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/dungnguyen/clean-architecture/adapter/health"
+)
+
+type authorizerChecker struct {
+	client HTTPGetter
+}
+
+// NewAuthorizerChecker creates new health checker for the authorizer reachability
+func NewAuthorizerChecker(client HTTPGetter) health.Checker {
+	return authorizerChecker{client: client}
+}
+
+// Name return the checker name
+func (a authorizerChecker) Name() string {
+	return "authorizer"
+}
+
+// Check verifies that the authorizer answers without a server error
+func (a authorizerChecker) Check(ctx context.Context) error {
+	res, err := a.client.Get(ctx, os.Getenv("AUTHORIZER_URI"))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("authorizer responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
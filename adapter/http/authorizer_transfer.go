@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/dungnguyen/clean-architecture/adapter/logger"
@@ -15,10 +17,6 @@ const (
 	Autorizado = "Autorizado"
 )
 
-var (
-	errAuthorizationDenied = errors.New("authorization denied")
-)
-
 type (
 	authorizer struct {
 		client HTTPGetter
@@ -40,32 +38,55 @@ func NewAuthorizer(client HTTPGetter, l logger.Logger) usecase.Authorizer {
 	}
 }
 
-// Authorized authorizes a transfer
-func (a authorizer) Authorized(_ context.Context, _ entity.Transfer) (bool, error) {
-	res, err := a.client.Get(os.Getenv("AUTHORIZER_URI"))
+// Authorized authorizes a transfer, the ones the service does not authorize
+// being denied. The transfers it cannot ask for are denied with
+// usecase.ErrAuthorizerUnavailable.
+func (a authorizer) Authorized(ctx context.Context, _ entity.Transfer) (bool, error) {
+	log := a.log.WithContext(ctx)
+
+	res, err := a.client.Get(ctx, os.Getenv("AUTHORIZER_URI"))
 	if err != nil {
-		a.log.WithFields(logger.Fields{
+		log.WithFields(logger.Fields{
 			"key":   a.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to client")
+
+		return false, errors.Wrap(usecase.ErrAuthorizerUnavailable, err.Error())
+	}
+	defer res.Body.Close()
+
+	// a failing service does not deny the transfer
+	if res.StatusCode >= http.StatusInternalServerError {
+		log.WithFields(logger.Fields{
+			"key":         a.logKey,
+			"http_status": res.StatusCode,
+		}).Errorf("failed to authorize")
+
+		return false, errors.Wrap(usecase.ErrAuthorizerUnavailable, fmt.Sprintf("authorizer responded with status %d", res.StatusCode))
 	}
 
 	b := &authorizerResponse{}
 	err = json.NewDecoder(res.Body).Decode(&b)
 	if err != nil {
-		a.log.WithFields(logger.Fields{
+		log.WithFields(logger.Fields{
 			"key":   a.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to marshal message")
 
-		return false, errAuthorizationDenied
+		return false, errors.Wrap(usecase.ErrAuthorizerUnavailable, err.Error())
 	}
 
 	if b.Message != Autorizado {
-		return false, errAuthorizationDenied
+		log.WithFields(logger.Fields{
+			"key":         a.logKey,
+			"http_status": res.StatusCode,
+			"message":     b.Message,
+		}).Infof("transfer not authorized")
+
+		return false, nil
 	}
 
-	a.log.WithFields(logger.Fields{
+	log.WithFields(logger.Fields{
 		"key":         a.logKey,
 		"http_status": res.StatusCode,
 	}).Infof("success to authorized")
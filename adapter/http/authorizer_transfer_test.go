@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type getterFunc func(ctx context.Context, url string) (*http.Response, error)
+
+func (f getterFunc) Get(ctx context.Context, url string) (*http.Response, error) {
+	return f(ctx, url)
+}
+
+type nopLogger struct{}
+
+func (l nopLogger) Debugf(string, ...interface{})             {}
+func (l nopLogger) Infof(string, ...interface{})              {}
+func (l nopLogger) Warnf(string, ...interface{})              {}
+func (l nopLogger) Errorf(string, ...interface{})             {}
+func (l nopLogger) WithFields(logger.Fields) logger.Logger    { return l }
+func (l nopLogger) WithError(error) logger.Logger             { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+func TestAuthorizerAuthorized(t *testing.T) {
+	respond := func(status int, body string) getterFunc {
+		return func(context.Context, string) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}
+	}
+
+	tests := []struct {
+		name   string
+		client HTTPGetter
+		ok     bool
+		err    error
+	}{
+		{name: "authorized", client: respond(http.StatusOK, `{"message":"Autorizado"}`), ok: true},
+		{name: "denied", client: respond(http.StatusForbidden, `{"message":"Negado"}`)},
+		{name: "server error", client: respond(http.StatusBadGateway, `{"message":"Negado"}`), err: usecase.ErrAuthorizerUnavailable},
+		{name: "unreadable answer", client: respond(http.StatusOK, `<html>`), err: usecase.ErrAuthorizerUnavailable},
+		{
+			name: "unreachable",
+			client: getterFunc(func(context.Context, string) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}),
+			err: usecase.ErrAuthorizerUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := NewAuthorizer(tt.client, nopLogger{}).Authorized(context.Background(), entity.Transfer{})
+			if ok != tt.ok || !errors.Is(err, tt.err) {
+				t.Errorf("Authorized() = %v, %v, want %v, %v", ok, err, tt.ok, tt.err)
+			}
+		})
+	}
+}
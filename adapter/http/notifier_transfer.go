@@ -44,63 +44,68 @@ func NewNotifier(c HTTPGetter, p queue.Producer, l logger.Logger) usecase.Notifi
 }
 
 // Notify send a notification
-func (n notifier) Notify(_ context.Context, _ entity.Transfer) {
-	res, err := n.client.Get(os.Getenv("NOTIFY_URI"))
+func (n notifier) Notify(ctx context.Context, _ entity.Transfer) {
+	log := n.log.WithContext(ctx)
+
+	res, err := n.client.Get(ctx, os.Getenv("NOTIFY_URI"))
 	if err != nil {
-		n.log.WithFields(logger.Fields{
+		log.WithFields(logger.Fields{
 			"key":   n.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to client")
 
-		n.publish(err)
+		n.publish(ctx, err)
 		return
 	}
+	defer res.Body.Close()
 
 	b := &notifierResponse{}
 	err = json.NewDecoder(res.Body).Decode(&b)
 	if err != nil {
-		n.log.WithFields(logger.Fields{
+		log.WithFields(logger.Fields{
 			"key":   n.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to marshal message")
 
-		n.publish(err)
+		n.publish(ctx, err)
 		return
 	}
 
 	if b.Message != enviado {
-		n.publish(errFailedToNotify)
+		n.publish(ctx, errFailedToNotify)
 		return
 	}
 
-	n.log.WithFields(logger.Fields{
+	log.WithFields(logger.Fields{
 		"key":         n.logKey,
 		"http_status": res.StatusCode,
 	}).Infof("success to notify")
 }
 
-func (n notifier) publish(err error) {
+func (n notifier) publish(ctx context.Context, err error) {
+	log := n.log.WithContext(ctx)
+
 	message, err := json.Marshal(map[string]string{
 		"uri":   os.Getenv("NOTIFY_URI"),
 		"error": err.Error(),
 	})
 	if err != nil {
-		n.log.WithFields(logger.Fields{
+		log.WithFields(logger.Fields{
 			"key":   n.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to marshal message")
 		return
 	}
 
-	if err := n.publisher.Publish(message); err != nil {
-		n.log.WithFields(logger.Fields{
+	if err := n.publisher.Publish(ctx, message); err != nil {
+		log.WithFields(logger.Fields{
 			"key":   n.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to publish to the queue")
 		return
 	}
 
-	n.log.WithFields(logger.Fields{
+	log.WithFields(logger.Fields{
 		"key": n.logKey,
 	}).Infof("success to publish to the queue")
 }
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	infrahttp "github.com/dungnguyen/clean-architecture/infrastructure/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type producerFunc func(ctx context.Context, message []byte) error
+
+func (f producerFunc) Publish(ctx context.Context, message []byte) error {
+	return f(ctx, message)
+}
+
+// recordSpans registers a tracer provider recording the spans and the W3C
+// propagator as the global ones for the test
+func recordSpans(t *testing.T) (*tracetest.SpanRecorder, trace.Tracer) {
+	t.Helper()
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return recorder, tp.Tracer("test")
+}
+
+// serve starts a server answering message and returns the traceparent of the last request it received
+func serve(t *testing.T, env, message string) *string {
+	t.Helper()
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		fmt.Fprintf(w, `{"message":%q}`, message)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv(env, srv.URL)
+
+	return &traceparent
+}
+
+// assertChildSpan checks the only span ended besides parent is its child, and
+// returns the traceparent it propagates
+func assertChildSpan(t *testing.T, recorder *tracetest.SpanRecorder, parent trace.Span) string {
+	t.Helper()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+
+	child := spans[0]
+	if child.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %s, want %s", child.SpanKind(), trace.SpanKindClient)
+	}
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("parent span = %s, want %s", child.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if child.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("trace = %s, want %s", child.SpanContext().TraceID(), parent.SpanContext().TraceID())
+	}
+
+	return fmt.Sprintf("00-%s-%s-01", child.SpanContext().TraceID(), child.SpanContext().SpanID())
+}
+
+func TestAuthorizerPropagatesTrace(t *testing.T) {
+	recorder, tracer := recordSpans(t)
+	traceparent := serve(t, "AUTHORIZER_URI", Autorizado)
+
+	a := NewAuthorizer(infrahttp.NewClient(infrahttp.NewRequest()), nopLogger{})
+
+	ctx, parent := tracer.Start(context.Background(), "create transfer")
+	ok, err := a.Authorized(ctx, entity.Transfer{})
+	parent.End()
+	if err != nil || !ok {
+		t.Fatalf("authorized = %v, %v, want true", ok, err)
+	}
+
+	want := assertChildSpan(t, recorder, parent)
+	if *traceparent != want {
+		t.Errorf("traceparent = %q, want %q", *traceparent, want)
+	}
+}
+
+func TestNotifierPropagatesTrace(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		published bool
+	}{
+		{name: "notified", message: enviado},
+		{name: "not notified", message: "Falha", published: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, tracer := recordSpans(t)
+			traceparent := serve(t, "NOTIFY_URI", tt.message)
+
+			var published trace.SpanContext
+			p := producerFunc(func(ctx context.Context, _ []byte) error {
+				published = trace.SpanContextFromContext(ctx)
+				return nil
+			})
+			n := NewNotifier(infrahttp.NewClient(infrahttp.NewRequest()), p, nopLogger{})
+
+			ctx, parent := tracer.Start(context.Background(), "create transfer")
+			n.Notify(ctx, entity.Transfer{})
+			parent.End()
+
+			want := assertChildSpan(t, recorder, parent)
+			if *traceparent != want {
+				t.Errorf("traceparent = %q, want %q", *traceparent, want)
+			}
+
+			// the failed notification is published in the trace of the transfer
+			if tt.published != published.IsValid() {
+				t.Fatalf("published = %v, want %v", published.IsValid(), tt.published)
+			}
+			if tt.published && published.SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("published span = %s, want %s", published.SpanID(), parent.SpanContext().SpanID())
+			}
+		})
+	}
+}
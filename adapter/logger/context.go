@@ -0,0 +1,19 @@
+package logger
+
+import "context"
+
+// contextKey avoids collisions with the context values of other packages
+type contextKey string
+
+const correlationIDKey contextKey = "correlation_id"
+
+// ContextWithCorrelationID returns a copy of ctx carrying the correlation id
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationIDFromContext returns the correlation id of ctx, or an empty string
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
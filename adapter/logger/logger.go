@@ -1,7 +1,11 @@
 package logger
 
+import "context"
+
 // Logger
 type Logger interface {
+	Debugf(format string, args ...interface{})
+
 	Infof(format string, args ...interface{})
 
 	Warnf(format string, args ...interface{})
@@ -11,6 +15,9 @@ type Logger interface {
 	WithFields(keyValues Fields) Logger
 
 	WithError(err error) Logger
+
+	// WithContext returns a Logger carrying the correlation and trace IDs found in ctx
+	WithContext(ctx context.Context) Logger
 }
 
 // Fields
@@ -0,0 +1,33 @@
+package metrics
+
+import "time"
+
+const (
+	// Execution results
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metrics port
+type Metrics interface {
+	// ObserveHTTPRequest records an inbound HTTP request by route template and status
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+
+	// ObserveUseCase records a use case execution, result is "success" or the error type
+	ObserveUseCase(useCase, result string, duration time.Duration)
+
+	// ObserveOutboundRequest records an outbound HTTP request, status is 0 on transport errors
+	ObserveOutboundRequest(target, method string, status int, duration time.Duration)
+
+	// IncRetryAttempt counts an outbound HTTP request retry
+	IncRetryAttempt(target string)
+
+	// SetBreakerState records the current state of a circuit breaker
+	SetBreakerState(target, state string)
+
+	// IncQueuePublish counts a message published to a queue
+	IncQueuePublish(queue, result string)
+
+	// SetReconciliation records the discrepancies of the last reconciliation by issue and when it ran
+	SetReconciliation(discrepancies map[string]int, at time.Time)
+}
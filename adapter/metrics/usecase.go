@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var errorTypes = []struct {
+	err  error
+	name string
+}{
+	{entity.ErrUserInsufficientBalance, "insufficient_balance"},
+	{entity.ErrNotFoundUser, "user_not_found"},
+	{vo.ErrNotAllowedTypeUser, "not_allowed_type_user"},
+	{vo.ErrInvalidTypeUser, "invalid_type_user"},
+	{entity.ErrUnauthorizedTransfer, "unauthorized_transfer"},
+	{entity.ErrReviewRequired, "review_required"},
+	{usecase.ErrAuthorizerUnavailable, "authorizer_unavailable"},
+	{entity.ErrConcurrentModification, "concurrent_modification"},
+	{entity.ErrSamePayerAndPayee, "same_payer_and_payee"},
+	{vo.ErrSplitMismatch, "split_mismatch"},
+	{vo.ErrEmptySplitShare, "empty_split_share"},
+	{entity.ErrDuplicatePayee, "duplicate_payee"},
+	{entity.ErrEmptyTransferLeg, "empty_transfer_leg"},
+	{entity.ErrNotFoundFeeAccount, "fee_account_not_found"},
+	{entity.ErrLimitExceeded, "limit_exceeded"},
+	{entity.ErrNotFoundSchedule, "schedule_not_found"},
+	{entity.ErrScheduleInPast, "schedule_in_past"},
+	{entity.ErrScheduleStatusTransition, "invalid_schedule_status"},
+	{entity.ErrNotFoundBatch, "batch_not_found"},
+	{entity.ErrEmptyBatch, "empty_batch"},
+	{entity.ErrBatchTooLarge, "batch_too_large"},
+	{entity.ErrNotFoundReview, "review_not_found"},
+	{entity.ErrReviewStatusTransition, "review_already_decided"},
+	{entity.ErrNotFoundPayment, "payment_not_found"},
+	{entity.ErrPayeeNotMerchant, "payee_not_merchant"},
+	{entity.ErrPaymentStatusTransition, "payment_not_authorized"},
+	{entity.ErrPaymentExpired, "payment_expired"},
+	{entity.ErrInvalidCaptureValue, "invalid_capture_value"},
+	{entity.ErrNotFoundWallet, "wallet_not_found"},
+	{entity.ErrWalletAlreadyExists, "wallet_already_exists"},
+	{entity.ErrInitialBalanceRestricted, "initial_balance_restricted"},
+	{entity.ErrNotFoundFunding, "funding_not_found"},
+	{entity.ErrInvalidFundingValue, "invalid_funding_value"},
+	{entity.ErrFundingStatusTransition, "funding_not_pending"},
+	{entity.ErrFundingFailed, "funding_failed"},
+	{usecase.ErrProviderUnavailable, "provider_unavailable"},
+	{entity.ErrReconciliationLocked, "reconciliation_locked"},
+	{usecase.ErrDayReconciled, "day_reconciled"},
+	{context.DeadlineExceeded, "timeout"},
+	{context.Canceled, "canceled"},
+}
+
+// useCase decorates the execute function of a use case with execution metrics
+type useCase[I, O any] struct {
+	name    string
+	execute func(context.Context, I) (O, error)
+	m       Metrics
+}
+
+// instrument decorates the execute function of a use case, recording its
+// executions under name
+func instrument[I, O any](name string, execute func(context.Context, I) (O, error), m Metrics) useCase[I, O] {
+	return useCase[I, O]{name: name, execute: execute, m: m}
+}
+
+// Execute records the execution of the decorated use case
+func (u useCase[I, O]) Execute(ctx context.Context, i I) (O, error) {
+	start := time.Now()
+	output, err := u.execute(ctx, i)
+	u.m.ObserveUseCase(u.name, Result(err), time.Since(start))
+
+	return output, err
+}
+
+// Result returns "success" for a nil error, the known error type or "failure" otherwise
+func Result(err error) string {
+	if err == nil {
+		return ResultSuccess
+	}
+
+	for _, t := range errorTypes {
+		if errors.Is(err, t.err) {
+			return t.name
+		}
+	}
+
+	return ResultFailure
+}
+
+// NewCreateTransferUseCase decorates the use case with execution metrics
+func NewCreateTransferUseCase(uc usecase.CreateTransferUseCase, m Metrics) usecase.CreateTransferUseCase {
+	return instrument("create_transfer", uc.Execute, m)
+}
+
+// NewCreateSplitTransferUseCase decorates the use case with execution metrics
+func NewCreateSplitTransferUseCase(uc usecase.CreateSplitTransferUseCase, m Metrics) usecase.CreateSplitTransferUseCase {
+	return instrument("create_split_transfer", uc.Execute, m)
+}
+
+// NewCreateUserUseCase decorates the use case with execution metrics
+func NewCreateUserUseCase(uc usecase.CreateUserUseCase, m Metrics) usecase.CreateUserUseCase {
+	return instrument("create_user", uc.Execute, m)
+}
+
+// NewFindUserByIDUseCase decorates the use case with execution metrics
+func NewFindUserByIDUseCase(uc usecase.FindUserByIDUseCase, m Metrics) usecase.FindUserByIDUseCase {
+	return instrument("find_user_by_id", uc.Execute, m)
+}
+
+// NewCreateWalletUseCase decorates the use case with execution metrics
+func NewCreateWalletUseCase(uc usecase.CreateWalletUseCase, m Metrics) usecase.CreateWalletUseCase {
+	return instrument("create_wallet", uc.Execute, m)
+}
+
+// NewScheduleTransferUseCase decorates the use case with execution metrics
+func NewScheduleTransferUseCase(uc usecase.ScheduleTransferUseCase, m Metrics) usecase.ScheduleTransferUseCase {
+	return instrument("schedule_transfer", uc.Execute, m)
+}
+
+// NewListSchedulesUseCase decorates the use case with execution metrics
+func NewListSchedulesUseCase(uc usecase.ListSchedulesUseCase, m Metrics) usecase.ListSchedulesUseCase {
+	return instrument("list_schedules", uc.Execute, m)
+}
+
+// NewChangeScheduleStatusUseCase decorates the use case with execution metrics
+func NewChangeScheduleStatusUseCase(uc usecase.ChangeScheduleStatusUseCase, m Metrics) usecase.ChangeScheduleStatusUseCase {
+	return instrument("change_schedule_status", uc.Execute, m)
+}
+
+// NewExecuteDueSchedulesUseCase decorates the use case with execution metrics
+func NewExecuteDueSchedulesUseCase(uc usecase.ExecuteDueSchedulesUseCase, m Metrics) usecase.ExecuteDueSchedulesUseCase {
+	return instrument("execute_due_schedules", uc.Execute, m)
+}
+
+// NewCreateBatchTransferUseCase decorates the use case with execution metrics
+func NewCreateBatchTransferUseCase(uc usecase.CreateBatchTransferUseCase, m Metrics) usecase.CreateBatchTransferUseCase {
+	return instrument("create_batch_transfer", uc.Execute, m)
+}
+
+// NewFindBatchTransferUseCase decorates the use case with execution metrics
+func NewFindBatchTransferUseCase(uc usecase.FindBatchTransferUseCase, m Metrics) usecase.FindBatchTransferUseCase {
+	return instrument("find_batch_transfer", uc.Execute, m)
+}
+
+// NewProcessBatchTransfersUseCase decorates the use case with execution metrics
+func NewProcessBatchTransfersUseCase(uc usecase.ProcessBatchTransfersUseCase, m Metrics) usecase.ProcessBatchTransfersUseCase {
+	return instrument("process_batch_transfers", uc.Execute, m)
+}
+
+// NewListReviewsUseCase decorates the use case with execution metrics
+func NewListReviewsUseCase(uc usecase.ListReviewsUseCase, m Metrics) usecase.ListReviewsUseCase {
+	return instrument("list_reviews", uc.Execute, m)
+}
+
+// NewDecideReviewUseCase decorates the use case with execution metrics
+func NewDecideReviewUseCase(uc usecase.DecideReviewUseCase, m Metrics) usecase.DecideReviewUseCase {
+	return instrument("decide_review", uc.Execute, m)
+}
+
+// NewExpireReviewsUseCase decorates the use case with execution metrics
+func NewExpireReviewsUseCase(uc usecase.ExpireReviewsUseCase, m Metrics) usecase.ExpireReviewsUseCase {
+	return instrument("expire_reviews", uc.Execute, m)
+}
+
+// NewAuthorizePaymentUseCase decorates the use case with execution metrics
+func NewAuthorizePaymentUseCase(uc usecase.AuthorizePaymentUseCase, m Metrics) usecase.AuthorizePaymentUseCase {
+	return instrument("authorize_payment", uc.Execute, m)
+}
+
+// NewCapturePaymentUseCase decorates the use case with execution metrics
+func NewCapturePaymentUseCase(uc usecase.CapturePaymentUseCase, m Metrics) usecase.CapturePaymentUseCase {
+	return instrument("capture_payment", uc.Execute, m)
+}
+
+// NewVoidPaymentUseCase decorates the use case with execution metrics
+func NewVoidPaymentUseCase(uc usecase.VoidPaymentUseCase, m Metrics) usecase.VoidPaymentUseCase {
+	return instrument("void_payment", uc.Execute, m)
+}
+
+// NewChainAuditUseCase decorates the use case with execution metrics
+func NewChainAuditUseCase(uc usecase.ChainAuditUseCase, m Metrics) usecase.ChainAuditUseCase {
+	return instrument("chain_audit", uc.Execute, m)
+}
+
+// NewVerifyAuditUseCase decorates the use case with execution metrics
+func NewVerifyAuditUseCase(uc usecase.VerifyAuditUseCase, m Metrics) usecase.VerifyAuditUseCase {
+	return instrument("verify_audit", uc.Execute, m)
+}
+
+// NewExpirePaymentsUseCase decorates the use case with execution metrics
+func NewExpirePaymentsUseCase(uc usecase.ExpirePaymentsUseCase, m Metrics) usecase.ExpirePaymentsUseCase {
+	return instrument("expire_payments", uc.Execute, m)
+}
+
+// NewDepositUseCase decorates the use case with execution metrics
+func NewDepositUseCase(uc usecase.DepositUseCase, m Metrics) usecase.DepositUseCase {
+	return instrument("deposit", uc.Execute, m)
+}
+
+// NewWithdrawalUseCase decorates the use case with execution metrics
+func NewWithdrawalUseCase(uc usecase.WithdrawalUseCase, m Metrics) usecase.WithdrawalUseCase {
+	return instrument("withdrawal", uc.Execute, m)
+}
+
+// NewSettleFundingUseCase decorates the use case with execution metrics
+func NewSettleFundingUseCase(uc usecase.SettleFundingUseCase, m Metrics) usecase.SettleFundingUseCase {
+	return instrument("settle_funding", uc.Execute, m)
+}
+
+// NewListAuditEntriesUseCase decorates the use case with execution metrics
+func NewListAuditEntriesUseCase(uc usecase.ListAuditEntriesUseCase, m Metrics) usecase.ListAuditEntriesUseCase {
+	return instrument("list_audit_entries", uc.Execute, m)
+}
+
+// NewReconcileUseCase decorates the use case with execution metrics and, when
+// it succeeds, the number of discrepancies of every issue
+func NewReconcileUseCase(uc usecase.ReconcileUseCase, m Metrics) usecase.ReconcileUseCase {
+	return instrument("reconcile", func(ctx context.Context, i usecase.ReconcileInput) (usecase.ReconcileOutput, error) {
+		output, err := uc.Execute(ctx, i)
+		if err != nil {
+			return output, err
+		}
+
+		discrepancies := make(map[string]int, len(usecase.ReconciliationIssues))
+		for _, issue := range usecase.ReconciliationIssues {
+			discrepancies[string(issue)] = 0
+		}
+		for _, d := range output.Discrepancies {
+			discrepancies[string(d.Issue)]++
+		}
+		m.SetReconciliation(discrepancies, time.Now())
+
+		return output, nil
+	}, m)
+}
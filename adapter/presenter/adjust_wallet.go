@@ -0,0 +1,29 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type adjustWalletPresenter struct{}
+
+// NewAdjustWalletPresenter create new adjustWalletPresenter
+func NewAdjustWalletPresenter() usecase.AdjustWalletPresenter {
+	return adjustWalletPresenter{}
+}
+
+// Output return the audit entry of the adjustment and the balances of the wallet adjusted
+func (a adjustWalletPresenter) Output(entry entity.AuditEntry, wallet *vo.Wallet) usecase.AdjustWalletOutput {
+	return usecase.AdjustWalletOutput{
+		ID:        entry.ID().Value(),
+		UserID:    entry.SubjectID().Value(),
+		Action:    entry.Action().String(),
+		Actor:     entry.Actor(),
+		Detail:    entry.Detail(),
+		Wallet:    walletOutput(wallet),
+		CreatedAt: entry.CreatedAt().Format(time.RFC3339),
+	}
+}
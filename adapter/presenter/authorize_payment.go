@@ -0,0 +1,35 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type authorizePaymentPresenter struct{}
+
+// NewAuthorizePaymentPresenter create new authorizePaymentPresenter
+func NewAuthorizePaymentPresenter() usecase.AuthorizePaymentPresenter {
+	return authorizePaymentPresenter{}
+}
+
+// Output return the authorized payment
+func (a authorizePaymentPresenter) Output(payment entity.Payment) usecase.PaymentOutput {
+	return paymentOutput(payment)
+}
+
+func paymentOutput(p entity.Payment) usecase.PaymentOutput {
+	return usecase.PaymentOutput{
+		ID:         p.ID().Value(),
+		PayerID:    p.Payer().Value(),
+		PayeeID:    p.Payee().Value(),
+		Status:     p.Status().String(),
+		Value:      p.Value().Amount().Value(),
+		Captured:   p.Captured().Value(),
+		TransferID: p.TransferID().Value(),
+		CreatedAt:  p.CreatedAt().Format(time.RFC3339),
+		ExpiresAt:  p.ExpiresAt().Format(time.RFC3339),
+		UpdatedAt:  p.UpdatedAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type capturePaymentPresenter struct{}
+
+// NewCapturePaymentPresenter create new capturePaymentPresenter
+func NewCapturePaymentPresenter() usecase.CapturePaymentPresenter {
+	return capturePaymentPresenter{}
+}
+
+// Output return the payment after the capture
+func (c capturePaymentPresenter) Output(payment entity.Payment) usecase.PaymentOutput {
+	return paymentOutput(payment)
+}
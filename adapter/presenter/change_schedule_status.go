@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type changeScheduleStatusPresenter struct{}
+
+// NewChangeScheduleStatusPresenter create new changeScheduleStatusPresenter
+func NewChangeScheduleStatusPresenter() usecase.ChangeScheduleStatusPresenter {
+	return changeScheduleStatusPresenter{}
+}
+
+// Output return the schedule after the status change
+func (c changeScheduleStatusPresenter) Output(schedule entity.Schedule) usecase.ScheduleOutput {
+	return scheduleOutput(schedule)
+}
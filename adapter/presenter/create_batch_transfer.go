@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type createBatchTransferPresenter struct{}
+
+// NewCreateBatchTransferPresenter create new createBatchTransferPresenter
+func NewCreateBatchTransferPresenter() usecase.CreateBatchTransferPresenter {
+	return createBatchTransferPresenter{}
+}
+
+// Output return the batch creation response
+func (c createBatchTransferPresenter) Output(batch entity.Batch) usecase.BatchTransferOutput {
+	return batchTransferOutput(batch)
+}
+
+func batchTransferOutput(b entity.Batch) usecase.BatchTransferOutput {
+	items := b.Items()
+	transfers := make([]usecase.BatchTransferItemOutput, 0, len(items))
+	for _, item := range items {
+		transfers = append(transfers, usecase.BatchTransferItemOutput{
+			ID:      item.TransferID().Value(),
+			PayeeID: item.Payee().Value(),
+			Value:   item.Value().Amount().Value(),
+			Status:  item.Status().String(),
+			Reason:  item.Reason(),
+		})
+	}
+
+	return usecase.BatchTransferOutput{
+		ID:        b.ID().Value(),
+		PayerID:   b.Payer().Value(),
+		Mode:      b.Mode().String(),
+		Status:    b.Status().String(),
+		Value:     b.Amount().Amount().Value(),
+		Total:     len(items),
+		Processed: b.Processed(),
+		Succeeded: b.Succeeded(),
+		Failed:    b.Failed(),
+		Transfers: transfers,
+		CreatedAt: b.CreatedAt().Format(time.RFC3339),
+		UpdatedAt: b.UpdatedAt().Format(time.RFC3339),
+	}
+}
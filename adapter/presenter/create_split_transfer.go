@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type createSplitTransferPresenter struct{}
+
+// NewCreateSplitTransferPresenter create new createSplitTransferPresenter
+func NewCreateSplitTransferPresenter() usecase.CreateSplitTransferPresenter {
+	return createSplitTransferPresenter{}
+}
+
+// Output return the split transfer creation response with the leg breakdown
+func (c createSplitTransferPresenter) Output(t entity.Transfer) usecase.CreateTransferOutput {
+	return transferOutput(t)
+}
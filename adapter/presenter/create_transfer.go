@@ -16,11 +16,55 @@ func NewCreateTransferPresenter() usecase.CreateTransferPresenter {
 
 // Output return the transfer creation response
 func (c createTransferPresenter) Output(t entity.Transfer) usecase.CreateTransferOutput {
-	return usecase.CreateTransferOutput{
-		ID:        t.ID().Value(),
-		PayerID:   t.Payer().Value(),
-		PayeeID:   t.Payee().Value(),
-		Value:     t.Value().Amount().Value(),
-		CreatedAt: t.CreatedAt().Format(time.RFC3339),
+	return transferOutput(t)
+}
+
+// transferOutput returns the payee and the fee rule of a simple transfer, the legs of a split one
+func transferOutput(t entity.Transfer) usecase.CreateTransferOutput {
+	output := usecase.CreateTransferOutput{
+		ID:                t.ID().Value(),
+		PayerID:           t.Payer().Value(),
+		Type:              t.Type().String(),
+		Status:            t.Status().String(),
+		Currency:          t.Value().Currency().String(),
+		SourceWallet:      t.Source().String(),
+		DestinationWallet: t.Destination().String(),
+		Value:             t.Value().Amount().Value(),
+		Gross:             t.Value().Amount().Value(),
+		Fee:               t.Fee().Amount().Value(),
+		Net:               t.Net().Amount().Value(),
+		CreatedAt:         t.CreatedAt().Format(time.RFC3339),
+	}
+
+	if !t.IsSplit() {
+		output.PayeeID = t.Payee().Value()
+		if legs := t.Legs(); len(legs) > 0 {
+			output.FeeRule = feeRuleOutput(legs[0].Fee())
+		}
+		return output
+	}
+
+	for _, leg := range t.Legs() {
+		output.Legs = append(output.Legs, usecase.TransferLegOutput{
+			PayeeID: leg.Payee().Value(),
+			Value:   leg.Value().Amount().Value(),
+			Fee:     leg.Fee().Amount().Amount().Value(),
+			Net:     leg.Net().Amount().Value(),
+			FeeRule: feeRuleOutput(leg.Fee()),
+		})
+	}
+
+	return output
+}
+
+// feeRuleOutput returns the version of the rule that charged the fee, nil when no rule applied
+func feeRuleOutput(f entity.Fee) *usecase.FeeRuleOutput {
+	if f.RuleID() == "" {
+		return nil
+	}
+
+	return &usecase.FeeRuleOutput{
+		ID:      f.RuleID(),
+		Version: f.RuleVersion(),
 	}
 }
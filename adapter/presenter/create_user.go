@@ -16,6 +16,15 @@ func NewCreateUserPresenter() usecase.CreateUserPresenter {
 
 // Output return the user creation response
 func (c createUserPresenter) Output(u entity.User) usecase.CreateUserOutput {
+	// the user of a failed creation has no wallet
+	var wallet usecase.CreateUserWalletOutput
+	if w := u.Wallet(); w != nil {
+		wallet = usecase.CreateUserWalletOutput{
+			Currency: w.Money().Currency().String(),
+			Amount:   w.Money().Amount().Value(),
+		}
+	}
+
 	return usecase.CreateUserOutput{
 		ID:       u.ID().Value(),
 		FullName: u.FullName().Value(),
@@ -25,10 +34,7 @@ func (c createUserPresenter) Output(u entity.User) usecase.CreateUserOutput {
 			Type:  u.Document().Type().String(),
 			Value: u.Document().Value(),
 		},
-		Wallet: usecase.CreateUserWalletOutput{
-			Currency: u.Wallet().Money().Currency().String(),
-			Amount:   u.Wallet().Money().Amount().Value(),
-		},
+		Wallet: wallet,
 		Roles: usecase.CreateUserRolesOutput{
 			CanTransfer: u.Roles().CanTransfer,
 		},
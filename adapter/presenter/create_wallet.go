@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type createWalletPresenter struct{}
+
+// NewCreateWalletPresenter create new createWalletPresenter
+func NewCreateWalletPresenter() usecase.CreateWalletPresenter {
+	return createWalletPresenter{}
+}
+
+// Output return the wallet creation response
+func (c createWalletPresenter) Output(userID vo.Uuid, w vo.Wallet) usecase.CreateWalletOutput {
+	return usecase.CreateWalletOutput{
+		UserID:   userID.Value(),
+		Currency: w.Money().Currency().String(),
+		Purpose:  w.Purpose().String(),
+		Amount:   w.Money().Amount().Value(),
+	}
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type decideReviewPresenter struct{}
+
+// NewDecideReviewPresenter create new decideReviewPresenter
+func NewDecideReviewPresenter() usecase.DecideReviewPresenter {
+	return decideReviewPresenter{}
+}
+
+// Output return the review after the decision
+func (d decideReviewPresenter) Output(review entity.Review) usecase.ReviewOutput {
+	return reviewOutput(review)
+}
@@ -0,0 +1,37 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type depositPresenter struct{}
+
+// NewDepositPresenter create new depositPresenter
+func NewDepositPresenter() usecase.DepositPresenter {
+	return depositPresenter{}
+}
+
+// Output return the deposit
+func (d depositPresenter) Output(funding entity.Funding) usecase.FundingOutput {
+	return fundingOutput(funding)
+}
+
+func fundingOutput(f entity.Funding) usecase.FundingOutput {
+	return usecase.FundingOutput{
+		ID:        f.ID().Value(),
+		UserID:    f.User().Value(),
+		Type:      f.Type().String(),
+		Method:    f.Method().String(),
+		Status:    f.Status().String(),
+		Currency:  f.Value().Currency().String(),
+		Wallet:    f.Wallet().String(),
+		Value:     f.Value().Amount().Value(),
+		Reference: f.Reference(),
+		Reason:    f.Reason(),
+		CreatedAt: f.CreatedAt().Format(time.RFC3339),
+		UpdatedAt: f.UpdatedAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type findBatchTransferPresenter struct{}
+
+// NewFindBatchTransferPresenter create new findBatchTransferPresenter
+func NewFindBatchTransferPresenter() usecase.FindBatchTransferPresenter {
+	return findBatchTransferPresenter{}
+}
+
+// Output return the batch with the result of its transfers
+func (f findBatchTransferPresenter) Output(batch entity.Batch) usecase.BatchTransferOutput {
+	return batchTransferOutput(batch)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
 	"github.com/dungnguyen/clean-architecture/usecase"
 )
 
@@ -16,6 +17,11 @@ func NewFindUserByIDPresenter() usecase.FindUserByIDPresenter {
 
 // Output return the user fetch response by ID
 func (f findUserByIDPresenter) Output(u entity.User) usecase.FindUserByIDOutput {
+	var wallets = make([]usecase.FindUserByIDWalletOutput, 0, len(u.Wallets()))
+	for _, w := range u.Wallets() {
+		wallets = append(wallets, walletOutput(w))
+	}
+
 	return usecase.FindUserByIDOutput{
 		ID:       u.ID().Value(),
 		FullName: u.FullName().Value(),
@@ -24,10 +30,8 @@ func (f findUserByIDPresenter) Output(u entity.User) usecase.FindUserByIDOutput
 			Type:  u.Document().Type().String(),
 			Value: u.Document().Value(),
 		},
-		Wallet: usecase.FindUserByIDWalletOutput{
-			Currency: u.Wallet().Money().Currency().String(),
-			Amount:   u.Wallet().Money().Amount().Value(),
-		},
+		Wallet:  walletOutput(u.Wallet()),
+		Wallets: wallets,
 		Roles: usecase.FindUserByIDRolesOutput{
 			CanTransfer: u.Roles().CanTransfer,
 		},
@@ -35,3 +39,18 @@ func (f findUserByIDPresenter) Output(u entity.User) usecase.FindUserByIDOutput
 		CreatedAt: u.CreatedAt().Format(time.RFC3339),
 	}
 }
+
+// walletOutput returns the balances of the wallet, empty without wallet
+func walletOutput(w *vo.Wallet) usecase.FindUserByIDWalletOutput {
+	if w == nil {
+		return usecase.FindUserByIDWalletOutput{}
+	}
+
+	return usecase.FindUserByIDWalletOutput{
+		Currency:  w.Money().Currency().String(),
+		Purpose:   w.Purpose().String(),
+		Amount:    w.Money().Amount().Value(),
+		Available: w.Available().Amount().Value(),
+		Held:      w.Held().Value(),
+	}
+}
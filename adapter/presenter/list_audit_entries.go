@@ -0,0 +1,51 @@
+package presenter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type listAuditEntriesPresenter struct{}
+
+// NewListAuditEntriesPresenter create new listAuditEntriesPresenter
+func NewListAuditEntriesPresenter() usecase.ListAuditEntriesPresenter {
+	return listAuditEntriesPresenter{}
+}
+
+// Output return the audit entries, an empty list when there is none
+func (l listAuditEntriesPresenter) Output(entries []entity.AuditEntry) []usecase.AuditEntryOutput {
+	output := make([]usecase.AuditEntryOutput, 0, len(entries))
+	for _, e := range entries {
+		output = append(output, usecase.AuditEntryOutput{
+			ID:            e.ID().Value(),
+			Sequence:      e.Sequence(),
+			Action:        e.Action().String(),
+			Actor:         e.Actor(),
+			Target:        e.Target().String(),
+			SubjectID:     e.SubjectID().Value(),
+			Detail:        e.Detail(),
+			Before:        rawSnapshot(e.Before()),
+			After:         rawSnapshot(e.After()),
+			CorrelationID: e.Origin().CorrelationID,
+			IP:            e.Origin().IP,
+			UserAgent:     e.Origin().UserAgent,
+			CreatedAt:     e.CreatedAt().Format(time.RFC3339Nano),
+			PrevHash:      e.PrevHash(),
+			Hash:          e.Hash(),
+		})
+	}
+
+	return output
+}
+
+// rawSnapshot returns the JSON snapshot, nil when there is none
+func rawSnapshot(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+
+	return json.RawMessage(s)
+}
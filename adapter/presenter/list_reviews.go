@@ -0,0 +1,46 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type listReviewsPresenter struct{}
+
+// NewListReviewsPresenter create new listReviewsPresenter
+func NewListReviewsPresenter() usecase.ListReviewsPresenter {
+	return listReviewsPresenter{}
+}
+
+// Output return the reviews, an empty list when there is none
+func (l listReviewsPresenter) Output(reviews []entity.Review) []usecase.ReviewOutput {
+	output := make([]usecase.ReviewOutput, 0, len(reviews))
+	for _, r := range reviews {
+		output = append(output, reviewOutput(r))
+	}
+
+	return output
+}
+
+func reviewOutput(r entity.Review) usecase.ReviewOutput {
+	output := usecase.ReviewOutput{
+		ID:         r.ID().Value(),
+		TransferID: r.TransferID().Value(),
+		PayerID:    r.Payer().Value(),
+		Value:      r.Value().Amount().Value(),
+		Reason:     r.Reason(),
+		Status:     r.Status().String(),
+		Reviewer:   r.Reviewer(),
+		Note:       r.Note(),
+		CreatedAt:  r.CreatedAt().Format(time.RFC3339),
+		ExpiresAt:  r.ExpiresAt().Format(time.RFC3339),
+	}
+
+	if decidedAt := r.DecidedAt(); !decidedAt.IsZero() {
+		output.DecidedAt = decidedAt.Format(time.RFC3339)
+	}
+
+	return output
+}
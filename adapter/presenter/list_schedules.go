@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type listSchedulesPresenter struct{}
+
+// NewListSchedulesPresenter create new listSchedulesPresenter
+func NewListSchedulesPresenter() usecase.ListSchedulesPresenter {
+	return listSchedulesPresenter{}
+}
+
+// Output return the schedules, an empty list when there is none
+func (l listSchedulesPresenter) Output(schedules []entity.Schedule) []usecase.ScheduleOutput {
+	output := make([]usecase.ScheduleOutput, 0, len(schedules))
+	for _, s := range schedules {
+		output = append(output, scheduleOutput(s))
+	}
+
+	return output
+}
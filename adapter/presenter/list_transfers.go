@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type listTransfersPresenter struct{}
+
+// NewListTransfersPresenter create new listTransfersPresenter
+func NewListTransfersPresenter() usecase.ListTransfersPresenter {
+	return listTransfersPresenter{}
+}
+
+// Output return the transfers, an empty list when there is none
+func (l listTransfersPresenter) Output(transfers []entity.Transfer) []usecase.CreateTransferOutput {
+	output := make([]usecase.CreateTransferOutput, 0, len(transfers))
+	for _, t := range transfers {
+		output = append(output, transferOutput(t))
+	}
+
+	return output
+}
@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type listUsersPresenter struct{}
+
+// NewListUsersPresenter create new listUsersPresenter
+func NewListUsersPresenter() usecase.ListUsersPresenter {
+	return listUsersPresenter{}
+}
+
+// Output return the users, an empty list when there is none
+func (l listUsersPresenter) Output(users []entity.User) []usecase.FindUserByIDOutput {
+	output := make([]usecase.FindUserByIDOutput, 0, len(users))
+	for _, u := range users {
+		output = append(output, findUserByIDPresenter{}.Output(u))
+	}
+
+	return output
+}
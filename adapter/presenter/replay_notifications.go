@@ -0,0 +1,26 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type replayNotificationsPresenter struct{}
+
+// NewReplayNotificationsPresenter create new replayNotificationsPresenter
+func NewReplayNotificationsPresenter() usecase.ReplayNotificationsPresenter {
+	return replayNotificationsPresenter{}
+}
+
+// Output return the number and the IDs of the transfers notified
+func (r replayNotificationsPresenter) Output(transfers []entity.Transfer) usecase.ReplayNotificationsOutput {
+	ids := make([]string, 0, len(transfers))
+	for _, t := range transfers {
+		ids = append(ids, t.ID().Value())
+	}
+
+	return usecase.ReplayNotificationsOutput{
+		Notified:  len(transfers),
+		Transfers: ids,
+	}
+}
@@ -0,0 +1,50 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type scheduleTransferPresenter struct{}
+
+// NewScheduleTransferPresenter create new scheduleTransferPresenter
+func NewScheduleTransferPresenter() usecase.ScheduleTransferPresenter {
+	return scheduleTransferPresenter{}
+}
+
+// Output return the schedule creation response
+func (s scheduleTransferPresenter) Output(schedule entity.Schedule) usecase.ScheduleOutput {
+	return scheduleOutput(schedule)
+}
+
+func scheduleOutput(s entity.Schedule) usecase.ScheduleOutput {
+	output := usecase.ScheduleOutput{
+		ID:      s.ID().Value(),
+		PayerID: s.Payer().Value(),
+		PayeeID: s.Payee().Value(),
+		Value:   s.Value().Amount().Value(),
+		Recurrence: usecase.ScheduleRecurrenceOutput{
+			Frequency:  s.Recurrence().Frequency().String(),
+			DayOfMonth: s.Recurrence().DayOfMonth(),
+			Count:      s.Recurrence().Count(),
+		},
+		Status:      s.Status().String(),
+		Occurrences: s.Occurrences(),
+		LastError:   s.LastError(),
+		CreatedAt:   s.CreatedAt().Format(time.RFC3339),
+		UpdatedAt:   s.UpdatedAt().Format(time.RFC3339),
+	}
+
+	if endAt := s.Recurrence().EndAt(); !endAt.IsZero() {
+		output.Recurrence.EndAt = endAt.Format(time.RFC3339)
+	}
+
+	if s.Status() == vo.ScheduleActive || s.Status() == vo.SchedulePaused {
+		output.NextRunAt = s.NextRunAt().Format(time.RFC3339)
+	}
+
+	return output
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type settleFundingPresenter struct{}
+
+// NewSettleFundingPresenter create new settleFundingPresenter
+func NewSettleFundingPresenter() usecase.SettleFundingPresenter {
+	return settleFundingPresenter{}
+}
+
+// Output return the funding as the payment provider answered it
+func (s settleFundingPresenter) Output(funding entity.Funding) usecase.FundingOutput {
+	return fundingOutput(funding)
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type voidPaymentPresenter struct{}
+
+// NewVoidPaymentPresenter create new voidPaymentPresenter
+func NewVoidPaymentPresenter() usecase.VoidPaymentPresenter {
+	return voidPaymentPresenter{}
+}
+
+// Output return the voided payment
+func (v voidPaymentPresenter) Output(payment entity.Payment) usecase.PaymentOutput {
+	return paymentOutput(payment)
+}
@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type withdrawalPresenter struct{}
+
+// NewWithdrawalPresenter create new withdrawalPresenter
+func NewWithdrawalPresenter() usecase.WithdrawalPresenter {
+	return withdrawalPresenter{}
+}
+
+// Output return the withdrawal
+func (w withdrawalPresenter) Output(funding entity.Funding) usecase.FundingOutput {
+	return fundingOutput(funding)
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// simulatedReason is why the simulated provider fails the fundings
+const simulatedReason = "failed by the simulated payment provider"
+
+// simulatedProvider answers every funding with the same status, without moving any money
+type simulatedProvider struct {
+	status vo.FundingStatus
+}
+
+// NewSimulatedProvider create new simulatedProvider answering the fundings
+// with the status. The pending ones are settled through the provider callbacks.
+func NewSimulatedProvider(status vo.FundingStatus) usecase.PaymentProvider {
+	return simulatedProvider{status: status}
+}
+
+// Deposit answers the deposit with the status of the provider
+func (s simulatedProvider) Deposit(_ context.Context, funding entity.Funding) (usecase.ProviderResult, error) {
+	return s.answer(funding), nil
+}
+
+// Withdraw answers the withdrawal with the status of the provider
+func (s simulatedProvider) Withdraw(_ context.Context, funding entity.Funding) (usecase.ProviderResult, error) {
+	return s.answer(funding), nil
+}
+
+func (s simulatedProvider) answer(funding entity.Funding) usecase.ProviderResult {
+	result := usecase.ProviderResult{
+		Reference: "sim-" + funding.ID().Value(),
+		Status:    s.status,
+	}
+	if s.status == vo.FundingFailed {
+		result.Reason = simulatedReason
+	}
+
+	return result
+}
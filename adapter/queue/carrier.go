@@ -0,0 +1,31 @@
+package queue
+
+import "github.com/streadway/amqp"
+
+// headersCarrier adapts the AMQP message headers to propagation.TextMapCarrier
+type headersCarrier amqp.Table
+
+// Get returns the value associated with the passed key
+func (c headersCarrier) Get(key string) string {
+	v, ok := c[key].(string)
+	if !ok {
+		return ""
+	}
+
+	return v
+}
+
+// Set stores the key-value pair
+func (c headersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the keys stored in this carrier
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,29 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+)
+
+type logProducer struct {
+	log    logger.Logger
+	logKey string
+}
+
+// NewLogProducer create new Producer that only logs the messages, used when no broker is configured
+func NewLogProducer(l logger.Logger) Producer {
+	return logProducer{
+		log:    l,
+		logKey: "queue_producer",
+	}
+}
+
+// Publish logs the message
+func (p logProducer) Publish(ctx context.Context, message []byte) error {
+	p.log.WithContext(ctx).WithFields(logger.Fields{
+		"key": p.logKey,
+	}).Warnf("no queue configured, message not published: %s", message)
+
+	return nil
+}
@@ -1,50 +1,89 @@
 package queue
 
 import (
+	"context"
+
 	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/adapter/metrics"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type producer struct {
-	channel   *amqp.Channel
-	queueName string
-	log       logger.Logger
-	logKey    string
-}
+type (
+	producer struct {
+		channel   publisher
+		queueName string
+		log       logger.Logger
+		logKey    string
+		metrics   metrics.Metrics
+		tracer    trace.Tracer
+	}
+
+	// publisher is the part of the AMQP channel the producer uses
+	publisher interface {
+		Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	}
+)
 
 // NewProducer create new Producer with it dependencies
-func NewProducer(ch *amqp.Channel, qn string, l logger.Logger) Producer {
+func NewProducer(ch *amqp.Channel, qn string, l logger.Logger, m metrics.Metrics) Producer {
 	return producer{
 		channel:   ch,
 		queueName: qn,
 		log:       l,
 		logKey:    "queue_producer",
+		metrics:   m,
+		tracer:    otel.Tracer("github.com/dungnguyen/clean-architecture/adapter/queue"),
 	}
 }
 
-// Publish send a Publishing from the client to an exchange on the server
-func (p producer) Publish(message []byte) error {
+// Publish send a Publishing from the client to an exchange on the server.
+// The trace context of ctx is propagated in the message headers.
+func (p producer) Publish(ctx context.Context, message []byte) error {
+	ctx, span := p.tracer.Start(
+		ctx,
+		p.queueName+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination", p.queueName),
+		),
+	)
+	defer span.End()
+
+	log := p.log.WithContext(ctx)
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headersCarrier(headers))
+
 	if err := p.channel.Publish(
 		"",
 		p.queueName,
 		false,
 		false,
 		amqp.Publishing{
-			Headers:     amqp.Table{},
+			Headers:     headers,
 			ContentType: "text/plain",
 			Body:        message,
 		}); err != nil {
-		p.log.WithFields(logger.Fields{
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		log.WithFields(logger.Fields{
 			"key":   p.logKey,
 			"error": err.Error(),
 		}).Errorf("failed to publish message: %s", message)
+		p.metrics.IncQueuePublish(p.queueName, metrics.ResultFailure)
 
 		return err
 	}
 
-	p.log.WithFields(logger.Fields{
+	log.WithFields(logger.Fields{
 		"key": p.logKey,
 	}).Infof("new message publish: %s", message)
+	p.metrics.IncQueuePublish(p.queueName, metrics.ResultSuccess)
 
 	return nil
 }
@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/infrastructure/metrics"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type publisherFunc func(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+
+func (f publisherFunc) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	return f(exchange, key, mandatory, immediate, msg)
+}
+
+type nopLogger struct{}
+
+func (l nopLogger) Debugf(string, ...interface{})             {}
+func (l nopLogger) Infof(string, ...interface{})              {}
+func (l nopLogger) Warnf(string, ...interface{})              {}
+func (l nopLogger) Errorf(string, ...interface{})             {}
+func (l nopLogger) WithFields(logger.Fields) logger.Logger    { return l }
+func (l nopLogger) WithError(error) logger.Logger             { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+func TestProducerPublishPropagatesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	var published amqp.Publishing
+	p := producer{
+		channel: publisherFunc(func(_, _ string, _, _ bool, msg amqp.Publishing) error {
+			published = msg
+			return nil
+		}),
+		queueName: "notifications",
+		log:       nopLogger{},
+		metrics:   metrics.Dummy{},
+		tracer:    tp.Tracer("test"),
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if err := p.Publish(ctx, []byte("message")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	send := spans[0]
+	if send.Name() != "notifications send" {
+		t.Fatalf("span = %q, want %q", send.Name(), "notifications send")
+	}
+	if send.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("parent span = %s, want %s", send.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if send.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("trace = %s, want %s", send.SpanContext().TraceID(), parent.SpanContext().TraceID())
+	}
+
+	want := fmt.Sprintf("00-%s-%s-01", send.SpanContext().TraceID(), send.SpanContext().SpanID())
+	if got := published.Headers["traceparent"]; got != want {
+		t.Errorf("traceparent = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appendedFirst sorts the audit entries in the order of the chain, after the
+// entries appended before it having no sequence and before the pending ones,
+// the documents without pending sorting first
+var appendedFirst = bson.D{
+	{Key: "pending", Value: 1},
+	{Key: "sequence", Value: 1},
+	{Key: "created_at", Value: 1},
+	{Key: "id", Value: 1},
+}
+
+type (
+	// Bson data
+	auditEntryBSON struct {
+		ID            string    `bson:"id"`
+		Action        string    `bson:"action"`
+		Actor         string    `bson:"actor"`
+		Target        string    `bson:"target"`
+		SubjectID     string    `bson:"subject_id"`
+		Detail        string    `bson:"detail"`
+		Before        string    `bson:"before"`
+		After         string    `bson:"after"`
+		CorrelationID string    `bson:"correlation_id"`
+		IP            string    `bson:"ip"`
+		UserAgent     string    `bson:"user_agent"`
+		CreatedAt     time.Time `bson:"created_at"`
+		// Sequence, PrevHash and Hash are missing until the entry is chained,
+		// the unique index on the sequence only covering the chained entries
+		Sequence int64  `bson:"sequence,omitempty"`
+		PrevHash string `bson:"prev_hash,omitempty"`
+		Hash     string `bson:"hash,omitempty"`
+		// Pending is set until the entry is chained
+		Pending bool `bson:"pending,omitempty"`
+	}
+
+	auditRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewAuditRepository create new auditRepository with its dependencies
+func NewAuditRepository(handler *database.MongoHandler) entity.AuditRepository {
+	return auditRepository{
+		handler:    handler,
+		collection: "audit_entries",
+	}
+}
+
+// Create perform insertOne into database of the entry pending, inserting a
+// document of its own only so that concurrent transactions do not conflict on it
+func (a auditRepository) Create(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	ctx, span := startSpan(ctx, "insertOne", a.collection)
+	defer span.End()
+
+	doc := newAuditEntryBSON(entry)
+	doc.Pending = true
+
+	if _, err := a.handler.Db().Collection(a.collection).InsertOne(ctx, doc); err != nil {
+		recordError(span, err)
+		if isWriteConflict(err) {
+			return entity.AuditEntry{}, errors.Wrap(entity.ErrConcurrentModification, entity.ErrCreateAuditEntry.Error())
+		}
+		return entity.AuditEntry{}, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	return entry, nil
+}
+
+// ChainPending perform updateOne into database of the pending entries, the
+// oldest first, each chained to the previous one. The unique sequence and the
+// pending condition make a concurrent chainer fail rather than fork the chain.
+func (a auditRepository) ChainPending(ctx context.Context, limit int) (int, error) {
+	ctx, span := startSpan(ctx, "updateOne", a.collection)
+	defer span.End()
+
+	collection := a.handler.Db().Collection(a.collection)
+
+	var last auditEntryBSON
+	err := collection.FindOne(
+		ctx,
+		bson.M{"sequence": bson.M{"$exists": true}},
+		options.FindOne().
+			SetSort(bson.D{{Key: "sequence", Value: -1}}).
+			SetProjection(bson.M{"sequence": 1, "hash": 1}),
+	).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		recordError(span, err)
+		return 0, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}})
+	if limit > 0 {
+		opts = opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"pending": true}, opts)
+	if err != nil {
+		recordError(span, err)
+		return 0, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	var docs []auditEntryBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return 0, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	sequence, hash := last.Sequence, last.Hash
+	for i, doc := range docs {
+		entry, err := doc.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return i, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+		}
+
+		chained := entry.Chain(sequence+1, hash)
+		res, err := collection.UpdateOne(
+			ctx,
+			bson.M{"id": chained.ID().Value(), "pending": true},
+			bson.M{
+				"$set": bson.M{
+					"sequence":  chained.Sequence(),
+					"prev_hash": chained.PrevHash(),
+					"hash":      chained.Hash(),
+				},
+				"$unset": bson.M{"pending": ""},
+			},
+		)
+		if err != nil {
+			recordError(span, err)
+			if mongo.IsDuplicateKeyError(err) || isWriteConflict(err) {
+				return i, errors.Wrap(entity.ErrConcurrentModification, entity.ErrCreateAuditEntry.Error())
+			}
+			return i, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+		}
+		if res.MatchedCount == 0 {
+			recordError(span, entity.ErrConcurrentModification)
+			return i, errors.Wrap(entity.ErrConcurrentModification, entity.ErrCreateAuditEntry.Error())
+		}
+
+		sequence, hash = chained.Sequence(), chained.Hash()
+	}
+
+	return len(docs), nil
+}
+
+// List perform find into database, the chained entries in the order of the
+// chain, after the ones appended before it, then the pending ones
+func (a auditRepository) List(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	ctx, span := startSpan(ctx, "find", a.collection)
+	defer span.End()
+
+	filter := bson.M{}
+	if f.Actor != "" {
+		filter["actor"] = f.Actor
+	}
+	if f.Action != "" {
+		filter["action"] = f.Action.String()
+	}
+	if f.Target != "" {
+		filter["target"] = f.Target.String()
+	}
+	if id := f.SubjectID.Value(); id != "" {
+		filter["subject_id"] = id
+	}
+	if f.CorrelationID != "" {
+		filter["correlation_id"] = f.CorrelationID
+	}
+
+	createdAt := bson.M{}
+	if !f.From.IsZero() {
+		createdAt["$gte"] = f.From.UTC()
+	}
+	if !f.To.IsZero() {
+		createdAt["$lt"] = f.To.UTC()
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+	if f.FromSequence > 0 {
+		filter["sequence"] = bson.M{"$gte": f.FromSequence}
+	}
+
+	opts := options.Find().
+		SetSort(appendedFirst).
+		SetSkip(int64(f.Offset))
+	if f.Limit > 0 {
+		opts = opts.SetLimit(int64(f.Limit))
+	}
+
+	cursor, err := a.handler.Db().Collection(a.collection).Find(ctx, filter, opts)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+	}
+
+	var docs []auditEntryBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+	}
+
+	entries := make([]entity.AuditEntry, 0, len(docs))
+	for _, doc := range docs {
+		entry, err := doc.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func newAuditEntryBSON(e entity.AuditEntry) auditEntryBSON {
+	return auditEntryBSON{
+		ID:            e.ID().Value(),
+		Action:        e.Action().String(),
+		Actor:         e.Actor(),
+		Target:        e.Target().String(),
+		SubjectID:     e.SubjectID().Value(),
+		Detail:        e.Detail(),
+		Before:        e.Before(),
+		After:         e.After(),
+		CorrelationID: e.Origin().CorrelationID,
+		IP:            e.Origin().IP,
+		UserAgent:     e.Origin().UserAgent,
+		CreatedAt:     e.CreatedAt().UTC(),
+		Sequence:      e.Sequence(),
+		PrevHash:      e.PrevHash(),
+		Hash:          e.Hash(),
+	}
+}
+
+// toEntity rebuilds the entry of the document, the entries appended before
+// they had a target being left without one
+func (d auditEntryBSON) toEntity() (entity.AuditEntry, error) {
+	ID, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	action, err := vo.NewAuditAction(d.Action)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	subjectID, err := vo.NewUuid(d.SubjectID)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	var target vo.AuditTarget
+	if d.Target != "" {
+		if target, err = vo.NewAuditTarget(d.Target); err != nil {
+			return entity.AuditEntry{}, err
+		}
+	}
+
+	return entity.NewAuditEntry(ID, action, d.Actor, subjectID, d.Detail, d.CreatedAt).
+		WithTarget(target).
+		WithSnapshots(d.Before, d.After).
+		WithOrigin(entity.AuditOrigin{
+			CorrelationID: d.CorrelationID,
+			IP:            d.IP,
+			UserAgent:     d.UserAgent,
+		}).
+		WithChain(d.Sequence, d.PrevHash, d.Hash), nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	batchBSON struct {
+		ID        string          `bson:"id"`
+		PayerID   string          `bson:"payer_id"`
+		Mode      string          `bson:"mode"`
+		Status    string          `bson:"status"`
+		Items     []batchItemBSON `bson:"items"`
+		CreatedAt time.Time       `bson:"created_at"`
+		UpdatedAt time.Time       `bson:"updated_at"`
+	}
+
+	// Bson data
+	batchItemBSON struct {
+		TransferID string `bson:"transfer_id"`
+		PayeeID    string `bson:"payee_id"`
+		Currency   string `bson:"currency"`
+		Value      int64  `bson:"value"`
+		Status     string `bson:"status"`
+		Reason     string `bson:"reason"`
+	}
+
+	batchRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewBatchRepository create new batchRepository with its dependencies
+func NewBatchRepository(handler *database.MongoHandler) entity.BatchRepository {
+	return batchRepository{
+		handler:    handler,
+		collection: "batches",
+	}
+}
+
+// Create perform insertOne into database
+func (b batchRepository) Create(ctx context.Context, batch entity.Batch) (entity.Batch, error) {
+	ctx, span := startSpan(ctx, "insertOne", b.collection)
+	defer span.End()
+
+	if _, err := b.handler.Db().Collection(b.collection).InsertOne(ctx, newBatchBSON(batch)); err != nil {
+		recordError(span, err)
+		return entity.Batch{}, errors.Wrap(err, entity.ErrCreateBatch.Error())
+	}
+
+	return batch, nil
+}
+
+// FindByID perform findOne into database
+func (b batchRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Batch, error) {
+	ctx, span := startSpan(ctx, "findOne", b.collection)
+	defer span.End()
+
+	var doc batchBSON
+	err := b.handler.Db().Collection(b.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Batch{}, entity.ErrNotFoundBatch
+		default:
+			recordError(span, err)
+			return entity.Batch{}, errors.Wrap(err, entity.ErrFindBatch.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// FindUnfinished perform find into database, oldest batches first
+func (b batchRepository) FindUnfinished(ctx context.Context, limit int) ([]entity.Batch, error) {
+	ctx, span := startSpan(ctx, "find", b.collection)
+	defer span.End()
+
+	cursor, err := b.handler.Db().Collection(b.collection).Find(
+		ctx,
+		bson.M{"status": bson.M{"$in": bson.A{vo.BatchPending.String(), vo.BatchProcessing.String()}}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+
+	var docs []batchBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+
+	batches := make([]entity.Batch, 0, len(docs))
+	for _, doc := range docs {
+		batch, err := doc.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+// Update perform updateOne into database, replacing the status and the items
+func (b batchRepository) Update(ctx context.Context, batch entity.Batch) error {
+	return b.update(ctx, batch, bson.M{
+		"status":     batch.Status().String(),
+		"items":      newBatchBSON(batch).Items,
+		"updated_at": batch.UpdatedAt().UTC(),
+	})
+}
+
+// UpdateItem perform updateOne into database, replacing the status and the item at the index
+func (b batchRepository) UpdateItem(ctx context.Context, batch entity.Batch, index int) error {
+	item := batch.Items()[index]
+	prefix := "items." + strconv.Itoa(index) + "."
+
+	return b.update(ctx, batch, bson.M{
+		"status":          batch.Status().String(),
+		prefix + "status": item.Status().String(),
+		prefix + "reason": item.Reason(),
+		"updated_at":      batch.UpdatedAt().UTC(),
+	})
+}
+
+func (b batchRepository) update(ctx context.Context, batch entity.Batch, set bson.M) error {
+	ctx, span := startSpan(ctx, "updateOne", b.collection)
+	defer span.End()
+
+	res, err := b.handler.Db().Collection(b.collection).
+		UpdateOne(ctx, bson.M{"id": batch.ID().Value()}, bson.M{"$set": set})
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateBatch.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.Wrap(entity.ErrNotFoundBatch, entity.ErrUpdateBatch.Error())
+	}
+
+	return nil
+}
+
+func newBatchBSON(b entity.Batch) batchBSON {
+	items := b.Items()
+	doc := batchBSON{
+		ID:        b.ID().Value(),
+		PayerID:   b.Payer().Value(),
+		Mode:      b.Mode().String(),
+		Status:    b.Status().String(),
+		Items:     make([]batchItemBSON, 0, len(items)),
+		CreatedAt: b.CreatedAt().UTC(),
+		UpdatedAt: b.UpdatedAt().UTC(),
+	}
+
+	for _, item := range items {
+		doc.Items = append(doc.Items, batchItemBSON{
+			TransferID: item.TransferID().Value(),
+			PayeeID:    item.Payee().Value(),
+			Currency:   item.Value().Currency().String(),
+			Value:      item.Value().Amount().Value(),
+			Status:     item.Status().String(),
+			Reason:     item.Reason(),
+		})
+	}
+
+	return doc
+}
+
+func (d batchBSON) toEntity() (entity.Batch, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	mode, err := vo.NewBatchMode(d.Mode)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	status, err := vo.NewBatchStatus(d.Status)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	items := make([]entity.BatchItem, 0, len(d.Items))
+	for _, i := range d.Items {
+		item, err := i.toEntity()
+		if err != nil {
+			return entity.Batch{}, err
+		}
+		items = append(items, item)
+	}
+
+	return entity.RestoreBatch(id, payer, mode, status, items, d.CreatedAt, d.UpdatedAt), nil
+}
+
+func (d batchItemBSON) toEntity() (entity.BatchItem, error) {
+	transferID, err := vo.NewUuid(d.TransferID)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	payee, err := vo.NewUuid(d.PayeeID)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	amount, err := vo.NewAmount(d.Value)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	status, err := vo.NewBatchItemStatus(d.Status)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	return entity.RestoreBatchItem(transferID, payee, vo.NewMoney(currency, amount), status, d.Reason), nil
+}
@@ -2,22 +2,55 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/infrastructure/database"
 	"github.com/pkg/errors"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const (
+	// writeConflictCode is the server error code of a write conflict
+	writeConflictCode = 112
+	// transientTransactionLabel marks the errors of a transaction that may succeed if retried
+	transientTransactionLabel = "TransientTransactionError"
+)
+
 type (
 	// Bson data
 	createTransferBSON struct {
-		ID        string `json:"id"`
-		PayerID   string `json:"payer"`
-		PayeeID   string `json:"payee"`
-		Value     int64  `json:"value"`
-		CreatedAt string `json:"created_at"`
+		ID                string       `bson:"id"`
+		PayerID           string       `bson:"payer_id"`
+		PayeeID           string       `bson:"payee_id"`
+		Value             int64        `bson:"value"`
+		Currency          string       `bson:"currency"`
+		SourceWallet      string       `bson:"source_wallet"`
+		DestinationWallet string       `bson:"destination_wallet"`
+		Type              string       `bson:"type"`
+		Status            string       `bson:"status"`
+		Fee               int64        `bson:"fee"`
+		FeeRule           *feeRuleBSON `bson:"fee_rule,omitempty"`
+		FeeAccount        string       `bson:"fee_account,omitempty"`
+		CreatedAt         time.Time    `bson:"created_at"`
+		// Legs are only stored for the split transfers
+		Legs []createTransferLegBSON `bson:"legs,omitempty"`
+	}
+
+	// Bson data
+	createTransferLegBSON struct {
+		PayeeID string       `bson:"payee"`
+		Value   int64        `bson:"value"`
+		Fee     int64        `bson:"fee"`
+		FeeRule *feeRuleBSON `bson:"fee_rule,omitempty"`
+	}
+
+	// Bson data
+	feeRuleBSON struct {
+		ID      string `bson:"id"`
+		Version int    `bson:"version"`
 	}
 
 	createTransferRepository struct {
@@ -34,24 +67,62 @@ func NewCreateTransferRepository(handler *database.MongoHandler) entity.Transfer
 	}
 }
 
-// Create perform insertOne into database
+// Create perform insertOne into database, entity.ErrTransferAlreadyExists when the ID is used
 func (c createTransferRepository) Create(ctx context.Context, t entity.Transfer) (entity.Transfer, error) {
-	var bson = createTransferBSON{
-		ID:        t.ID().Value(),
-		PayerID:   t.Payer().Value(),
-		PayeeID:   t.Payee().Value(),
-		Value:     t.Value().Amount().Value(),
-		CreatedAt: t.CreatedAt().String(),
+	ctx, span := startSpan(ctx, "insertOne", c.collection)
+	defer span.End()
+
+	n, err := c.handler.Db().Collection(c.collection).CountDocuments(ctx, bson.M{"id": t.ID().Value()})
+	if err != nil {
+		recordError(span, err)
+		return entity.Transfer{}, errors.Wrap(err, entity.ErrCreateTransfer.Error())
+	}
+	if n > 0 {
+		return entity.Transfer{}, errors.Wrap(entity.ErrTransferAlreadyExists, entity.ErrCreateTransfer.Error())
 	}
 
-	if _, err := c.handler.Db().Collection(c.collection).InsertOne(ctx, bson); err != nil {
+	var doc = createTransferBSON{
+		ID:                t.ID().Value(),
+		PayerID:           t.Payer().Value(),
+		PayeeID:           t.Payee().Value(),
+		Value:             t.Value().Amount().Value(),
+		Currency:          t.Value().Currency().String(),
+		SourceWallet:      t.Source().String(),
+		DestinationWallet: t.Destination().String(),
+		Type:              t.Type().String(),
+		Status:            t.Status().String(),
+		Fee:               t.Fee().Amount().Value(),
+		FeeAccount:        t.FeeAccount().Value(),
+		CreatedAt:         t.CreatedAt().UTC(),
+	}
+	if legs := t.Legs(); !t.IsSplit() && len(legs) > 0 {
+		doc.FeeRule = newFeeRuleBSON(legs[0].Fee())
+	}
+	if t.IsSplit() {
+		for _, leg := range t.Legs() {
+			doc.Legs = append(doc.Legs, createTransferLegBSON{
+				PayeeID: leg.Payee().Value(),
+				Value:   leg.Value().Amount().Value(),
+				Fee:     leg.Fee().Amount().Amount().Value(),
+				FeeRule: newFeeRuleBSON(leg.Fee()),
+			})
+		}
+	}
+
+	if _, err := c.handler.Db().Collection(c.collection).InsertOne(ctx, doc); err != nil {
+		recordError(span, err)
 		return entity.Transfer{}, errors.Wrap(err, entity.ErrCreateTransfer.Error())
 	}
 
 	return t, nil
 }
 
+// WithTransaction runs fn inside a session transaction. Write conflicts with a
+// concurrent transaction are reported as entity.ErrConcurrentModification.
 func (c createTransferRepository) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	ctx, span := startSpan(ctx, "transaction", c.collection)
+	defer span.End()
+
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
 		err := fn(sessCtx)
 		if err != nil {
@@ -62,14 +133,39 @@ func (c createTransferRepository) WithTransaction(ctx context.Context, fn func(c
 
 	session, err := c.handler.Client().StartSession()
 	if err != nil {
+		recordError(span, err)
 		return err
 	}
 	defer session.EndSession(ctx)
 
 	_, err = session.WithTransaction(ctx, callback)
 	if err != nil {
+		recordError(span, err)
+		if isWriteConflict(err) {
+			return errors.Wrap(entity.ErrConcurrentModification, err.Error())
+		}
 		return err
 	}
 
 	return nil
 }
+
+// newFeeRuleBSON returns the rule of the fee, nil when no rule applied
+func newFeeRuleBSON(f entity.Fee) *feeRuleBSON {
+	if f.RuleID() == "" {
+		return nil
+	}
+
+	return &feeRuleBSON{ID: f.RuleID(), Version: f.RuleVersion()}
+}
+
+// isWriteConflict reports whether err was caused by a transaction conflicting with another one
+func isWriteConflict(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == writeConflictCode {
+		return true
+	}
+
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(transientTransactionLabel)
+}
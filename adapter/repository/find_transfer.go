@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type (
+	// Bson data
+	transferUsageBSON struct {
+		Count int   `bson:"count"`
+		Value int64 `bson:"value"`
+	}
+
+	findTransferRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewFindTransferRepository create new findTransferRepository with its dependencies
+func NewFindTransferRepository(handler *database.MongoHandler) entity.TransferRepositoryFinder {
+	return findTransferRepository{
+		handler:    handler,
+		collection: "transfers",
+	}
+}
+
+// SumByPayer perform aggregate into database over the transfers, the withdrawals and
+// the payments held, the rejected transfers and the failed withdrawals are not counted
+func (f findTransferRepository) SumByPayer(
+	ctx context.Context,
+	payerID vo.Uuid,
+	currency vo.Currency,
+	from, to time.Time,
+) (entity.TransferUsage, error) {
+	transfers, err := f.sum(ctx, f.collection, bson.M{
+		"payer_id":   payerID.Value(),
+		"currency":   currency.String(),
+		"created_at": bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+		"status":     bson.M{"$ne": vo.TransferRejected.String()},
+	})
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	withdrawals, err := f.sum(ctx, "fundings", bson.M{
+		"user_id":    payerID.Value(),
+		"type":       vo.Withdrawal.String(),
+		"currency":   currency.String(),
+		"created_at": bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+		"status":     bson.M{"$ne": vo.FundingFailed.String()},
+	})
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	// a captured payment is counted by its transfer
+	holds, err := f.sum(ctx, "payments", bson.M{
+		"payer_id":   payerID.Value(),
+		"currency":   currency.String(),
+		"created_at": bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+		"status":     vo.PaymentAuthorized.String(),
+	})
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	amount, err := vo.NewAmount(transfers.Value + withdrawals.Value + holds.Value)
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	return entity.NewTransferUsage(transfers.Count+withdrawals.Count+holds.Count, amount), nil
+}
+
+// sum perform aggregate into database, counting and summing the values of the matched documents
+func (f findTransferRepository) sum(ctx context.Context, collection string, match bson.M) (transferUsageBSON, error) {
+	ctx, span := startSpan(ctx, "aggregate", collection)
+	defer span.End()
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{
+			"_id":   nil,
+			"count": bson.M{"$sum": 1},
+			"value": bson.M{"$sum": "$value"},
+		}},
+	}
+
+	cursor, err := f.handler.Db().Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		recordError(span, err)
+		return transferUsageBSON{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var usage transferUsageBSON
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&usage); err != nil {
+			recordError(span, err)
+			return transferUsageBSON{}, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		recordError(span, err)
+		return transferUsageBSON{}, err
+	}
+
+	return usage, nil
+}
+
+// FindPayees perform distinct into database, once for the payees of the
+// transfers and once for the ones of the split legs, the rejected transfers excluded
+func (f findTransferRepository) FindPayees(ctx context.Context, payerID vo.Uuid, from, to time.Time) ([]vo.Uuid, error) {
+	ctx, span := startSpan(ctx, "distinct", f.collection)
+	defer span.End()
+
+	filter := bson.M{
+		"payer_id":   payerID.Value(),
+		"created_at": bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+		"status":     bson.M{"$ne": vo.TransferRejected.String()},
+	}
+
+	var (
+		payees []vo.Uuid
+		seen   = map[string]bool{}
+	)
+	for _, field := range []string{"payee_id", "legs.payee"} {
+		values, err := f.handler.Db().Collection(f.collection).Distinct(ctx, field, filter)
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+
+		for _, v := range values {
+			id, ok := v.(string)
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			payee, err := vo.NewUuid(id)
+			if err != nil {
+				return nil, err
+			}
+			payees = append(payees, payee)
+		}
+	}
+
+	return payees, nil
+}
+
+// FindByID perform findOne into database
+func (f findTransferRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Transfer, error) {
+	ctx, span := startSpan(ctx, "findOne", f.collection)
+	defer span.End()
+
+	var doc createTransferBSON
+	err := f.handler.Db().Collection(f.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Transfer{}, entity.ErrNotFoundTransfer
+		default:
+			recordError(span, err)
+			return entity.Transfer{}, errors.Wrap(err, entity.ErrFindTransfer.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// toEntity rebuilds the transfer of the document. The documents written before
+// the transfers had a currency, a type or a status are BRL, DIRECT and COMPLETED,
+// the ones written before the wallets had a purpose are between the main wallets.
+func (d createTransferBSON) toEntity() (entity.Transfer, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	code := d.Currency
+	if code == "" {
+		code = string(vo.BRL)
+	}
+
+	currency, err := vo.NewCurrency(code)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	transferType := vo.DirectTransfer
+	if d.Type != "" {
+		if transferType, err = vo.NewTransferType(d.Type); err != nil {
+			return entity.Transfer{}, err
+		}
+	}
+
+	status := vo.TransferCompleted
+	if d.Status != "" {
+		if status, err = vo.NewTransferStatus(d.Status); err != nil {
+			return entity.Transfer{}, err
+		}
+	}
+
+	source, err := vo.NewWalletPurpose(d.SourceWallet)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	destination, err := vo.NewWalletPurpose(d.DestinationWallet)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	legDocs := d.Legs
+	// a transfer which is not split is its only leg
+	if len(legDocs) == 0 {
+		legDocs = []createTransferLegBSON{{PayeeID: d.PayeeID, Value: d.Value, Fee: d.Fee, FeeRule: d.FeeRule}}
+	}
+
+	// the account of the fees charged by a rule, the transfers recorded before it was having none
+	var feeAccount vo.Uuid
+	if d.FeeAccount != "" {
+		if feeAccount, err = vo.NewUuid(d.FeeAccount); err != nil {
+			return entity.Transfer{}, err
+		}
+	}
+
+	legs := make([]entity.TransferLeg, 0, len(legDocs))
+	fees := make([]entity.Fee, 0, len(legDocs))
+	for _, l := range legDocs {
+		payee, err := vo.NewUuid(l.PayeeID)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		value, err := vo.NewAmount(l.Value)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		fee, err := vo.NewAmount(l.Fee)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		var (
+			rule    feeRuleBSON
+			account vo.Uuid
+		)
+		if l.FeeRule != nil {
+			rule, account = *l.FeeRule, feeAccount
+		}
+
+		legs = append(legs, entity.NewTransferLeg(payee, vo.NewMoney(currency, value)))
+		fees = append(fees, entity.RestoreFee(vo.NewMoney(currency, fee), rule.ID, rule.Version, account))
+	}
+
+	t, err := entity.RestoreTransfer(id, payer, legs, transferType, status, d.CreatedAt)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	for i, fee := range fees {
+		t = t.WithFee(i, fee)
+	}
+
+	return t.WithWallets(source, destination), nil
+}
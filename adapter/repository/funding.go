@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	fundingBSON struct {
+		ID        string    `bson:"id"`
+		UserID    string    `bson:"user_id"`
+		Type      string    `bson:"type"`
+		Method    string    `bson:"method"`
+		Currency  string    `bson:"currency"`
+		Value     int64     `bson:"value"`
+		Wallet    string    `bson:"wallet"`
+		Status    string    `bson:"status"`
+		Reference string    `bson:"reference,omitempty"`
+		Reason    string    `bson:"reason,omitempty"`
+		CreatedAt time.Time `bson:"created_at"`
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+
+	fundingRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewFundingRepository create new fundingRepository with its dependencies
+func NewFundingRepository(handler *database.MongoHandler) entity.FundingRepository {
+	return fundingRepository{
+		handler:    handler,
+		collection: "fundings",
+	}
+}
+
+// Create perform insertOne into database
+func (f fundingRepository) Create(ctx context.Context, funding entity.Funding) (entity.Funding, error) {
+	ctx, span := startSpan(ctx, "insertOne", f.collection)
+	defer span.End()
+
+	if _, err := f.handler.Db().Collection(f.collection).InsertOne(ctx, newFundingBSON(funding)); err != nil {
+		recordError(span, err)
+		return entity.Funding{}, errors.Wrap(err, entity.ErrCreateFunding.Error())
+	}
+
+	return funding, nil
+}
+
+// FindByID perform findOne into database
+func (f fundingRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Funding, error) {
+	ctx, span := startSpan(ctx, "findOne", f.collection)
+	defer span.End()
+
+	var doc fundingBSON
+	err := f.handler.Db().Collection(f.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Funding{}, entity.ErrNotFoundFunding
+		default:
+			recordError(span, err)
+			return entity.Funding{}, errors.Wrap(err, entity.ErrFindFunding.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// List perform find into database, oldest fundings first
+func (f fundingRepository) List(ctx context.Context, filter entity.FundingFilter) ([]entity.Funding, error) {
+	ctx, span := startSpan(ctx, "find", f.collection)
+	defer span.End()
+
+	query := bson.M{}
+	if id := filter.UserID.Value(); id != "" {
+		query["user_id"] = id
+	}
+
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From.UTC()
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To.UTC()
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		query["updated_at"] = bson.M{"$gte": filter.UpdatedFrom.UTC()}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}).
+		SetSkip(int64(filter.Offset))
+	if filter.Limit > 0 {
+		opts = opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := f.handler.Db().Collection(f.collection).Find(ctx, query, opts)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+	}
+
+	var docs []fundingBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+	}
+
+	fundings := make([]entity.Funding, 0, len(docs))
+	for _, doc := range docs {
+		funding, err := doc.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+		}
+		fundings = append(fundings, funding)
+	}
+
+	return fundings, nil
+}
+
+// Update perform replaceOne into database when the stored funding is still pending
+func (f fundingRepository) Update(ctx context.Context, funding entity.Funding) error {
+	ctx, span := startSpan(ctx, "replaceOne", f.collection)
+	defer span.End()
+
+	res, err := f.handler.Db().Collection(f.collection).ReplaceOne(
+		ctx,
+		bson.M{"id": funding.ID().Value(), "status": vo.FundingPending.String()},
+		newFundingBSON(funding),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateFunding.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := f.handler.Db().Collection(f.collection).CountDocuments(ctx, bson.M{"id": funding.ID().Value()})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateFunding.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundFunding, entity.ErrUpdateFunding.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateFunding.Error())
+	}
+
+	return nil
+}
+
+func newFundingBSON(f entity.Funding) fundingBSON {
+	return fundingBSON{
+		ID:        f.ID().Value(),
+		UserID:    f.User().Value(),
+		Type:      f.Type().String(),
+		Method:    f.Method().String(),
+		Currency:  f.Value().Currency().String(),
+		Value:     f.Value().Amount().Value(),
+		Wallet:    f.Wallet().String(),
+		Status:    f.Status().String(),
+		Reference: f.Reference(),
+		Reason:    f.Reason(),
+		CreatedAt: f.CreatedAt().UTC(),
+		UpdatedAt: f.UpdatedAt().UTC(),
+	}
+}
+
+func (d fundingBSON) toEntity() (entity.Funding, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	userID, err := vo.NewUuid(d.UserID)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	fundingType, err := vo.NewFundingType(d.Type)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	method, err := vo.NewFundingMethod(d.Method)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	value, err := vo.NewAmount(d.Value)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	wallet, err := vo.NewWalletPurpose(d.Wallet)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	status, err := vo.NewFundingStatus(d.Status)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	return entity.RestoreFunding(
+		id,
+		userID,
+		fundingType,
+		method,
+		vo.NewMoney(currency, value),
+		wallet,
+		status,
+		d.Reference,
+		d.Reason,
+		d.CreatedAt,
+		d.UpdatedAt,
+	), nil
+}
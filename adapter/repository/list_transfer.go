@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type listTransferRepository struct {
+	handler    *database.MongoHandler
+	collection string
+}
+
+// NewListTransferRepository create new listTransferRepository with its dependencies
+func NewListTransferRepository(handler *database.MongoHandler) entity.TransferRepositoryLister {
+	return listTransferRepository{
+		handler:    handler,
+		collection: "transfers",
+	}
+}
+
+// List perform find into database, oldest transfers first
+func (l listTransferRepository) List(ctx context.Context, f entity.TransferFilter) ([]entity.Transfer, error) {
+	ctx, span := startSpan(ctx, "find", l.collection)
+	defer span.End()
+
+	filter := bson.M{}
+	if id := f.UserID.Value(); id != "" {
+		filter["$or"] = bson.A{
+			bson.M{"payer_id": id},
+			bson.M{"payee_id": id},
+			bson.M{"legs.payee": id},
+		}
+	}
+
+	createdAt := bson.M{}
+	if !f.From.IsZero() {
+		createdAt["$gte"] = f.From.UTC()
+	}
+	if !f.To.IsZero() {
+		createdAt["$lt"] = f.To.UTC()
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}).
+		SetSkip(int64(f.Offset))
+	if f.Limit > 0 {
+		opts = opts.SetLimit(int64(f.Limit))
+	}
+
+	cursor, err := l.handler.Db().Collection(l.collection).Find(ctx, filter, opts)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListTransfers.Error())
+	}
+
+	var docs []createTransferBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListTransfers.Error())
+	}
+
+	transfers := make([]entity.Transfer, 0, len(docs))
+	for _, doc := range docs {
+		transfer, err := doc.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	paymentBSON struct {
+		ID         string    `bson:"id"`
+		PayerID    string    `bson:"payer_id"`
+		PayeeID    string    `bson:"payee_id"`
+		Currency   string    `bson:"currency"`
+		Value      int64     `bson:"value"`
+		Captured   int64     `bson:"captured"`
+		Status     string    `bson:"status"`
+		TransferID string    `bson:"transfer_id,omitempty"`
+		CreatedAt  time.Time `bson:"created_at"`
+		ExpiresAt  time.Time `bson:"expires_at"`
+		UpdatedAt  time.Time `bson:"updated_at"`
+	}
+
+	paymentRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewPaymentRepository create new paymentRepository with its dependencies
+func NewPaymentRepository(handler *database.MongoHandler) entity.PaymentRepository {
+	return paymentRepository{
+		handler:    handler,
+		collection: "payments",
+	}
+}
+
+// Create perform insertOne into database
+func (p paymentRepository) Create(ctx context.Context, payment entity.Payment) (entity.Payment, error) {
+	ctx, span := startSpan(ctx, "insertOne", p.collection)
+	defer span.End()
+
+	if _, err := p.handler.Db().Collection(p.collection).InsertOne(ctx, newPaymentBSON(payment)); err != nil {
+		recordError(span, err)
+		return entity.Payment{}, errors.Wrap(err, entity.ErrCreatePayment.Error())
+	}
+
+	return payment, nil
+}
+
+// FindByID perform findOne into database
+func (p paymentRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Payment, error) {
+	ctx, span := startSpan(ctx, "findOne", p.collection)
+	defer span.End()
+
+	var doc paymentBSON
+	err := p.handler.Db().Collection(p.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Payment{}, entity.ErrNotFoundPayment
+		default:
+			recordError(span, err)
+			return entity.Payment{}, errors.Wrap(err, entity.ErrFindPayment.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// FindExpired perform find into database, oldest payments first
+func (p paymentRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Payment, error) {
+	ctx, span := startSpan(ctx, "find", p.collection)
+	defer span.End()
+
+	cursor, err := p.handler.Db().Collection(p.collection).Find(
+		ctx,
+		bson.M{
+			"status":     vo.PaymentAuthorized.String(),
+			"expires_at": bson.M{"$lte": at.UTC()},
+		},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindPayment.Error())
+	}
+
+	var docs []paymentBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindPayment.Error())
+	}
+
+	payments := make([]entity.Payment, 0, len(docs))
+	for _, doc := range docs {
+		payment, err := doc.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// Update perform replaceOne into database when the stored payment is still authorized
+func (p paymentRepository) Update(ctx context.Context, payment entity.Payment) error {
+	ctx, span := startSpan(ctx, "replaceOne", p.collection)
+	defer span.End()
+
+	res, err := p.handler.Db().Collection(p.collection).ReplaceOne(
+		ctx,
+		bson.M{"id": payment.ID().Value(), "status": vo.PaymentAuthorized.String()},
+		newPaymentBSON(payment),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdatePayment.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := p.handler.Db().Collection(p.collection).CountDocuments(ctx, bson.M{"id": payment.ID().Value()})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdatePayment.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundPayment, entity.ErrUpdatePayment.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdatePayment.Error())
+	}
+
+	return nil
+}
+
+func newPaymentBSON(p entity.Payment) paymentBSON {
+	return paymentBSON{
+		ID:         p.ID().Value(),
+		PayerID:    p.Payer().Value(),
+		PayeeID:    p.Payee().Value(),
+		Currency:   p.Value().Currency().String(),
+		Value:      p.Value().Amount().Value(),
+		Captured:   p.Captured().Value(),
+		Status:     p.Status().String(),
+		TransferID: p.TransferID().Value(),
+		CreatedAt:  p.CreatedAt().UTC(),
+		ExpiresAt:  p.ExpiresAt().UTC(),
+		UpdatedAt:  p.UpdatedAt().UTC(),
+	}
+}
+
+func (d paymentBSON) toEntity() (entity.Payment, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payee, err := vo.NewUuid(d.PayeeID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	value, err := vo.NewAmount(d.Value)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	captured, err := vo.NewAmount(d.Captured)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	status, err := vo.NewPaymentStatus(d.Status)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	var transferID vo.Uuid
+	if d.TransferID != "" {
+		if transferID, err = vo.NewUuid(d.TransferID); err != nil {
+			return entity.Payment{}, err
+		}
+	}
+
+	return entity.RestorePayment(
+		id,
+		payer,
+		payee,
+		vo.NewMoney(currency, value),
+		captured,
+		status,
+		transferID,
+		d.CreatedAt,
+		d.ExpiresAt,
+		d.UpdatedAt,
+	), nil
+}
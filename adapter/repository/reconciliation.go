@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	walletBaselineBSON struct {
+		UserID     string    `bson:"user_id"`
+		Currency   string    `bson:"currency"`
+		Purpose    string    `bson:"purpose"`
+		Amount     int64     `bson:"amount"`
+		BaselineAt time.Time `bson:"baseline_at"`
+	}
+
+	reconciliationRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+const (
+	reconciliationCheckpointCollection = "reconciliation_checkpoints"
+	reconciliationLockCollection       = "reconciliation_locks"
+	// reconciliationLockID is the id of the lock of the reconciliation
+	reconciliationLockID = "reconciliation"
+)
+
+// NewReconciliationRepository create new reconciliationRepository with its dependencies
+func NewReconciliationRepository(handler *database.MongoHandler) entity.ReconciliationRepository {
+	return reconciliationRepository{
+		handler:    handler,
+		collection: "wallet_baselines",
+	}
+}
+
+// Baselines perform aggregate into database, the latest baseline of every wallet at or before the time
+func (r reconciliationRepository) Baselines(ctx context.Context, at time.Time) ([]entity.WalletBaseline, error) {
+	ctx, span := startSpan(ctx, "aggregate", r.collection)
+	defer span.End()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"baseline_at": bson.M{"$lte": at.UTC()}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "baseline_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"user_id": "$user_id", "currency": "$currency", "purpose": "$purpose"},
+			"baseline": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$baseline"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "user_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "purpose", Value: 1}}}},
+	}
+
+	cursor, err := r.handler.Db().Collection(r.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+	}
+
+	var docs []walletBaselineBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+	}
+
+	baselines := make([]entity.WalletBaseline, 0, len(docs))
+	for _, doc := range docs {
+		baseline, err := doc.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+		}
+		baselines = append(baselines, baseline)
+	}
+
+	return baselines, nil
+}
+
+// SaveBaselines perform bulkWrite into database, replacing the baseline of the same wallet and time
+func (r reconciliationRepository) SaveBaselines(ctx context.Context, baselines []entity.WalletBaseline) error {
+	if len(baselines) == 0 {
+		return nil
+	}
+
+	ctx, span := startSpan(ctx, "bulkWrite", r.collection)
+	defer span.End()
+
+	models := make([]mongo.WriteModel, 0, len(baselines))
+	for _, b := range baselines {
+		doc := newWalletBaselineBSON(b)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{
+				"user_id":     doc.UserID,
+				"currency":    doc.Currency,
+				"purpose":     doc.Purpose,
+				"baseline_at": doc.BaselineAt,
+			}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+
+	if _, err := r.handler.Db().Collection(r.collection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrSaveWalletBaselines.Error())
+	}
+
+	return nil
+}
+
+// Checkpoint perform find into database, the latest checkpoint at or before the time
+func (r reconciliationRepository) Checkpoint(ctx context.Context, at time.Time) (time.Time, error) {
+	ctx, span := startSpan(ctx, "findOne", reconciliationCheckpointCollection)
+	defer span.End()
+
+	var doc struct {
+		CheckpointAt time.Time `bson:"checkpoint_at"`
+	}
+
+	err := r.handler.Db().Collection(reconciliationCheckpointCollection).FindOne(
+		ctx,
+		bson.M{"checkpoint_at": bson.M{"$lte": at.UTC()}},
+		options.FindOne().SetSort(bson.D{{Key: "checkpoint_at", Value: -1}}),
+	).Decode(&doc)
+	switch err {
+	case nil:
+		return doc.CheckpointAt.UTC(), nil
+	case mongo.ErrNoDocuments:
+		return time.Time{}, nil
+	default:
+		recordError(span, err)
+		return time.Time{}, errors.Wrap(err, entity.ErrFindReconciliationCheckpoint.Error())
+	}
+}
+
+// SaveCheckpoint perform bulkWrite and updateOne into database, the baselines before the checkpoint
+func (r reconciliationRepository) SaveCheckpoint(ctx context.Context, at time.Time, baselines []entity.WalletBaseline) error {
+	if err := r.SaveBaselines(ctx, baselines); err != nil {
+		return errors.Wrap(err, entity.ErrSaveReconciliationCheckpoint.Error())
+	}
+
+	ctx, span := startSpan(ctx, "updateOne", reconciliationCheckpointCollection)
+	defer span.End()
+
+	checkpoint := at.UTC().Truncate(time.Millisecond)
+	if _, err := r.handler.Db().Collection(reconciliationCheckpointCollection).UpdateOne(
+		ctx,
+		bson.M{"checkpoint_at": checkpoint},
+		bson.M{"$setOnInsert": bson.M{"checkpoint_at": checkpoint}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrSaveReconciliationCheckpoint.Error())
+	}
+
+	return nil
+}
+
+// Lock perform updateOne into database, taking the lock when it expired or is already held by the owner
+func (r reconciliationRepository) Lock(ctx context.Context, owner string, at, expiresAt time.Time) error {
+	ctx, span := startSpan(ctx, "updateOne", reconciliationLockCollection)
+	defer span.End()
+
+	_, err := r.handler.Db().Collection(reconciliationLockCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": reconciliationLockID, "$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": at.UTC()}},
+			bson.M{"owner": owner},
+		}},
+		bson.M{"$set": bson.M{"owner": owner, "locked_at": at.UTC(), "expires_at": expiresAt.UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrReconciliationLocked
+	}
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrLockReconciliation.Error())
+	}
+
+	return nil
+}
+
+// Unlock perform deleteOne into database, releasing the lock held by the owner
+func (r reconciliationRepository) Unlock(ctx context.Context, owner string) error {
+	ctx, span := startSpan(ctx, "deleteOne", reconciliationLockCollection)
+	defer span.End()
+
+	if _, err := r.handler.Db().Collection(reconciliationLockCollection).DeleteOne(
+		ctx,
+		bson.M{"_id": reconciliationLockID, "owner": owner},
+	); err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrLockReconciliation.Error())
+	}
+
+	return nil
+}
+
+func newWalletBaselineBSON(b entity.WalletBaseline) walletBaselineBSON {
+	return walletBaselineBSON{
+		UserID:     b.User().Value(),
+		Currency:   b.Currency().String(),
+		Purpose:    b.Purpose().String(),
+		Amount:     b.Amount(),
+		BaselineAt: b.At().UTC(),
+	}
+}
+
+func (d walletBaselineBSON) toEntity() (entity.WalletBaseline, error) {
+	userID, err := vo.NewUuid(d.UserID)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	purpose, err := vo.NewWalletPurpose(d.Purpose)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	return entity.NewWalletBaseline(userID, currency, purpose, d.Amount, d.BaselineAt), nil
+}
@@ -0,0 +1,2588 @@
+// Package repositorytest implements support for testing the storage backends.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/google/uuid"
+)
+
+var errRollback = errors.New("repositorytest: rollback")
+
+type (
+	// Repositories groups the entity ports implemented by a storage backend
+	Repositories struct {
+		UserCreator     entity.UserRepositoryCreator
+		UserFinder      entity.UserRepositoryFinder
+		UserUpdater     entity.UserRepositoryUpdater
+		UserLister      entity.UserRepositoryLister
+		Wallets         entity.UserWalletRepositoryCreator
+		TransferCreator entity.TransferRepositoryCreator
+		TransferFinder  entity.TransferRepositoryFinder
+		TransferUpdater entity.TransferRepositoryUpdater
+		TransferLister  entity.TransferRepositoryLister
+		Schedules       entity.ScheduleRepository
+		Batches         entity.BatchRepository
+		Risks           entity.RiskAssessmentRepository
+		Reviews         entity.ReviewRepository
+		Audit           entity.AuditRepository
+		Payments        entity.PaymentRepository
+		Fundings        entity.FundingRepository
+		Reconciliation  entity.ReconciliationRepository
+	}
+
+	// ConformanceError lists every failed check
+	ConformanceError struct {
+		Failures []string
+	}
+
+	check struct {
+		name string
+		fn   func(context.Context, Repositories) error
+	}
+)
+
+var checks = []check{
+	{"create and find user", testCreateAndFindUser},
+	{"user email changed at", testUserEmailChangedAt},
+	{"find unknown user", testFindUnknownUser},
+	{"find user by email", testFindUserByEmail},
+	{"find user by document", testFindUserByDocument},
+	{"list users", testListUsers},
+	{"update wallet", testUpdateWallet},
+	{"update unknown wallet", testUpdateUnknownWallet},
+	{"update stale wallet", testUpdateStaleWallet},
+	{"create wallet", testCreateWallet},
+	{"update wallet of a purpose", testUpdateWalletOfPurpose},
+	{"update missing wallet", testUpdateMissingWallet},
+	{"commit transaction", testCommitTransaction},
+	{"rollback transaction", testRollbackTransaction},
+	{"concurrent transactions", testConcurrentTransactions},
+	{"duplicate transfer", testDuplicateTransfer},
+	{"split transfer", testSplitTransfer},
+	{"sum transfers by payer", testSumTransfersByPayer},
+	{"find payees", testFindPayees},
+	{"find transfer by id", testFindTransferByID},
+	{"find unknown transfer", testFindUnknownTransfer},
+	{"update transfer status", testUpdateTransferStatus},
+	{"list transfers", testListTransfers},
+	{"create and find schedule", testCreateAndFindSchedule},
+	{"find unknown schedule", testFindUnknownSchedule},
+	{"find due schedules", testFindDueSchedules},
+	{"update schedule", testUpdateSchedule},
+	{"create and find batch", testCreateAndFindBatch},
+	{"find unknown batch", testFindUnknownBatch},
+	{"find unfinished batches", testFindUnfinishedBatches},
+	{"update batch", testUpdateBatch},
+	{"create and find risk assessment", testCreateAndFindRiskAssessment},
+	{"find unknown risk assessment", testFindUnknownRiskAssessment},
+	{"create and find review", testCreateAndFindReview},
+	{"find unknown review", testFindUnknownReview},
+	{"find reviews", testFindReviews},
+	{"update review", testUpdateReview},
+	{"create audit entry", testCreateAuditEntry},
+	{"chain audit entries", testChainAuditEntries},
+	{"concurrent audit entries", testConcurrentAuditEntries},
+	{"list audit entries", testListAuditEntries},
+	{"create and find payment", testCreateAndFindPayment},
+	{"find unknown payment", testFindUnknownPayment},
+	{"find expired payments", testFindExpiredPayments},
+	{"update payment", testUpdatePayment},
+	{"create and find funding", testCreateAndFindFunding},
+	{"find unknown funding", testFindUnknownFunding},
+	{"update funding", testUpdateFunding},
+	{"list fundings", testListFundings},
+	{"save and find wallet baselines", testWalletBaselines},
+	{"save and find reconciliation checkpoints", testReconciliationCheckpoints},
+	{"lock the reconciliation", testReconciliationLock},
+}
+
+// TestRepositories checks that the repositories of a storage backend behave as the
+// entity ports expect. Every backend must pass it against an empty or disposable database.
+// It returns a *ConformanceError describing every failed check, nil otherwise.
+func TestRepositories(ctx context.Context, r Repositories) error {
+	var failures []string
+	for _, c := range checks {
+		if err := c.fn(ctx, r); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ConformanceError{Failures: failures}
+	}
+
+	return nil
+}
+
+// Error returns one line per failed check
+func (e *ConformanceError) Error() string {
+	return "repositorytest: conformance failed:\n\t" + strings.Join(e.Failures, "\n\t")
+}
+
+func testCreateAndFindUser(ctx context.Context, r Repositories) error {
+	want, err := createUser(ctx, r, vo.USD, 1500, vo.MERCHANT)
+	if err != nil {
+		return err
+	}
+
+	got, err := r.UserFinder.FindByID(ctx, want.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.FullName().Equals(want.FullName()):
+		return fmt.Errorf("full name = %s, want %s", got.FullName().Value(), want.FullName().Value())
+	case !got.Email().Equals(want.Email()):
+		return fmt.Errorf("email = %s, want %s", got.Email(), want.Email())
+	case !got.Password().Equals(want.Password()):
+		return errors.New("password differs")
+	case !got.Document().Equals(want.Document()):
+		return fmt.Errorf("document = %s, want %s", got.Document().Value(), want.Document().Value())
+	case !got.Wallet().Money().Equals(want.Wallet().Money()):
+		return fmt.Errorf("wallet = %s %d, want %s %d",
+			got.Wallet().Money().Currency(), got.Wallet().Money().Amount().Value(),
+			want.Wallet().Money().Currency(), want.Wallet().Money().Amount().Value())
+	case got.TypeUser() != want.TypeUser():
+		return fmt.Errorf("type = %s, want %s", got.TypeUser(), want.TypeUser())
+	case got.Roles() != want.Roles():
+		return fmt.Errorf("roles = %+v, want %+v", got.Roles(), want.Roles())
+	case !got.CreatedAt().Equal(want.CreatedAt()):
+		return fmt.Errorf("created at = %s, want %s", got.CreatedAt(), want.CreatedAt())
+	}
+
+	return nil
+}
+
+func testUserEmailChangedAt(ctx context.Context, r Repositories) error {
+	u, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	got, err := r.UserFinder.FindByID(ctx, u.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if !got.EmailChangedAt().IsZero() {
+		return fmt.Errorf("email changed at = %s, want zero", got.EmailChangedAt())
+	}
+
+	changed, err := entity.NewUser(
+		newID(),
+		u.FullName(),
+		u.Email(),
+		u.Password(),
+		u.Document(),
+		vo.NewWallet(u.Wallet().Money()),
+		u.TypeUser(),
+		now(),
+	)
+	if err != nil {
+		return err
+	}
+	changed = changed.WithEmailChangedAt(now().Add(-time.Hour))
+
+	if _, err := r.UserCreator.Create(ctx, changed); err != nil {
+		return fmt.Errorf("Create user: %w", err)
+	}
+
+	got, err = r.UserFinder.FindByID(ctx, changed.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if !got.EmailChangedAt().Equal(changed.EmailChangedAt()) {
+		return fmt.Errorf("email changed at = %s, want %s", got.EmailChangedAt(), changed.EmailChangedAt())
+	}
+
+	return nil
+}
+
+func testFindUnknownUser(ctx context.Context, r Repositories) error {
+	_, err := r.UserFinder.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundUser) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundUser)
+	}
+
+	return nil
+}
+
+func testFindUserByEmail(ctx context.Context, r Repositories) error {
+	email := vo.NewEmailTest(newID().Value()[:8] + "@conformance.test")
+	document := vo.NewDocumentTest(vo.CPF, "070.910.549-45")
+
+	return testFindUserBy(ctx, r, email, document, func(ctx context.Context) (entity.User, error) {
+		return r.UserFinder.FindByEmail(ctx, email)
+	}, func(ctx context.Context) (entity.User, error) {
+		return r.UserFinder.FindByEmail(ctx, vo.NewEmailTest(newID().Value()[:8]+"@conformance.test"))
+	})
+}
+
+func testFindUserByDocument(ctx context.Context, r Repositories) error {
+	email := vo.NewEmailTest(newID().Value()[:8] + "@conformance.test")
+	document := vo.NewDocumentTest(vo.CNPJ, newCNPJ())
+
+	return testFindUserBy(ctx, r, email, document, func(ctx context.Context) (entity.User, error) {
+		return r.UserFinder.FindByDocument(ctx, document)
+	}, func(ctx context.Context) (entity.User, error) {
+		return r.UserFinder.FindByDocument(ctx, vo.NewDocumentTest(vo.CNPJ, newCNPJ()))
+	})
+}
+
+// testFindUserBy creates two users of the email and the document, the second
+// one older, and checks that find returns the older one and findUnknown none
+func testFindUserBy(
+	ctx context.Context,
+	r Repositories,
+	email vo.Email,
+	document vo.Document,
+	find func(context.Context) (entity.User, error),
+	findUnknown func(context.Context) (entity.User, error),
+) error {
+	base := time.Date(1992, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var created []entity.User
+	for _, at := range []time.Time{base, base.Add(-time.Hour)} {
+		u, err := createUserWith(ctx, r, email, document, vo.BRL, 0, vo.COMMON, at)
+		if err != nil {
+			return err
+		}
+		created = append(created, u)
+	}
+
+	got, err := find(ctx)
+	if err != nil {
+		return fmt.Errorf("find: %w", err)
+	}
+	if !got.ID().Equals(created[1].ID()) {
+		return fmt.Errorf("find returned %s, want the oldest user %s", got.ID(), created[1].ID())
+	}
+	if got.Wallet() == nil || got.Email().Value() != email.Value() || got.Document().Value() != document.Value() {
+		return fmt.Errorf("find returned %s without its wallet, its email or its document", got.ID())
+	}
+
+	if _, err := findUnknown(ctx); !errors.Is(err, entity.ErrNotFoundUser) {
+		return fmt.Errorf("find unknown error = %v, want %v", err, entity.ErrNotFoundUser)
+	}
+
+	return nil
+}
+
+func testListUsers(ctx context.Context, r Repositories) error {
+	base := time.Date(1991, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var want []entity.User
+	for i := 0; i < 2; i++ {
+		u, err := createUserAt(ctx, r, vo.BRL, int64(i), vo.COMMON, base.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			return err
+		}
+		want = append(want, u)
+	}
+
+	merchant, err := createUserAt(ctx, r, vo.BRL, 0, vo.MERCHANT, base.Add(30*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	users, err := r.UserLister.List(ctx, entity.UserFilter{})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+
+	positions := map[string]int{}
+	for i, u := range users {
+		positions[u.ID().Value()] = i
+	}
+
+	first, ok := positions[want[0].ID().Value()]
+	if !ok {
+		return fmt.Errorf("List did not return %s", want[0].ID())
+	}
+	if second, ok := positions[want[1].ID().Value()]; !ok || second <= first {
+		return fmt.Errorf("List returned %s at %d and %s at %d, want the oldest first", want[0].ID(), first, want[1].ID(), second)
+	}
+
+	page, err := r.UserLister.List(ctx, entity.UserFilter{Offset: first + 1, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+	if len(page) != 1 || !page[0].ID().Equals(users[first+1].ID()) {
+		return fmt.Errorf("List(%d, 1) returned %d users, want %s", first+1, len(page), users[first+1].ID())
+	}
+
+	for _, c := range []struct {
+		typeUser vo.TypeUser
+		want     []entity.User
+	}{
+		{vo.COMMON, want},
+		{vo.MERCHANT, []entity.User{merchant}},
+	} {
+		filtered, err := r.UserLister.List(ctx, entity.UserFilter{Type: c.typeUser, From: base, To: base.Add(2 * time.Hour)})
+		if err != nil {
+			return fmt.Errorf("List: %w", err)
+		}
+		if len(filtered) != len(c.want) {
+			return fmt.Errorf("List of the %s users returned %d users, want %d", c.typeUser, len(filtered), len(c.want))
+		}
+		for i, u := range filtered {
+			if !u.ID().Equals(c.want[i].ID()) {
+				return fmt.Errorf("List of the %s users returned %s at %d, want %s", c.typeUser, u.ID(), i, c.want[i].ID())
+			}
+		}
+	}
+
+	got := users[first]
+	if got.Wallet() == nil || got.Wallet().Money().Amount().Value() != 0 || !got.CreatedAt().Equal(want[0].CreatedAt()) {
+		return fmt.Errorf("List returned %s without its wallet or its creation time", got.ID())
+	}
+
+	return nil
+}
+
+func testUpdateWallet(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.USD, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	user.Wallet().Add(vo.NewAmountTest(250))
+	user.Wallet().Hold(vo.NewAmountTest(120))
+	if err := r.UserUpdater.UpdateWallet(ctx, user.ID(), user.Wallet()); err != nil {
+		return fmt.Errorf("UpdateWallet: %w", err)
+	}
+
+	got, err := r.UserFinder.FindByID(ctx, user.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if held := got.Wallet().Held().Value(); held != 120 {
+		return fmt.Errorf("held = %d, want 120", held)
+	}
+	if available := got.Wallet().Available().Amount().Value(); available != 230 {
+		return fmt.Errorf("available = %d, want 230", available)
+	}
+
+	return expectWallet(ctx, r, user.ID(), vo.USD, 350)
+}
+
+func testUpdateUnknownWallet(ctx context.Context, r Repositories) error {
+	err := r.UserUpdater.UpdateWallet(ctx, newID(), vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(1))))
+	if !errors.Is(err, entity.ErrNotFoundUser) {
+		return fmt.Errorf("UpdateWallet error = %v, want %v", err, entity.ErrNotFoundUser)
+	}
+
+	return nil
+}
+
+// testUpdateStaleWallet updates twice from the same read, the second update must be refused
+func testUpdateStaleWallet(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.USD, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	read, err := r.UserFinder.FindByID(ctx, user.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	read.Wallet().Add(vo.NewAmountTest(10))
+	if err := r.UserUpdater.UpdateWallet(ctx, user.ID(), read.Wallet()); err != nil {
+		return fmt.Errorf("UpdateWallet: %w", err)
+	}
+
+	read.Wallet().Add(vo.NewAmountTest(10))
+	err = r.UserUpdater.UpdateWallet(ctx, user.ID(), read.Wallet())
+	if !errors.Is(err, entity.ErrConcurrentModification) {
+		return fmt.Errorf("stale UpdateWallet error = %v, want %v", err, entity.ErrConcurrentModification)
+	}
+
+	return expectWallet(ctx, r, user.ID(), vo.USD, 110)
+}
+
+func testCreateWallet(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.USD, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	brl, _ := vo.NewCurrency(vo.BRL.String())
+	usd := user.Wallet().Money().Currency()
+	added := []*vo.Wallet{
+		vo.NewWalletWithPurpose(vo.NewMoney(brl, vo.NewAmountTest(0)), vo.MainWallet),
+		vo.NewWalletWithPurpose(vo.NewMoney(usd, vo.NewAmountTest(0)), "savings"),
+	}
+	for _, w := range added {
+		if err := r.Wallets.CreateWallet(ctx, user.ID(), w); err != nil {
+			return fmt.Errorf("CreateWallet %s %s: %w", w.Money().Currency(), w.Purpose(), err)
+		}
+	}
+
+	err = r.Wallets.CreateWallet(ctx, user.ID(), vo.NewWalletWithPurpose(vo.NewMoney(usd, vo.NewAmountTest(0)), "savings"))
+	if !errors.Is(err, entity.ErrWalletAlreadyExists) {
+		return fmt.Errorf("duplicate CreateWallet error = %v, want %v", err, entity.ErrWalletAlreadyExists)
+	}
+
+	err = r.Wallets.CreateWallet(ctx, newID(), vo.NewWallet(vo.NewMoney(usd, vo.NewAmountTest(0))))
+	if !errors.Is(err, entity.ErrNotFoundUser) {
+		return fmt.Errorf("CreateWallet of unknown user error = %v, want %v", err, entity.ErrNotFoundUser)
+	}
+
+	got, err := r.UserFinder.FindByID(ctx, user.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	want := append([]*vo.Wallet{user.Wallet()}, added...)
+	if len(got.Wallets()) != len(want) {
+		return fmt.Errorf("%d wallets, want %d", len(got.Wallets()), len(want))
+	}
+	for i, w := range got.Wallets() {
+		if !w.Equals(want[i]) {
+			return fmt.Errorf("wallet %d = %s %s %d, want %s %s %d", i,
+				w.Money().Currency(), w.Purpose(), w.Money().Amount().Value(),
+				want[i].Money().Currency(), want[i].Purpose(), want[i].Money().Amount().Value())
+		}
+	}
+
+	return nil
+}
+
+// testUpdateWalletOfPurpose updates two wallets of the user from the same read, each wallet has its own version
+func testUpdateWalletOfPurpose(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.USD, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	usd := user.Wallet().Money().Currency()
+	if err := r.Wallets.CreateWallet(ctx, user.ID(), vo.NewWalletWithPurpose(vo.NewMoney(usd, vo.NewAmountTest(0)), "savings")); err != nil {
+		return fmt.Errorf("CreateWallet: %w", err)
+	}
+
+	read, err := r.UserFinder.FindByID(ctx, user.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	if err := read.Withdraw(vo.NewMoney(usd, vo.NewAmountTest(30)), vo.MainWallet); err != nil {
+		return err
+	}
+	if err := read.Deposit(vo.NewMoney(usd, vo.NewAmountTest(30)), "savings"); err != nil {
+		return err
+	}
+
+	for _, purpose := range []vo.WalletPurpose{"savings", vo.MainWallet} {
+		wallet, err := read.FindWallet(usd, purpose)
+		if err != nil {
+			return err
+		}
+
+		if err := r.UserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+			return fmt.Errorf("UpdateWallet %s: %w", purpose, err)
+		}
+	}
+
+	got, err := r.UserFinder.FindByID(ctx, user.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	savings, err := got.FindWallet(usd, "savings")
+	if err != nil {
+		return fmt.Errorf("FindWallet: %w", err)
+	}
+	if amount := savings.Money().Amount().Value(); amount != 30 {
+		return fmt.Errorf("savings = %d, want 30", amount)
+	}
+
+	return expectWallet(ctx, r, user.ID(), vo.USD, 70)
+}
+
+func testUpdateMissingWallet(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.USD, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	wallet := vo.NewWalletWithPurpose(vo.NewMoney(user.Wallet().Money().Currency(), vo.NewAmountTest(1)), "savings")
+	err = r.UserUpdater.UpdateWallet(ctx, user.ID(), wallet)
+	if !errors.Is(err, entity.ErrNotFoundWallet) {
+		return fmt.Errorf("UpdateWallet error = %v, want %v", err, entity.ErrNotFoundWallet)
+	}
+
+	return nil
+}
+
+func testCommitTransaction(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	err = r.TransferCreator.WithTransaction(ctx, func(ctx context.Context) error {
+		return transfer(ctx, r, payer.ID(), payee.ID(), 40)
+	})
+	if err != nil {
+		return fmt.Errorf("WithTransaction: %w", err)
+	}
+
+	if err := expectWallet(ctx, r, payer.ID(), vo.BRL, 60); err != nil {
+		return err
+	}
+
+	return expectWallet(ctx, r, payee.ID(), vo.BRL, 40)
+}
+
+func testRollbackTransaction(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	err = r.TransferCreator.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := transfer(ctx, r, payer.ID(), payee.ID(), 40); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		return fmt.Errorf("WithTransaction error = %v, want %v", err, errRollback)
+	}
+
+	if err := expectWallet(ctx, r, payer.ID(), vo.BRL, 100); err != nil {
+		return err
+	}
+
+	return expectWallet(ctx, r, payee.ID(), vo.BRL, 0)
+}
+
+// testConcurrentTransactions detects lost updates: every transaction moves one
+// unit between the same two wallets, so any lost update breaks the final balances
+func testConcurrentTransactions(ctx context.Context, r Repositories) error {
+	const workers = 20
+
+	payer, err := createUser(ctx, r, vo.BRL, workers, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := r.TransferCreator.WithTransaction(ctx, func(ctx context.Context) error {
+				return transfer(ctx, r, payer.ID(), payee.ID(), 1)
+			})
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	committed := int64(workers - len(errs))
+	if err := expectWallet(ctx, r, payer.ID(), vo.BRL, workers-committed); err != nil {
+		return err
+	}
+
+	return expectWallet(ctx, r, payee.ID(), vo.BRL, committed)
+}
+
+func testDuplicateTransfer(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	t := entity.NewTransfer(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(1)), now())
+	if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+		return fmt.Errorf("Create transfer: %w", err)
+	}
+
+	_, err = r.TransferCreator.Create(ctx, t)
+	if !errors.Is(err, entity.ErrTransferAlreadyExists) {
+		return fmt.Errorf("Create duplicate transfer error = %v, want %v", err, entity.ErrTransferAlreadyExists)
+	}
+
+	return nil
+}
+
+func testSplitTransfer(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	var legs []entity.TransferLeg
+	for _, value := range []int64{60, 40} {
+		payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+		if err != nil {
+			return err
+		}
+		legs = append(legs, entity.NewTransferLeg(payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(value))))
+	}
+
+	t, err := entity.NewSplitTransfer(newID(), payer.ID(), legs, now())
+	if err != nil {
+		return err
+	}
+
+	err = r.TransferCreator.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := r.TransferCreator.Create(ctx, t)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Create split transfer: %w", err)
+	}
+
+	_, err = r.TransferCreator.Create(ctx, t)
+	if !errors.Is(err, entity.ErrTransferAlreadyExists) {
+		return fmt.Errorf("Create duplicate error = %v, want %v", err, entity.ErrTransferAlreadyExists)
+	}
+
+	return nil
+}
+
+func testSumTransfersByPayer(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	brl, _ := vo.NewCurrency(vo.BRL.String())
+	usd, _ := vo.NewCurrency(vo.USD.String())
+
+	from := now()
+	for i, value := range []int64{10, 20, 40} {
+		at := from.Add(time.Duration(i) * time.Hour)
+		t := entity.NewTransfer(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(value)), at)
+		if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+
+	// a transfer in another currency is counted apart
+	t := entity.NewTransfer(newID(), payer.ID(), payee.ID(), vo.NewMoney(usd, vo.NewAmountTest(1000)), from.Add(time.Minute))
+	if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	// the withdrawals are counted with the transfers, the failed ones and the deposits are not
+	withdrawal := entity.NewFunding(newID(), payer.ID(), vo.Withdrawal, vo.BankTransferMethod, vo.NewMoneyBRL(vo.NewAmountTest(5)), vo.MainWallet, from.Add(2*time.Minute))
+	failed := entity.NewFunding(newID(), payer.ID(), vo.Withdrawal, vo.BankTransferMethod, vo.NewMoneyBRL(vo.NewAmountTest(7)), vo.MainWallet, from.Add(3*time.Minute))
+	if err := failed.Fail("refused", from.Add(4*time.Minute)); err != nil {
+		return err
+	}
+	deposit := entity.NewFunding(newID(), payer.ID(), vo.Deposit, vo.BankTransferMethod, vo.NewMoneyBRL(vo.NewAmountTest(9)), vo.MainWallet, from.Add(5*time.Minute))
+	for _, funding := range []entity.Funding{withdrawal, failed, deposit} {
+		if _, err := r.Fundings.Create(ctx, funding); err != nil {
+			return fmt.Errorf("Create funding: %w", err)
+		}
+	}
+
+	// the payments held are counted with the transfers, the voided ones are not
+	held := entity.NewPayment(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(3)), from.Add(6*time.Minute), from.Add(time.Hour))
+	voided := entity.NewPayment(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(4)), from.Add(7*time.Minute), from.Add(time.Hour))
+	if err := voided.Void(from.Add(8 * time.Minute)); err != nil {
+		return err
+	}
+	for _, payment := range []entity.Payment{held, voided} {
+		if _, err := r.Payments.Create(ctx, payment); err != nil {
+			return fmt.Errorf("Create payment: %w", err)
+		}
+	}
+
+	usage, err := r.TransferFinder.SumByPayer(ctx, payer.ID(), brl, from, from.Add(2*time.Hour))
+	if err != nil {
+		return fmt.Errorf("SumByPayer: %w", err)
+	}
+	if usage.Count() != 4 || usage.Value().Value() != 38 {
+		return fmt.Errorf("SumByPayer = %d transfers of %d, want 4 transfers of 38", usage.Count(), usage.Value().Value())
+	}
+
+	usage, err = r.TransferFinder.SumByPayer(ctx, payer.ID(), usd, from, from.Add(2*time.Hour))
+	if err != nil {
+		return fmt.Errorf("SumByPayer: %w", err)
+	}
+	if usage.Count() != 1 || usage.Value().Value() != 1000 {
+		return fmt.Errorf("SumByPayer in USD = %d transfers of %d, want 1 transfer of 1000", usage.Count(), usage.Value().Value())
+	}
+
+	usage, err = r.TransferFinder.SumByPayer(ctx, payee.ID(), brl, from, from.Add(3*time.Hour))
+	if err != nil {
+		return fmt.Errorf("SumByPayer: %w", err)
+	}
+	if usage.Count() != 0 || usage.Value().Value() != 0 {
+		return fmt.Errorf("SumByPayer of the payee = %d transfers of %d, want none", usage.Count(), usage.Value().Value())
+	}
+
+	return nil
+}
+
+func testFindPayees(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	var payees []entity.User
+	for i := 0; i < 3; i++ {
+		payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+		if err != nil {
+			return err
+		}
+		payees = append(payees, payee)
+	}
+
+	from := now()
+	transfers := []entity.Transfer{
+		entity.NewTransfer(newID(), payer.ID(), payees[0].ID(), vo.NewMoneyBRL(vo.NewAmountTest(10)), from),
+		entity.NewTransfer(newID(), payer.ID(), payees[0].ID(), vo.NewMoneyBRL(vo.NewAmountTest(10)), from.Add(time.Minute)),
+		entity.NewTransfer(newID(), payer.ID(), payees[2].ID(), vo.NewMoneyBRL(vo.NewAmountTest(10)), from.Add(2*time.Hour)),
+	}
+	split, err := entity.NewSplitTransfer(newID(), payer.ID(), []entity.TransferLeg{
+		entity.NewTransferLeg(payees[0].ID(), vo.NewMoneyBRL(vo.NewAmountTest(5))),
+		entity.NewTransferLeg(payees[1].ID(), vo.NewMoneyBRL(vo.NewAmountTest(5))),
+	}, from.Add(time.Hour))
+	if err != nil {
+		return err
+	}
+	transfers = append(transfers, split)
+
+	for _, t := range transfers {
+		if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+
+	got, err := r.TransferFinder.FindPayees(ctx, payer.ID(), from, from.Add(2*time.Hour))
+	if err != nil {
+		return fmt.Errorf("FindPayees: %w", err)
+	}
+
+	want := map[string]bool{payees[0].ID().Value(): true, payees[1].ID().Value(): true}
+	if len(got) != len(want) {
+		return fmt.Errorf("FindPayees returned %d payees, want %d", len(got), len(want))
+	}
+	for _, p := range got {
+		if !want[p.Value()] {
+			return fmt.Errorf("FindPayees returned %s, want %s and %s", p, payees[0].ID(), payees[1].ID())
+		}
+	}
+
+	return nil
+}
+
+func testFindTransferByID(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	var legs []entity.TransferLeg
+	for _, value := range []int64{60, 40} {
+		payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+		if err != nil {
+			return err
+		}
+		legs = append(legs, entity.NewTransferLeg(payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(value))))
+	}
+
+	split, err := entity.NewSplitTransfer(newID(), payer.ID(), legs, now())
+	if err != nil {
+		return err
+	}
+	split = split.
+		WithStatus(vo.TransferHeld).
+		WithFee(0, entity.RestoreFee(vo.NewMoneyBRL(vo.NewAmountTest(3)), "merchant", 2, newID()))
+
+	// a transfer recorded before the fee account was has none
+	simple := entity.NewTransfer(newID(), payer.ID(), legs[0].Payee(), vo.NewMoneyBRL(vo.NewAmountTest(25)), now()).
+		WithType(vo.ScheduledTransfer).
+		WithWallets("savings", vo.MainWallet).
+		WithFee(0, entity.RestoreFee(vo.NewMoneyBRL(vo.NewAmountTest(1)), "common", 1, vo.Uuid{}))
+
+	for _, want := range []entity.Transfer{split, simple} {
+		if _, err := r.TransferCreator.Create(ctx, want); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+
+		got, err := r.TransferFinder.FindByID(ctx, want.ID())
+		if err != nil {
+			return fmt.Errorf("FindByID: %w", err)
+		}
+
+		if err := compareTransfers(got, want); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testFindUnknownTransfer(ctx context.Context, r Repositories) error {
+	_, err := r.TransferFinder.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundTransfer) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundTransfer)
+	}
+
+	return nil
+}
+
+func testUpdateTransferStatus(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	from := now()
+	var transfers []entity.Transfer
+	for i, value := range []int64{10, 20} {
+		t := entity.NewTransfer(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(value)), from.Add(time.Duration(i)*time.Minute)).
+			WithStatus(vo.TransferHeld)
+		if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err := r.TransferUpdater.UpdateStatus(ctx, transfers[0].ID(), vo.TransferRejected); err != nil {
+		return fmt.Errorf("UpdateStatus: %w", err)
+	}
+
+	got, err := r.TransferFinder.FindByID(ctx, transfers[0].ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if got.Status() != vo.TransferRejected {
+		return fmt.Errorf("status = %s, want %s", got.Status(), vo.TransferRejected)
+	}
+
+	usage, err := r.TransferFinder.SumByPayer(ctx, payer.ID(), transfers[0].Value().Currency(), from, from.Add(time.Hour))
+	if err != nil {
+		return fmt.Errorf("SumByPayer: %w", err)
+	}
+	if usage.Count() != 1 || usage.Value().Value() != 20 {
+		return fmt.Errorf("SumByPayer = %d transfers of %d, want the held one of 20 only", usage.Count(), usage.Value().Value())
+	}
+
+	payees, err := r.TransferFinder.FindPayees(ctx, payer.ID(), from, from.Add(time.Minute))
+	if err != nil {
+		return fmt.Errorf("FindPayees: %w", err)
+	}
+	if len(payees) != 0 {
+		return fmt.Errorf("FindPayees returned %d payees, want none of the rejected transfer", len(payees))
+	}
+
+	err = r.TransferUpdater.UpdateStatus(ctx, newID(), vo.TransferCompleted)
+	if !errors.Is(err, entity.ErrNotFoundTransfer) {
+		return fmt.Errorf("UpdateStatus unknown error = %v, want %v", err, entity.ErrNotFoundTransfer)
+	}
+
+	return nil
+}
+
+func testListTransfers(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	var payees []entity.User
+	for i := 0; i < 2; i++ {
+		payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+		if err != nil {
+			return err
+		}
+		payees = append(payees, payee)
+	}
+
+	from := now()
+	var transfers []entity.Transfer
+	for i, value := range []int64{10, 20, 40} {
+		at := from.Add(time.Duration(i) * time.Hour)
+		transfers = append(transfers,
+			entity.NewTransfer(newID(), payer.ID(), payees[0].ID(), vo.NewMoneyBRL(vo.NewAmountTest(value)), at))
+	}
+
+	// the second payee is only a leg of a split transfer of another payer
+	split, err := entity.NewSplitTransfer(newID(), payees[0].ID(), []entity.TransferLeg{
+		entity.NewTransferLeg(payer.ID(), vo.NewMoneyBRL(vo.NewAmountTest(5))),
+		entity.NewTransferLeg(payees[1].ID(), vo.NewMoneyBRL(vo.NewAmountTest(5))),
+	}, from.Add(3*time.Hour))
+	if err != nil {
+		return err
+	}
+	transfers = append(transfers, split)
+
+	for _, t := range transfers {
+		if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+
+	cases := []struct {
+		filter entity.TransferFilter
+		want   []entity.Transfer
+	}{
+		{entity.TransferFilter{UserID: payer.ID()}, transfers},
+		{entity.TransferFilter{UserID: payer.ID(), From: from.Add(time.Hour), To: from.Add(2 * time.Hour)}, transfers[1:2]},
+		{entity.TransferFilter{UserID: payer.ID(), Offset: 1, Limit: 2}, transfers[1:3]},
+		{entity.TransferFilter{UserID: payees[1].ID()}, transfers[3:]},
+	}
+	for _, c := range cases {
+		got, err := r.TransferLister.List(ctx, c.filter)
+		if err != nil {
+			return fmt.Errorf("List: %w", err)
+		}
+
+		if len(got) != len(c.want) {
+			return fmt.Errorf("List(%+v) returned %d transfers, want %d", c.filter, len(got), len(c.want))
+		}
+		for i := range got {
+			if err := compareTransfers(got[i], c.want[i]); err != nil {
+				return fmt.Errorf("List(%+v) transfer %d: %w", c.filter, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testCreateAndFindSchedule(ctx context.Context, r Repositories) error {
+	recurrence, err := vo.NewRecurrence(vo.MONTHLY, 31, now().AddDate(1, 0, 0), 12)
+	if err != nil {
+		return err
+	}
+
+	want, err := createSchedule(ctx, r, recurrence, now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Schedules.FindByID(ctx, want.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	if err := compareSchedules(got, want); err != nil {
+		return err
+	}
+
+	list, err := r.Schedules.FindByPayer(ctx, want.Payer())
+	if err != nil {
+		return fmt.Errorf("FindByPayer: %w", err)
+	}
+	if len(list) != 1 {
+		return fmt.Errorf("FindByPayer returned %d schedules, want 1", len(list))
+	}
+
+	return compareSchedules(list[0], want)
+}
+
+func testFindUnknownSchedule(ctx context.Context, r Repositories) error {
+	_, err := r.Schedules.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundSchedule) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundSchedule)
+	}
+
+	return nil
+}
+
+// testFindDueSchedules uses dates in the far past so that the schedules of the
+// other checks, which all start in the future, are never due
+func testFindDueSchedules(ctx context.Context, r Repositories) error {
+	var (
+		base    = time.Date(2001, 1, 1, 12, 0, 0, 0, time.UTC)
+		created = base.Add(-time.Hour)
+	)
+
+	first, err := createScheduleAt(ctx, r, vo.NewRecurrenceOnce(), base.Add(time.Minute), created)
+	if err != nil {
+		return err
+	}
+
+	second, err := createScheduleAt(ctx, r, vo.NewRecurrenceOnce(), base.Add(2*time.Minute), created)
+	if err != nil {
+		return err
+	}
+
+	if _, err := createScheduleAt(ctx, r, vo.NewRecurrenceOnce(), base.Add(time.Hour), created); err != nil {
+		return err
+	}
+
+	paused, err := createScheduleAt(ctx, r, vo.NewRecurrenceOnce(), base, created)
+	if err != nil {
+		return err
+	}
+	if err := paused.Pause(base); err != nil {
+		return err
+	}
+	if err := r.Schedules.Update(ctx, paused); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	due, err := r.Schedules.FindDue(ctx, base.Add(30*time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("FindDue: %w", err)
+	}
+	if len(due) != 2 || !due[0].ID().Equals(first.ID()) || !due[1].ID().Equals(second.ID()) {
+		return fmt.Errorf("FindDue returned %s, want [%s %s]", scheduleIDs(due), first.ID(), second.ID())
+	}
+
+	due, err = r.Schedules.FindDue(ctx, base.Add(30*time.Minute), 1)
+	if err != nil {
+		return fmt.Errorf("FindDue: %w", err)
+	}
+	if len(due) != 1 || !due[0].ID().Equals(first.ID()) {
+		return fmt.Errorf("FindDue with limit returned %s, want [%s]", scheduleIDs(due), first.ID())
+	}
+
+	// leaves no due schedule behind for the backends shared with other checks
+	for _, s := range []entity.Schedule{first, second} {
+		s.Advance(nil, base)
+		if err := r.Schedules.Update(ctx, s); err != nil {
+			return fmt.Errorf("Update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func testUpdateSchedule(ctx context.Context, r Repositories) error {
+	recurrence, err := vo.NewRecurrence(vo.DAILY, 0, time.Time{}, 0)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := createSchedule(ctx, r, recurrence, now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	schedule.Advance(entity.ErrUserInsufficientBalance, now())
+	if err := r.Schedules.Update(ctx, schedule); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	got, err := r.Schedules.FindByID(ctx, schedule.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	if err := compareSchedules(got, schedule); err != nil {
+		return err
+	}
+
+	unknown, err := entity.NewSchedule(newID(), newID(), newID(), vo.NewMoneyBRL(vo.NewAmountTest(1)), recurrence, now(), now())
+	if err != nil {
+		return err
+	}
+
+	err = r.Schedules.Update(ctx, unknown)
+	if !errors.Is(err, entity.ErrNotFoundSchedule) {
+		return fmt.Errorf("Update unknown error = %v, want %v", err, entity.ErrNotFoundSchedule)
+	}
+
+	return nil
+}
+
+func testCreateAndFindBatch(ctx context.Context, r Repositories) error {
+	want, err := createBatch(ctx, r, vo.BatchAtomic, now())
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Batches.FindByID(ctx, want.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	return compareBatches(got, want)
+}
+
+func testFindUnknownBatch(ctx context.Context, r Repositories) error {
+	_, err := r.Batches.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundBatch) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundBatch)
+	}
+
+	return nil
+}
+
+// testFindUnfinishedBatches creates the batches in the far past so that they are
+// the oldest ones, and finishes them afterwards so that a later run ignores them
+func testFindUnfinishedBatches(ctx context.Context, r Repositories) error {
+	base := time.Date(2001, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := createBatch(ctx, r, vo.BatchBestEffort, base)
+	if err != nil {
+		return err
+	}
+
+	second, err := createBatch(ctx, r, vo.BatchAtomic, base.Add(time.Minute))
+	if err != nil {
+		return err
+	}
+	if err := second.Start(base.Add(2 * time.Minute)); err != nil {
+		return err
+	}
+	if err := r.Batches.Update(ctx, second); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	unfinished, err := r.Batches.FindUnfinished(ctx, 2)
+	if err != nil {
+		return fmt.Errorf("FindUnfinished: %w", err)
+	}
+
+	got, want := batchIDs(unfinished), batchIDs([]entity.Batch{first, second})
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		return fmt.Errorf("FindUnfinished = %v, want %v", got, want)
+	}
+
+	for _, b := range []entity.Batch{first, second} {
+		for i := range b.Items() {
+			b.Fail(i, errRollback, now())
+		}
+		b.Finish(now())
+		if err := r.Batches.Update(ctx, b); err != nil {
+			return fmt.Errorf("Update: %w", err)
+		}
+	}
+
+	unfinished, err = r.Batches.FindUnfinished(ctx, 2)
+	if err != nil {
+		return fmt.Errorf("FindUnfinished: %w", err)
+	}
+	for _, b := range unfinished {
+		if b.ID().Equals(first.ID()) || b.ID().Equals(second.ID()) {
+			return fmt.Errorf("FindUnfinished returned the finished batch %s", b.ID())
+		}
+	}
+
+	return nil
+}
+
+func testUpdateBatch(ctx context.Context, r Repositories) error {
+	batch, err := createBatch(ctx, r, vo.BatchBestEffort, now())
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Start(now()); err != nil {
+		return err
+	}
+	batch.Succeed(0, now())
+	if err := r.Batches.UpdateItem(ctx, batch, 0); err != nil {
+		return fmt.Errorf("UpdateItem: %w", err)
+	}
+
+	got, err := r.Batches.FindByID(ctx, batch.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if err := compareBatches(got, batch); err != nil {
+		return err
+	}
+
+	batch.Fail(1, entity.ErrUserInsufficientBalance, now())
+	batch.Finish(now())
+	if err := r.Batches.Update(ctx, batch); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	got, err = r.Batches.FindByID(ctx, batch.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if err := compareBatches(got, batch); err != nil {
+		return err
+	}
+
+	unknown, err := entity.NewBatch(newID(), newID(), vo.BatchAtomic, batch.Items(), now())
+	if err != nil {
+		return err
+	}
+
+	err = r.Batches.Update(ctx, unknown)
+	if !errors.Is(err, entity.ErrNotFoundBatch) {
+		return fmt.Errorf("Update unknown error = %v, want %v", err, entity.ErrNotFoundBatch)
+	}
+
+	return nil
+}
+
+func testCreateAndFindRiskAssessment(ctx context.Context, r Repositories) error {
+	payer, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	transferID := newID()
+	hits := []entity.RiskHit{
+		entity.NewRiskHit(vo.NewPayeeSignal, 20, "1 new payees"),
+		entity.NewRiskHit(vo.ManyPayeesSignal, 50, "6 payees in 1h0m0s"),
+	}
+
+	first := entity.RestoreRiskAssessment(newID(), transferID, payer.ID(), 20, vo.RiskApprove, hits[:1], now().Add(-time.Minute))
+	want := entity.RestoreRiskAssessment(newID(), transferID, payer.ID(), 70, vo.RiskDeny, hits, now())
+
+	for _, a := range []entity.RiskAssessment{first, want} {
+		if _, err := r.Risks.Create(ctx, a); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+
+	got, err := r.Risks.FindByTransferID(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("FindByTransferID: %w", err)
+	}
+
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want the latest %s", got.ID(), want.ID())
+	case !got.Payer().Equals(want.Payer()):
+		return fmt.Errorf("payer = %s, want %s", got.Payer(), want.Payer())
+	case got.Score() != want.Score() || got.Decision() != want.Decision():
+		return fmt.Errorf("score = %d %s, want %d %s", got.Score(), got.Decision(), want.Score(), want.Decision())
+	case !got.CreatedAt().Equal(want.CreatedAt()):
+		return fmt.Errorf("created at = %s, want %s", got.CreatedAt(), want.CreatedAt())
+	case len(got.Hits()) != len(hits):
+		return fmt.Errorf("%d hits, want %d", len(got.Hits()), len(hits))
+	}
+
+	for i, hit := range got.Hits() {
+		if hit != hits[i] {
+			return fmt.Errorf("hit %d = %s %d %q, want %s %d %q",
+				i, hit.Signal(), hit.Score(), hit.Detail(), hits[i].Signal(), hits[i].Score(), hits[i].Detail())
+		}
+	}
+
+	return nil
+}
+
+func testFindUnknownRiskAssessment(ctx context.Context, r Repositories) error {
+	_, err := r.Risks.FindByTransferID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundRiskAssessment) {
+		return fmt.Errorf("FindByTransferID error = %v, want %v", err, entity.ErrNotFoundRiskAssessment)
+	}
+
+	return nil
+}
+
+func testCreateAndFindReview(ctx context.Context, r Repositories) error {
+	want, err := createReview(ctx, r, now(), now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Reviews.FindByID(ctx, want.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	return compareReviews(got, want)
+}
+
+func testFindUnknownReview(ctx context.Context, r Repositories) error {
+	_, err := r.Reviews.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundReview) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundReview)
+	}
+
+	return nil
+}
+
+func testFindReviews(ctx context.Context, r Repositories) error {
+	base := time.Date(2002, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := createReview(ctx, r, base, base.Add(time.Minute))
+	if err != nil {
+		return err
+	}
+
+	second, err := createReview(ctx, r, base.Add(time.Second), base.Add(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	if _, err := createReview(ctx, r, base.Add(2*time.Second), base.Add(time.Hour)); err != nil {
+		return err
+	}
+
+	decided, err := createReview(ctx, r, base.Add(-time.Second), base)
+	if err != nil {
+		return err
+	}
+	if err := decided.Decide(vo.ReviewApproved, "alice", "", base); err != nil {
+		return err
+	}
+	if err := r.Reviews.Update(ctx, decided); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	expired, err := r.Reviews.FindExpired(ctx, base.Add(30*time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("FindExpired: %w", err)
+	}
+	if len(expired) != 2 || !expired[0].ID().Equals(first.ID()) || !expired[1].ID().Equals(second.ID()) {
+		return fmt.Errorf("FindExpired returned %s, want [%s %s]", reviewIDs(expired), first.ID(), second.ID())
+	}
+
+	pending, err := r.Reviews.FindByStatus(ctx, vo.ReviewPending, 1)
+	if err != nil {
+		return fmt.Errorf("FindByStatus: %w", err)
+	}
+	if len(pending) != 1 || !pending[0].ID().Equals(first.ID()) {
+		return fmt.Errorf("FindByStatus with limit returned %s, want [%s]", reviewIDs(pending), first.ID())
+	}
+
+	approved, err := r.Reviews.FindByStatus(ctx, vo.ReviewApproved, 10)
+	if err != nil {
+		return fmt.Errorf("FindByStatus: %w", err)
+	}
+	found := false
+	for _, review := range approved {
+		found = found || review.ID().Equals(decided.ID())
+	}
+	if !found {
+		return fmt.Errorf("FindByStatus returned %s, want %s among them", reviewIDs(approved), decided.ID())
+	}
+
+	// leaves no expired review behind for the backends shared with other checks
+	for _, review := range []entity.Review{first, second} {
+		if err := review.Decide(vo.ReviewRejected, "alice", "", base); err != nil {
+			return err
+		}
+		if err := r.Reviews.Update(ctx, review); err != nil {
+			return fmt.Errorf("Update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func testUpdateReview(ctx context.Context, r Repositories) error {
+	review, err := createReview(ctx, r, now(), now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	stale := review
+	if err := review.Decide(vo.ReviewRejected, "alice", "unknown payee", now()); err != nil {
+		return err
+	}
+	if err := r.Reviews.Update(ctx, review); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	got, err := r.Reviews.FindByID(ctx, review.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if err := compareReviews(got, review); err != nil {
+		return err
+	}
+
+	if err := stale.Decide(vo.ReviewApproved, "bob", "", now()); err != nil {
+		return err
+	}
+	err = r.Reviews.Update(ctx, stale)
+	if !errors.Is(err, entity.ErrConcurrentModification) {
+		return fmt.Errorf("Update decided error = %v, want %v", err, entity.ErrConcurrentModification)
+	}
+
+	unknown := entity.NewReview(newID(), entity.NewTransfer(newID(), newID(), newID(), vo.NewMoneyBRL(vo.NewAmountTest(1)), now()), "", now(), now())
+	err = r.Reviews.Update(ctx, unknown)
+	if !errors.Is(err, entity.ErrNotFoundReview) {
+		return fmt.Errorf("Update unknown error = %v, want %v", err, entity.ErrNotFoundReview)
+	}
+
+	return nil
+}
+
+func testCreateAuditEntry(ctx context.Context, r Repositories) error {
+	entry := entity.NewAuditEntry(newID(), vo.ReviewApprovedAction, "alice", newID(), "known payee", now()).
+		WithTarget(vo.ReviewTarget).
+		WithSnapshots(`{"status":"PENDING"}`, `{"status":"APPROVED"}`).
+		WithOrigin(entity.AuditOrigin{CorrelationID: uuid.New().String(), IP: "10.0.0.1", UserAgent: "curl/8.0"})
+	created, err := r.Audit.Create(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if created.Sequence() != 0 || created.Hash() != "" {
+		return fmt.Errorf("Create sequence = %d, hash = %q, want the entry pending", created.Sequence(), created.Hash())
+	}
+
+	pending, err := listAuditEntry(ctx, r, entry.SubjectID())
+	if err != nil {
+		return err
+	}
+	if pending.Sequence() != 0 {
+		return fmt.Errorf("pending entry sequence = %d, want none", pending.Sequence())
+	}
+
+	if _, err := r.Audit.ChainPending(ctx, 0); err != nil {
+		return fmt.Errorf("ChainPending: %w", err)
+	}
+
+	got, err := listAuditEntry(ctx, r, entry.SubjectID())
+	if err != nil {
+		return err
+	}
+	if got.Sequence() == 0 || got.Hash() == "" {
+		return fmt.Errorf("chained entry sequence = %d, hash = %q, want it chained", got.Sequence(), got.Hash())
+	}
+
+	switch {
+	case got.ID() != entry.ID():
+		return fmt.Errorf("ID = %s, want %s", got.ID().Value(), entry.ID().Value())
+	case got.Action() != vo.ReviewApprovedAction || got.Target() != vo.ReviewTarget:
+		return fmt.Errorf("action, target = %s, %s, want %s, %s", got.Action(), got.Target(), vo.ReviewApprovedAction, vo.ReviewTarget)
+	case got.Actor() != "alice" || got.Detail() != "known payee":
+		return fmt.Errorf("actor, detail = %q, %q, want %q, %q", got.Actor(), got.Detail(), "alice", "known payee")
+	case got.Before() != entry.Before() || got.After() != entry.After():
+		return fmt.Errorf("snapshots = %q, %q, want %q, %q", got.Before(), got.After(), entry.Before(), entry.After())
+	case got.Origin() != entry.Origin():
+		return fmt.Errorf("origin = %+v, want %+v", got.Origin(), entry.Origin())
+	case !got.CreatedAt().Equal(entry.CreatedAt()):
+		return fmt.Errorf("CreatedAt = %v, want %v", got.CreatedAt(), entry.CreatedAt())
+	}
+
+	return nil
+}
+
+func testChainAuditEntries(ctx context.Context, r Repositories) error {
+	at := now()
+	appended := []entity.AuditEntry{
+		entity.NewAuditEntry(newID(), vo.UserCreatedAction, "admin", newID(), "", at),
+		entity.NewAuditEntry(newID(), vo.WalletCreditedAction, "admin", newID(), "", at.Add(time.Millisecond)),
+	}
+	for _, entry := range appended {
+		if _, err := r.Audit.Create(ctx, entry); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+
+	// an entry appended in a rolled back transaction is never chained
+	rolledBack := entity.NewAuditEntry(newID(), vo.WalletDebitedAction, "admin", newID(), "", at)
+	errRollback := errors.New("rollback")
+	err := r.TransferCreator.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.Audit.Create(ctx, rolledBack); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		return fmt.Errorf("WithTransaction error = %v, want %v", err, errRollback)
+	}
+
+	if _, err := r.Audit.ChainPending(ctx, 0); err != nil {
+		return fmt.Errorf("ChainPending: %w", err)
+	}
+
+	first, err := listAuditEntry(ctx, r, appended[0].SubjectID())
+	if err != nil {
+		return err
+	}
+	second, err := listAuditEntry(ctx, r, appended[1].SubjectID())
+	if err != nil {
+		return err
+	}
+	if err := second.VerifyChain(first); err != nil {
+		return fmt.Errorf("VerifyChain of the stored entry: %w", err)
+	}
+
+	entries, err := r.Audit.List(ctx, entity.AuditFilter{FromSequence: first.Sequence(), Limit: 2})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+	if len(entries) != 2 || entries[0].ID() != first.ID() || entries[1].ID() != second.ID() {
+		return fmt.Errorf("List from sequence %d got %d entries, want the first and the second", first.Sequence(), len(entries))
+	}
+
+	entries, err = r.Audit.List(ctx, entity.AuditFilter{SubjectID: rolledBack.SubjectID()})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("List got %d entries appended in the rolled back transaction, want none", len(entries))
+	}
+
+	tampered := entity.NewAuditEntry(second.ID(), second.Action(), "mallory", second.SubjectID(), second.Detail(), second.CreatedAt()).
+		WithChain(second.Sequence(), second.PrevHash(), second.Hash())
+	if err := tampered.VerifyChain(first); !errors.Is(err, entity.ErrAuditChainBroken) {
+		return fmt.Errorf("VerifyChain of a changed entry error = %v, want %v", err, entity.ErrAuditChainBroken)
+	}
+
+	return nil
+}
+
+func testConcurrentAuditEntries(ctx context.Context, r Repositories) error {
+	const appends = 4
+	actor := "actor-" + uuid.New().String()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, appends)
+	for i := 0; i < appends; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := entity.NewAuditEntry(newID(), vo.TransferCreatedAction, actor, newID(), "", now())
+			if _, err := r.Audit.Create(ctx, entry); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return fmt.Errorf("concurrent Create: %w", err)
+	}
+
+	// concurrent chainers leave the entries to the first one instead of forking the chain
+	chainErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Audit.ChainPending(ctx, 1); err != nil && !errors.Is(err, entity.ErrConcurrentModification) {
+				chainErrs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(chainErrs)
+
+	if err := <-chainErrs; err != nil {
+		return fmt.Errorf("concurrent ChainPending: %w", err)
+	}
+	if _, err := r.Audit.ChainPending(ctx, 0); err != nil {
+		return fmt.Errorf("ChainPending: %w", err)
+	}
+
+	entries, err := r.Audit.List(ctx, entity.AuditFilter{Actor: actor})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+	if len(entries) != appends {
+		return fmt.Errorf("List got %d entries, want %d", len(entries), appends)
+	}
+
+	for i := 1; i < len(entries); i++ {
+		if entries[i-1].Sequence() == 0 {
+			return fmt.Errorf("entry %d not chained", i-1)
+		}
+		if entries[i].Sequence() <= entries[i-1].Sequence() {
+			return fmt.Errorf("sequences %d then %d, want them increasing", entries[i-1].Sequence(), entries[i].Sequence())
+		}
+	}
+
+	return nil
+}
+
+// listAuditEntry returns the single audit entry of the subject
+func listAuditEntry(ctx context.Context, r Repositories, subjectID vo.Uuid) (entity.AuditEntry, error) {
+	entries, err := r.Audit.List(ctx, entity.AuditFilter{SubjectID: subjectID})
+	if err != nil {
+		return entity.AuditEntry{}, fmt.Errorf("List: %w", err)
+	}
+	if len(entries) != 1 {
+		return entity.AuditEntry{}, fmt.Errorf("List got %d entries, want 1", len(entries))
+	}
+
+	return entries[0], nil
+}
+
+func testListAuditEntries(ctx context.Context, r Repositories) error {
+	actor := "actor-" + uuid.New().String()
+	correlationID := uuid.New().String()
+	start := now().Add(-time.Hour)
+
+	var created []entity.AuditEntry
+	for i, action := range []vo.AuditAction{vo.UserCreatedAction, vo.TransferCreatedAction, vo.TransferRefundedAction} {
+		target := vo.TransferTarget
+		if action == vo.UserCreatedAction {
+			target = vo.UserTarget
+		}
+
+		entry := entity.NewAuditEntry(newID(), action, actor, newID(), "", start.Add(time.Duration(i)*time.Minute)).
+			WithTarget(target).
+			WithOrigin(entity.AuditOrigin{CorrelationID: correlationID})
+		entry, err := r.Audit.Create(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+		created = append(created, entry)
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.AuditFilter
+		want   []entity.AuditEntry
+	}{
+		{"actor", entity.AuditFilter{Actor: actor}, created},
+		{"action", entity.AuditFilter{Actor: actor, Action: vo.TransferRefundedAction}, created[2:]},
+		{"target", entity.AuditFilter{Actor: actor, Target: vo.TransferTarget}, created[1:]},
+		{"subject", entity.AuditFilter{SubjectID: created[1].SubjectID()}, created[1:2]},
+		{"correlation id", entity.AuditFilter{CorrelationID: correlationID}, created},
+		{"period", entity.AuditFilter{Actor: actor, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}, created[1:2]},
+		{"page", entity.AuditFilter{Actor: actor, Offset: 1, Limit: 1}, created[1:2]},
+	}
+
+	for _, tt := range tests {
+		entries, err := r.Audit.List(ctx, tt.filter)
+		if err != nil {
+			return fmt.Errorf("List by %s: %w", tt.name, err)
+		}
+		if len(entries) != len(tt.want) {
+			return fmt.Errorf("List by %s got %d entries, want %d", tt.name, len(entries), len(tt.want))
+		}
+		for i, entry := range entries {
+			if entry.ID() != tt.want[i].ID() {
+				return fmt.Errorf("List by %s entry %d = %s, want %s", tt.name, i, entry.ID().Value(), tt.want[i].ID().Value())
+			}
+		}
+	}
+
+	return nil
+}
+
+// transfer moves value between the wallets and records the transfer, as the use case does
+func transfer(ctx context.Context, r Repositories, payerID, payeeID vo.Uuid, value int64) error {
+	payer, err := r.UserFinder.FindByID(ctx, payerID)
+	if err != nil {
+		return err
+	}
+
+	payee, err := r.UserFinder.FindByID(ctx, payeeID)
+	if err != nil {
+		return err
+	}
+
+	money := vo.NewMoney(payer.Wallet().Money().Currency(), vo.NewAmountTest(value))
+	if err := payer.Withdraw(money, vo.MainWallet); err != nil {
+		return err
+	}
+	if err := payee.Deposit(money, vo.MainWallet); err != nil {
+		return err
+	}
+
+	if err := r.UserUpdater.UpdateWallet(ctx, payerID, payer.Wallet()); err != nil {
+		return err
+	}
+
+	if err := r.UserUpdater.UpdateWallet(ctx, payeeID, payee.Wallet()); err != nil {
+		return err
+	}
+
+	_, err = r.TransferCreator.Create(ctx, entity.NewTransfer(newID(), payerID, payeeID, money, now()))
+
+	return err
+}
+
+func compareTransfers(got, want entity.Transfer) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.Payer().Equals(want.Payer()):
+		return fmt.Errorf("payer = %s, want %s", got.Payer(), want.Payer())
+	case !got.Value().Equals(want.Value()) || !got.Fee().Equals(want.Fee()):
+		return fmt.Errorf("value, fee = %d, %d, want %d, %d",
+			got.Value().Amount().Value(), got.Fee().Amount().Value(), want.Value().Amount().Value(), want.Fee().Amount().Value())
+	case got.Type() != want.Type() || got.Status() != want.Status():
+		return fmt.Errorf("type, status = %s, %s, want %s, %s", got.Type(), got.Status(), want.Type(), want.Status())
+	case got.Source() != want.Source() || got.Destination() != want.Destination():
+		return fmt.Errorf("wallets = %s, %s, want %s, %s", got.Source(), got.Destination(), want.Source(), want.Destination())
+	case !got.CreatedAt().Equal(want.CreatedAt()):
+		return fmt.Errorf("created at = %s, want %s", got.CreatedAt(), want.CreatedAt())
+	case len(got.Legs()) != len(want.Legs()):
+		return fmt.Errorf("%d legs, want %d", len(got.Legs()), len(want.Legs()))
+	}
+
+	for i, leg := range got.Legs() {
+		w := want.Legs()[i]
+		if !leg.Payee().Equals(w.Payee()) || !leg.Value().Equals(w.Value()) || leg.Fee() != w.Fee() {
+			return fmt.Errorf("leg %d = %s %d %+v, want %s %d %+v",
+				i, leg.Payee(), leg.Value().Amount().Value(), leg.Fee(), w.Payee(), w.Value().Amount().Value(), w.Fee())
+		}
+	}
+
+	return nil
+}
+
+// createReview creates a pending review of a new held transfer of 10
+func testCreateAndFindPayment(ctx context.Context, r Repositories) error {
+	payment, err := createPayment(ctx, r, now(), now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Payments.FindByID(ctx, payment.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	return comparePayments(got, payment)
+}
+
+func testFindUnknownPayment(ctx context.Context, r Repositories) error {
+	_, err := r.Payments.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundPayment) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundPayment)
+	}
+
+	return nil
+}
+
+func testFindExpiredPayments(ctx context.Context, r Repositories) error {
+	base := time.Date(2003, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := createPayment(ctx, r, base, base.Add(time.Minute))
+	if err != nil {
+		return err
+	}
+
+	second, err := createPayment(ctx, r, base.Add(time.Second), base.Add(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	if _, err := createPayment(ctx, r, base.Add(2*time.Second), base.Add(time.Hour)); err != nil {
+		return err
+	}
+
+	voided, err := createPayment(ctx, r, base.Add(-time.Second), base)
+	if err != nil {
+		return err
+	}
+	if err := voided.Void(base); err != nil {
+		return err
+	}
+	if err := r.Payments.Update(ctx, voided); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	expired, err := r.Payments.FindExpired(ctx, base.Add(30*time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("FindExpired: %w", err)
+	}
+	if len(expired) != 2 || !expired[0].ID().Equals(first.ID()) || !expired[1].ID().Equals(second.ID()) {
+		return fmt.Errorf("FindExpired returned %s, want [%s %s]", paymentIDs(expired), first.ID(), second.ID())
+	}
+
+	limited, err := r.Payments.FindExpired(ctx, base.Add(30*time.Minute), 1)
+	if err != nil {
+		return fmt.Errorf("FindExpired: %w", err)
+	}
+	if len(limited) != 1 || !limited[0].ID().Equals(first.ID()) {
+		return fmt.Errorf("FindExpired with limit returned %s, want [%s]", paymentIDs(limited), first.ID())
+	}
+
+	// leaves no expired payment behind for the backends shared with other checks
+	for _, payment := range []entity.Payment{first, second} {
+		if err := payment.Expire(base.Add(30 * time.Minute)); err != nil {
+			return err
+		}
+		if err := r.Payments.Update(ctx, payment); err != nil {
+			return fmt.Errorf("Update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func testUpdatePayment(ctx context.Context, r Repositories) error {
+	payment, err := createPayment(ctx, r, now(), now().Add(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	// the transfer of the capture is stored first, as the capture does
+	t := entity.NewTransfer(newID(), payment.Payer(), payment.Payee(), vo.NewMoneyBRL(vo.NewAmountTest(40)), now()).
+		WithType(vo.PaymentTransfer)
+	if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+		return fmt.Errorf("Create transfer: %w", err)
+	}
+
+	stale := payment
+	if err := payment.Capture(vo.NewAmountTest(40), t.ID(), now()); err != nil {
+		return err
+	}
+	if err := r.Payments.Update(ctx, payment); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	got, err := r.Payments.FindByID(ctx, payment.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if err := comparePayments(got, payment); err != nil {
+		return err
+	}
+
+	if err := stale.Void(now()); err != nil {
+		return err
+	}
+	err = r.Payments.Update(ctx, stale)
+	if !errors.Is(err, entity.ErrConcurrentModification) {
+		return fmt.Errorf("Update captured error = %v, want %v", err, entity.ErrConcurrentModification)
+	}
+
+	unknown := entity.NewPayment(newID(), newID(), newID(), vo.NewMoneyBRL(vo.NewAmountTest(1)), now(), now())
+	err = r.Payments.Update(ctx, unknown)
+	if !errors.Is(err, entity.ErrNotFoundPayment) {
+		return fmt.Errorf("Update unknown error = %v, want %v", err, entity.ErrNotFoundPayment)
+	}
+
+	return nil
+}
+
+// createPayment creates a payment of 50 from a new user to a new merchant
+func createPayment(ctx context.Context, r Repositories, createdAt, expiresAt time.Time) (entity.Payment, error) {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.MERCHANT)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payment := entity.NewPayment(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(50)), createdAt, expiresAt)
+	if _, err := r.Payments.Create(ctx, payment); err != nil {
+		return entity.Payment{}, fmt.Errorf("Create: %w", err)
+	}
+
+	return payment, nil
+}
+
+func comparePayments(got, want entity.Payment) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.Payer().Equals(want.Payer()) || !got.Payee().Equals(want.Payee()):
+		return fmt.Errorf("payer, payee = %s, %s, want %s, %s", got.Payer(), got.Payee(), want.Payer(), want.Payee())
+	case !got.Value().Equals(want.Value()) || got.Captured() != want.Captured():
+		return fmt.Errorf("value, captured = %d, %d, want %d, %d",
+			got.Value().Amount().Value(), got.Captured().Value(), want.Value().Amount().Value(), want.Captured().Value())
+	case got.Status() != want.Status():
+		return fmt.Errorf("status = %s, want %s", got.Status(), want.Status())
+	case got.TransferID() != want.TransferID():
+		return fmt.Errorf("transfer id = %q, want %q", got.TransferID(), want.TransferID())
+	case !got.CreatedAt().Equal(want.CreatedAt()) || !got.ExpiresAt().Equal(want.ExpiresAt()) || !got.UpdatedAt().Equal(want.UpdatedAt()):
+		return fmt.Errorf("created, expires, updated at = %s, %s, %s, want %s, %s, %s",
+			got.CreatedAt(), got.ExpiresAt(), got.UpdatedAt(), want.CreatedAt(), want.ExpiresAt(), want.UpdatedAt())
+	}
+
+	return nil
+}
+
+func paymentIDs(payments []entity.Payment) []string {
+	ids := make([]string, 0, len(payments))
+	for _, p := range payments {
+		ids = append(ids, p.ID().Value())
+	}
+
+	return ids
+}
+
+func testCreateAndFindFunding(ctx context.Context, r Repositories) error {
+	funding, err := createFunding(ctx, r, vo.Deposit)
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Fundings.FindByID(ctx, funding.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	return compareFundings(got, funding)
+}
+
+func testFindUnknownFunding(ctx context.Context, r Repositories) error {
+	_, err := r.Fundings.FindByID(ctx, newID())
+	if !errors.Is(err, entity.ErrNotFoundFunding) {
+		return fmt.Errorf("FindByID error = %v, want %v", err, entity.ErrNotFoundFunding)
+	}
+
+	return nil
+}
+
+func testUpdateFunding(ctx context.Context, r Repositories) error {
+	funding, err := createFunding(ctx, r, vo.Withdrawal)
+	if err != nil {
+		return err
+	}
+
+	// a pending funding may be updated, e.g. with the reference of the provider
+	funding = funding.WithReference("provider-" + funding.ID().Value()[:8])
+	if err := r.Fundings.Update(ctx, funding); err != nil {
+		return fmt.Errorf("Update pending: %w", err)
+	}
+
+	stale := funding
+	if err := funding.Fail("account closed", now()); err != nil {
+		return err
+	}
+	if err := r.Fundings.Update(ctx, funding); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	got, err := r.Fundings.FindByID(ctx, funding.ID())
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+	if err := compareFundings(got, funding); err != nil {
+		return err
+	}
+
+	if err := stale.Settle(now()); err != nil {
+		return err
+	}
+	err = r.Fundings.Update(ctx, stale)
+	if !errors.Is(err, entity.ErrConcurrentModification) {
+		return fmt.Errorf("Update failed error = %v, want %v", err, entity.ErrConcurrentModification)
+	}
+
+	unknown := entity.NewFunding(newID(), newID(), vo.Deposit, vo.CardMethod, vo.NewMoneyBRL(vo.NewAmountTest(1)), vo.MainWallet, now())
+	err = r.Fundings.Update(ctx, unknown)
+	if !errors.Is(err, entity.ErrNotFoundFunding) {
+		return fmt.Errorf("Update unknown error = %v, want %v", err, entity.ErrNotFoundFunding)
+	}
+
+	return nil
+}
+
+func testListFundings(ctx context.Context, r Repositories) error {
+	user, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return err
+	}
+
+	start := now().Add(-time.Hour)
+	var created []entity.Funding
+	for i, fundingType := range []vo.FundingType{vo.Deposit, vo.Withdrawal, vo.Deposit} {
+		funding := entity.NewFunding(
+			newID(),
+			user.ID(),
+			fundingType,
+			vo.BankTransferMethod,
+			vo.NewMoneyBRL(vo.NewAmountTest(int64(10*(i+1)))),
+			vo.MainWallet,
+			start.Add(time.Duration(i)*time.Minute),
+		)
+		if _, err := r.Fundings.Create(ctx, funding); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+		created = append(created, funding)
+	}
+
+	// the first deposit is settled after the others were created
+	if err := created[0].Settle(start.Add(time.Hour)); err != nil {
+		return err
+	}
+	if err := r.Fundings.Update(ctx, created[0]); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.FundingFilter
+		want   []entity.Funding
+	}{
+		{"user", entity.FundingFilter{UserID: user.ID()}, created},
+		{"period", entity.FundingFilter{UserID: user.ID(), From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}, created[1:2]},
+		{"update", entity.FundingFilter{UserID: user.ID(), UpdatedFrom: start.Add(2 * time.Minute)}, []entity.Funding{created[0], created[2]}},
+		{"page", entity.FundingFilter{UserID: user.ID(), Offset: 1, Limit: 1}, created[1:2]},
+		{"unknown user", entity.FundingFilter{UserID: newID()}, nil},
+	}
+
+	for _, tt := range tests {
+		fundings, err := r.Fundings.List(ctx, tt.filter)
+		if err != nil {
+			return fmt.Errorf("List by %s: %w", tt.name, err)
+		}
+		if len(fundings) != len(tt.want) {
+			return fmt.Errorf("List by %s got %d fundings, want %d", tt.name, len(fundings), len(tt.want))
+		}
+		for i, funding := range fundings {
+			if err := compareFundings(funding, tt.want[i]); err != nil {
+				return fmt.Errorf("List by %s: %w", tt.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testWalletBaselines(ctx context.Context, r Repositories) error {
+	brl, err := vo.NewCurrency(vo.BRL.String())
+	if err != nil {
+		return err
+	}
+	usd, err := vo.NewCurrency(vo.USD.String())
+	if err != nil {
+		return err
+	}
+
+	user := newID()
+	at := now().Truncate(time.Second)
+
+	saved := []entity.WalletBaseline{
+		entity.NewWalletBaseline(user, brl, vo.MainWallet, 100, at.Add(-time.Hour)),
+		entity.NewWalletBaseline(user, brl, vo.MainWallet, 150, at),
+		entity.NewWalletBaseline(user, usd, "savings", -20, at.Add(-2*time.Hour)),
+	}
+	if err := r.Reconciliation.SaveBaselines(ctx, saved); err != nil {
+		return fmt.Errorf("SaveBaselines: %w", err)
+	}
+
+	// the baseline of the same wallet and time is replaced
+	replaced := entity.NewWalletBaseline(user, brl, vo.MainWallet, 160, at)
+	if err := r.Reconciliation.SaveBaselines(ctx, []entity.WalletBaseline{replaced}); err != nil {
+		return fmt.Errorf("SaveBaselines: %w", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []entity.WalletBaseline
+	}{
+		{"latest", at.Add(time.Hour), []entity.WalletBaseline{replaced, saved[2]}},
+		{"at the time", at, []entity.WalletBaseline{replaced, saved[2]}},
+		{"before the latest", at.Add(-time.Minute), []entity.WalletBaseline{saved[0], saved[2]}},
+		{"before every baseline", at.Add(-3 * time.Hour), nil},
+	}
+
+	for _, tt := range tests {
+		baselines, err := r.Reconciliation.Baselines(ctx, tt.at)
+		if err != nil {
+			return fmt.Errorf("Baselines %s: %w", tt.name, err)
+		}
+
+		// the database may hold the baselines of other users
+		var got []entity.WalletBaseline
+		for _, b := range baselines {
+			if b.User().Equals(user) {
+				got = append(got, b)
+			}
+		}
+
+		if len(got) != len(tt.want) {
+			return fmt.Errorf("Baselines %s got %d baselines, want %d", tt.name, len(got), len(tt.want))
+		}
+		for i, b := range got {
+			want := tt.want[i]
+			if b.Currency() != want.Currency() || b.Purpose() != want.Purpose() || b.Amount() != want.Amount() || !b.At().Equal(want.At()) {
+				return fmt.Errorf("Baselines %s = %s %s %d at %s, want %s %s %d at %s", tt.name,
+					b.Currency(), b.Purpose(), b.Amount(), b.At(),
+					want.Currency(), want.Purpose(), want.Amount(), want.At())
+			}
+		}
+	}
+
+	return nil
+}
+
+func testReconciliationCheckpoints(ctx context.Context, r Repositories) error {
+	brl, err := vo.NewCurrency(vo.BRL.String())
+	if err != nil {
+		return err
+	}
+
+	user := newID()
+	at := now().Add(time.Hour)
+
+	baseline := entity.NewWalletBaseline(user, brl, vo.MainWallet, 70, at)
+	if err := r.Reconciliation.SaveCheckpoint(ctx, at, []entity.WalletBaseline{baseline}); err != nil {
+		return fmt.Errorf("SaveCheckpoint: %w", err)
+	}
+	if err := r.Reconciliation.SaveCheckpoint(ctx, at.Add(time.Hour), nil); err != nil {
+		return fmt.Errorf("SaveCheckpoint: %w", err)
+	}
+	// saving a checkpoint again keeps it
+	if err := r.Reconciliation.SaveCheckpoint(ctx, at, nil); err != nil {
+		return fmt.Errorf("SaveCheckpoint again: %w", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"latest", at.Add(2 * time.Hour), at.Add(time.Hour)},
+		{"at the time", at, at},
+		{"between", at.Add(time.Minute), at},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Reconciliation.Checkpoint(ctx, tt.at)
+		if err != nil {
+			return fmt.Errorf("Checkpoint %s: %w", tt.name, err)
+		}
+		if !got.Equal(tt.want) {
+			return fmt.Errorf("Checkpoint %s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// the database may hold the checkpoints of other runs, older than these
+	got, err := r.Reconciliation.Checkpoint(ctx, at.Add(-time.Millisecond))
+	if err != nil {
+		return fmt.Errorf("Checkpoint before: %w", err)
+	}
+	if !got.Before(at) {
+		return fmt.Errorf("Checkpoint before = %s, want before %s", got, at)
+	}
+
+	baselines, err := r.Reconciliation.Baselines(ctx, at)
+	if err != nil {
+		return fmt.Errorf("Baselines: %w", err)
+	}
+	for _, b := range baselines {
+		if b.User().Equals(user) {
+			if b.Amount() != baseline.Amount() || !b.At().Equal(at) {
+				return fmt.Errorf("baseline = %d at %s, want %d at %s", b.Amount(), b.At(), baseline.Amount(), at)
+			}
+			return nil
+		}
+	}
+
+	return errors.New("the baseline saved with the checkpoint is missing")
+}
+
+func testReconciliationLock(ctx context.Context, r Repositories) error {
+	var (
+		first  = newID().Value()
+		second = newID().Value()
+		at     = now()
+	)
+
+	if err := r.Reconciliation.Lock(ctx, first, at, at.Add(time.Minute)); err != nil {
+		return fmt.Errorf("Lock: %w", err)
+	}
+	// the owner extends the lock it holds
+	if err := r.Reconciliation.Lock(ctx, first, at, at.Add(2*time.Minute)); err != nil {
+		return fmt.Errorf("Lock again: %w", err)
+	}
+	if err := r.Reconciliation.Lock(ctx, second, at.Add(time.Minute), at.Add(time.Hour)); !errors.Is(err, entity.ErrReconciliationLocked) {
+		return fmt.Errorf("Lock held by another owner error = %v, want %v", err, entity.ErrReconciliationLocked)
+	}
+
+	// releasing a lock held by another owner keeps it
+	if err := r.Reconciliation.Unlock(ctx, second); err != nil {
+		return fmt.Errorf("Unlock: %w", err)
+	}
+	if err := r.Reconciliation.Lock(ctx, second, at, at.Add(time.Hour)); !errors.Is(err, entity.ErrReconciliationLocked) {
+		return fmt.Errorf("Lock after another owner unlocked error = %v, want %v", err, entity.ErrReconciliationLocked)
+	}
+
+	if err := r.Reconciliation.Unlock(ctx, first); err != nil {
+		return fmt.Errorf("Unlock: %w", err)
+	}
+	if err := r.Reconciliation.Lock(ctx, second, at, at.Add(time.Minute)); err != nil {
+		return fmt.Errorf("Lock after unlock: %w", err)
+	}
+
+	// an expired lock is taken over
+	if err := r.Reconciliation.Lock(ctx, first, at.Add(time.Minute), at.Add(time.Hour)); err != nil {
+		return fmt.Errorf("Lock expired: %w", err)
+	}
+
+	if err := r.Reconciliation.Unlock(ctx, first); err != nil {
+		return fmt.Errorf("Unlock: %w", err)
+	}
+
+	return nil
+}
+
+// createFunding creates a pending funding of 30 on the main wallet of a new user
+func createFunding(ctx context.Context, r Repositories, fundingType vo.FundingType) (entity.Funding, error) {
+	user, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	funding := entity.NewFunding(
+		newID(),
+		user.ID(),
+		fundingType,
+		vo.BankTransferMethod,
+		vo.NewMoneyBRL(vo.NewAmountTest(30)),
+		vo.MainWallet,
+		now(),
+	)
+	if _, err := r.Fundings.Create(ctx, funding); err != nil {
+		return entity.Funding{}, fmt.Errorf("Create: %w", err)
+	}
+
+	return funding, nil
+}
+
+func compareFundings(got, want entity.Funding) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.User().Equals(want.User()):
+		return fmt.Errorf("user = %s, want %s", got.User(), want.User())
+	case got.Type() != want.Type() || got.Method() != want.Method():
+		return fmt.Errorf("type, method = %s, %s, want %s, %s", got.Type(), got.Method(), want.Type(), want.Method())
+	case !got.Value().Equals(want.Value()) || got.Wallet() != want.Wallet():
+		return fmt.Errorf("value, wallet = %d %s, want %d %s",
+			got.Value().Amount().Value(), got.Wallet(), want.Value().Amount().Value(), want.Wallet())
+	case got.Status() != want.Status() || got.Reference() != want.Reference() || got.Reason() != want.Reason():
+		return fmt.Errorf("status, reference, reason = %s, %q, %q, want %s, %q, %q",
+			got.Status(), got.Reference(), got.Reason(), want.Status(), want.Reference(), want.Reason())
+	case !got.CreatedAt().Equal(want.CreatedAt()) || !got.UpdatedAt().Equal(want.UpdatedAt()):
+		return fmt.Errorf("created, updated at = %s, %s, want %s, %s", got.CreatedAt(), got.UpdatedAt(), want.CreatedAt(), want.UpdatedAt())
+	}
+
+	return nil
+}
+
+func createReview(ctx context.Context, r Repositories, createdAt, expiresAt time.Time) (entity.Review, error) {
+	payer, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	t := entity.NewTransfer(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(10)), createdAt).
+		WithStatus(vo.TransferHeld)
+	if _, err := r.TransferCreator.Create(ctx, t); err != nil {
+		return entity.Review{}, fmt.Errorf("Create transfer: %w", err)
+	}
+
+	review := entity.NewReview(newID(), t, "risk score 70", createdAt, expiresAt)
+	if _, err := r.Reviews.Create(ctx, review); err != nil {
+		return entity.Review{}, fmt.Errorf("Create: %w", err)
+	}
+
+	return review, nil
+}
+
+func compareReviews(got, want entity.Review) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.TransferID().Equals(want.TransferID()) || !got.Payer().Equals(want.Payer()):
+		return fmt.Errorf("transfer, payer = %s, %s, want %s, %s", got.TransferID(), got.Payer(), want.TransferID(), want.Payer())
+	case !got.Value().Equals(want.Value()):
+		return fmt.Errorf("value = %d, want %d", got.Value().Amount().Value(), want.Value().Amount().Value())
+	case got.Reason() != want.Reason():
+		return fmt.Errorf("reason = %q, want %q", got.Reason(), want.Reason())
+	case got.Status() != want.Status() || got.Reviewer() != want.Reviewer() || got.Note() != want.Note():
+		return fmt.Errorf("status, reviewer, note = %s, %q, %q, want %s, %q, %q",
+			got.Status(), got.Reviewer(), got.Note(), want.Status(), want.Reviewer(), want.Note())
+	case !got.CreatedAt().Equal(want.CreatedAt()) || !got.ExpiresAt().Equal(want.ExpiresAt()):
+		return fmt.Errorf("created, expires at = %s, %s, want %s, %s", got.CreatedAt(), got.ExpiresAt(), want.CreatedAt(), want.ExpiresAt())
+	case !got.DecidedAt().Equal(want.DecidedAt()):
+		return fmt.Errorf("decided at = %s, want %s", got.DecidedAt(), want.DecidedAt())
+	}
+
+	return nil
+}
+
+func reviewIDs(reviews []entity.Review) []string {
+	ids := make([]string, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r.ID().Value())
+	}
+
+	return ids
+}
+
+func createSchedule(ctx context.Context, r Repositories, recurrence vo.Recurrence, startAt time.Time) (entity.Schedule, error) {
+	return createScheduleAt(ctx, r, recurrence, startAt, now())
+}
+
+func createScheduleAt(ctx context.Context, r Repositories, recurrence vo.Recurrence, startAt, createdAt time.Time) (entity.Schedule, error) {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	s, err := entity.NewSchedule(newID(), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(25)), recurrence, startAt, createdAt)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	created, err := r.Schedules.Create(ctx, s)
+	if err != nil {
+		return entity.Schedule{}, fmt.Errorf("Create schedule: %w", err)
+	}
+
+	return created, nil
+}
+
+func compareSchedules(got, want entity.Schedule) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.Payer().Equals(want.Payer()) || !got.Payee().Equals(want.Payee()):
+		return fmt.Errorf("payer, payee = %s, %s, want %s, %s", got.Payer(), got.Payee(), want.Payer(), want.Payee())
+	case !got.Value().Equals(want.Value()):
+		return fmt.Errorf("value = %d, want %d", got.Value().Amount().Value(), want.Value().Amount().Value())
+	case got.Recurrence().Frequency() != want.Recurrence().Frequency(),
+		got.Recurrence().DayOfMonth() != want.Recurrence().DayOfMonth(),
+		got.Recurrence().Count() != want.Recurrence().Count(),
+		!got.Recurrence().EndAt().Equal(want.Recurrence().EndAt()):
+		return fmt.Errorf("recurrence = %+v, want %+v", got.Recurrence(), want.Recurrence())
+	case got.Status() != want.Status():
+		return fmt.Errorf("status = %s, want %s", got.Status(), want.Status())
+	case !got.NextRunAt().Equal(want.NextRunAt()):
+		return fmt.Errorf("next run at = %s, want %s", got.NextRunAt(), want.NextRunAt())
+	case got.Occurrences() != want.Occurrences():
+		return fmt.Errorf("occurrences = %d, want %d", got.Occurrences(), want.Occurrences())
+	case got.LastError() != want.LastError():
+		return fmt.Errorf("last error = %q, want %q", got.LastError(), want.LastError())
+	case !got.CreatedAt().Equal(want.CreatedAt()) || !got.UpdatedAt().Equal(want.UpdatedAt()):
+		return fmt.Errorf("created, updated at = %s, %s, want %s, %s", got.CreatedAt(), got.UpdatedAt(), want.CreatedAt(), want.UpdatedAt())
+	}
+
+	return nil
+}
+
+func scheduleIDs(schedules []entity.Schedule) []string {
+	ids := make([]string, 0, len(schedules))
+	for _, s := range schedules {
+		ids = append(ids, s.ID().Value())
+	}
+
+	return ids
+}
+
+// createBatch creates a batch paying 10 and 20 to two new users
+func createBatch(ctx context.Context, r Repositories, mode vo.BatchMode, createdAt time.Time) (entity.Batch, error) {
+	payer, err := createUser(ctx, r, vo.BRL, 100, vo.COMMON)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	var items []entity.BatchItem
+	for _, value := range []int64{10, 20} {
+		payee, err := createUser(ctx, r, vo.BRL, 0, vo.COMMON)
+		if err != nil {
+			return entity.Batch{}, err
+		}
+		items = append(items, entity.NewBatchItem(newID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(value))))
+	}
+
+	b, err := entity.NewBatch(newID(), payer.ID(), mode, items, createdAt)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	created, err := r.Batches.Create(ctx, b)
+	if err != nil {
+		return entity.Batch{}, fmt.Errorf("Create batch: %w", err)
+	}
+
+	return created, nil
+}
+
+func compareBatches(got, want entity.Batch) error {
+	switch {
+	case !got.ID().Equals(want.ID()):
+		return fmt.Errorf("id = %s, want %s", got.ID(), want.ID())
+	case !got.Payer().Equals(want.Payer()):
+		return fmt.Errorf("payer = %s, want %s", got.Payer(), want.Payer())
+	case got.Mode() != want.Mode():
+		return fmt.Errorf("mode = %s, want %s", got.Mode(), want.Mode())
+	case got.Status() != want.Status():
+		return fmt.Errorf("status = %s, want %s", got.Status(), want.Status())
+	case !got.CreatedAt().Equal(want.CreatedAt()) || !got.UpdatedAt().Equal(want.UpdatedAt()):
+		return fmt.Errorf("created, updated at = %s, %s, want %s, %s", got.CreatedAt(), got.UpdatedAt(), want.CreatedAt(), want.UpdatedAt())
+	case len(got.Items()) != len(want.Items()):
+		return fmt.Errorf("%d items, want %d", len(got.Items()), len(want.Items()))
+	}
+
+	for i, w := range want.Items() {
+		g := got.Items()[i]
+		switch {
+		case !g.TransferID().Equals(w.TransferID()):
+			return fmt.Errorf("item %d: transfer id = %s, want %s", i, g.TransferID(), w.TransferID())
+		case !g.Payee().Equals(w.Payee()):
+			return fmt.Errorf("item %d: payee = %s, want %s", i, g.Payee(), w.Payee())
+		case !g.Value().Equals(w.Value()):
+			return fmt.Errorf("item %d: value = %d, want %d", i, g.Value().Amount().Value(), w.Value().Amount().Value())
+		case g.Status() != w.Status() || g.Reason() != w.Reason():
+			return fmt.Errorf("item %d: status = %s %q, want %s %q", i, g.Status(), g.Reason(), w.Status(), w.Reason())
+		}
+	}
+
+	return nil
+}
+
+func batchIDs(batches []entity.Batch) []string {
+	ids := make([]string, 0, len(batches))
+	for _, b := range batches {
+		ids = append(ids, b.ID().Value())
+	}
+
+	return ids
+}
+
+func createUser(ctx context.Context, r Repositories, currency vo.TypeCurrency, amount int64, typeUser vo.TypeUser) (entity.User, error) {
+	return createUserAt(ctx, r, currency, amount, typeUser, now())
+}
+
+func createUserAt(
+	ctx context.Context,
+	r Repositories,
+	currency vo.TypeCurrency,
+	amount int64,
+	typeUser vo.TypeUser,
+	at time.Time,
+) (entity.User, error) {
+	return createUserWith(
+		ctx,
+		r,
+		vo.NewEmailTest(newID().Value()[:8]+"@conformance.test"),
+		vo.NewDocumentTest(vo.CPF, "070.910.549-45"),
+		currency,
+		amount,
+		typeUser,
+		at,
+	)
+}
+
+func createUserWith(
+	ctx context.Context,
+	r Repositories,
+	email vo.Email,
+	document vo.Document,
+	currency vo.TypeCurrency,
+	amount int64,
+	typeUser vo.TypeUser,
+	at time.Time,
+) (entity.User, error) {
+	c, err := vo.NewCurrency(currency.String())
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	id := newID()
+	u, err := entity.NewUser(
+		id,
+		vo.NewFullName("Conformance "+id.Value()[:8]),
+		email,
+		vo.NewPassword("secret"),
+		document,
+		vo.NewWallet(vo.NewMoney(c, vo.NewAmountTest(amount))),
+		typeUser,
+		at,
+	)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	created, err := r.UserCreator.Create(ctx, u)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("Create user: %w", err)
+	}
+
+	return created, nil
+}
+
+func expectWallet(ctx context.Context, r Repositories, ID vo.Uuid, currency vo.TypeCurrency, amount int64) error {
+	u, err := r.UserFinder.FindByID(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("FindByID: %w", err)
+	}
+
+	money := u.Wallet().Money()
+	if money.Currency().Value() != currency || money.Amount().Value() != amount {
+		return fmt.Errorf("wallet of %s = %s %d, want %s %d", ID, money.Currency(), money.Amount().Value(), currency, amount)
+	}
+
+	return nil
+}
+
+func newID() vo.Uuid {
+	id, _ := vo.NewUuid(uuid.New().String())
+	return id
+}
+
+// newCNPJ returns a random CNPJ, well formed though its check digits are not computed
+func newCNPJ() string {
+	var b strings.Builder
+	for _, c := range strings.ReplaceAll(uuid.New().String(), "-", "")[:14] {
+		b.WriteByte(byte('0' + c%10))
+	}
+
+	// the branch number is never 0000
+	digits := []byte(b.String())
+	digits[11] = '1'
+
+	return string(digits)
+}
+
+// now is truncated to the millisecond, the precision kept by every backend
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
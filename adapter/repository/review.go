@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	reviewBSON struct {
+		ID         string     `bson:"id"`
+		TransferID string     `bson:"transfer_id"`
+		PayerID    string     `bson:"payer_id"`
+		Currency   string     `bson:"currency"`
+		Value      int64      `bson:"value"`
+		Reason     string     `bson:"reason"`
+		Status     string     `bson:"status"`
+		Reviewer   string     `bson:"reviewer"`
+		Note       string     `bson:"note"`
+		CreatedAt  time.Time  `bson:"created_at"`
+		ExpiresAt  time.Time  `bson:"expires_at"`
+		DecidedAt  *time.Time `bson:"decided_at,omitempty"`
+	}
+
+	reviewRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewReviewRepository create new reviewRepository with its dependencies
+func NewReviewRepository(handler *database.MongoHandler) entity.ReviewRepository {
+	return reviewRepository{
+		handler:    handler,
+		collection: "reviews",
+	}
+}
+
+// Create perform insertOne into database
+func (r reviewRepository) Create(ctx context.Context, review entity.Review) (entity.Review, error) {
+	ctx, span := startSpan(ctx, "insertOne", r.collection)
+	defer span.End()
+
+	if _, err := r.handler.Db().Collection(r.collection).InsertOne(ctx, newReviewBSON(review)); err != nil {
+		recordError(span, err)
+		return entity.Review{}, errors.Wrap(err, entity.ErrCreateReview.Error())
+	}
+
+	return review, nil
+}
+
+// FindByID perform findOne into database
+func (r reviewRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Review, error) {
+	ctx, span := startSpan(ctx, "findOne", r.collection)
+	defer span.End()
+
+	var doc reviewBSON
+	err := r.handler.Db().Collection(r.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Review{}, entity.ErrNotFoundReview
+		default:
+			recordError(span, err)
+			return entity.Review{}, errors.Wrap(err, entity.ErrFindReview.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// FindByStatus perform find into database, oldest reviews first
+func (r reviewRepository) FindByStatus(ctx context.Context, status vo.ReviewStatus, limit int) ([]entity.Review, error) {
+	ctx, span := startSpan(ctx, "find", r.collection)
+	defer span.End()
+
+	reviews, err := r.find(
+		ctx,
+		bson.M{"status": status.String()},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// FindExpired perform find into database, oldest reviews first
+func (r reviewRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Review, error) {
+	ctx, span := startSpan(ctx, "find", r.collection)
+	defer span.End()
+
+	reviews, err := r.find(
+		ctx,
+		bson.M{
+			"status":     vo.ReviewPending.String(),
+			"expires_at": bson.M{"$lte": at.UTC()},
+		},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Update perform replaceOne into database when the stored review is still pending
+func (r reviewRepository) Update(ctx context.Context, review entity.Review) error {
+	ctx, span := startSpan(ctx, "replaceOne", r.collection)
+	defer span.End()
+
+	res, err := r.handler.Db().Collection(r.collection).ReplaceOne(
+		ctx,
+		bson.M{"id": review.ID().Value(), "status": vo.ReviewPending.String()},
+		newReviewBSON(review),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateReview.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := r.handler.Db().Collection(r.collection).CountDocuments(ctx, bson.M{"id": review.ID().Value()})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateReview.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundReview, entity.ErrUpdateReview.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateReview.Error())
+	}
+
+	return nil
+}
+
+func (r reviewRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entity.Review, error) {
+	cursor, err := r.handler.Db().Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindReview.Error())
+	}
+
+	var docs []reviewBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindReview.Error())
+	}
+
+	reviews := make([]entity.Review, 0, len(docs))
+	for _, doc := range docs {
+		review, err := doc.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+func newReviewBSON(r entity.Review) reviewBSON {
+	doc := reviewBSON{
+		ID:         r.ID().Value(),
+		TransferID: r.TransferID().Value(),
+		PayerID:    r.Payer().Value(),
+		Currency:   r.Value().Currency().String(),
+		Value:      r.Value().Amount().Value(),
+		Reason:     r.Reason(),
+		Status:     r.Status().String(),
+		Reviewer:   r.Reviewer(),
+		Note:       r.Note(),
+		CreatedAt:  r.CreatedAt().UTC(),
+		ExpiresAt:  r.ExpiresAt().UTC(),
+	}
+
+	if decidedAt := r.DecidedAt(); !decidedAt.IsZero() {
+		decidedAt = decidedAt.UTC()
+		doc.DecidedAt = &decidedAt
+	}
+
+	return doc
+}
+
+func (d reviewBSON) toEntity() (entity.Review, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	transferID, err := vo.NewUuid(d.TransferID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	amount, err := vo.NewAmount(d.Value)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	status, err := vo.NewReviewStatus(d.Status)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	var decidedAt time.Time
+	if d.DecidedAt != nil {
+		decidedAt = *d.DecidedAt
+	}
+
+	return entity.RestoreReview(
+		id,
+		transferID,
+		payer,
+		vo.NewMoney(currency, amount),
+		d.Reason,
+		status,
+		d.Reviewer,
+		d.Note,
+		d.CreatedAt,
+		d.ExpiresAt,
+		decidedAt,
+	), nil
+}
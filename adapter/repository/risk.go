@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	riskAssessmentBSON struct {
+		ID         string        `bson:"id"`
+		TransferID string        `bson:"transfer_id"`
+		PayerID    string        `bson:"payer_id"`
+		Score      int           `bson:"score"`
+		Decision   string        `bson:"decision"`
+		Hits       []riskHitBSON `bson:"hits"`
+		CreatedAt  time.Time     `bson:"created_at"`
+	}
+
+	// Bson data
+	riskHitBSON struct {
+		Signal string `bson:"signal"`
+		Score  int    `bson:"score"`
+		Detail string `bson:"detail"`
+	}
+
+	riskRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewRiskRepository create new riskRepository with its dependencies
+func NewRiskRepository(handler *database.MongoHandler) entity.RiskAssessmentRepository {
+	return riskRepository{
+		handler:    handler,
+		collection: "risk_assessments",
+	}
+}
+
+// Create perform insertOne into database
+func (r riskRepository) Create(ctx context.Context, assessment entity.RiskAssessment) (entity.RiskAssessment, error) {
+	ctx, span := startSpan(ctx, "insertOne", r.collection)
+	defer span.End()
+
+	if _, err := r.handler.Db().Collection(r.collection).InsertOne(ctx, newRiskAssessmentBSON(assessment)); err != nil {
+		recordError(span, err)
+		return entity.RiskAssessment{}, errors.Wrap(err, entity.ErrCreateRiskAssessment.Error())
+	}
+
+	return assessment, nil
+}
+
+// FindByTransferID perform findOne into database, the latest assessment of the transfer
+func (r riskRepository) FindByTransferID(ctx context.Context, transferID vo.Uuid) (entity.RiskAssessment, error) {
+	ctx, span := startSpan(ctx, "findOne", r.collection)
+	defer span.End()
+
+	var doc riskAssessmentBSON
+	err := r.handler.Db().Collection(r.collection).FindOne(
+		ctx,
+		bson.M{"transfer_id": transferID.Value()},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.RiskAssessment{}, entity.ErrNotFoundRiskAssessment
+		default:
+			recordError(span, err)
+			return entity.RiskAssessment{}, errors.Wrap(err, entity.ErrFindRiskAssessment.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+func newRiskAssessmentBSON(a entity.RiskAssessment) riskAssessmentBSON {
+	hits := a.Hits()
+	doc := riskAssessmentBSON{
+		ID:         a.ID().Value(),
+		TransferID: a.TransferID().Value(),
+		PayerID:    a.Payer().Value(),
+		Score:      a.Score(),
+		Decision:   a.Decision().String(),
+		Hits:       make([]riskHitBSON, 0, len(hits)),
+		CreatedAt:  a.CreatedAt().UTC(),
+	}
+
+	for _, hit := range hits {
+		doc.Hits = append(doc.Hits, riskHitBSON{
+			Signal: hit.Signal().String(),
+			Score:  hit.Score(),
+			Detail: hit.Detail(),
+		})
+	}
+
+	return doc
+}
+
+func (d riskAssessmentBSON) toEntity() (entity.RiskAssessment, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	transferID, err := vo.NewUuid(d.TransferID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	decision, err := vo.NewRiskDecision(d.Decision)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	hits := make([]entity.RiskHit, 0, len(d.Hits))
+	for _, h := range d.Hits {
+		signal, err := vo.NewRiskSignal(h.Signal)
+		if err != nil {
+			return entity.RiskAssessment{}, err
+		}
+		hits = append(hits, entity.NewRiskHit(signal, h.Score, h.Detail))
+	}
+
+	return entity.RestoreRiskAssessment(id, transferID, payer, d.Score, decision, hits, d.CreatedAt), nil
+}
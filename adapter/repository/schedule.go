@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// Bson data
+	scheduleBSON struct {
+		ID          string                 `bson:"id"`
+		PayerID     string                 `bson:"payer_id"`
+		PayeeID     string                 `bson:"payee_id"`
+		Currency    string                 `bson:"currency"`
+		Value       int64                  `bson:"value"`
+		Recurrence  scheduleRecurrenceBSON `bson:"recurrence"`
+		Status      string                 `bson:"status"`
+		NextRunAt   time.Time              `bson:"next_run_at"`
+		Occurrences int                    `bson:"occurrences"`
+		LastError   string                 `bson:"last_error"`
+		CreatedAt   time.Time              `bson:"created_at"`
+		UpdatedAt   time.Time              `bson:"updated_at"`
+	}
+
+	// Bson data
+	scheduleRecurrenceBSON struct {
+		Frequency  string     `bson:"frequency"`
+		DayOfMonth int        `bson:"day_of_month"`
+		EndAt      *time.Time `bson:"end_at,omitempty"`
+		Count      int        `bson:"count"`
+	}
+
+	scheduleRepository struct {
+		handler    *database.MongoHandler
+		collection string
+	}
+)
+
+// NewScheduleRepository create new scheduleRepository with its dependencies
+func NewScheduleRepository(handler *database.MongoHandler) entity.ScheduleRepository {
+	return scheduleRepository{
+		handler:    handler,
+		collection: "schedules",
+	}
+}
+
+// Create perform insertOne into database
+func (s scheduleRepository) Create(ctx context.Context, schedule entity.Schedule) (entity.Schedule, error) {
+	ctx, span := startSpan(ctx, "insertOne", s.collection)
+	defer span.End()
+
+	if _, err := s.handler.Db().Collection(s.collection).InsertOne(ctx, newScheduleBSON(schedule)); err != nil {
+		recordError(span, err)
+		return entity.Schedule{}, errors.Wrap(err, entity.ErrCreateSchedule.Error())
+	}
+
+	return schedule, nil
+}
+
+// FindByID perform findOne into database
+func (s scheduleRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Schedule, error) {
+	ctx, span := startSpan(ctx, "findOne", s.collection)
+	defer span.End()
+
+	var doc scheduleBSON
+	err := s.handler.Db().Collection(s.collection).FindOne(ctx, bson.M{"id": ID.Value()}).Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.Schedule{}, entity.ErrNotFoundSchedule
+		default:
+			recordError(span, err)
+			return entity.Schedule{}, errors.Wrap(err, entity.ErrFindSchedule.Error())
+		}
+	}
+
+	return doc.toEntity()
+}
+
+// FindByPayer perform find into database, oldest schedules first
+func (s scheduleRepository) FindByPayer(ctx context.Context, payerID vo.Uuid) ([]entity.Schedule, error) {
+	ctx, span := startSpan(ctx, "find", s.collection)
+	defer span.End()
+
+	schedules, err := s.find(
+		ctx,
+		bson.M{"payer_id": payerID.Value()},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// FindDue perform find into database, earliest runs first
+func (s scheduleRepository) FindDue(ctx context.Context, at time.Time, limit int) ([]entity.Schedule, error) {
+	ctx, span := startSpan(ctx, "find", s.collection)
+	defer span.End()
+
+	schedules, err := s.find(
+		ctx,
+		bson.M{
+			"status":      vo.ScheduleActive.String(),
+			"next_run_at": bson.M{"$lte": at.UTC()},
+		},
+		options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// Update perform replaceOne into database
+func (s scheduleRepository) Update(ctx context.Context, schedule entity.Schedule) error {
+	ctx, span := startSpan(ctx, "replaceOne", s.collection)
+	defer span.End()
+
+	res, err := s.handler.Db().Collection(s.collection).
+		ReplaceOne(ctx, bson.M{"id": schedule.ID().Value()}, newScheduleBSON(schedule))
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateSchedule.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.Wrap(entity.ErrNotFoundSchedule, entity.ErrUpdateSchedule.Error())
+	}
+
+	return nil
+}
+
+func (s scheduleRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entity.Schedule, error) {
+	cursor, err := s.handler.Db().Collection(s.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindSchedule.Error())
+	}
+
+	var docs []scheduleBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindSchedule.Error())
+	}
+
+	schedules := make([]entity.Schedule, 0, len(docs))
+	for _, doc := range docs {
+		schedule, err := doc.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+func newScheduleBSON(s entity.Schedule) scheduleBSON {
+	doc := scheduleBSON{
+		ID:       s.ID().Value(),
+		PayerID:  s.Payer().Value(),
+		PayeeID:  s.Payee().Value(),
+		Currency: s.Value().Currency().String(),
+		Value:    s.Value().Amount().Value(),
+		Recurrence: scheduleRecurrenceBSON{
+			Frequency:  s.Recurrence().Frequency().String(),
+			DayOfMonth: s.Recurrence().DayOfMonth(),
+			Count:      s.Recurrence().Count(),
+		},
+		Status:      s.Status().String(),
+		NextRunAt:   s.NextRunAt().UTC(),
+		Occurrences: s.Occurrences(),
+		LastError:   s.LastError(),
+		CreatedAt:   s.CreatedAt().UTC(),
+		UpdatedAt:   s.UpdatedAt().UTC(),
+	}
+
+	if endAt := s.Recurrence().EndAt(); !endAt.IsZero() {
+		endAt = endAt.UTC()
+		doc.Recurrence.EndAt = &endAt
+	}
+
+	return doc
+}
+
+func (d scheduleBSON) toEntity() (entity.Schedule, error) {
+	id, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	payer, err := vo.NewUuid(d.PayerID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	payee, err := vo.NewUuid(d.PayeeID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	amount, err := vo.NewAmount(d.Value)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	frequency, err := vo.NewFrequency(d.Recurrence.Frequency)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	var endAt time.Time
+	if d.Recurrence.EndAt != nil {
+		endAt = *d.Recurrence.EndAt
+	}
+
+	recurrence, err := vo.NewRecurrence(frequency, d.Recurrence.DayOfMonth, endAt, d.Recurrence.Count)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	status, err := vo.NewScheduleStatus(d.Status)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	return entity.RestoreSchedule(
+		id,
+		payer,
+		payee,
+		vo.NewMoney(currency, amount),
+		recurrence,
+		status,
+		d.NextRunAt,
+		d.Occurrences,
+		d.LastError,
+		d.CreatedAt,
+		d.UpdatedAt,
+	), nil
+}
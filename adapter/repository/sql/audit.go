@@ -0,0 +1,341 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const auditColumns = `id, action, actor, target, subject_id, detail, before_snapshot, after_snapshot,
+	correlation_id, ip, user_agent, created_at, sequence, prev_hash, hash`
+
+type (
+	// Row data
+	auditRow struct {
+		ID            string
+		Action        string
+		Actor         string
+		Target        string
+		SubjectID     string
+		Detail        string
+		Before        string
+		After         string
+		CorrelationID string
+		IP            string
+		UserAgent     string
+		CreatedAt     time.Time
+		// Sequence is NULL for the entries appended before the log was chained
+		Sequence sql.NullInt64
+		PrevHash string
+		Hash     string
+	}
+
+	auditRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewAuditRepository create new auditRepository with its dependencies
+func NewAuditRepository(handler *database.SQLHandler) entity.AuditRepository {
+	return auditRepository{
+		handler: handler,
+		table:   "audit_entries",
+	}
+}
+
+// Create perform insert into database of the entry pending, inserting a row
+// of its own only so that concurrent transactions do not conflict on it
+func (a auditRepository) Create(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	ctx, span := startSpan(ctx, a.handler.Driver(), "insert", a.table)
+	defer span.End()
+
+	row := newAuditRow(entry)
+	query := rebind(a.handler.Driver(), `
+		INSERT INTO audit_entries (`+auditColumns+`, pending)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, TRUE)`)
+
+	_, err := conn(ctx, a.handler).ExecContext(
+		ctx,
+		query,
+		row.ID,
+		row.Action,
+		row.Actor,
+		row.Target,
+		row.SubjectID,
+		row.Detail,
+		row.Before,
+		row.After,
+		row.CorrelationID,
+		row.IP,
+		row.UserAgent,
+		row.CreatedAt,
+		row.Sequence,
+		row.PrevHash,
+		row.Hash,
+	)
+	if err != nil {
+		recordError(span, err)
+		return entity.AuditEntry{}, errors.Wrap(conflictError(err), entity.ErrCreateAuditEntry.Error())
+	}
+
+	return entry, nil
+}
+
+// ChainPending perform update into database of the pending entries, the oldest
+// first, each chained to the previous one. The unique sequence and the pending
+// condition make a concurrent chainer fail rather than fork the chain.
+func (a auditRepository) ChainPending(ctx context.Context, limit int) (int, error) {
+	ctx, span := startSpan(ctx, a.handler.Driver(), "update", a.table)
+	defer span.End()
+
+	var (
+		sequence int64
+		hash     string
+	)
+	err := conn(ctx, a.handler).QueryRowContext(ctx, `
+		SELECT sequence, hash FROM audit_entries
+		WHERE sequence IS NOT NULL
+		ORDER BY sequence DESC
+		LIMIT 1`).
+		Scan(&sequence, &hash)
+	if err != nil && err != sql.ErrNoRows {
+		recordError(span, err)
+		return 0, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	pending, err := a.pending(ctx, limit)
+	if err != nil {
+		recordError(span, err)
+		return 0, errors.Wrap(err, entity.ErrCreateAuditEntry.Error())
+	}
+
+	query := rebind(a.handler.Driver(), `
+		UPDATE audit_entries SET sequence = ?, prev_hash = ?, hash = ?, pending = FALSE
+		WHERE id = ? AND pending`)
+
+	for i, entry := range pending {
+		chained := entry.Chain(sequence+1, hash)
+
+		res, err := conn(ctx, a.handler).ExecContext(ctx, query, chained.Sequence(), chained.PrevHash(), chained.Hash(), chained.ID().Value())
+		if err != nil {
+			recordError(span, err)
+			if isUniqueViolation(err) {
+				return i, errors.Wrap(entity.ErrConcurrentModification, entity.ErrCreateAuditEntry.Error())
+			}
+			return i, errors.Wrap(conflictError(err), entity.ErrCreateAuditEntry.Error())
+		}
+
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			recordError(span, entity.ErrConcurrentModification)
+			return i, errors.Wrap(entity.ErrConcurrentModification, entity.ErrCreateAuditEntry.Error())
+		}
+
+		sequence, hash = chained.Sequence(), chained.Hash()
+	}
+
+	return len(pending), nil
+}
+
+// pending returns up to limit pending entries, the oldest first, read fully
+// before they are chained as SQLite has a single connection
+func (a auditRepository) pending(ctx context.Context, limit int) ([]entity.AuditEntry, error) {
+	query := rebind(a.handler.Driver(), `
+		SELECT `+auditColumns+` FROM audit_entries
+		WHERE pending
+		ORDER BY created_at, id
+		LIMIT ?`)
+
+	rows, err := conn(ctx, a.handler).QueryContext(ctx, query, pageLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entity.AuditEntry
+	for rows.Next() {
+		row, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := row.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// List perform select into database, the chained entries in the order of the
+// chain, after the ones appended before it, then the pending ones
+func (a auditRepository) List(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	ctx, span := startSpan(ctx, a.handler.Driver(), "select", a.table)
+	defer span.End()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if f.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, f.Actor)
+	}
+	if f.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, f.Action.String())
+	}
+	if f.Target != "" {
+		conditions = append(conditions, "target = ?")
+		args = append(args, f.Target.String())
+	}
+	if id := f.SubjectID.Value(); id != "" {
+		conditions = append(conditions, "subject_id = ?")
+		args = append(args, id)
+	}
+	if f.CorrelationID != "" {
+		conditions = append(conditions, "correlation_id = ?")
+		args = append(args, f.CorrelationID)
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, f.To.UTC())
+	}
+	if f.FromSequence > 0 {
+		conditions = append(conditions, "sequence >= ?")
+		args = append(args, f.FromSequence)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := rebind(a.handler.Driver(), `
+		SELECT `+auditColumns+` FROM audit_entries
+		`+where+`
+		ORDER BY pending, COALESCE(sequence, 0), created_at, id
+		LIMIT ? OFFSET ?`)
+	args = append(args, pageLimit(f.Limit), f.Offset)
+
+	rows, err := conn(ctx, a.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+	}
+	defer rows.Close()
+
+	var entries []entity.AuditEntry
+	for rows.Next() {
+		row, err := scanAuditEntry(rows)
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+		}
+
+		entry, err := row.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListAuditEntries.Error())
+	}
+
+	return entries, nil
+}
+
+func newAuditRow(e entity.AuditEntry) auditRow {
+	return auditRow{
+		ID:            e.ID().Value(),
+		Action:        e.Action().String(),
+		Actor:         e.Actor(),
+		Target:        e.Target().String(),
+		SubjectID:     e.SubjectID().Value(),
+		Detail:        e.Detail(),
+		Before:        e.Before(),
+		After:         e.After(),
+		CorrelationID: e.Origin().CorrelationID,
+		IP:            e.Origin().IP,
+		UserAgent:     e.Origin().UserAgent,
+		CreatedAt:     e.CreatedAt().UTC(),
+		Sequence:      sql.NullInt64{Int64: e.Sequence(), Valid: e.Sequence() > 0},
+		PrevHash:      e.PrevHash(),
+		Hash:          e.Hash(),
+	}
+}
+
+func scanAuditEntry(s scanner) (auditRow, error) {
+	var row auditRow
+	err := s.Scan(
+		&row.ID,
+		&row.Action,
+		&row.Actor,
+		&row.Target,
+		&row.SubjectID,
+		&row.Detail,
+		&row.Before,
+		&row.After,
+		&row.CorrelationID,
+		&row.IP,
+		&row.UserAgent,
+		&row.CreatedAt,
+		&row.Sequence,
+		&row.PrevHash,
+		&row.Hash,
+	)
+
+	return row, err
+}
+
+// toEntity rebuilds the entry of the row, the entries appended before they
+// had a target being left without one
+func (r auditRow) toEntity() (entity.AuditEntry, error) {
+	ID, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	action, err := vo.NewAuditAction(r.Action)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	subjectID, err := vo.NewUuid(r.SubjectID)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	var target vo.AuditTarget
+	if r.Target != "" {
+		if target, err = vo.NewAuditTarget(r.Target); err != nil {
+			return entity.AuditEntry{}, err
+		}
+	}
+
+	return entity.NewAuditEntry(ID, action, r.Actor, subjectID, r.Detail, r.CreatedAt).
+		WithTarget(target).
+		WithSnapshots(r.Before, r.After).
+		WithOrigin(entity.AuditOrigin{
+			CorrelationID: r.CorrelationID,
+			IP:            r.IP,
+			UserAgent:     r.UserAgent,
+		}).
+		WithChain(r.Sequence.Int64, r.PrevHash, r.Hash), nil
+}
@@ -0,0 +1,369 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Row data
+	batchRow struct {
+		ID        string
+		PayerID   string
+		Mode      string
+		Status    string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	// Row data
+	batchItemRow struct {
+		TransferID string
+		PayeeID    string
+		Currency   string
+		Value      int64
+		Status     string
+		Reason     string
+	}
+
+	batchRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewBatchRepository create new batchRepository with its dependencies
+func NewBatchRepository(handler *database.SQLHandler) entity.BatchRepository {
+	return batchRepository{
+		handler: handler,
+		table:   "batches",
+	}
+}
+
+// Create perform insert into database, the batch and its items in one transaction
+func (b batchRepository) Create(ctx context.Context, batch entity.Batch) (entity.Batch, error) {
+	ctx, span := startSpan(ctx, b.handler.Driver(), "insert", b.table)
+	defer span.End()
+
+	batchQuery := rebind(b.handler.Driver(), `
+		INSERT INTO batches (id, payer_id, mode, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	itemQuery := rebind(b.handler.Driver(), `
+		INSERT INTO batch_transfers (batch_id, position, transfer_id, payee_id, currency, value, status, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	err := inTransaction(ctx, b.handler, func(ctx context.Context) error {
+		if _, err := conn(ctx, b.handler).ExecContext(
+			ctx,
+			batchQuery,
+			batch.ID().Value(),
+			batch.Payer().Value(),
+			batch.Mode().String(),
+			batch.Status().String(),
+			batch.CreatedAt().UTC(),
+			batch.UpdatedAt().UTC(),
+		); err != nil {
+			return err
+		}
+
+		for position, item := range batch.Items() {
+			if _, err := conn(ctx, b.handler).ExecContext(
+				ctx,
+				itemQuery,
+				batch.ID().Value(),
+				position,
+				item.TransferID().Value(),
+				item.Payee().Value(),
+				item.Value().Currency().String(),
+				item.Value().Amount().Value(),
+				item.Status().String(),
+				item.Reason(),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		recordError(span, err)
+		return entity.Batch{}, errors.Wrap(err, entity.ErrCreateBatch.Error())
+	}
+
+	return batch, nil
+}
+
+// FindByID perform select into database
+func (b batchRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Batch, error) {
+	ctx, span := startSpan(ctx, b.handler.Driver(), "select", b.table)
+	defer span.End()
+
+	query := rebind(b.handler.Driver(), `
+		SELECT id, payer_id, mode, status, created_at, updated_at
+		FROM batches WHERE id = ?`)
+
+	var row batchRow
+	err := conn(ctx, b.handler).QueryRowContext(ctx, query, ID.Value()).Scan(
+		&row.ID,
+		&row.PayerID,
+		&row.Mode,
+		&row.Status,
+		&row.CreatedAt,
+		&row.UpdatedAt,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Batch{}, entity.ErrNotFoundBatch
+		default:
+			recordError(span, err)
+			return entity.Batch{}, errors.Wrap(err, entity.ErrFindBatch.Error())
+		}
+	}
+
+	batch, err := b.withItems(ctx, row)
+	if err != nil {
+		recordError(span, err)
+		return entity.Batch{}, err
+	}
+
+	return batch, nil
+}
+
+// FindUnfinished perform select into database, oldest batches first
+func (b batchRepository) FindUnfinished(ctx context.Context, limit int) ([]entity.Batch, error) {
+	ctx, span := startSpan(ctx, b.handler.Driver(), "select", b.table)
+	defer span.End()
+
+	query := rebind(b.handler.Driver(), `
+		SELECT id, payer_id, mode, status, created_at, updated_at
+		FROM batches
+		WHERE status IN (?, ?)
+		ORDER BY created_at
+		LIMIT ?`)
+
+	rows, err := conn(ctx, b.handler).QueryContext(ctx, query, vo.BatchPending.String(), vo.BatchProcessing.String(), limit)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+
+	var batchRows []batchRow
+	for rows.Next() {
+		var row batchRow
+		if err := rows.Scan(&row.ID, &row.PayerID, &row.Mode, &row.Status, &row.CreatedAt, &row.UpdatedAt); err != nil {
+			rows.Close()
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrFindBatch.Error())
+		}
+		batchRows = append(batchRows, row)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+
+	batches := make([]entity.Batch, 0, len(batchRows))
+	for _, row := range batchRows {
+		batch, err := b.withItems(ctx, row)
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+// Update perform update into database, the batch and every of its items in one transaction
+func (b batchRepository) Update(ctx context.Context, batch entity.Batch) error {
+	ctx, span := startSpan(ctx, b.handler.Driver(), "update", b.table)
+	defer span.End()
+
+	err := inTransaction(ctx, b.handler, func(ctx context.Context) error {
+		if err := b.updateBatch(ctx, batch); err != nil {
+			return err
+		}
+
+		for position, item := range batch.Items() {
+			if err := b.updateItem(ctx, batch, position, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateBatch.Error())
+	}
+
+	return nil
+}
+
+// UpdateItem perform update into database, the batch and its item at the index in one transaction
+func (b batchRepository) UpdateItem(ctx context.Context, batch entity.Batch, index int) error {
+	ctx, span := startSpan(ctx, b.handler.Driver(), "update", b.table)
+	defer span.End()
+
+	err := inTransaction(ctx, b.handler, func(ctx context.Context) error {
+		if err := b.updateBatch(ctx, batch); err != nil {
+			return err
+		}
+
+		return b.updateItem(ctx, batch, index, batch.Items()[index])
+	})
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateBatch.Error())
+	}
+
+	return nil
+}
+
+func (b batchRepository) updateBatch(ctx context.Context, batch entity.Batch) error {
+	query := rebind(b.handler.Driver(), `UPDATE batches SET status = ?, updated_at = ? WHERE id = ?`)
+
+	res, err := conn(ctx, b.handler).ExecContext(
+		ctx,
+		query,
+		batch.Status().String(),
+		batch.UpdatedAt().UTC(),
+		batch.ID().Value(),
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return entity.ErrNotFoundBatch
+	}
+
+	return nil
+}
+
+func (b batchRepository) updateItem(ctx context.Context, batch entity.Batch, position int, item entity.BatchItem) error {
+	query := rebind(b.handler.Driver(), `
+		UPDATE batch_transfers SET status = ?, reason = ?
+		WHERE batch_id = ? AND position = ?`)
+
+	_, err := conn(ctx, b.handler).ExecContext(
+		ctx,
+		query,
+		item.Status().String(),
+		item.Reason(),
+		batch.ID().Value(),
+		position,
+	)
+
+	return err
+}
+
+// withItems loads the items of the batch row
+func (b batchRepository) withItems(ctx context.Context, row batchRow) (entity.Batch, error) {
+	query := rebind(b.handler.Driver(), `
+		SELECT transfer_id, payee_id, currency, value, status, reason
+		FROM batch_transfers
+		WHERE batch_id = ?
+		ORDER BY position`)
+
+	rows, err := conn(ctx, b.handler).QueryContext(ctx, query, row.ID)
+	if err != nil {
+		return entity.Batch{}, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+	defer rows.Close()
+
+	var items []entity.BatchItem
+	for rows.Next() {
+		var itemRow batchItemRow
+		if err := rows.Scan(
+			&itemRow.TransferID,
+			&itemRow.PayeeID,
+			&itemRow.Currency,
+			&itemRow.Value,
+			&itemRow.Status,
+			&itemRow.Reason,
+		); err != nil {
+			return entity.Batch{}, errors.Wrap(err, entity.ErrFindBatch.Error())
+		}
+
+		item, err := itemRow.toEntity()
+		if err != nil {
+			return entity.Batch{}, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.Batch{}, errors.Wrap(err, entity.ErrFindBatch.Error())
+	}
+
+	return row.toEntity(items)
+}
+
+func (r batchRow) toEntity(items []entity.BatchItem) (entity.Batch, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	mode, err := vo.NewBatchMode(r.Mode)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	status, err := vo.NewBatchStatus(r.Status)
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	return entity.RestoreBatch(id, payer, mode, status, items, r.CreatedAt, r.UpdatedAt), nil
+}
+
+func (r batchItemRow) toEntity() (entity.BatchItem, error) {
+	transferID, err := vo.NewUuid(r.TransferID)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	payee, err := vo.NewUuid(r.PayeeID)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	amount, err := vo.NewAmount(r.Value)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	status, err := vo.NewBatchItemStatus(r.Status)
+	if err != nil {
+		return entity.BatchItem{}, err
+	}
+
+	return entity.RestoreBatchItem(transferID, payee, vo.NewMoney(currency, amount), status, r.Reason), nil
+}
@@ -0,0 +1,127 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type createTransferRepository struct {
+	handler *database.SQLHandler
+	table   string
+}
+
+// NewCreateTransferRepository creates new createTransferRepository with its dependencies
+func NewCreateTransferRepository(handler *database.SQLHandler) entity.TransferRepositoryCreator {
+	return createTransferRepository{
+		handler: handler,
+		table:   "transfers",
+	}
+}
+
+// Create perform insert into database, entity.ErrTransferAlreadyExists when the ID is used.
+// The legs of a split transfer are inserted in the same transaction, the fee
+// rule of a simple transfer is stored with it and the fee account with every transfer.
+func (c createTransferRepository) Create(ctx context.Context, t entity.Transfer) (entity.Transfer, error) {
+	ctx, span := startSpan(ctx, c.handler.Driver(), "insert", c.table)
+	defer span.End()
+
+	query := rebind(c.handler.Driver(), `
+		INSERT INTO transfers (
+			id, payer_id, payee_id, currency, value, source_wallet, destination_wallet,
+			type, status, fee, fee_rule_id, fee_rule_version, fee_account, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	legQuery := rebind(c.handler.Driver(), `
+		INSERT INTO transfer_legs (transfer_id, position, payee_id, currency, value, fee, fee_rule_id, fee_rule_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	var ruleID, ruleVersion interface{}
+	if legs := t.Legs(); !t.IsSplit() && len(legs) > 0 {
+		ruleID, ruleVersion = feeRuleArgs(legs[0].Fee())
+	}
+
+	err := inTransaction(ctx, c.handler, func(ctx context.Context) error {
+		if _, err := conn(ctx, c.handler).ExecContext(
+			ctx,
+			query,
+			t.ID().Value(),
+			t.Payer().Value(),
+			t.Payee().Value(),
+			t.Value().Currency().String(),
+			t.Value().Amount().Value(),
+			t.Source().String(),
+			t.Destination().String(),
+			t.Type().String(),
+			t.Status().String(),
+			t.Fee().Amount().Value(),
+			ruleID,
+			ruleVersion,
+			sql.NullString{String: t.FeeAccount().Value(), Valid: t.FeeAccount().Value() != ""},
+			t.CreatedAt().UTC(),
+		); err != nil {
+			return err
+		}
+
+		if !t.IsSplit() {
+			return nil
+		}
+
+		for position, leg := range t.Legs() {
+			ruleID, ruleVersion := feeRuleArgs(leg.Fee())
+			if _, err := conn(ctx, c.handler).ExecContext(
+				ctx,
+				legQuery,
+				t.ID().Value(),
+				position,
+				leg.Payee().Value(),
+				leg.Value().Currency().String(),
+				leg.Value().Amount().Value(),
+				leg.Fee().Amount().Amount().Value(),
+				ruleID,
+				ruleVersion,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		recordError(span, err)
+		if isUniqueViolation(err) {
+			return entity.Transfer{}, errors.Wrap(entity.ErrTransferAlreadyExists, entity.ErrCreateTransfer.Error())
+		}
+		return entity.Transfer{}, errors.Wrap(err, entity.ErrCreateTransfer.Error())
+	}
+
+	return t, nil
+}
+
+// WithTransaction runs fn inside a SQL transaction, committed when fn returns nil.
+// The repositories called with the context given to fn take part in the transaction
+// and the users read by FindByID are locked until it ends. Serialization failures
+// and deadlocks are reported as entity.ErrConcurrentModification.
+func (c createTransferRepository) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := startSpan(ctx, c.handler.Driver(), "transaction", c.table)
+	defer span.End()
+
+	if err := inTransaction(ctx, c.handler, fn); err != nil {
+		recordError(span, err)
+		return conflictError(err)
+	}
+
+	return nil
+}
+
+// feeRuleArgs returns the rule of the fee as arguments, NULL when no rule applied
+func feeRuleArgs(f entity.Fee) (interface{}, interface{}) {
+	valid := f.RuleID() != ""
+	return sql.NullString{String: f.RuleID(), Valid: valid}, sql.NullInt64{Int64: int64(f.RuleVersion()), Valid: valid}
+}
@@ -0,0 +1,338 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const transferColumns = `id, payer_id, payee_id, currency, value, source_wallet, destination_wallet,
+	type, status, fee, fee_rule_id, fee_rule_version, fee_account, created_at`
+
+type (
+	// Row data
+	transferRow struct {
+		ID                string
+		PayerID           string
+		PayeeID           string
+		Currency          string
+		Value             int64
+		SourceWallet      string
+		DestinationWallet string
+		Type              string
+		Status            string
+		Fee               int64
+		FeeRuleID         sql.NullString
+		FeeRuleVersion    sql.NullInt64
+		FeeAccount        sql.NullString
+		CreatedAt         time.Time
+	}
+
+	// Row data
+	transferLegRow struct {
+		PayeeID        string
+		Currency       string
+		Value          int64
+		Fee            int64
+		FeeRuleID      sql.NullString
+		FeeRuleVersion sql.NullInt64
+	}
+
+	findTransferRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewFindTransferRepository creates new findTransferRepository with its dependencies
+func NewFindTransferRepository(handler *database.SQLHandler) entity.TransferRepositoryFinder {
+	return findTransferRepository{
+		handler: handler,
+		table:   "transfers",
+	}
+}
+
+// SumByPayer perform select into database over the transfers, the withdrawals and the
+// payments held, the rejected transfers and the failed withdrawals excluded. A captured
+// payment is counted by its transfer.
+func (f findTransferRepository) SumByPayer(
+	ctx context.Context,
+	payerID vo.Uuid,
+	currency vo.Currency,
+	from, to time.Time,
+) (entity.TransferUsage, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "select", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `
+		SELECT COUNT(*), COALESCE(SUM(value), 0) FROM (
+			SELECT value FROM transfers
+			WHERE payer_id = ? AND currency = ? AND created_at >= ? AND created_at < ? AND status <> 'REJECTED'
+			UNION ALL
+			SELECT value FROM fundings
+			WHERE user_id = ? AND currency = ? AND created_at >= ? AND created_at < ? AND type = 'WITHDRAWAL' AND status <> 'FAILED'
+			UNION ALL
+			SELECT value FROM payments
+			WHERE payer_id = ? AND currency = ? AND created_at >= ? AND created_at < ? AND status = 'AUTHORIZED'
+		) usage`)
+
+	var (
+		count int
+		value int64
+	)
+	err := conn(ctx, f.handler).
+		QueryRowContext(ctx, query,
+			payerID.Value(), currency.String(), from.UTC(), to.UTC(),
+			payerID.Value(), currency.String(), from.UTC(), to.UTC(),
+			payerID.Value(), currency.String(), from.UTC(), to.UTC()).
+		Scan(&count, &value)
+	if err != nil {
+		recordError(span, err)
+		return entity.TransferUsage{}, err
+	}
+
+	amount, err := vo.NewAmount(value)
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	return entity.NewTransferUsage(count, amount), nil
+}
+
+// FindPayees perform select into database, the rejected transfers excluded
+func (f findTransferRepository) FindPayees(ctx context.Context, payerID vo.Uuid, from, to time.Time) ([]vo.Uuid, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "select", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `
+		SELECT payee_id FROM transfers
+		WHERE payer_id = ? AND created_at >= ? AND created_at < ? AND status <> 'REJECTED'
+		UNION
+		SELECT l.payee_id FROM transfer_legs l
+		JOIN transfers t ON t.id = l.transfer_id
+		WHERE t.payer_id = ? AND t.created_at >= ? AND t.created_at < ? AND t.status <> 'REJECTED'`)
+
+	rows, err := conn(ctx, f.handler).QueryContext(
+		ctx,
+		query,
+		payerID.Value(), from.UTC(), to.UTC(),
+		payerID.Value(), from.UTC(), to.UTC(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payees []vo.Uuid
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+
+		payee, err := vo.NewUuid(id)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, payee)
+	}
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return payees, nil
+}
+
+// FindByID perform select into database, the legs of a split transfer included
+func (f findTransferRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Transfer, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "select", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `SELECT `+transferColumns+` FROM transfers WHERE id = ?`)
+
+	row, err := scanTransfer(conn(ctx, f.handler).QueryRowContext(ctx, query, ID.Value()))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Transfer{}, entity.ErrNotFoundTransfer
+		default:
+			recordError(span, err)
+			return entity.Transfer{}, errors.Wrap(err, entity.ErrFindTransfer.Error())
+		}
+	}
+
+	legs, err := findLegs(ctx, f.handler, row)
+	if err != nil {
+		recordError(span, err)
+		return entity.Transfer{}, err
+	}
+
+	return row.toEntity(legs)
+}
+
+// findLegs returns the legs of the transfer in their order, a transfer which
+// is not split being its only leg
+func findLegs(ctx context.Context, handler *database.SQLHandler, row transferRow) ([]transferLegRow, error) {
+	query := rebind(handler.Driver(), `
+		SELECT payee_id, currency, value, fee, fee_rule_id, fee_rule_version
+		FROM transfer_legs
+		WHERE transfer_id = ?
+		ORDER BY position`)
+
+	rows, err := conn(ctx, handler).QueryContext(ctx, query, row.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindTransfer.Error())
+	}
+	defer rows.Close()
+
+	var legs []transferLegRow
+	for rows.Next() {
+		var leg transferLegRow
+		if err := rows.Scan(
+			&leg.PayeeID,
+			&leg.Currency,
+			&leg.Value,
+			&leg.Fee,
+			&leg.FeeRuleID,
+			&leg.FeeRuleVersion,
+		); err != nil {
+			return nil, errors.Wrap(err, entity.ErrFindTransfer.Error())
+		}
+		legs = append(legs, leg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindTransfer.Error())
+	}
+
+	if len(legs) == 0 {
+		legs = []transferLegRow{{
+			PayeeID:        row.PayeeID,
+			Currency:       row.Currency,
+			Value:          row.Value,
+			Fee:            row.Fee,
+			FeeRuleID:      row.FeeRuleID,
+			FeeRuleVersion: row.FeeRuleVersion,
+		}}
+	}
+
+	return legs, nil
+}
+
+func scanTransfer(s scanner) (transferRow, error) {
+	var row transferRow
+	err := s.Scan(
+		&row.ID,
+		&row.PayerID,
+		&row.PayeeID,
+		&row.Currency,
+		&row.Value,
+		&row.SourceWallet,
+		&row.DestinationWallet,
+		&row.Type,
+		&row.Status,
+		&row.Fee,
+		&row.FeeRuleID,
+		&row.FeeRuleVersion,
+		&row.FeeAccount,
+		&row.CreatedAt,
+	)
+
+	return row, err
+}
+
+func (r transferRow) toEntity(legRows []transferLegRow) (entity.Transfer, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	transferType, err := vo.NewTransferType(r.Type)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	status, err := vo.NewTransferStatus(r.Status)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	source, err := vo.NewWalletPurpose(r.SourceWallet)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	destination, err := vo.NewWalletPurpose(r.DestinationWallet)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	// the account of the fees charged by a rule, the transfers recorded before it was having none
+	var feeAccount vo.Uuid
+	if r.FeeAccount.Valid {
+		if feeAccount, err = vo.NewUuid(r.FeeAccount.String); err != nil {
+			return entity.Transfer{}, err
+		}
+	}
+
+	legs := make([]entity.TransferLeg, 0, len(legRows))
+	fees := make([]entity.Fee, 0, len(legRows))
+	for _, l := range legRows {
+		payee, err := vo.NewUuid(l.PayeeID)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		currency, err := vo.NewCurrency(l.Currency)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		value, err := vo.NewAmount(l.Value)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		fee, err := vo.NewAmount(l.Fee)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		var account vo.Uuid
+		if l.FeeRuleID.Valid {
+			account = feeAccount
+		}
+
+		legs = append(legs, entity.NewTransferLeg(payee, vo.NewMoney(currency, value)))
+		fees = append(fees, entity.RestoreFee(
+			vo.NewMoney(currency, fee),
+			l.FeeRuleID.String,
+			int(l.FeeRuleVersion.Int64),
+			account,
+		))
+	}
+
+	t, err := entity.RestoreTransfer(id, payer, legs, transferType, status, r.CreatedAt)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	for i, fee := range fees {
+		t = t.WithFee(i, fee)
+	}
+
+	return t.WithWallets(source, destination), nil
+}
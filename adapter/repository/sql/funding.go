@@ -0,0 +1,295 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const fundingColumns = `id, user_id, type, method, currency, value, wallet, status, reference, reason,
+	created_at, updated_at`
+
+type (
+	// Row data
+	fundingRow struct {
+		ID        string
+		UserID    string
+		Type      string
+		Method    string
+		Currency  string
+		Value     int64
+		Wallet    string
+		Status    string
+		Reference string
+		Reason    string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	fundingRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewFundingRepository create new fundingRepository with its dependencies
+func NewFundingRepository(handler *database.SQLHandler) entity.FundingRepository {
+	return fundingRepository{
+		handler: handler,
+		table:   "fundings",
+	}
+}
+
+// Create perform insert into database
+func (f fundingRepository) Create(ctx context.Context, funding entity.Funding) (entity.Funding, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "insert", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `
+		INSERT INTO fundings (`+fundingColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	if _, err := conn(ctx, f.handler).ExecContext(
+		ctx,
+		query,
+		funding.ID().Value(),
+		funding.User().Value(),
+		funding.Type().String(),
+		funding.Method().String(),
+		funding.Value().Currency().String(),
+		funding.Value().Amount().Value(),
+		funding.Wallet().String(),
+		funding.Status().String(),
+		funding.Reference(),
+		funding.Reason(),
+		funding.CreatedAt().UTC(),
+		funding.UpdatedAt().UTC(),
+	); err != nil {
+		recordError(span, err)
+		return entity.Funding{}, errors.Wrap(err, entity.ErrCreateFunding.Error())
+	}
+
+	return funding, nil
+}
+
+// FindByID perform select into database
+func (f fundingRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Funding, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "select", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `SELECT `+fundingColumns+` FROM fundings WHERE id = ?`)
+
+	row, err := scanFunding(conn(ctx, f.handler).QueryRowContext(ctx, query, ID.Value()))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Funding{}, entity.ErrNotFoundFunding
+		default:
+			recordError(span, err)
+			return entity.Funding{}, errors.Wrap(err, entity.ErrFindFunding.Error())
+		}
+	}
+
+	return row.toEntity()
+}
+
+// List perform select into database, oldest fundings first
+func (f fundingRepository) List(ctx context.Context, filter entity.FundingFilter) ([]entity.Funding, error) {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "select", f.table)
+	defer span.End()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if id := filter.UserID.Value(); id != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, id)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		conditions = append(conditions, "updated_at >= ?")
+		args = append(args, filter.UpdatedFrom.UTC())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := rebind(f.handler.Driver(), `
+		SELECT `+fundingColumns+` FROM fundings
+		`+where+`
+		ORDER BY created_at, id
+		LIMIT ? OFFSET ?`)
+	args = append(args, pageLimit(filter.Limit), filter.Offset)
+
+	rows, err := conn(ctx, f.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+	}
+	defer rows.Close()
+
+	var fundings []entity.Funding
+	for rows.Next() {
+		row, err := scanFunding(rows)
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+		}
+
+		funding, err := row.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+		}
+		fundings = append(fundings, funding)
+	}
+
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListFundings.Error())
+	}
+
+	return fundings, nil
+}
+
+// Update perform update into database when the stored funding is still pending
+func (f fundingRepository) Update(ctx context.Context, funding entity.Funding) error {
+	ctx, span := startSpan(ctx, f.handler.Driver(), "update", f.table)
+	defer span.End()
+
+	query := rebind(f.handler.Driver(), `
+		UPDATE fundings SET status = ?, reference = ?, reason = ?, updated_at = ?
+		WHERE id = ? AND status = ?`)
+
+	res, err := conn(ctx, f.handler).ExecContext(
+		ctx,
+		query,
+		funding.Status().String(),
+		funding.Reference(),
+		funding.Reason(),
+		funding.UpdatedAt().UTC(),
+		funding.ID().Value(),
+		vo.FundingPending.String(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(conflictError(err), entity.ErrUpdateFunding.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateFunding.Error())
+	}
+
+	if affected == 0 {
+		var exists int
+		err := conn(ctx, f.handler).
+			QueryRowContext(ctx, rebind(f.handler.Driver(), `SELECT COUNT(*) FROM fundings WHERE id = ?`), funding.ID().Value()).
+			Scan(&exists)
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateFunding.Error())
+		}
+
+		if exists == 0 {
+			return errors.Wrap(entity.ErrNotFoundFunding, entity.ErrUpdateFunding.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateFunding.Error())
+	}
+
+	return nil
+}
+
+func scanFunding(s scanner) (fundingRow, error) {
+	var row fundingRow
+	err := s.Scan(
+		&row.ID,
+		&row.UserID,
+		&row.Type,
+		&row.Method,
+		&row.Currency,
+		&row.Value,
+		&row.Wallet,
+		&row.Status,
+		&row.Reference,
+		&row.Reason,
+		&row.CreatedAt,
+		&row.UpdatedAt,
+	)
+
+	return row, err
+}
+
+func (r fundingRow) toEntity() (entity.Funding, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	userID, err := vo.NewUuid(r.UserID)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	fundingType, err := vo.NewFundingType(r.Type)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	method, err := vo.NewFundingMethod(r.Method)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	value, err := vo.NewAmount(r.Value)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	wallet, err := vo.NewWalletPurpose(r.Wallet)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	status, err := vo.NewFundingStatus(r.Status)
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	return entity.RestoreFunding(
+		id,
+		userID,
+		fundingType,
+		method,
+		vo.NewMoney(currency, value),
+		wallet,
+		status,
+		r.Reference,
+		r.Reason,
+		r.CreatedAt,
+		r.UpdatedAt,
+	), nil
+}
@@ -0,0 +1,99 @@
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type listTransferRepository struct {
+	handler *database.SQLHandler
+	table   string
+}
+
+// NewListTransferRepository create new listTransferRepository with its dependencies
+func NewListTransferRepository(handler *database.SQLHandler) entity.TransferRepositoryLister {
+	return listTransferRepository{
+		handler: handler,
+		table:   "transfers",
+	}
+}
+
+// List perform select into database, oldest transfers first, then the legs of every transfer
+func (l listTransferRepository) List(ctx context.Context, f entity.TransferFilter) ([]entity.Transfer, error) {
+	ctx, span := startSpan(ctx, l.handler.Driver(), "select", l.table)
+	defer span.End()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if id := f.UserID.Value(); id != "" {
+		conditions = append(conditions, `(payer_id = ? OR payee_id = ?
+			OR id IN (SELECT transfer_id FROM transfer_legs WHERE payee_id = ?))`)
+		args = append(args, id, id, id)
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, f.To.UTC())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := rebind(l.handler.Driver(), `
+		SELECT `+transferColumns+` FROM transfers
+		`+where+`
+		ORDER BY created_at, id
+		LIMIT ? OFFSET ?`)
+	args = append(args, pageLimit(f.Limit), f.Offset)
+
+	rows, err := conn(ctx, l.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListTransfers.Error())
+	}
+
+	// the rows are read before the legs are selected, SQLite having a single connection
+	var transferRows []transferRow
+	for rows.Next() {
+		row, err := scanTransfer(rows)
+		if err != nil {
+			rows.Close()
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListTransfers.Error())
+		}
+		transferRows = append(transferRows, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListTransfers.Error())
+	}
+
+	transfers := make([]entity.Transfer, 0, len(transferRows))
+	for _, row := range transferRows {
+		legs, err := findLegs(ctx, l.handler, row)
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+
+		transfer, err := row.toEntity(legs)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
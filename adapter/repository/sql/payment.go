@@ -0,0 +1,269 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const paymentColumns = `id, payer_id, payee_id, currency, value, captured, status, transfer_id,
+	created_at, expires_at, updated_at`
+
+type (
+	// Row data
+	paymentRow struct {
+		ID         string
+		PayerID    string
+		PayeeID    string
+		Currency   string
+		Value      int64
+		Captured   int64
+		Status     string
+		TransferID sql.NullString
+		CreatedAt  time.Time
+		ExpiresAt  time.Time
+		UpdatedAt  time.Time
+	}
+
+	paymentRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewPaymentRepository create new paymentRepository with its dependencies
+func NewPaymentRepository(handler *database.SQLHandler) entity.PaymentRepository {
+	return paymentRepository{
+		handler: handler,
+		table:   "payments",
+	}
+}
+
+// Create perform insert into database
+func (p paymentRepository) Create(ctx context.Context, payment entity.Payment) (entity.Payment, error) {
+	ctx, span := startSpan(ctx, p.handler.Driver(), "insert", p.table)
+	defer span.End()
+
+	query := rebind(p.handler.Driver(), `
+		INSERT INTO payments (`+paymentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	if _, err := conn(ctx, p.handler).ExecContext(
+		ctx,
+		query,
+		payment.ID().Value(),
+		payment.Payer().Value(),
+		payment.Payee().Value(),
+		payment.Value().Currency().String(),
+		payment.Value().Amount().Value(),
+		payment.Captured().Value(),
+		payment.Status().String(),
+		transferIDArg(payment),
+		payment.CreatedAt().UTC(),
+		payment.ExpiresAt().UTC(),
+		payment.UpdatedAt().UTC(),
+	); err != nil {
+		recordError(span, err)
+		return entity.Payment{}, errors.Wrap(err, entity.ErrCreatePayment.Error())
+	}
+
+	return payment, nil
+}
+
+// FindByID perform select into database
+func (p paymentRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Payment, error) {
+	ctx, span := startSpan(ctx, p.handler.Driver(), "select", p.table)
+	defer span.End()
+
+	query := rebind(p.handler.Driver(), `SELECT `+paymentColumns+` FROM payments WHERE id = ?`)
+
+	row, err := scanPayment(conn(ctx, p.handler).QueryRowContext(ctx, query, ID.Value()))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Payment{}, entity.ErrNotFoundPayment
+		default:
+			recordError(span, err)
+			return entity.Payment{}, errors.Wrap(err, entity.ErrFindPayment.Error())
+		}
+	}
+
+	return row.toEntity()
+}
+
+// FindExpired perform select into database, oldest payments first
+func (p paymentRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Payment, error) {
+	ctx, span := startSpan(ctx, p.handler.Driver(), "select", p.table)
+	defer span.End()
+
+	query := rebind(p.handler.Driver(), `
+		SELECT `+paymentColumns+` FROM payments
+		WHERE status = ? AND expires_at <= ?
+		ORDER BY created_at
+		LIMIT ?`)
+
+	rows, err := conn(ctx, p.handler).QueryContext(ctx, query, vo.PaymentAuthorized.String(), at.UTC(), limit)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindPayment.Error())
+	}
+	defer rows.Close()
+
+	var payments []entity.Payment
+	for rows.Next() {
+		row, err := scanPayment(rows)
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrFindPayment.Error())
+		}
+
+		payment, err := row.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindPayment.Error())
+	}
+
+	return payments, nil
+}
+
+// Update perform update into database when the stored payment is still authorized
+func (p paymentRepository) Update(ctx context.Context, payment entity.Payment) error {
+	ctx, span := startSpan(ctx, p.handler.Driver(), "update", p.table)
+	defer span.End()
+
+	query := rebind(p.handler.Driver(), `
+		UPDATE payments SET captured = ?, status = ?, transfer_id = ?, updated_at = ?
+		WHERE id = ? AND status = ?`)
+
+	res, err := conn(ctx, p.handler).ExecContext(
+		ctx,
+		query,
+		payment.Captured().Value(),
+		payment.Status().String(),
+		transferIDArg(payment),
+		payment.UpdatedAt().UTC(),
+		payment.ID().Value(),
+		vo.PaymentAuthorized.String(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(conflictError(err), entity.ErrUpdatePayment.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdatePayment.Error())
+	}
+
+	if affected == 0 {
+		var exists int
+		err := conn(ctx, p.handler).
+			QueryRowContext(ctx, rebind(p.handler.Driver(), `SELECT COUNT(*) FROM payments WHERE id = ?`), payment.ID().Value()).
+			Scan(&exists)
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdatePayment.Error())
+		}
+
+		if exists == 0 {
+			return errors.Wrap(entity.ErrNotFoundPayment, entity.ErrUpdatePayment.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdatePayment.Error())
+	}
+
+	return nil
+}
+
+// transferIDArg returns the transfer of the capture as argument, NULL until the payment is captured
+func transferIDArg(p entity.Payment) sql.NullString {
+	return sql.NullString{String: p.TransferID().Value(), Valid: p.TransferID().Value() != ""}
+}
+
+func scanPayment(s scanner) (paymentRow, error) {
+	var row paymentRow
+	err := s.Scan(
+		&row.ID,
+		&row.PayerID,
+		&row.PayeeID,
+		&row.Currency,
+		&row.Value,
+		&row.Captured,
+		&row.Status,
+		&row.TransferID,
+		&row.CreatedAt,
+		&row.ExpiresAt,
+		&row.UpdatedAt,
+	)
+
+	return row, err
+}
+
+func (r paymentRow) toEntity() (entity.Payment, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	payee, err := vo.NewUuid(r.PayeeID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	value, err := vo.NewAmount(r.Value)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	captured, err := vo.NewAmount(r.Captured)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	status, err := vo.NewPaymentStatus(r.Status)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	var transferID vo.Uuid
+	if r.TransferID.Valid {
+		if transferID, err = vo.NewUuid(r.TransferID.String); err != nil {
+			return entity.Payment{}, err
+		}
+	}
+
+	return entity.RestorePayment(
+		id,
+		payer,
+		payee,
+		vo.NewMoney(currency, value),
+		captured,
+		status,
+		transferID,
+		r.CreatedAt,
+		r.ExpiresAt,
+		r.UpdatedAt,
+	), nil
+}
@@ -0,0 +1,232 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Row data
+	walletBaselineRow struct {
+		UserID     string
+		Currency   string
+		Purpose    string
+		Amount     int64
+		BaselineAt time.Time
+	}
+
+	reconciliationRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// reconciliationLock is the name of the lock of the reconciliation
+const reconciliationLock = "reconciliation"
+
+// NewReconciliationRepository create new reconciliationRepository with its dependencies
+func NewReconciliationRepository(handler *database.SQLHandler) entity.ReconciliationRepository {
+	return reconciliationRepository{
+		handler: handler,
+		table:   "wallet_baselines",
+	}
+}
+
+// Baselines perform select into database, the latest baseline of every wallet at or before the time
+func (r reconciliationRepository) Baselines(ctx context.Context, at time.Time) ([]entity.WalletBaseline, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		SELECT b.user_id, b.currency, b.purpose, b.amount, b.baseline_at
+		FROM wallet_baselines b
+		WHERE b.baseline_at <= ? AND NOT EXISTS (
+			SELECT 1 FROM wallet_baselines l
+			WHERE l.user_id = b.user_id AND l.currency = b.currency AND l.purpose = b.purpose
+				AND l.baseline_at > b.baseline_at AND l.baseline_at <= ?
+		)
+		ORDER BY b.user_id, b.currency, b.purpose`)
+
+	rows, err := conn(ctx, r.handler).QueryContext(ctx, query, at.UTC(), at.UTC())
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+	}
+	defer rows.Close()
+
+	var baselines []entity.WalletBaseline
+	for rows.Next() {
+		var row walletBaselineRow
+		if err := rows.Scan(&row.UserID, &row.Currency, &row.Purpose, &row.Amount, &row.BaselineAt); err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+		}
+
+		baseline, err := row.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+		}
+		baselines = append(baselines, baseline)
+	}
+
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrFindWalletBaselines.Error())
+	}
+
+	return baselines, nil
+}
+
+// SaveBaselines perform upsert into database, the baselines in one transaction
+func (r reconciliationRepository) SaveBaselines(ctx context.Context, baselines []entity.WalletBaseline) error {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "upsert", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		INSERT INTO wallet_baselines (user_id, currency, purpose, amount, baseline_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, currency, purpose, baseline_at) DO UPDATE SET amount = excluded.amount`)
+
+	err := inTransaction(ctx, r.handler, func(ctx context.Context) error {
+		for _, b := range baselines {
+			if _, err := conn(ctx, r.handler).ExecContext(
+				ctx,
+				query,
+				b.User().Value(),
+				b.Currency().String(),
+				b.Purpose().String(),
+				b.Amount(),
+				b.At().UTC(),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrSaveWalletBaselines.Error())
+	}
+
+	return nil
+}
+
+// Checkpoint perform select into database, the latest checkpoint at or before the time
+func (r reconciliationRepository) Checkpoint(ctx context.Context, at time.Time) (time.Time, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", "reconciliation_checkpoints")
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		SELECT checkpoint_at FROM reconciliation_checkpoints
+		WHERE checkpoint_at <= ?
+		ORDER BY checkpoint_at DESC
+		LIMIT 1`)
+
+	var checkpoint time.Time
+	err := conn(ctx, r.handler).QueryRowContext(ctx, query, at.UTC()).Scan(&checkpoint)
+	switch err {
+	case nil:
+		return checkpoint.UTC(), nil
+	case sql.ErrNoRows:
+		return time.Time{}, nil
+	default:
+		recordError(span, err)
+		return time.Time{}, errors.Wrap(err, entity.ErrFindReconciliationCheckpoint.Error())
+	}
+}
+
+// SaveCheckpoint perform insert into database, the baselines and the checkpoint in one transaction
+func (r reconciliationRepository) SaveCheckpoint(ctx context.Context, at time.Time, baselines []entity.WalletBaseline) error {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "insert", "reconciliation_checkpoints")
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		INSERT INTO reconciliation_checkpoints (checkpoint_at) VALUES (?)
+		ON CONFLICT (checkpoint_at) DO NOTHING`)
+
+	err := inTransaction(ctx, r.handler, func(ctx context.Context) error {
+		if err := r.SaveBaselines(ctx, baselines); err != nil {
+			return err
+		}
+
+		_, err := conn(ctx, r.handler).ExecContext(ctx, query, at.UTC().Truncate(time.Millisecond))
+		return err
+	})
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrSaveReconciliationCheckpoint.Error())
+	}
+
+	return nil
+}
+
+// Lock perform upsert into database, taking the lock when it expired or is already held by the owner
+func (r reconciliationRepository) Lock(ctx context.Context, owner string, at, expiresAt time.Time) error {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "upsert", "reconciliation_locks")
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		INSERT INTO reconciliation_locks (name, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE reconciliation_locks.expires_at <= ? OR reconciliation_locks.owner = excluded.owner`)
+
+	res, err := conn(ctx, r.handler).ExecContext(ctx, query, reconciliationLock, owner, expiresAt.UTC(), at.UTC())
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrLockReconciliation.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrLockReconciliation.Error())
+	}
+
+	if affected == 0 {
+		return entity.ErrReconciliationLocked
+	}
+
+	return nil
+}
+
+// Unlock perform delete into database, releasing the lock held by the owner
+func (r reconciliationRepository) Unlock(ctx context.Context, owner string) error {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "delete", "reconciliation_locks")
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `DELETE FROM reconciliation_locks WHERE name = ? AND owner = ?`)
+
+	if _, err := conn(ctx, r.handler).ExecContext(ctx, query, reconciliationLock, owner); err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrLockReconciliation.Error())
+	}
+
+	return nil
+}
+
+func (r walletBaselineRow) toEntity() (entity.WalletBaseline, error) {
+	userID, err := vo.NewUuid(r.UserID)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	purpose, err := vo.NewWalletPurpose(r.Purpose)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	return entity.NewWalletBaseline(userID, currency, purpose, r.Amount, r.BaselineAt), nil
+}
@@ -0,0 +1,293 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const reviewColumns = `id, transfer_id, payer_id, currency, value, reason, status, reviewer, note,
+	created_at, expires_at, decided_at`
+
+type (
+	// Row data
+	reviewRow struct {
+		ID         string
+		TransferID string
+		PayerID    string
+		Currency   string
+		Value      int64
+		Reason     string
+		Status     string
+		Reviewer   string
+		Note       string
+		CreatedAt  time.Time
+		ExpiresAt  time.Time
+		DecidedAt  sql.NullTime
+	}
+
+	reviewRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewReviewRepository create new reviewRepository with its dependencies
+func NewReviewRepository(handler *database.SQLHandler) entity.ReviewRepository {
+	return reviewRepository{
+		handler: handler,
+		table:   "reviews",
+	}
+}
+
+// Create perform insert into database
+func (r reviewRepository) Create(ctx context.Context, review entity.Review) (entity.Review, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "insert", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		INSERT INTO reviews (`+reviewColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	if _, err := conn(ctx, r.handler).ExecContext(
+		ctx,
+		query,
+		review.ID().Value(),
+		review.TransferID().Value(),
+		review.Payer().Value(),
+		review.Value().Currency().String(),
+		review.Value().Amount().Value(),
+		review.Reason(),
+		review.Status().String(),
+		review.Reviewer(),
+		review.Note(),
+		review.CreatedAt().UTC(),
+		review.ExpiresAt().UTC(),
+		decidedAtArg(review),
+	); err != nil {
+		recordError(span, err)
+		return entity.Review{}, errors.Wrap(err, entity.ErrCreateReview.Error())
+	}
+
+	return review, nil
+}
+
+// FindByID perform select into database
+func (r reviewRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Review, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `SELECT `+reviewColumns+` FROM reviews WHERE id = ?`)
+
+	row, err := scanReview(conn(ctx, r.handler).QueryRowContext(ctx, query, ID.Value()))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Review{}, entity.ErrNotFoundReview
+		default:
+			recordError(span, err)
+			return entity.Review{}, errors.Wrap(err, entity.ErrFindReview.Error())
+		}
+	}
+
+	return row.toEntity()
+}
+
+// FindByStatus perform select into database, oldest reviews first
+func (r reviewRepository) FindByStatus(ctx context.Context, status vo.ReviewStatus, limit int) ([]entity.Review, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		SELECT `+reviewColumns+` FROM reviews
+		WHERE status = ?
+		ORDER BY created_at
+		LIMIT ?`)
+
+	reviews, err := r.query(ctx, query, status.String(), limit)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// FindExpired perform select into database, oldest reviews first
+func (r reviewRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Review, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		SELECT `+reviewColumns+` FROM reviews
+		WHERE status = ? AND expires_at <= ?
+		ORDER BY created_at
+		LIMIT ?`)
+
+	reviews, err := r.query(ctx, query, vo.ReviewPending.String(), at.UTC(), limit)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Update perform update into database when the stored review is still pending
+func (r reviewRepository) Update(ctx context.Context, review entity.Review) error {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "update", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		UPDATE reviews SET status = ?, reviewer = ?, note = ?, decided_at = ?
+		WHERE id = ? AND status = ?`)
+
+	res, err := conn(ctx, r.handler).ExecContext(
+		ctx,
+		query,
+		review.Status().String(),
+		review.Reviewer(),
+		review.Note(),
+		decidedAtArg(review),
+		review.ID().Value(),
+		vo.ReviewPending.String(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(conflictError(err), entity.ErrUpdateReview.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateReview.Error())
+	}
+
+	if affected == 0 {
+		var exists int
+		err := conn(ctx, r.handler).
+			QueryRowContext(ctx, rebind(r.handler.Driver(), `SELECT COUNT(*) FROM reviews WHERE id = ?`), review.ID().Value()).
+			Scan(&exists)
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateReview.Error())
+		}
+
+		if exists == 0 {
+			return errors.Wrap(entity.ErrNotFoundReview, entity.ErrUpdateReview.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateReview.Error())
+	}
+
+	return nil
+}
+
+func (r reviewRepository) query(ctx context.Context, query string, args ...interface{}) ([]entity.Review, error) {
+	rows, err := conn(ctx, r.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindReview.Error())
+	}
+	defer rows.Close()
+
+	var reviews []entity.Review
+	for rows.Next() {
+		row, err := scanReview(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, entity.ErrFindReview.Error())
+		}
+
+		review, err := row.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindReview.Error())
+	}
+
+	return reviews, nil
+}
+
+// decidedAtArg returns when the review was decided as argument, NULL while it is pending
+func decidedAtArg(r entity.Review) sql.NullTime {
+	return sql.NullTime{Time: r.DecidedAt().UTC(), Valid: !r.DecidedAt().IsZero()}
+}
+
+func scanReview(s scanner) (reviewRow, error) {
+	var row reviewRow
+	err := s.Scan(
+		&row.ID,
+		&row.TransferID,
+		&row.PayerID,
+		&row.Currency,
+		&row.Value,
+		&row.Reason,
+		&row.Status,
+		&row.Reviewer,
+		&row.Note,
+		&row.CreatedAt,
+		&row.ExpiresAt,
+		&row.DecidedAt,
+	)
+
+	return row, err
+}
+
+func (r reviewRow) toEntity() (entity.Review, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	transferID, err := vo.NewUuid(r.TransferID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	amount, err := vo.NewAmount(r.Value)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	status, err := vo.NewReviewStatus(r.Status)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	var decidedAt time.Time
+	if r.DecidedAt.Valid {
+		decidedAt = r.DecidedAt.Time
+	}
+
+	return entity.RestoreReview(
+		id,
+		transferID,
+		payer,
+		vo.NewMoney(currency, amount),
+		r.Reason,
+		status,
+		r.Reviewer,
+		r.Note,
+		r.CreatedAt,
+		r.ExpiresAt,
+		decidedAt,
+	), nil
+}
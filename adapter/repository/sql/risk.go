@@ -0,0 +1,191 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Row data
+	riskAssessmentRow struct {
+		ID         string
+		TransferID string
+		PayerID    string
+		Score      int
+		Decision   string
+		CreatedAt  time.Time
+	}
+
+	// Row data
+	riskHitRow struct {
+		Signal string
+		Score  int
+		Detail string
+	}
+
+	riskRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewRiskRepository create new riskRepository with its dependencies
+func NewRiskRepository(handler *database.SQLHandler) entity.RiskAssessmentRepository {
+	return riskRepository{
+		handler: handler,
+		table:   "risk_assessments",
+	}
+}
+
+// Create perform insert into database, the assessment and its hits in one transaction
+func (r riskRepository) Create(ctx context.Context, assessment entity.RiskAssessment) (entity.RiskAssessment, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "insert", r.table)
+	defer span.End()
+
+	assessmentQuery := rebind(r.handler.Driver(), `
+		INSERT INTO risk_assessments (id, transfer_id, payer_id, score, decision, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	hitQuery := rebind(r.handler.Driver(), `
+		INSERT INTO risk_hits (assessment_id, position, signal, score, detail)
+		VALUES (?, ?, ?, ?, ?)`)
+
+	err := inTransaction(ctx, r.handler, func(ctx context.Context) error {
+		if _, err := conn(ctx, r.handler).ExecContext(
+			ctx,
+			assessmentQuery,
+			assessment.ID().Value(),
+			assessment.TransferID().Value(),
+			assessment.Payer().Value(),
+			assessment.Score(),
+			assessment.Decision().String(),
+			assessment.CreatedAt().UTC(),
+		); err != nil {
+			return err
+		}
+
+		for position, hit := range assessment.Hits() {
+			if _, err := conn(ctx, r.handler).ExecContext(
+				ctx,
+				hitQuery,
+				assessment.ID().Value(),
+				position,
+				hit.Signal().String(),
+				hit.Score(),
+				hit.Detail(),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		recordError(span, err)
+		return entity.RiskAssessment{}, errors.Wrap(err, entity.ErrCreateRiskAssessment.Error())
+	}
+
+	return assessment, nil
+}
+
+// FindByTransferID perform select into database, the latest assessment of the transfer
+func (r riskRepository) FindByTransferID(ctx context.Context, transferID vo.Uuid) (entity.RiskAssessment, error) {
+	ctx, span := startSpan(ctx, r.handler.Driver(), "select", r.table)
+	defer span.End()
+
+	query := rebind(r.handler.Driver(), `
+		SELECT id, transfer_id, payer_id, score, decision, created_at
+		FROM risk_assessments WHERE transfer_id = ?
+		ORDER BY created_at DESC LIMIT 1`)
+
+	var row riskAssessmentRow
+	err := conn(ctx, r.handler).QueryRowContext(ctx, query, transferID.Value()).Scan(
+		&row.ID,
+		&row.TransferID,
+		&row.PayerID,
+		&row.Score,
+		&row.Decision,
+		&row.CreatedAt,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.RiskAssessment{}, entity.ErrNotFoundRiskAssessment
+		default:
+			recordError(span, err)
+			return entity.RiskAssessment{}, errors.Wrap(err, entity.ErrFindRiskAssessment.Error())
+		}
+	}
+
+	hits, err := r.hits(ctx, row.ID)
+	if err != nil {
+		recordError(span, err)
+		return entity.RiskAssessment{}, err
+	}
+
+	return row.toEntity(hits)
+}
+
+// hits returns the hits of the assessment in the order they were scored
+func (r riskRepository) hits(ctx context.Context, assessmentID string) ([]entity.RiskHit, error) {
+	query := rebind(r.handler.Driver(), `
+		SELECT signal, score, detail
+		FROM risk_hits
+		WHERE assessment_id = ?
+		ORDER BY position`)
+
+	rows, err := conn(ctx, r.handler).QueryContext(ctx, query, assessmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindRiskAssessment.Error())
+	}
+	defer rows.Close()
+
+	var hits []entity.RiskHit
+	for rows.Next() {
+		var hitRow riskHitRow
+		if err := rows.Scan(&hitRow.Signal, &hitRow.Score, &hitRow.Detail); err != nil {
+			return nil, errors.Wrap(err, entity.ErrFindRiskAssessment.Error())
+		}
+
+		signal, err := vo.NewRiskSignal(hitRow.Signal)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, entity.NewRiskHit(signal, hitRow.Score, hitRow.Detail))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindRiskAssessment.Error())
+	}
+
+	return hits, nil
+}
+
+func (r riskAssessmentRow) toEntity(hits []entity.RiskHit) (entity.RiskAssessment, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	transferID, err := vo.NewUuid(r.TransferID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	decision, err := vo.NewRiskDecision(r.Decision)
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	return entity.RestoreRiskAssessment(id, transferID, payer, r.Score, decision, hits, r.CreatedAt), nil
+}
@@ -0,0 +1,303 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const scheduleColumns = `id, payer_id, payee_id, currency, value, frequency, day_of_month, end_at,
+	occurrence_limit, status, next_run_at, occurrences, last_error, created_at, updated_at`
+
+type (
+	// Row data
+	scheduleRow struct {
+		ID              string
+		PayerID         string
+		PayeeID         string
+		Currency        string
+		Value           int64
+		Frequency       string
+		DayOfMonth      int
+		EndAt           sql.NullTime
+		OccurrenceLimit int
+		Status          string
+		NextRunAt       time.Time
+		Occurrences     int
+		LastError       string
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+	}
+
+	// scanner is implemented by both *sql.Row and *sql.Rows
+	scanner interface {
+		Scan(dest ...interface{}) error
+	}
+
+	scheduleRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewScheduleRepository create new scheduleRepository with its dependencies
+func NewScheduleRepository(handler *database.SQLHandler) entity.ScheduleRepository {
+	return scheduleRepository{
+		handler: handler,
+		table:   "schedules",
+	}
+}
+
+// Create perform insert into database
+func (s scheduleRepository) Create(ctx context.Context, schedule entity.Schedule) (entity.Schedule, error) {
+	ctx, span := startSpan(ctx, s.handler.Driver(), "insert", s.table)
+	defer span.End()
+
+	query := rebind(s.handler.Driver(), `
+		INSERT INTO schedules (`+scheduleColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	if _, err := conn(ctx, s.handler).ExecContext(ctx, query, scheduleArgs(schedule)...); err != nil {
+		recordError(span, err)
+		return entity.Schedule{}, errors.Wrap(err, entity.ErrCreateSchedule.Error())
+	}
+
+	return schedule, nil
+}
+
+// FindByID perform select into database
+func (s scheduleRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.Schedule, error) {
+	ctx, span := startSpan(ctx, s.handler.Driver(), "select", s.table)
+	defer span.End()
+
+	query := rebind(s.handler.Driver(), `SELECT `+scheduleColumns+` FROM schedules WHERE id = ?`)
+
+	row, err := scanSchedule(conn(ctx, s.handler).QueryRowContext(ctx, query, ID.Value()))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.Schedule{}, entity.ErrNotFoundSchedule
+		default:
+			recordError(span, err)
+			return entity.Schedule{}, errors.Wrap(err, entity.ErrFindSchedule.Error())
+		}
+	}
+
+	return row.toEntity()
+}
+
+// FindByPayer perform select into database, oldest schedules first
+func (s scheduleRepository) FindByPayer(ctx context.Context, payerID vo.Uuid) ([]entity.Schedule, error) {
+	ctx, span := startSpan(ctx, s.handler.Driver(), "select", s.table)
+	defer span.End()
+
+	query := rebind(s.handler.Driver(), `
+		SELECT `+scheduleColumns+` FROM schedules
+		WHERE payer_id = ?
+		ORDER BY created_at`)
+
+	schedules, err := s.query(ctx, query, payerID.Value())
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// FindDue perform select into database, earliest runs first
+func (s scheduleRepository) FindDue(ctx context.Context, at time.Time, limit int) ([]entity.Schedule, error) {
+	ctx, span := startSpan(ctx, s.handler.Driver(), "select", s.table)
+	defer span.End()
+
+	query := rebind(s.handler.Driver(), `
+		SELECT `+scheduleColumns+` FROM schedules
+		WHERE status = ? AND next_run_at <= ?
+		ORDER BY next_run_at
+		LIMIT ?`)
+
+	schedules, err := s.query(ctx, query, vo.ScheduleActive.String(), at.UTC(), limit)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// Update perform update into database
+func (s scheduleRepository) Update(ctx context.Context, schedule entity.Schedule) error {
+	ctx, span := startSpan(ctx, s.handler.Driver(), "update", s.table)
+	defer span.End()
+
+	query := rebind(s.handler.Driver(), `
+		UPDATE schedules SET
+			status = ?, next_run_at = ?, occurrences = ?, last_error = ?, updated_at = ?
+		WHERE id = ?`)
+
+	res, err := conn(ctx, s.handler).ExecContext(
+		ctx,
+		query,
+		schedule.Status().String(),
+		schedule.NextRunAt().UTC(),
+		schedule.Occurrences(),
+		schedule.LastError(),
+		schedule.UpdatedAt().UTC(),
+		schedule.ID().Value(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateSchedule.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateSchedule.Error())
+	}
+
+	if affected == 0 {
+		return errors.Wrap(entity.ErrNotFoundSchedule, entity.ErrUpdateSchedule.Error())
+	}
+
+	return nil
+}
+
+func (s scheduleRepository) query(ctx context.Context, query string, args ...interface{}) ([]entity.Schedule, error) {
+	rows, err := conn(ctx, s.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindSchedule.Error())
+	}
+	defer rows.Close()
+
+	var schedules []entity.Schedule
+	for rows.Next() {
+		row, err := scanSchedule(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, entity.ErrFindSchedule.Error())
+		}
+
+		schedule, err := row.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, entity.ErrFindSchedule.Error())
+	}
+
+	return schedules, nil
+}
+
+func scheduleArgs(s entity.Schedule) []interface{} {
+	endAt := sql.NullTime{Time: s.Recurrence().EndAt().UTC(), Valid: !s.Recurrence().EndAt().IsZero()}
+
+	return []interface{}{
+		s.ID().Value(),
+		s.Payer().Value(),
+		s.Payee().Value(),
+		s.Value().Currency().String(),
+		s.Value().Amount().Value(),
+		s.Recurrence().Frequency().String(),
+		s.Recurrence().DayOfMonth(),
+		endAt,
+		s.Recurrence().Count(),
+		s.Status().String(),
+		s.NextRunAt().UTC(),
+		s.Occurrences(),
+		s.LastError(),
+		s.CreatedAt().UTC(),
+		s.UpdatedAt().UTC(),
+	}
+}
+
+func scanSchedule(s scanner) (scheduleRow, error) {
+	var row scheduleRow
+	err := s.Scan(
+		&row.ID,
+		&row.PayerID,
+		&row.PayeeID,
+		&row.Currency,
+		&row.Value,
+		&row.Frequency,
+		&row.DayOfMonth,
+		&row.EndAt,
+		&row.OccurrenceLimit,
+		&row.Status,
+		&row.NextRunAt,
+		&row.Occurrences,
+		&row.LastError,
+		&row.CreatedAt,
+		&row.UpdatedAt,
+	)
+
+	return row, err
+}
+
+func (r scheduleRow) toEntity() (entity.Schedule, error) {
+	id, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	payer, err := vo.NewUuid(r.PayerID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	payee, err := vo.NewUuid(r.PayeeID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	amount, err := vo.NewAmount(r.Value)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	frequency, err := vo.NewFrequency(r.Frequency)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	var endAt time.Time
+	if r.EndAt.Valid {
+		endAt = r.EndAt.Time
+	}
+
+	recurrence, err := vo.NewRecurrence(frequency, r.DayOfMonth, endAt, r.OccurrenceLimit)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	status, err := vo.NewScheduleStatus(r.Status)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	return entity.RestoreSchedule(
+		id,
+		payer,
+		payee,
+		vo.NewMoney(currency, amount),
+		recurrence,
+		status,
+		r.NextRunAt,
+		r.Occurrences,
+		r.LastError,
+		r.CreatedAt,
+		r.UpdatedAt,
+	), nil
+}
@@ -0,0 +1,176 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PostgreSQL codes of the errors raised when transactions conflict
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	uniqueViolation      = "23505"
+)
+
+// SQLite extended codes of the constraint violations on a unique key
+const (
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+var tracer = otel.Tracer("github.com/dungnguyen/clean-architecture/adapter/repository/sql")
+
+type (
+	// querier is implemented by both *sql.DB and *sql.Tx
+	querier interface {
+		ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	}
+
+	txKey struct{}
+)
+
+// withTx returns a copy of ctx carrying the transaction
+func withTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// txFromContext returns the transaction started by WithTransaction, if any
+func txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// conn returns the transaction of ctx or the database itself
+func conn(ctx context.Context, handler *database.SQLHandler) querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+
+	return handler.DB()
+}
+
+// inTransaction runs fn with the transaction of ctx, or in a new transaction
+// committed when fn returns nil and rolled back otherwise
+func inTransaction(ctx context.Context, handler *database.SQLHandler, fn func(context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := handler.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(withTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Wrap(err, rbErr.Error())
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// rebind converts the ? placeholders to the syntax of the driver
+func rebind(driver, query string) string {
+	if driver != database.DriverPostgres {
+		return query
+	}
+
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// pageLimit returns the LIMIT of a page, every row when the limit is not positive
+func pageLimit(limit int) int64 {
+	if limit <= 0 {
+		return math.MaxInt64
+	}
+
+	return int64(limit)
+}
+
+// forUpdate returns the row lock clause when ctx is in a transaction and the driver supports it
+func forUpdate(ctx context.Context, driver string) string {
+	if _, ok := txFromContext(ctx); ok && driver == database.DriverPostgres {
+		return " FOR UPDATE"
+	}
+
+	return ""
+}
+
+// conflictError maps the errors raised when transactions conflict to entity.ErrConcurrentModification
+func conflictError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected) {
+		return errors.Wrap(entity.ErrConcurrentModification, err.Error())
+	}
+
+	return err
+}
+
+// isUniqueViolation reports whether err was raised by a duplicate primary or unique key
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == uniqueViolation
+	}
+
+	// implemented by the errors of the sqlite driver
+	var coder interface{ Code() int }
+	if errors.As(err, &coder) {
+		return coder.Code() == sqliteConstraintPrimaryKey || coder.Code() == sqliteConstraintUnique
+	}
+
+	return false
+}
+
+// startSpan starts a client span for a database operation on the table
+func startSpan(ctx context.Context, driver, operation, table string) (context.Context, trace.Span) {
+	return tracer.Start(
+		ctx,
+		driver+"."+table+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", driver),
+			attribute.String("db.operation", operation),
+			attribute.String("db.sql.table", table),
+		),
+	)
+}
+
+// recordError marks the span as failed when err is not nil
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+type updateTransferRepository struct {
+	handler *database.SQLHandler
+	table   string
+}
+
+// NewUpdateTransferRepository create new updateTransferRepository with its dependencies
+func NewUpdateTransferRepository(handler *database.SQLHandler) entity.TransferRepositoryUpdater {
+	return updateTransferRepository{
+		handler: handler,
+		table:   "transfers",
+	}
+}
+
+// UpdateStatus perform update into database
+func (u updateTransferRepository) UpdateStatus(ctx context.Context, ID vo.Uuid, status vo.TransferStatus) error {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "update", u.table)
+	defer span.End()
+
+	query := rebind(u.handler.Driver(), `UPDATE transfers SET status = ? WHERE id = ?`)
+
+	res, err := conn(ctx, u.handler).ExecContext(ctx, query, status.String(), ID.Value())
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(conflictError(err), entity.ErrUpdateTransfer.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateTransfer.Error())
+	}
+
+	if affected == 0 {
+		return errors.Wrap(entity.ErrNotFoundTransfer, entity.ErrUpdateTransfer.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,517 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+)
+
+const userColumns = `id, full_name, email, password, document_type, document_value,
+	can_transfer, type, created_at, email_changed_at`
+
+type (
+	// Row data
+	userRow struct {
+		ID             string
+		FullName       string
+		Email          string
+		Password       string
+		DocumentType   string
+		DocumentValue  string
+		CanTransfer    bool
+		Type           string
+		CreatedAt      time.Time
+		EmailChangedAt sql.NullTime
+	}
+
+	// Row data
+	walletRow struct {
+		Currency string
+		Purpose  string
+		Amount   int64
+		Held     int64
+		Version  int64
+	}
+
+	userRepository struct {
+		handler *database.SQLHandler
+		table   string
+	}
+)
+
+// NewUserRepository create new userRepository with its dependencies
+func NewUserRepository(handler *database.SQLHandler) entity.UserRepository {
+	return userRepository{
+		handler: handler,
+		table:   "users",
+	}
+}
+
+// Create perform insert into database, the wallets of the user are inserted in the same transaction
+func (u userRepository) Create(ctx context.Context, user entity.User) (entity.User, error) {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "insert", u.table)
+	defer span.End()
+
+	row := newUserRow(user)
+	query := rebind(u.handler.Driver(), `
+		INSERT INTO users (`+userColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	err := inTransaction(ctx, u.handler, func(ctx context.Context) error {
+		if _, err := conn(ctx, u.handler).ExecContext(
+			ctx,
+			query,
+			row.ID,
+			row.FullName,
+			row.Email,
+			row.Password,
+			row.DocumentType,
+			row.DocumentValue,
+			row.CanTransfer,
+			row.Type,
+			row.CreatedAt,
+			row.EmailChangedAt,
+		); err != nil {
+			return err
+		}
+
+		return insertWallets(ctx, u.handler, user)
+	})
+	if err != nil {
+		recordError(span, err)
+		return entity.User{}, errors.Wrap(err, entity.ErrCreateUser.Error())
+	}
+
+	return user, nil
+}
+
+// FindByID perform select into database with the wallets of the user, locking the rows when called inside a transaction
+func (u userRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.User, error) {
+	return u.findOne(ctx, "id = ?", []interface{}{ID.Value()}, entity.ErrFindUserByID)
+}
+
+// FindByEmail perform select into database of the oldest user of the email with its wallets
+func (u userRepository) FindByEmail(ctx context.Context, email vo.Email) (entity.User, error) {
+	return u.findOne(ctx, "email = ?", []interface{}{email.Value()}, entity.ErrFindUser)
+}
+
+// FindByDocument perform select into database of the oldest user of the document with its wallets
+func (u userRepository) FindByDocument(ctx context.Context, doc vo.Document) (entity.User, error) {
+	return u.findOne(
+		ctx,
+		"document_type = ? AND document_value = ?",
+		[]interface{}{doc.Type().String(), doc.Value()},
+		entity.ErrFindUser,
+	)
+}
+
+// List perform select into database, oldest users first, then the wallets of every user
+func (u userRepository) List(ctx context.Context, f entity.UserFilter) ([]entity.User, error) {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "select", u.table)
+	defer span.End()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if f.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, f.Type.ToUpper().String())
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, f.To.UTC())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := rebind(u.handler.Driver(), `
+		SELECT `+userColumns+` FROM users
+		`+where+`
+		ORDER BY created_at, id
+		LIMIT ? OFFSET ?`)
+	args = append(args, pageLimit(f.Limit), f.Offset)
+
+	rows, err := conn(ctx, u.handler).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+	}
+
+	// the rows are read before the wallets are selected, SQLite having a single connection
+	var userRows []userRow
+	for rows.Next() {
+		row, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+		}
+		userRows = append(userRows, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+	}
+
+	users := make([]entity.User, 0, len(userRows))
+	for _, row := range userRows {
+		walletRows, err := findWallets(ctx, u.handler, row.ID)
+		if err != nil {
+			recordError(span, err)
+			return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+		}
+
+		user, err := row.toEntity(walletRows)
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// UpdateWallet perform update into database of the wallet of the same currency and purpose when the stored
+// wallet is still at the version of the wallet
+func (u userRepository) UpdateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "update", "wallets")
+	defer span.End()
+
+	query := rebind(u.handler.Driver(), `
+		UPDATE wallets SET amount = ?, held = ?, version = version + 1
+		WHERE user_id = ? AND currency = ? AND purpose = ? AND version = ?`)
+
+	res, err := conn(ctx, u.handler).ExecContext(
+		ctx,
+		query,
+		wallet.Money().Amount().Value(),
+		wallet.Held().Value(),
+		ID.Value(),
+		wallet.Money().Currency().String(),
+		wallet.Purpose().String(),
+		wallet.Version(),
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(conflictError(err), entity.ErrUpdateUserWallet.Error())
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateUserWallet.Error())
+	}
+
+	if affected == 0 {
+		var users, wallets int
+		err := conn(ctx, u.handler).
+			QueryRowContext(
+				ctx,
+				rebind(u.handler.Driver(), `
+					SELECT
+						(SELECT COUNT(*) FROM users WHERE id = ?),
+						(SELECT COUNT(*) FROM wallets WHERE user_id = ? AND currency = ? AND purpose = ?)`),
+				ID.Value(),
+				ID.Value(),
+				wallet.Money().Currency().String(),
+				wallet.Purpose().String(),
+			).
+			Scan(&users, &wallets)
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateUserWallet.Error())
+		}
+
+		switch {
+		case users == 0:
+			return errors.Wrap(entity.ErrNotFoundUser, entity.ErrUpdateUserWallet.Error())
+		case wallets == 0:
+			return errors.Wrap(entity.ErrNotFoundWallet, entity.ErrUpdateUserWallet.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateUserWallet.Error())
+	}
+
+	return nil
+}
+
+// CreateWallet perform insert into database, entity.ErrWalletAlreadyExists when the user has a wallet of the
+// same currency and purpose
+func (u userRepository) CreateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "insert", "wallets")
+	defer span.End()
+
+	var exists int
+	err := conn(ctx, u.handler).
+		QueryRowContext(ctx, rebind(u.handler.Driver(), `SELECT COUNT(*) FROM users WHERE id = ?`), ID.Value()).
+		Scan(&exists)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrCreateWallet.Error())
+	}
+
+	if exists == 0 {
+		return errors.Wrap(entity.ErrNotFoundUser, entity.ErrCreateWallet.Error())
+	}
+
+	// the wallet goes after the ones the user already has
+	query := rebind(u.handler.Driver(), `
+		INSERT INTO wallets (user_id, currency, purpose, position, amount, held, version)
+		SELECT ?, ?, ?, COALESCE(MAX(position), -1) + 1, ?, ?, ? FROM wallets WHERE user_id = ?`)
+
+	row := newWalletRow(wallet)
+	if _, err := conn(ctx, u.handler).ExecContext(
+		ctx,
+		query,
+		ID.Value(),
+		row.Currency,
+		row.Purpose,
+		row.Amount,
+		row.Held,
+		row.Version,
+		ID.Value(),
+	); err != nil {
+		recordError(span, err)
+		if isUniqueViolation(err) {
+			return errors.Wrap(entity.ErrWalletAlreadyExists, entity.ErrCreateWallet.Error())
+		}
+		return errors.Wrap(err, entity.ErrCreateWallet.Error())
+	}
+
+	return nil
+}
+
+// findOne perform select into database of the oldest user matching the condition with its wallets, locking
+// the rows when called inside a transaction. The errors of the database are wrapped with errFind.
+func (u userRepository) findOne(ctx context.Context, condition string, args []interface{}, errFind error) (entity.User, error) {
+	ctx, span := startSpan(ctx, u.handler.Driver(), "select", u.table)
+	defer span.End()
+
+	query := rebind(u.handler.Driver(), `
+		SELECT `+userColumns+` FROM users
+		WHERE `+condition+`
+		ORDER BY created_at, id
+		LIMIT 1`+forUpdate(ctx, u.handler.Driver()))
+
+	row, err := scanUser(conn(ctx, u.handler).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return entity.User{}, entity.ErrNotFoundUser
+		default:
+			recordError(span, err)
+			return entity.User{}, errors.Wrap(err, errFind.Error())
+		}
+	}
+
+	walletRows, err := findWallets(ctx, u.handler, row.ID)
+	if err != nil {
+		recordError(span, err)
+		return entity.User{}, errors.Wrap(err, errFind.Error())
+	}
+
+	user, err := row.toEntity(walletRows)
+	if err != nil {
+		recordError(span, err)
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// insertWallets inserts the wallets of the user in their order
+func insertWallets(ctx context.Context, handler *database.SQLHandler, u entity.User) error {
+	query := rebind(handler.Driver(), `
+		INSERT INTO wallets (user_id, currency, purpose, position, amount, held, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+
+	for position, w := range u.Wallets() {
+		row := newWalletRow(w)
+		if _, err := conn(ctx, handler).ExecContext(
+			ctx,
+			query,
+			u.ID().Value(),
+			row.Currency,
+			row.Purpose,
+			position,
+			row.Amount,
+			row.Held,
+			row.Version,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findWallets selects the wallets of the user in their order, locking the rows when called inside a transaction
+func findWallets(ctx context.Context, handler *database.SQLHandler, userID string) ([]walletRow, error) {
+	query := rebind(handler.Driver(), `
+		SELECT currency, purpose, amount, held, version
+		FROM wallets
+		WHERE user_id = ?
+		ORDER BY position, currency, purpose`+forUpdate(ctx, handler.Driver()))
+
+	rows, err := conn(ctx, handler).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wallets []walletRow
+	for rows.Next() {
+		var row walletRow
+		if err := rows.Scan(&row.Currency, &row.Purpose, &row.Amount, &row.Held, &row.Version); err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, row)
+	}
+
+	return wallets, rows.Err()
+}
+
+func scanUser(s scanner) (userRow, error) {
+	var row userRow
+	err := s.Scan(
+		&row.ID,
+		&row.FullName,
+		&row.Email,
+		&row.Password,
+		&row.DocumentType,
+		&row.DocumentValue,
+		&row.CanTransfer,
+		&row.Type,
+		&row.CreatedAt,
+		&row.EmailChangedAt,
+	)
+
+	return row, err
+}
+
+func newUserRow(u entity.User) userRow {
+	return userRow{
+		ID:             u.ID().Value(),
+		FullName:       u.FullName().Value(),
+		Email:          u.Email().Value(),
+		Password:       u.Password().Value(),
+		DocumentType:   u.Document().Type().String(),
+		DocumentValue:  u.Document().Value(),
+		CanTransfer:    u.Roles().CanTransfer,
+		Type:           u.TypeUser().String(),
+		CreatedAt:      u.CreatedAt().UTC(),
+		EmailChangedAt: sql.NullTime{Time: u.EmailChangedAt().UTC(), Valid: !u.EmailChangedAt().IsZero()},
+	}
+}
+
+func newWalletRow(w *vo.Wallet) walletRow {
+	return walletRow{
+		Currency: w.Money().Currency().String(),
+		Purpose:  w.Purpose().String(),
+		Amount:   w.Money().Amount().Value(),
+		Held:     w.Held().Value(),
+		Version:  w.Version(),
+	}
+}
+
+// toEntity rebuilds the user of the rows, entity.ErrCorruptedUser when they do not hold a valid user
+func (r userRow) toEntity(walletRows []walletRow) (entity.User, error) {
+	u, err := r.mapEntity(walletRows)
+	if err != nil {
+		return entity.User{}, errors.Wrapf(entity.ErrCorruptedUser, "user %q: %s", r.ID, err)
+	}
+
+	return u, nil
+}
+
+func (r userRow) mapEntity(walletRows []walletRow) (entity.User, error) {
+	if len(walletRows) == 0 {
+		return entity.User{}, entity.ErrNotFoundWallet
+	}
+
+	wallets := make([]*vo.Wallet, 0, len(walletRows))
+	for _, w := range walletRows {
+		wallet, err := w.toWallet()
+		if err != nil {
+			return entity.User{}, err
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	uuid, err := vo.NewUuid(r.ID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	email, err := vo.NewEmail(r.Email)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	doc, err := vo.NewDocument(vo.TypeDocument(r.DocumentType), r.DocumentValue)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	u, err := entity.NewUser(
+		uuid,
+		vo.NewFullName(r.FullName),
+		email,
+		vo.NewPassword(r.Password),
+		doc,
+		wallets[0],
+		vo.TypeUser(r.Type),
+		r.CreatedAt,
+	)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	u = u.WithWallets(wallets)
+
+	if r.EmailChangedAt.Valid {
+		u = u.WithEmailChangedAt(r.EmailChangedAt.Time)
+	}
+
+	return u, nil
+}
+
+func (r walletRow) toWallet() (*vo.Wallet, error) {
+	currency, err := vo.NewCurrency(r.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose, err := vo.NewWalletPurpose(r.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := vo.NewAmount(r.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := vo.NewAmount(r.Held)
+	if err != nil {
+		return nil, err
+	}
+
+	return vo.RestoreWallet(vo.NewMoney(currency, amount), purpose, held, r.Version), nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/dungnguyen/clean-architecture/adapter/repository")
+
+// startSpan starts a client span for a database operation on the collection
+func startSpan(ctx context.Context, operation, collection string) (context.Context, trace.Span) {
+	return tracer.Start(
+		ctx,
+		"mongodb."+collection+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.mongodb.collection", collection),
+		),
+	)
+}
+
+// recordError marks the span as failed when err is not nil
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type updateTransferRepository struct {
+	handler    *database.MongoHandler
+	collection string
+}
+
+// NewUpdateTransferRepository create new updateTransferRepository with its dependencies
+func NewUpdateTransferRepository(handler *database.MongoHandler) entity.TransferRepositoryUpdater {
+	return updateTransferRepository{
+		handler:    handler,
+		collection: "transfers",
+	}
+}
+
+// UpdateStatus perform updateOne into database
+func (u updateTransferRepository) UpdateStatus(ctx context.Context, ID vo.Uuid, status vo.TransferStatus) error {
+	ctx, span := startSpan(ctx, "updateOne", u.collection)
+	defer span.End()
+
+	res, err := u.handler.Db().Collection(u.collection).UpdateOne(
+		ctx,
+		bson.M{"id": ID.Value()},
+		bson.M{"$set": bson.M{"status": status.String()}},
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateTransfer.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.Wrap(entity.ErrNotFoundTransfer, entity.ErrUpdateTransfer.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// oldestUsersFirst sorts the users by creation, the ID breaking the ties
+var oldestUsersFirst = bson.D{{Key: userCreatedAtField, Value: 1}, {Key: userIDField, Value: 1}}
+
+type userRepository struct {
+	handler    *database.MongoHandler
+	collection string
+}
+
+// NewUserRepository create new userRepository with its dependencies
+func NewUserRepository(handler *database.MongoHandler) entity.UserRepository {
+	return userRepository{
+		handler:    handler,
+		collection: "users",
+	}
+}
+
+// Create perform insertOne into database
+func (u userRepository) Create(ctx context.Context, user entity.User) (entity.User, error) {
+	ctx, span := startSpan(ctx, "insertOne", u.collection)
+	defer span.End()
+
+	if _, err := u.handler.Db().Collection(u.collection).InsertOne(ctx, newUserBSON(user)); err != nil {
+		recordError(span, err)
+		return entity.User{}, errors.Wrap(err, entity.ErrCreateUser.Error())
+	}
+
+	return user, nil
+}
+
+// FindByID perform findOne into database
+func (u userRepository) FindByID(ctx context.Context, ID vo.Uuid) (entity.User, error) {
+	return u.findOne(ctx, bson.M{userIDField: ID.Value()}, entity.ErrFindUserByID)
+}
+
+// FindByEmail perform findOne into database of the oldest user of the email
+func (u userRepository) FindByEmail(ctx context.Context, email vo.Email) (entity.User, error) {
+	return u.findOne(ctx, bson.M{userEmailField: email.Value()}, entity.ErrFindUser)
+}
+
+// FindByDocument perform findOne into database of the oldest user of the document
+func (u userRepository) FindByDocument(ctx context.Context, doc vo.Document) (entity.User, error) {
+	return u.findOne(ctx, bson.M{
+		userDocumentTypeField:  doc.Type().String(),
+		userDocumentValueField: doc.Value(),
+	}, entity.ErrFindUser)
+}
+
+// List perform find into database, oldest users first
+func (u userRepository) List(ctx context.Context, f entity.UserFilter) ([]entity.User, error) {
+	ctx, span := startSpan(ctx, "find", u.collection)
+	defer span.End()
+
+	filter := bson.M{}
+	if f.Type != "" {
+		filter[userTypeField] = f.Type.ToUpper().String()
+	}
+
+	createdAt := bson.M{}
+	if !f.From.IsZero() {
+		createdAt["$gte"] = f.From.UTC()
+	}
+	if !f.To.IsZero() {
+		createdAt["$lt"] = f.To.UTC()
+	}
+	if len(createdAt) > 0 {
+		filter[userCreatedAtField] = createdAt
+	}
+
+	opts := options.Find().
+		SetSort(oldestUsersFirst).
+		SetSkip(int64(f.Offset))
+	if f.Limit > 0 {
+		opts = opts.SetLimit(int64(f.Limit))
+	}
+
+	cursor, err := u.handler.Db().Collection(u.collection).Find(ctx, filter, opts)
+	if err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+	}
+
+	var docs []userBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		recordError(span, err)
+		return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+	}
+
+	users := make([]entity.User, 0, len(docs))
+	for _, doc := range docs {
+		user, err := doc.toEntity()
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// UpdateWallet perform updateOne into database of the wallet of the same currency and purpose when the
+// stored wallet is still at the version of the wallet. A document written before the users had several
+// wallets is upgraded when no wallet matches.
+func (u userRepository) UpdateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	ctx, span := startSpan(ctx, "updateOne", u.collection)
+	defer span.End()
+
+	versioned := walletFilter(wallet)
+	versioned[walletVersionField] = wallet.Version()
+
+	var (
+		collection = u.handler.Db().Collection(u.collection)
+		query      = bson.M{userIDField: ID.Value(), userWalletsField: bson.M{"$elemMatch": versioned}}
+		update     = bson.M{
+			"$set": bson.M{
+				userWalletsField + ".$." + walletAmountField: wallet.Money().Amount().Value(),
+				userWalletsField + ".$." + walletHeldField:   wallet.Held().Value(),
+			},
+			"$inc": bson.M{userWalletsField + ".$." + walletVersionField: 1},
+		}
+	)
+
+	res, err := collection.UpdateOne(ctx, query, update)
+	if err == nil && res.MatchedCount == 0 {
+		var upgraded bool
+		if upgraded, err = upgradeUserWallets(ctx, collection, ID); err == nil && upgraded {
+			res, err = collection.UpdateOne(ctx, query, update)
+		}
+	}
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrUpdateUserWallet.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := collection.CountDocuments(ctx, bson.M{userIDField: ID.Value()})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateUserWallet.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundUser, entity.ErrUpdateUserWallet.Error())
+		}
+
+		n, err = collection.CountDocuments(ctx, bson.M{
+			userIDField:      ID.Value(),
+			userWalletsField: bson.M{"$elemMatch": walletFilter(wallet)},
+		})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrUpdateUserWallet.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundWallet, entity.ErrUpdateUserWallet.Error())
+		}
+
+		return errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateUserWallet.Error())
+	}
+
+	return nil
+}
+
+// CreateWallet perform updateOne into database pushing the wallet, entity.ErrWalletAlreadyExists when the
+// user has a wallet of the same currency and purpose
+func (u userRepository) CreateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	ctx, span := startSpan(ctx, "updateOne", u.collection)
+	defer span.End()
+
+	collection := u.handler.Db().Collection(u.collection)
+
+	if _, err := upgradeUserWallets(ctx, collection, ID); err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrCreateWallet.Error())
+	}
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{
+			userIDField:      ID.Value(),
+			userWalletsField: bson.M{"$not": bson.M{"$elemMatch": walletFilter(wallet)}},
+		},
+		bson.M{"$push": bson.M{userWalletsField: newUserWalletBSON(wallet)}},
+	)
+	if err != nil {
+		recordError(span, err)
+		return errors.Wrap(err, entity.ErrCreateWallet.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		n, err := collection.CountDocuments(ctx, bson.M{userIDField: ID.Value()})
+		if err != nil {
+			recordError(span, err)
+			return errors.Wrap(err, entity.ErrCreateWallet.Error())
+		}
+
+		if n == 0 {
+			return errors.Wrap(entity.ErrNotFoundUser, entity.ErrCreateWallet.Error())
+		}
+
+		return errors.Wrap(entity.ErrWalletAlreadyExists, entity.ErrCreateWallet.Error())
+	}
+
+	return nil
+}
+
+// findOne perform findOne into database of the oldest user matching the filter, the errors
+// of the database being wrapped with errFind
+func (u userRepository) findOne(ctx context.Context, filter bson.M, errFind error) (entity.User, error) {
+	ctx, span := startSpan(ctx, "findOne", u.collection)
+	defer span.End()
+
+	var doc userBSON
+	err := u.handler.Db().Collection(u.collection).
+		FindOne(ctx, filter, options.FindOne().SetSort(oldestUsersFirst)).
+		Decode(&doc)
+	if err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return entity.User{}, entity.ErrNotFoundUser
+		default:
+			recordError(span, err)
+			return entity.User{}, errors.Wrap(err, errFind.Error())
+		}
+	}
+
+	user, err := doc.toEntity()
+	if err != nil {
+		recordError(span, err)
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
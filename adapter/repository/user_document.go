@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// the paths of the fields of userBSON the queries and the updates are made on
+const (
+	userIDField            = "id"
+	userEmailField         = "email"
+	userDocumentTypeField  = "document.type"
+	userDocumentValueField = "document.value"
+	userTypeField          = "type"
+	userCreatedAtField     = "created_at"
+	userWalletsField       = "wallets"
+	userLegacyWalletField  = "wallet"
+
+	walletCurrencyField = "currency"
+	walletPurposeField  = "purpose"
+	walletAmountField   = "amount"
+	walletHeldField     = "held"
+	walletVersionField  = "version"
+)
+
+type (
+	// Bson data, the document of a user in the users collection
+	userBSON struct {
+		ID        string           `bson:"id"`
+		FullName  string           `bson:"full_name"`
+		Email     string           `bson:"email"`
+		Password  string           `bson:"password"`
+		Document  userDocumentBSON `bson:"document"`
+		Wallets   []userWalletBSON `bson:"wallets"`
+		Roles     userRolesBSON    `bson:"roles"`
+		Type      string           `bson:"type"`
+		CreatedAt time.Time        `bson:"created_at"`
+		// EmailChangedAt is absent when the email never changed
+		EmailChangedAt *time.Time `bson:"email_changed_at,omitempty"`
+		// Wallet is the only wallet of the documents written before the users had several wallets
+		Wallet *legacyWalletBSON `bson:"wallet,omitempty"`
+	}
+
+	// Bson data
+	userDocumentBSON struct {
+		Type  string `bson:"type"`
+		Value string `bson:"value"`
+	}
+
+	// Bson data
+	userWalletBSON struct {
+		Currency string `bson:"currency"`
+		Purpose  string `bson:"purpose"`
+		Amount   int64  `bson:"amount"`
+		Held     int64  `bson:"held"`
+		Version  int64  `bson:"version"`
+	}
+
+	// Bson data
+	legacyWalletBSON struct {
+		Currency string `bson:"currency"`
+		Amount   int64  `bson:"amount"`
+		Held     int64  `bson:"held"`
+		Version  int64  `bson:"version"`
+	}
+
+	// Bson data
+	userRolesBSON struct {
+		CanTransfer bool `bson:"can_transfer"`
+	}
+)
+
+func newUserBSON(u entity.User) userBSON {
+	doc := userBSON{
+		ID:       u.ID().Value(),
+		FullName: u.FullName().Value(),
+		Email:    u.Email().Value(),
+		Password: u.Password().Value(),
+		Document: userDocumentBSON{
+			Type:  u.Document().Type().String(),
+			Value: u.Document().Value(),
+		},
+		Roles: userRolesBSON{
+			CanTransfer: u.Roles().CanTransfer,
+		},
+		Type:      u.TypeUser().String(),
+		CreatedAt: u.CreatedAt().UTC(),
+	}
+	for _, w := range u.Wallets() {
+		doc.Wallets = append(doc.Wallets, newUserWalletBSON(w))
+	}
+	if at := u.EmailChangedAt(); !at.IsZero() {
+		at = at.UTC()
+		doc.EmailChangedAt = &at
+	}
+
+	return doc
+}
+
+func newUserWalletBSON(w *vo.Wallet) userWalletBSON {
+	return userWalletBSON{
+		Currency: w.Money().Currency().String(),
+		Purpose:  w.Purpose().String(),
+		Amount:   w.Money().Amount().Value(),
+		Held:     w.Held().Value(),
+		Version:  w.Version(),
+	}
+}
+
+// toEntity rebuilds the user of the document, entity.ErrCorruptedUser when the
+// document does not hold a valid user. The documents written before the users
+// had several wallets have their only wallet as the main one.
+func (d userBSON) toEntity() (entity.User, error) {
+	u, err := d.mapEntity()
+	if err != nil {
+		return entity.User{}, errors.Wrapf(entity.ErrCorruptedUser, "user %q: %s", d.ID, err)
+	}
+
+	return u, nil
+}
+
+func (d userBSON) mapEntity() (entity.User, error) {
+	uuid, err := vo.NewUuid(d.ID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	email, err := vo.NewEmail(d.Email)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	doc, err := vo.NewDocument(vo.TypeDocument(d.Document.Type), d.Document.Value)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	walletDocs := d.Wallets
+	if len(walletDocs) == 0 && d.Wallet != nil {
+		walletDocs = []userWalletBSON{{
+			Currency: d.Wallet.Currency,
+			Amount:   d.Wallet.Amount,
+			Held:     d.Wallet.Held,
+			Version:  d.Wallet.Version,
+		}}
+	}
+
+	if len(walletDocs) == 0 {
+		return entity.User{}, entity.ErrNotFoundWallet
+	}
+
+	wallets := make([]*vo.Wallet, 0, len(walletDocs))
+	for _, w := range walletDocs {
+		wallet, err := w.toWallet()
+		if err != nil {
+			return entity.User{}, err
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	u, err := entity.NewUser(
+		uuid,
+		vo.NewFullName(d.FullName),
+		email,
+		vo.NewPassword(d.Password),
+		doc,
+		wallets[0],
+		vo.TypeUser(d.Type),
+		d.CreatedAt,
+	)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	u = u.WithWallets(wallets)
+
+	if d.EmailChangedAt != nil {
+		u = u.WithEmailChangedAt(*d.EmailChangedAt)
+	}
+
+	return u, nil
+}
+
+func (d userWalletBSON) toWallet() (*vo.Wallet, error) {
+	currency, err := vo.NewCurrency(d.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose, err := vo.NewWalletPurpose(d.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := vo.NewAmount(d.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := vo.NewAmount(d.Held)
+	if err != nil {
+		return nil, err
+	}
+
+	return vo.RestoreWallet(vo.NewMoney(currency, amount), purpose, held, d.Version), nil
+}
+
+// walletFilter matches the wallet of the currency for the purpose among the wallets of the user
+func walletFilter(wallet *vo.Wallet) bson.M {
+	return bson.M{
+		walletCurrencyField: wallet.Money().Currency().String(),
+		walletPurposeField:  wallet.Purpose().String(),
+	}
+}
+
+// upgradeUserWallets moves the single wallet of a user document written before
+// the users had several wallets into its wallets, as the main wallet of its
+// currency. It reports whether the document was upgraded.
+func upgradeUserWallets(ctx context.Context, collection *mongo.Collection, ID vo.Uuid) (bool, error) {
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{
+			userIDField:           ID.Value(),
+			userWalletsField:      bson.M{"$exists": false},
+			userLegacyWalletField: bson.M{"$exists": true},
+		},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{userWalletsField: bson.A{bson.M{
+				walletCurrencyField: "$wallet.currency",
+				walletPurposeField:  vo.MainWallet.String(),
+				walletAmountField:   "$wallet.amount",
+				// documents written before the payments have no held amount,
+				// the ones written before the wallet was versioned are at version 0
+				walletHeldField:    bson.M{"$ifNull": bson.A{"$wallet.held", 0}},
+				walletVersionField: bson.M{"$ifNull": bson.A{"$wallet.version", 0}},
+			}}}}},
+			{{Key: "$unset", Value: userLegacyWalletField}},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
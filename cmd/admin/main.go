@@ -0,0 +1,67 @@
+// Command admin operates the service from the command line: it creates and
+// shows the users, credits and debits their wallets with a reason, lists the
+// transfers, replays their notifications, creates the MongoDB indexes,
+// exports the data and verifies the audit log.
+//
+// The storage is selected by STORAGE_DRIVER as for the HTTP server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/dungnguyen/clean-architecture/adapter/cli"
+	"github.com/dungnguyen/clean-architecture/infrastructure"
+)
+
+func main() {
+	var (
+		format = flag.String("format", cli.FormatTable, "output format, table, json or csv")
+		actor  = flag.String("actor", defaultActor(), "operator recorded in the audit log")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: admin [flags] COMMAND [arguments]\n\nflags:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nrun 'admin help' for the commands\n")
+	}
+	flag.Parse()
+
+	admin, err := infrastructure.NewAdmin(*actor)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	if flag.NArg() == 0 || flag.Arg(0) == "help" {
+		fmt.Println("commands:")
+		admin.Usage(os.Stdout)
+	} else {
+		err = admin.Run(ctx, os.Stdout, *format, flag.Args())
+	}
+
+	if closeErr := admin.Close(ctx); closeErr != nil {
+		fmt.Fprintln(os.Stderr, closeErr)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, cli.ErrUsage) {
+			fmt.Fprintln(os.Stderr, "run 'admin help' for the commands and their arguments")
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// defaultActor returns the name of the user running the command
+func defaultActor() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return "admin"
+}
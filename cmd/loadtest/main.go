@@ -0,0 +1,79 @@
+// Command loadtest sends concurrent random transfers to the API, prints the latency
+// percentiles and checks that the balances stayed consistent.
+//
+// Without -target the application is started in-process against the in-memory storage.
+// The CreateTransferInteractor benchmarks run with go test -bench ./infrastructure/loadtest.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/infrastructure/loadtest"
+)
+
+func main() {
+	cfg := loadtest.DefaultConfig()
+
+	target := flag.String("target", "", "base URL of the API, the application is started in-process when empty")
+	flag.StringVar(&cfg.AdminToken, "admin-token", os.Getenv("ADMIN_TOKEN"), "bearer token of the admin routes of the target")
+	flag.IntVar(&cfg.Users, "users", cfg.Users, "number of users created")
+	flag.Int64Var(&cfg.Balance, "balance", cfg.Balance, "initial balance of every user")
+	flag.IntVar(&cfg.Transfers, "transfers", cfg.Transfers, "number of transfers sent")
+	flag.IntVar(&cfg.Concurrency, "concurrency", cfg.Concurrency, "number of transfers in flight")
+	flag.Int64Var(&cfg.MaxValue, "max-value", cfg.MaxValue, "upper bound of the transfer values")
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "seed of the generated transfers")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	baseURL := *target
+	if baseURL == "" {
+		server, err := loadtest.NewServer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer server.Close(ctx)
+
+		baseURL = server.URL()
+		cfg.AdminToken = server.AdminToken()
+	}
+
+	report, err := loadtest.Run(ctx, baseURL, cfg)
+	printReport(cfg, report)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("invariants: ok")
+}
+
+func printReport(cfg loadtest.Config, r loadtest.Report) {
+	fmt.Printf("users: %d, transfers: %d, concurrency: %d, seed: %d\n", cfg.Users, r.Transfers, cfg.Concurrency, cfg.Seed)
+	fmt.Printf("succeeded: %d, rejected: %d, errors: %d\n", r.Succeeded, r.Rejected, r.Errors)
+
+	statuses := make([]int, 0, len(r.Statuses))
+	for s := range r.Statuses {
+		statuses = append(statuses, s)
+	}
+	sort.Ints(statuses)
+	for _, s := range statuses {
+		fmt.Printf("  %d: %d\n", s, r.Statuses[s])
+	}
+
+	fmt.Printf("duration: %s, throughput: %.1f req/s\n", r.Duration.Round(time.Millisecond), r.Throughput())
+	fmt.Printf("latency: mean %s, p50 %s, p90 %s, p95 %s, p99 %s, max %s\n",
+		r.Latencies.Mean().Round(time.Microsecond),
+		r.Latencies.Percentile(50).Round(time.Microsecond),
+		r.Latencies.Percentile(90).Round(time.Microsecond),
+		r.Latencies.Percentile(95).Round(time.Microsecond),
+		r.Latencies.Percentile(99).Round(time.Microsecond),
+		r.Latencies.Max().Round(time.Microsecond),
+	)
+}
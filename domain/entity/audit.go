@@ -0,0 +1,268 @@
+package entity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrCreateAuditEntry = errors.New("error creating audit entry")
+	ErrListAuditEntries = errors.New("error listing audit entries")
+	ErrAuditChainBroken = errors.New("audit log chain is broken")
+)
+
+type (
+	// AuditRepositoryCreator define the operation of appending an entry to the
+	// audit log, the log being append-only. The entry is appended pending, the
+	// chainer chaining it afterwards, so that the appends made in concurrent
+	// transactions do not conflict.
+	AuditRepositoryCreator interface {
+		Create(context.Context, AuditEntry) (AuditEntry, error)
+	}
+
+	// AuditRepositoryChainer define the chaining of the pending entries
+	AuditRepositoryChainer interface {
+		// ChainPending chains up to limit pending entries, the oldest first, to
+		// the last one chained and returns how many it chained. It returns
+		// ErrConcurrentModification when another chainer chained them meanwhile.
+		ChainPending(ctx context.Context, limit int) (int, error)
+	}
+
+	// AuditRepositoryLister defines the listing of the audit entries matching
+	// the filter, the chained ones in the order of the chain then the pending ones
+	AuditRepositoryLister interface {
+		List(context.Context, AuditFilter) ([]AuditEntry, error)
+	}
+
+	// AuditRepository groups the operations of the audit log
+	AuditRepository interface {
+		AuditRepositoryCreator
+		AuditRepositoryChainer
+		AuditRepositoryLister
+	}
+
+	// AuditFilter selects the audit entries listed. The zero values match every entry.
+	AuditFilter struct {
+		Actor         string
+		Action        vo.AuditAction
+		Target        vo.AuditTarget
+		SubjectID     vo.Uuid
+		CorrelationID string
+		// From and To match the entries created in [From, To)
+		From time.Time
+		To   time.Time
+		// FromSequence matches the chained entries from the sequence, when set
+		FromSequence int64
+		Offset       int
+		Limit        int
+	}
+
+	// AuditOrigin define the request an audited action was taken by
+	AuditOrigin struct {
+		CorrelationID string
+		IP            string
+		UserAgent     string
+	}
+
+	// AuditEntry define an action taken by someone on an entity. Before and
+	// after are the JSON snapshots of the entity, empty when it did not exist
+	// or is not recorded. The entries appended before the log was chained, and
+	// the pending ones, have no sequence.
+	AuditEntry struct {
+		id        vo.Uuid
+		action    vo.AuditAction
+		actor     string
+		target    vo.AuditTarget
+		subjectID vo.Uuid
+		detail    string
+		before    string
+		after     string
+		origin    AuditOrigin
+		createdAt time.Time
+		sequence  int64
+		prevHash  string
+		hash      string
+	}
+
+	// auditHashContent is the content of an entry its hash is computed on
+	auditHashContent struct {
+		Sequence      int64  `json:"sequence"`
+		PrevHash      string `json:"prev_hash"`
+		ID            string `json:"id"`
+		Action        string `json:"action"`
+		Actor         string `json:"actor"`
+		Target        string `json:"target"`
+		SubjectID     string `json:"subject_id"`
+		Detail        string `json:"detail"`
+		Before        string `json:"before"`
+		After         string `json:"after"`
+		CorrelationID string `json:"correlation_id"`
+		IP            string `json:"ip"`
+		UserAgent     string `json:"user_agent"`
+		CreatedAt     string `json:"created_at"`
+	}
+)
+
+// NewAuditEntry create new AuditEntry of the action taken by the actor on the subject.
+// The creation date is truncated to the millisecond so that every storage keeps it as hashed.
+func NewAuditEntry(
+	ID vo.Uuid,
+	action vo.AuditAction,
+	actor string,
+	subjectID vo.Uuid,
+	detail string,
+	createdAt time.Time,
+) AuditEntry {
+	return AuditEntry{
+		id:        ID,
+		action:    action,
+		actor:     actor,
+		subjectID: subjectID,
+		detail:    detail,
+		createdAt: createdAt.Truncate(time.Millisecond),
+	}
+}
+
+// WithTarget returns a copy of the entry taken on an entity of the target
+func (a AuditEntry) WithTarget(target vo.AuditTarget) AuditEntry {
+	a.target = target
+	return a
+}
+
+// WithSnapshots returns a copy of the entry with the JSON snapshots of its subject
+func (a AuditEntry) WithSnapshots(before, after string) AuditEntry {
+	a.before = before
+	a.after = after
+	return a
+}
+
+// WithOrigin returns a copy of the entry taken by the request
+func (a AuditEntry) WithOrigin(origin AuditOrigin) AuditEntry {
+	a.origin = origin
+	return a
+}
+
+// Chain returns a copy of the entry chained at the sequence after the entry
+// of the hash prevHash, empty for the first one, with its hash
+func (a AuditEntry) Chain(sequence int64, prevHash string) AuditEntry {
+	a.sequence = sequence
+	a.prevHash = prevHash
+	a.hash = a.computeHash()
+	return a
+}
+
+// WithChain returns a copy of the entry with the chain it was stored with
+func (a AuditEntry) WithChain(sequence int64, prevHash, hash string) AuditEntry {
+	a.sequence = sequence
+	a.prevHash = prevHash
+	a.hash = hash
+	return a
+}
+
+// VerifyChain checks the entry follows the previous one of the chain, the
+// zero AuditEntry for the first one, and was not changed since it was hashed
+func (a AuditEntry) VerifyChain(prev AuditEntry) error {
+	switch {
+	case a.sequence != prev.sequence+1:
+		return ErrAuditChainBroken
+	case a.prevHash != prev.hash:
+		return ErrAuditChainBroken
+	case a.hash != a.computeHash():
+		return ErrAuditChainBroken
+	}
+
+	return nil
+}
+
+func (a AuditEntry) computeHash() string {
+	content, _ := json.Marshal(auditHashContent{
+		Sequence:      a.sequence,
+		PrevHash:      a.prevHash,
+		ID:            a.id.Value(),
+		Action:        a.action.String(),
+		Actor:         a.actor,
+		Target:        a.target.String(),
+		SubjectID:     a.subjectID.Value(),
+		Detail:        a.detail,
+		Before:        a.before,
+		After:         a.after,
+		CorrelationID: a.origin.CorrelationID,
+		IP:            a.origin.IP,
+		UserAgent:     a.origin.UserAgent,
+		CreatedAt:     a.createdAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ID returns the id property
+func (a AuditEntry) ID() vo.Uuid {
+	return a.id
+}
+
+// Action returns the action property
+func (a AuditEntry) Action() vo.AuditAction {
+	return a.action
+}
+
+// Actor returns the actor property
+func (a AuditEntry) Actor() string {
+	return a.actor
+}
+
+// Target returns the kind of the entity the action was taken on
+func (a AuditEntry) Target() vo.AuditTarget {
+	return a.target
+}
+
+// SubjectID returns the ID of the entity the action was taken on
+func (a AuditEntry) SubjectID() vo.Uuid {
+	return a.subjectID
+}
+
+// Detail returns the detail property
+func (a AuditEntry) Detail() string {
+	return a.detail
+}
+
+// Before returns the JSON snapshot of the subject before the action
+func (a AuditEntry) Before() string {
+	return a.before
+}
+
+// After returns the JSON snapshot of the subject after the action
+func (a AuditEntry) After() string {
+	return a.after
+}
+
+// Origin returns the origin property
+func (a AuditEntry) Origin() AuditOrigin {
+	return a.origin
+}
+
+// CreatedAt returns the createdAt property
+func (a AuditEntry) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+// Sequence returns the position of the entry in the chain, 0 when it is not chained
+func (a AuditEntry) Sequence() int64 {
+	return a.sequence
+}
+
+// PrevHash returns the hash of the previous entry of the chain
+func (a AuditEntry) PrevHash() string {
+	return a.prevHash
+}
+
+// Hash returns the hash of the entry, chaining it to the previous one
+func (a AuditEntry) Hash() string {
+	return a.hash
+}
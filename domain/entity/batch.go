@@ -0,0 +1,327 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+// MaxBatchItems is the largest number of transfers accepted in a batch
+const MaxBatchItems = 1000
+
+var (
+	ErrNotFoundBatch = errors.New("not found batch")
+
+	ErrCreateBatch = errors.New("error creating batch")
+
+	ErrFindBatch = errors.New("error fetching batch")
+
+	ErrUpdateBatch = errors.New("error updating batch")
+
+	ErrEmptyBatch = errors.New("batch must have at least one transfer")
+
+	ErrBatchTooLarge = errors.New("batch has too many transfers")
+
+	ErrBatchFinished = errors.New("batch is already finished")
+)
+
+type (
+	// BatchRepositoryCreator define the operation of creating a batch entity
+	BatchRepositoryCreator interface {
+		Create(context.Context, Batch) (Batch, error)
+	}
+
+	// BatchRepositoryFinder define the search operations of batch entities
+	BatchRepositoryFinder interface {
+		FindByID(context.Context, vo.Uuid) (Batch, error)
+		// FindUnfinished returns up to limit pending or processing batches, oldest first
+		FindUnfinished(ctx context.Context, limit int) ([]Batch, error)
+	}
+
+	// BatchRepositoryUpdater define the update operations of a batch entity
+	BatchRepositoryUpdater interface {
+		// Update saves the status of the batch and of every of its items
+		Update(context.Context, Batch) error
+		// UpdateItem saves the status of the batch and of its item at the index
+		UpdateItem(ctx context.Context, batch Batch, index int) error
+	}
+
+	// BatchRepository groups the operations on batch entities
+	BatchRepository interface {
+		BatchRepositoryCreator
+		BatchRepositoryFinder
+		BatchRepositoryUpdater
+	}
+
+	// Batch define a group of transfers from one payer submitted at once
+	Batch struct {
+		id        vo.Uuid
+		payer     vo.Uuid
+		mode      vo.BatchMode
+		status    vo.BatchStatus
+		items     []BatchItem
+		createdAt time.Time
+		updatedAt time.Time
+	}
+
+	// BatchItem define a transfer of a batch and its result
+	BatchItem struct {
+		transferID vo.Uuid
+		payee      vo.Uuid
+		value      vo.Money
+		status     vo.BatchItemStatus
+		reason     string
+	}
+)
+
+// NewBatchItem create new pending batch item, transferID being the ID of the transfer it creates
+func NewBatchItem(transferID vo.Uuid, payeeID vo.Uuid, value vo.Money) BatchItem {
+	return BatchItem{
+		transferID: transferID,
+		payee:      payeeID,
+		value:      value,
+		status:     vo.BatchItemPending,
+	}
+}
+
+// RestoreBatchItem create a batch item from its stored state
+func RestoreBatchItem(
+	transferID vo.Uuid,
+	payeeID vo.Uuid,
+	value vo.Money,
+	status vo.BatchItemStatus,
+	reason string,
+) BatchItem {
+	return BatchItem{
+		transferID: transferID,
+		payee:      payeeID,
+		value:      value,
+		status:     status,
+		reason:     reason,
+	}
+}
+
+// NewBatch create new pending batch
+func NewBatch(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	mode vo.BatchMode,
+	items []BatchItem,
+	createdAt time.Time,
+) (Batch, error) {
+	if len(items) == 0 {
+		return Batch{}, ErrEmptyBatch
+	}
+
+	if len(items) > MaxBatchItems {
+		return Batch{}, ErrBatchTooLarge
+	}
+
+	for _, item := range items {
+		if payerID.Equals(item.payee) {
+			return Batch{}, ErrSamePayerAndPayee
+		}
+	}
+
+	return Batch{
+		id:        ID,
+		payer:     payerID,
+		mode:      mode,
+		status:    vo.BatchPending,
+		items:     append([]BatchItem(nil), items...),
+		createdAt: createdAt,
+		updatedAt: createdAt,
+	}, nil
+}
+
+// RestoreBatch create a batch from its stored state
+func RestoreBatch(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	mode vo.BatchMode,
+	status vo.BatchStatus,
+	items []BatchItem,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Batch {
+	return Batch{
+		id:        ID,
+		payer:     payerID,
+		mode:      mode,
+		status:    status,
+		items:     items,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// Start marks the batch as processing, a batch interrupted while processing can be started again
+func (b *Batch) Start(at time.Time) error {
+	if b.Finished() {
+		return ErrBatchFinished
+	}
+
+	b.status = vo.BatchProcessing
+	b.updatedAt = at
+
+	return nil
+}
+
+// Succeed records that the transfer of the item at the index was created
+func (b *Batch) Succeed(index int, at time.Time) {
+	b.settle(index, vo.BatchItemSucceeded, "", at)
+}
+
+// Fail records why the transfer of the item at the index was refused
+func (b *Batch) Fail(index int, err error, at time.Time) {
+	b.settle(index, vo.BatchItemFailed, err.Error(), at)
+}
+
+// Rollback fails an atomic batch: the item at the index fails with err and the
+// other items are skipped. An index out of range fails every item with err.
+func (b *Batch) Rollback(index int, err error, at time.Time) {
+	for i := range b.items {
+		switch {
+		case i == index:
+			b.settle(i, vo.BatchItemFailed, err.Error(), at)
+		case index < 0 || index >= len(b.items):
+			b.settle(i, vo.BatchItemFailed, err.Error(), at)
+		default:
+			b.settle(i, vo.BatchItemSkipped, "batch rolled back", at)
+		}
+	}
+
+	b.status = vo.BatchFailed
+}
+
+// Finish sets the final status of the batch from the results of its items
+func (b *Batch) Finish(at time.Time) {
+	b.updatedAt = at
+
+	switch succeeded := b.Succeeded(); {
+	case succeeded == len(b.items):
+		b.status = vo.BatchCompleted
+	case succeeded == 0:
+		b.status = vo.BatchFailed
+	default:
+		b.status = vo.BatchPartiallyCompleted
+	}
+}
+
+// Finished reports whether every item of the batch has its result
+func (b Batch) Finished() bool {
+	switch b.status {
+	case vo.BatchCompleted, vo.BatchPartiallyCompleted, vo.BatchFailed:
+		return true
+	}
+
+	return false
+}
+
+// Amount returns the sum of the values of the items
+func (b Batch) Amount() vo.Money {
+	if len(b.items) == 0 {
+		return vo.Money{}
+	}
+
+	amount := vo.NewMoney(b.items[0].value.Currency(), vo.Amount{})
+	for _, item := range b.items {
+		amount = amount.Add(item.value.Amount())
+	}
+
+	return amount
+}
+
+// Processed returns the number of items with their result
+func (b Batch) Processed() int {
+	return len(b.items) - b.count(vo.BatchItemPending)
+}
+
+// Succeeded returns the number of items whose transfer was created
+func (b Batch) Succeeded() int {
+	return b.count(vo.BatchItemSucceeded)
+}
+
+// Failed returns the number of items whose transfer was refused or rolled back
+func (b Batch) Failed() int {
+	return b.count(vo.BatchItemFailed) + b.count(vo.BatchItemSkipped)
+}
+
+// ID returns the id property
+func (b Batch) ID() vo.Uuid {
+	return b.id
+}
+
+// Payer returns the payer property
+func (b Batch) Payer() vo.Uuid {
+	return b.payer
+}
+
+// Mode returns the mode property
+func (b Batch) Mode() vo.BatchMode {
+	return b.mode
+}
+
+// Status returns the status property
+func (b Batch) Status() vo.BatchStatus {
+	return b.status
+}
+
+// Items returns a copy of the items
+func (b Batch) Items() []BatchItem {
+	return append([]BatchItem(nil), b.items...)
+}
+
+// CreatedAt returns the createdAt property
+func (b Batch) CreatedAt() time.Time {
+	return b.createdAt
+}
+
+// UpdatedAt returns the updatedAt property
+func (b Batch) UpdatedAt() time.Time {
+	return b.updatedAt
+}
+
+func (b *Batch) settle(index int, status vo.BatchItemStatus, reason string, at time.Time) {
+	b.items[index].status = status
+	b.items[index].reason = reason
+	b.updatedAt = at
+}
+
+func (b Batch) count(status vo.BatchItemStatus) int {
+	var n int
+	for _, item := range b.items {
+		if item.status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// TransferID returns the ID of the transfer created by the item
+func (i BatchItem) TransferID() vo.Uuid {
+	return i.transferID
+}
+
+// Payee returns the payee property
+func (i BatchItem) Payee() vo.Uuid {
+	return i.payee
+}
+
+// Value returns the value property
+func (i BatchItem) Value() vo.Money {
+	return i.value
+}
+
+// Status returns the status property
+func (i BatchItem) Status() vo.BatchItemStatus {
+	return i.status
+}
+
+// Reason returns why the transfer of the item failed, empty otherwise
+func (i BatchItem) Reason() string {
+	return i.reason
+}
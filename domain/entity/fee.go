@@ -0,0 +1,267 @@
+package entity
+
+import (
+	"errors"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+// maxBasisPoints is 100% in basis points
+const maxBasisPoints = 10000
+
+var (
+	ErrInvalidFeeRule = errors.New("invalid fee rule")
+
+	ErrInvalidFeeTier = errors.New("invalid fee tier")
+
+	ErrDuplicateFeeRule = errors.New("fee rule already exists")
+
+	ErrRequiredFeeAccount = errors.New("fee account is required to charge fees")
+
+	ErrNotFoundFeeAccount = errors.New("not found fee account")
+)
+
+type (
+	// FeeTier define the price of the transfers up to a value, a zero upTo
+	// meaning without limit
+	FeeTier struct {
+		upTo        vo.Amount
+		flat        vo.Amount
+		basisPoints int64
+	}
+
+	// FeeRule define the fee charged on the transfers received by a type of
+	// user. The empty criteria match any transfer. A flat or percentage rule
+	// has a single tier without limit, the fee of a tiered rule is the price of
+	// the first tier the value fits in, then bounded by min and max.
+	FeeRule struct {
+		id           string
+		version      int
+		payeeType    vo.TypeUser
+		currency     vo.TypeCurrency
+		transferType vo.TransferType
+		tiers        []FeeTier
+		min          vo.Amount
+		max          vo.Amount
+	}
+
+	// Fee define the fee charged on a transfer leg, the version of the rule
+	// that applied and the account it was credited to
+	Fee struct {
+		amount      vo.Money
+		ruleID      string
+		ruleVersion int
+		account     vo.Uuid
+	}
+
+	// Pricing define the fee rules and the account the fees are credited to.
+	// The zero Pricing charges no fee.
+	Pricing struct {
+		account vo.Uuid
+		rules   []FeeRule
+	}
+)
+
+// NewFeeTier create new FeeTier, basisPoints being the percentage of the value with 1% being 100
+func NewFeeTier(upTo vo.Amount, flat vo.Amount, basisPoints int64) (FeeTier, error) {
+	if basisPoints < 0 || basisPoints > maxBasisPoints {
+		return FeeTier{}, ErrInvalidFeeTier
+	}
+
+	return FeeTier{
+		upTo:        upTo,
+		flat:        flat,
+		basisPoints: basisPoints,
+	}, nil
+}
+
+// NewFeeRule create new FeeRule. The tiers are sorted by increasing upTo and
+// only the last one is without limit.
+func NewFeeRule(
+	ID string,
+	version int,
+	payeeType vo.TypeUser,
+	currency vo.TypeCurrency,
+	transferType vo.TransferType,
+	tiers []FeeTier,
+	min vo.Amount,
+	max vo.Amount,
+) (FeeRule, error) {
+	if ID == "" || version < 1 || len(tiers) == 0 {
+		return FeeRule{}, ErrInvalidFeeRule
+	}
+
+	if max.Value() > 0 && min.Value() > max.Value() {
+		return FeeRule{}, ErrInvalidFeeRule
+	}
+
+	last := len(tiers) - 1
+	for i, tier := range tiers {
+		switch {
+		case i == last && tier.upTo.Value() != 0:
+			return FeeRule{}, ErrInvalidFeeTier
+		case i < last && tier.upTo.Value() == 0:
+			return FeeRule{}, ErrInvalidFeeTier
+		case i > 0 && i < last && tier.upTo.Value() <= tiers[i-1].upTo.Value():
+			return FeeRule{}, ErrInvalidFeeTier
+		}
+	}
+
+	return FeeRule{
+		id:           ID,
+		version:      version,
+		payeeType:    payeeType,
+		currency:     currency,
+		transferType: transferType,
+		tiers:        append([]FeeTier(nil), tiers...),
+		min:          min,
+		max:          max,
+	}, nil
+}
+
+// Charge returns the fee of a transfer leg of the given value, never more than the value
+func (r FeeRule) Charge(value vo.Money) Fee {
+	tier := r.tiers[len(r.tiers)-1]
+	for _, t := range r.tiers {
+		if t.upTo.Value() != 0 && value.Amount().Value() <= t.upTo.Value() {
+			tier = t
+			break
+		}
+	}
+
+	zero := vo.NewMoney(value.Currency(), vo.Amount{})
+	fee := zero.Add(tier.flat).Add(value.Amount().Percentage(tier.basisPoints))
+
+	switch {
+	case fee.Amount().Value() < r.min.Value():
+		fee = zero.Add(r.min)
+	case r.max.Value() > 0 && fee.Amount().Value() > r.max.Value():
+		fee = zero.Add(r.max)
+	}
+
+	if fee.Amount().Value() > value.Amount().Value() {
+		fee = value
+	}
+
+	return Fee{
+		amount:      fee,
+		ruleID:      r.id,
+		ruleVersion: r.version,
+	}
+}
+
+// matches reports whether the rule applies to a transfer leg
+func (r FeeRule) matches(payeeType vo.TypeUser, currency vo.Currency, transferType vo.TransferType) bool {
+	return (r.payeeType == "" || r.payeeType == payeeType.ToUpper()) &&
+		(r.currency == "" || r.currency == currency.Value()) &&
+		(r.transferType == "" || r.transferType == transferType)
+}
+
+// specificity returns the number of criteria of the rule
+func (r FeeRule) specificity() int {
+	var n int
+	for _, set := range []bool{r.payeeType != "", r.currency != "", r.transferType != ""} {
+		if set {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ID returns the id property
+func (r FeeRule) ID() string {
+	return r.id
+}
+
+// Version returns the version property
+func (r FeeRule) Version() int {
+	return r.version
+}
+
+// RestoreFee rebuilds a stored Fee
+func RestoreFee(amount vo.Money, ruleID string, ruleVersion int, account vo.Uuid) Fee {
+	return Fee{
+		amount:      amount,
+		ruleID:      ruleID,
+		ruleVersion: ruleVersion,
+		account:     account,
+	}
+}
+
+// Amount returns the amount property
+func (f Fee) Amount() vo.Money {
+	return f.amount
+}
+
+// RuleID returns the id of the rule that charged the fee, empty when no rule applied
+func (f Fee) RuleID() string {
+	return f.ruleID
+}
+
+// RuleVersion returns the version of the rule that charged the fee
+func (f Fee) RuleVersion() int {
+	return f.ruleVersion
+}
+
+// Account returns the user credited with the fee, empty when no rule applied
+// or the fee was recorded without it
+func (f Fee) Account() vo.Uuid {
+	return f.account
+}
+
+// NewPricing create new Pricing crediting the fees to the wallet of the account user
+func NewPricing(account vo.Uuid, rules []FeeRule) (Pricing, error) {
+	if len(rules) > 0 && account.Value() == "" {
+		return Pricing{}, ErrRequiredFeeAccount
+	}
+
+	ids := map[string]bool{}
+	for _, r := range rules {
+		if ids[r.id] {
+			return Pricing{}, ErrDuplicateFeeRule
+		}
+		ids[r.id] = true
+	}
+
+	return Pricing{
+		account: account,
+		rules:   append([]FeeRule(nil), rules...),
+	}, nil
+}
+
+// Fee returns the fee of a transfer leg, charged by the most specific rule
+// matching it, the first one winning the ties, to the account. The fee is zero without rule.
+func (p Pricing) Fee(payeeType vo.TypeUser, value vo.Money, transferType vo.TransferType) Fee {
+	var (
+		rule  FeeRule
+		found bool
+	)
+	for _, r := range p.rules {
+		if !r.matches(payeeType, value.Currency(), transferType) {
+			continue
+		}
+		if !found || r.specificity() > rule.specificity() {
+			rule, found = r, true
+		}
+	}
+
+	if !found {
+		return Fee{amount: vo.NewMoney(value.Currency(), vo.Amount{})}
+	}
+
+	fee := rule.Charge(value)
+	fee.account = p.account
+
+	return fee
+}
+
+// Account returns the user credited with the fees
+func (p Pricing) Account() vo.Uuid {
+	return p.account
+}
+
+// Rules returns a copy of the rules
+func (p Pricing) Rules() []FeeRule {
+	return append([]FeeRule(nil), p.rules...)
+}
@@ -0,0 +1,169 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+func TestNewFeeRule(t *testing.T) {
+	tier := func(upTo int64) entity.FeeTier {
+		return newFeeTier(t, upTo, 0, 100)
+	}
+
+	tests := []struct {
+		name  string
+		tiers []entity.FeeTier
+		min   int64
+		max   int64
+		err   error
+	}{
+		{name: "single tier", tiers: []entity.FeeTier{tier(0)}},
+		{name: "sorted tiers", tiers: []entity.FeeTier{tier(100), tier(1000), tier(0)}},
+		{name: "no tier", err: entity.ErrInvalidFeeRule},
+		{name: "min over max", tiers: []entity.FeeTier{tier(0)}, min: 500, max: 100, err: entity.ErrInvalidFeeRule},
+		{name: "last tier with limit", tiers: []entity.FeeTier{tier(100), tier(1000)}, err: entity.ErrInvalidFeeTier},
+		{name: "tier without limit before the last", tiers: []entity.FeeTier{tier(0), tier(100), tier(0)}, err: entity.ErrInvalidFeeTier},
+		{name: "unsorted tiers", tiers: []entity.FeeTier{tier(1000), tier(100), tier(0)}, err: entity.ErrInvalidFeeTier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.NewFeeRule("rule", 1, "", "", "", tt.tiers, vo.NewAmountTest(tt.min), vo.NewAmountTest(tt.max))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("NewFeeRule() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFeeRuleCharge(t *testing.T) {
+	// 0.50 up to 10.00, 1% up to 100.00, 0.5% above
+	tiered := newFeeRule(t, "tiered", "", []entity.FeeTier{
+		newFeeTier(t, 1000, 50, 0),
+		newFeeTier(t, 10000, 0, 100),
+		newFeeTier(t, 0, 0, 50),
+	}, 0, 0)
+	// 2.5% of at least 0.30 and at most 5.00
+	bounded := newFeeRule(t, "bounded", "", []entity.FeeTier{newFeeTier(t, 0, 0, 250)}, 30, 500)
+	// 0.10 plus 0.5%
+	mixed := newFeeRule(t, "mixed", "", []entity.FeeTier{newFeeTier(t, 0, 10, 50)}, 0, 0)
+
+	tests := []struct {
+		name  string
+		rule  entity.FeeRule
+		value int64
+		fee   int64
+	}{
+		{name: "fee capped at the value", rule: tiered, value: 1, fee: 1},
+		{name: "first tier upper boundary", rule: tiered, value: 1000, fee: 50},
+		{name: "second tier lower boundary", rule: tiered, value: 1001, fee: 10},
+		{name: "second tier upper boundary", rule: tiered, value: 10000, fee: 100},
+		{name: "last tier", rule: tiered, value: 10001, fee: 50},
+		{name: "min", rule: bounded, value: 100, fee: 30},
+		{name: "above min", rule: bounded, value: 2000, fee: 50},
+		{name: "max", rule: bounded, value: 100000, fee: 500},
+		{name: "min over the value", rule: bounded, value: 20, fee: 20},
+		{name: "half a cent rounded up", rule: mixed, value: 100, fee: 11},
+		{name: "below half a cent rounded down", rule: mixed, value: 99, fee: 10},
+		{name: "above half a cent rounded up", rule: mixed, value: 101, fee: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := tt.rule.Charge(vo.NewMoneyBRL(vo.NewAmountTest(tt.value)))
+			if fee.Amount().Amount().Value() != tt.fee {
+				t.Errorf("fee = %d, want %d", fee.Amount().Amount().Value(), tt.fee)
+			}
+			if fee.RuleID() != tt.rule.ID() || fee.RuleVersion() != tt.rule.Version() {
+				t.Errorf("rule = %s v%d, want %s v%d", fee.RuleID(), fee.RuleVersion(), tt.rule.ID(), tt.rule.Version())
+			}
+		})
+	}
+}
+
+func TestPricingFee(t *testing.T) {
+	var (
+		account = newUuid(t)
+		brl     = currency(t, vo.BRL)
+		usd     = currency(t, vo.USD)
+		flat    = func(fee int64) []entity.FeeTier {
+			return []entity.FeeTier{newFeeTier(t, 0, fee, 0)}
+		}
+	)
+
+	pricing, err := entity.NewPricing(account, []entity.FeeRule{
+		newFeeRule(t, "any", "", flat(1), 0, 0),
+		newFeeRule(t, "brl", vo.BRL, flat(2), 0, 0),
+		newFeeRule(t, "brl again", vo.BRL, flat(3), 0, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merchants, err := entity.NewFeeRule("merchant", 1, vo.MERCHANT, "", "", flat(4), vo.Amount{}, vo.Amount{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	onlyMerchants, err := entity.NewPricing(account, []entity.FeeRule{merchants})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		pricing  entity.Pricing
+		currency vo.Currency
+		rule     string
+		fee      int64
+	}{
+		{name: "currency rule over the wildcard", pricing: pricing, currency: brl, rule: "brl", fee: 2},
+		{name: "wildcard for other currencies", pricing: pricing, currency: usd, rule: "any", fee: 1},
+		{name: "no matching rule", pricing: onlyMerchants, currency: brl},
+		{name: "no rule", currency: brl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := tt.pricing.Fee(vo.COMMON, vo.NewMoney(tt.currency, vo.NewAmountTest(1000)), vo.DirectTransfer)
+			if fee.RuleID() != tt.rule {
+				t.Errorf("rule = %q, want %q", fee.RuleID(), tt.rule)
+			}
+			if fee.Amount().Amount().Value() != tt.fee || fee.Amount().Currency() != tt.currency {
+				t.Errorf("fee = %v, want %d %v", fee.Amount(), tt.fee, tt.currency)
+			}
+
+			want := vo.Uuid{}
+			if tt.rule != "" {
+				want = account
+			}
+			if fee.Account() != want {
+				t.Errorf("account = %v, want %v", fee.Account(), want)
+			}
+		})
+	}
+}
+
+func newFeeTier(t *testing.T, upTo, flat, basisPoints int64) entity.FeeTier {
+	t.Helper()
+
+	tier, err := entity.NewFeeTier(vo.NewAmountTest(upTo), vo.NewAmountTest(flat), basisPoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tier
+}
+
+func newFeeRule(t *testing.T, id string, currency vo.TypeCurrency, tiers []entity.FeeTier, min, max int64) entity.FeeRule {
+	t.Helper()
+
+	rule, err := entity.NewFeeRule(id, 1, "", currency, "", tiers, vo.NewAmountTest(min), vo.NewAmountTest(max))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rule
+}
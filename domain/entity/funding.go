@@ -0,0 +1,227 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrNotFoundFunding = errors.New("not found funding")
+
+	ErrCreateFunding = errors.New("error creating funding")
+
+	ErrFindFunding = errors.New("error fetching funding")
+
+	ErrUpdateFunding = errors.New("error updating funding")
+
+	ErrListFundings = errors.New("error listing fundings")
+
+	ErrInvalidFundingValue = errors.New("funding value must be positive")
+
+	ErrFundingStatusTransition = errors.New("funding is no longer pending")
+
+	ErrFundingFailed = errors.New("payment provider failed the funding")
+)
+
+type (
+	// FundingRepositoryCreator define the operation of creating a funding entity
+	FundingRepositoryCreator interface {
+		Create(context.Context, Funding) (Funding, error)
+	}
+
+	// FundingRepositoryFinder define the search operation of funding entities
+	FundingRepositoryFinder interface {
+		FindByID(context.Context, vo.Uuid) (Funding, error)
+	}
+
+	// FundingRepositoryLister define the listing of the fundings matching the filter, the oldest first
+	FundingRepositoryLister interface {
+		List(context.Context, FundingFilter) ([]Funding, error)
+	}
+
+	// FundingFilter selects the fundings listed. The zero values match every funding.
+	FundingFilter struct {
+		UserID vo.Uuid
+		// From and To match the fundings created in [From, To)
+		From time.Time
+		To   time.Time
+		// UpdatedFrom matches the fundings updated from the time, when set
+		UpdatedFrom time.Time
+		Offset      int
+		Limit       int
+	}
+
+	// FundingRepositoryUpdater define the update operation of a funding entity.
+	// Update saves a funding still pending in the repository,
+	// ErrConcurrentModification is returned otherwise.
+	FundingRepositoryUpdater interface {
+		Update(context.Context, Funding) error
+	}
+
+	// FundingRepository groups the operations on funding entities
+	FundingRepository interface {
+		FundingRepositoryCreator
+		FundingRepositoryFinder
+		FundingRepositoryLister
+		FundingRepositoryUpdater
+	}
+
+	// Funding define a deposit bringing money into a wallet from outside the
+	// system, or a withdrawal paying money out of it, made by a payment
+	// provider. A deposit credits the wallet once it is settled, a withdrawal
+	// debits it when it is requested and is refunded if it fails.
+	Funding struct {
+		id          vo.Uuid
+		user        vo.Uuid
+		fundingType vo.FundingType
+		method      vo.FundingMethod
+		value       vo.Money
+		wallet      vo.WalletPurpose
+		status      vo.FundingStatus
+		reference   string
+		reason      string
+		createdAt   time.Time
+		updatedAt   time.Time
+	}
+)
+
+// NewFunding create new pending Funding
+func NewFunding(
+	ID vo.Uuid,
+	userID vo.Uuid,
+	fundingType vo.FundingType,
+	method vo.FundingMethod,
+	value vo.Money,
+	wallet vo.WalletPurpose,
+	createdAt time.Time,
+) Funding {
+	return Funding{
+		id:          ID,
+		user:        userID,
+		fundingType: fundingType,
+		method:      method,
+		value:       value,
+		wallet:      wallet,
+		status:      vo.FundingPending,
+		createdAt:   createdAt,
+		updatedAt:   createdAt,
+	}
+}
+
+// RestoreFunding rebuilds a stored Funding
+func RestoreFunding(
+	ID vo.Uuid,
+	userID vo.Uuid,
+	fundingType vo.FundingType,
+	method vo.FundingMethod,
+	value vo.Money,
+	wallet vo.WalletPurpose,
+	status vo.FundingStatus,
+	reference string,
+	reason string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Funding {
+	return Funding{
+		id:          ID,
+		user:        userID,
+		fundingType: fundingType,
+		method:      method,
+		value:       value,
+		wallet:      wallet,
+		status:      status,
+		reference:   reference,
+		reason:      reason,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+	}
+}
+
+// WithReference returns a copy of the funding identified by the reference at the payment provider
+func (f Funding) WithReference(reference string) Funding {
+	f.reference = reference
+	return f
+}
+
+// Settle settles the pending funding
+func (f *Funding) Settle(at time.Time) error {
+	if f.status != vo.FundingPending {
+		return ErrFundingStatusTransition
+	}
+
+	f.status = vo.FundingSettled
+	f.updatedAt = at
+
+	return nil
+}
+
+// Fail fails the pending funding for the reason
+func (f *Funding) Fail(reason string, at time.Time) error {
+	if f.status != vo.FundingPending {
+		return ErrFundingStatusTransition
+	}
+
+	f.status = vo.FundingFailed
+	f.reason = reason
+	f.updatedAt = at
+
+	return nil
+}
+
+// ID returns the id property
+func (f Funding) ID() vo.Uuid {
+	return f.id
+}
+
+// User returns the user owning the funded wallet
+func (f Funding) User() vo.Uuid {
+	return f.user
+}
+
+// Type returns whether the funding is a deposit or a withdrawal
+func (f Funding) Type() vo.FundingType {
+	return f.fundingType
+}
+
+// Method returns the method property
+func (f Funding) Method() vo.FundingMethod {
+	return f.method
+}
+
+// Value returns the value property
+func (f Funding) Value() vo.Money {
+	return f.value
+}
+
+// Wallet returns the purpose of the wallet of the user funded
+func (f Funding) Wallet() vo.WalletPurpose {
+	return f.wallet
+}
+
+// Status returns the status property
+func (f Funding) Status() vo.FundingStatus {
+	return f.status
+}
+
+// Reference returns the identifier of the funding at the payment provider, empty until it answered
+func (f Funding) Reference() string {
+	return f.reference
+}
+
+// Reason returns why the funding failed, empty unless it did
+func (f Funding) Reason() string {
+	return f.reason
+}
+
+// CreatedAt returns the createdAt property
+func (f Funding) CreatedAt() time.Time {
+	return f.createdAt
+}
+
+// UpdatedAt returns the updatedAt property
+func (f Funding) UpdatedAt() time.Time {
+	return f.updatedAt
+}
@@ -0,0 +1,267 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrLimitExceeded = errors.New("transfer limit exceeded")
+
+	ErrInvalidLimit = errors.New("invalid transfer limit")
+)
+
+type (
+	// LimitExceededError define the limit a transfer exceeded and when the
+	// limit resets, zero for the limit of a single transfer
+	LimitExceededError struct {
+		Limit   vo.LimitKind
+		ResetAt time.Time
+	}
+
+	// Velocity define the maximum number of transfers in a window of time. The
+	// windows are fixed, the first one starting at the zero time.
+	Velocity struct {
+		count  int
+		window time.Duration
+	}
+
+	// Night define the maximum value transferred during the night, whose start
+	// and end are durations since midnight. The night ends the next day when
+	// end is before start.
+	Night struct {
+		start  time.Duration
+		end    time.Duration
+		amount vo.Amount
+	}
+
+	// Limits define the limits of the transfers of a payer, the zero limits
+	// being unlimited. The days and months are the ones of the location. The
+	// amounts are in the currency of the transfers, whose usage is counted by
+	// currency, and the limits of a currency override them for its transfers.
+	Limits struct {
+		transaction vo.Amount
+		daily       vo.Amount
+		monthly     vo.Amount
+		velocity    Velocity
+		night       Night
+		location    *time.Location
+		currencies  map[vo.TypeCurrency]Limits
+	}
+
+	// LimitPolicy define the limits of each type of user and the overrides of some users
+	LimitPolicy struct {
+		location *time.Location
+		types    map[vo.TypeUser]Limits
+		users    map[string]Limits
+	}
+)
+
+// Error returns the limit exceeded and when it resets
+func (e *LimitExceededError) Error() string {
+	if e.ResetAt.IsZero() {
+		return fmt.Sprintf("%s: %s", ErrLimitExceeded, e.Limit)
+	}
+
+	return fmt.Sprintf("%s: %s, resets at %s", ErrLimitExceeded, e.Limit, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrLimitExceeded) true
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// NewVelocity create new Velocity
+func NewVelocity(count int, window time.Duration) (Velocity, error) {
+	if count < 0 || window < 0 || (count > 0) != (window > 0) {
+		return Velocity{}, ErrInvalidLimit
+	}
+
+	return Velocity{count: count, window: window}, nil
+}
+
+// NewNight create new Night
+func NewNight(start, end time.Duration, amount vo.Amount) (Night, error) {
+	day := 24 * time.Hour
+	if start < 0 || start >= day || end < 0 || end >= day || start == end {
+		return Night{}, ErrInvalidLimit
+	}
+
+	return Night{start: start, end: end, amount: amount}, nil
+}
+
+// NewLimits create new Limits
+func NewLimits(transaction, daily, monthly vo.Amount, velocity Velocity, night Night) Limits {
+	return Limits{
+		transaction: transaction,
+		daily:       daily,
+		monthly:     monthly,
+		velocity:    velocity,
+		night:       night,
+	}
+}
+
+// WithCurrency returns the limits with o overriding them for the transfers in the currency
+func (l Limits) WithCurrency(currency vo.TypeCurrency, o Limits) Limits {
+	currencies := make(map[vo.TypeCurrency]Limits, len(l.currencies)+1)
+	for c, limits := range l.currencies {
+		currencies[c] = limits
+	}
+	currencies[currency] = o
+	l.currencies = currencies
+
+	return l
+}
+
+// In returns the limits of the transfers in the currency
+func (l Limits) In(currency vo.Currency) Limits {
+	o := l.currencies[currency.Value()]
+	l.currencies = nil
+
+	return l.Override(o)
+}
+
+// Override returns the limits with the ones set in o replacing them
+func (l Limits) Override(o Limits) Limits {
+	if o.transaction.Value() > 0 {
+		l.transaction = o.transaction
+	}
+	if o.daily.Value() > 0 {
+		l.daily = o.daily
+	}
+	if o.monthly.Value() > 0 {
+		l.monthly = o.monthly
+	}
+	if o.velocity.count > 0 {
+		l.velocity = o.velocity
+	}
+	if o.night.amount.Value() > 0 {
+		l.night = o.night
+	}
+
+	return l
+}
+
+// Check returns a *LimitExceededError for the first limit a transfer of value
+// made at the given time exceeds. usage returns the number and the value of
+// the transfers of the payer in the currency of value made in [from, to).
+func (l Limits) Check(
+	value vo.Money,
+	at time.Time,
+	usage func(from, to time.Time) (TransferUsage, error),
+) error {
+	if l.transaction.Value() > 0 && value.Amount().Value() > l.transaction.Value() {
+		return &LimitExceededError{Limit: vo.TransactionLimit}
+	}
+
+	type period struct {
+		limit    vo.LimitKind
+		from, to time.Time
+		amount   vo.Amount
+		count    int
+	}
+
+	location := l.location
+	if location == nil {
+		location = time.UTC
+	}
+	local := at.In(location)
+	y, m, d := local.Date()
+
+	var periods []period
+	if l.daily.Value() > 0 {
+		periods = append(periods, period{
+			limit:  vo.DailyLimit,
+			from:   time.Date(y, m, d, 0, 0, 0, 0, location),
+			to:     time.Date(y, m, d+1, 0, 0, 0, 0, location),
+			amount: l.daily,
+		})
+	}
+	if l.monthly.Value() > 0 {
+		periods = append(periods, period{
+			limit:  vo.MonthlyLimit,
+			from:   time.Date(y, m, 1, 0, 0, 0, 0, location),
+			to:     time.Date(y, m+1, 1, 0, 0, 0, 0, location),
+			amount: l.monthly,
+		})
+	}
+	if l.velocity.count > 0 {
+		from := at.Truncate(l.velocity.window)
+		periods = append(periods, period{
+			limit: vo.VelocityLimit,
+			from:  from,
+			to:    from.Add(l.velocity.window),
+			count: l.velocity.count,
+		})
+	}
+	if from, to, ok := l.night.period(local); ok && l.night.amount.Value() > 0 {
+		periods = append(periods, period{
+			limit:  vo.NightLimit,
+			from:   from,
+			to:     to,
+			amount: l.night.amount,
+		})
+	}
+
+	for _, p := range periods {
+		u, err := usage(p.from, p.to)
+		if err != nil {
+			return err
+		}
+
+		if (p.amount.Value() > 0 && u.value.Value()+value.Amount().Value() > p.amount.Value()) ||
+			(p.count > 0 && u.count+1 > p.count) {
+			return &LimitExceededError{Limit: p.limit, ResetAt: p.to}
+		}
+	}
+
+	return nil
+}
+
+// period returns the night containing the local time, if any
+func (n Night) period(local time.Time) (time.Time, time.Time, bool) {
+	if n.start == n.end {
+		return time.Time{}, time.Time{}, false
+	}
+
+	y, m, d := local.Date()
+	midnight := func(days int) time.Time {
+		return time.Date(y, m, d+days, 0, 0, 0, 0, local.Location())
+	}
+	since := local.Sub(midnight(0))
+
+	switch {
+	case n.start < n.end && since >= n.start && since < n.end:
+		return midnight(0).Add(n.start), midnight(0).Add(n.end), true
+	case n.start > n.end && since >= n.start:
+		return midnight(0).Add(n.start), midnight(1).Add(n.end), true
+	case n.start > n.end && since < n.end:
+		return midnight(-1).Add(n.start), midnight(0).Add(n.end), true
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// NewLimitPolicy create new LimitPolicy, the user overrides being indexed by user ID
+func NewLimitPolicy(location *time.Location, types map[vo.TypeUser]Limits, users map[string]Limits) LimitPolicy {
+	return LimitPolicy{
+		location: location,
+		types:    types,
+		users:    users,
+	}
+}
+
+// For returns the limits of the transfers of the user in the currency, the
+// ones of the user type overridden by the ones of the user
+func (p LimitPolicy) For(user User, currency vo.Currency) Limits {
+	limits := p.types[user.TypeUser().ToUpper()].In(currency)
+	if o, ok := p.users[user.ID().Value()]; ok {
+		limits = limits.Override(o.In(currency))
+	}
+	limits.location = p.location
+
+	return limits
+}
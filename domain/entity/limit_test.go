@@ -0,0 +1,73 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+func TestLimitPolicyForCurrency(t *testing.T) {
+	var (
+		at  = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		brl = currency(t, vo.BRL)
+		usd = currency(t, vo.USD)
+	)
+
+	user, err := entity.NewUser(
+		newUuid(t),
+		vo.NewFullName("Limited User"),
+		vo.NewEmailTest("limited@example.com"),
+		vo.NewPassword("secret"),
+		vo.NewDocumentTest(vo.CPF, "070.910.549-45"),
+		vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(0))),
+		vo.COMMON,
+		at,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	common := entity.NewLimits(vo.NewAmountTest(0), vo.NewAmountTest(1000), vo.NewAmountTest(0), entity.Velocity{}, entity.Night{}).
+		WithCurrency(vo.USD, entity.NewLimits(vo.NewAmountTest(0), vo.NewAmountTest(100), vo.NewAmountTest(0), entity.Velocity{}, entity.Night{}))
+	policy := entity.NewLimitPolicy(time.UTC, map[vo.TypeUser]entity.Limits{vo.COMMON: common}, nil)
+
+	tests := []struct {
+		name     string
+		currency vo.Currency
+		value    int64
+		used     map[vo.Currency]int64
+		err      error
+	}{
+		{name: "within the limits", currency: brl, value: 500, used: map[vo.Currency]int64{brl: 500}},
+		{name: "usage of the currency only", currency: brl, value: 500, used: map[vo.Currency]int64{brl: 500, usd: 90}},
+		{name: "daily limit", currency: brl, value: 501, used: map[vo.Currency]int64{brl: 500}, err: entity.ErrLimitExceeded},
+		{name: "limits of the currency", currency: usd, value: 20, used: map[vo.Currency]int64{usd: 90}, err: entity.ErrLimitExceeded},
+		{name: "other currency not counted", currency: usd, value: 20, used: map[vo.Currency]int64{brl: 900}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := vo.NewMoney(tt.currency, vo.NewAmountTest(tt.value))
+			err := policy.For(user, tt.currency).Check(value, at, func(from, to time.Time) (entity.TransferUsage, error) {
+				return entity.NewTransferUsage(1, vo.NewAmountTest(tt.used[tt.currency])), nil
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Check() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func currency(t *testing.T, c vo.TypeCurrency) vo.Currency {
+	t.Helper()
+
+	currency, err := vo.NewCurrency(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return currency
+}
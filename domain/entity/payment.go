@@ -0,0 +1,208 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrNotFoundPayment = errors.New("not found payment")
+
+	ErrCreatePayment = errors.New("error creating payment")
+
+	ErrFindPayment = errors.New("error fetching payment")
+
+	ErrUpdatePayment = errors.New("error updating payment")
+
+	ErrPayeeNotMerchant = errors.New("payee of a payment must be a merchant")
+
+	ErrPaymentStatusTransition = errors.New("payment is no longer authorized")
+
+	ErrPaymentExpired = errors.New("payment authorization expired")
+
+	ErrInvalidCaptureValue = errors.New("captured value must be positive and at most the authorized value")
+)
+
+type (
+	// PaymentRepositoryCreator define the operation of creating a payment entity
+	PaymentRepositoryCreator interface {
+		Create(context.Context, Payment) (Payment, error)
+	}
+
+	// PaymentRepositoryFinder define the search operations of payment entities
+	PaymentRepositoryFinder interface {
+		FindByID(context.Context, vo.Uuid) (Payment, error)
+		// FindExpired returns up to limit authorized payments expired at the time, oldest first
+		FindExpired(ctx context.Context, at time.Time, limit int) ([]Payment, error)
+	}
+
+	// PaymentRepositoryUpdater define the update operation of a payment entity.
+	// Update saves a payment still authorized in the repository,
+	// ErrConcurrentModification is returned otherwise.
+	PaymentRepositoryUpdater interface {
+		Update(context.Context, Payment) error
+	}
+
+	// PaymentRepository groups the operations on payment entities
+	PaymentRepository interface {
+		PaymentRepositoryCreator
+		PaymentRepositoryFinder
+		PaymentRepositoryUpdater
+	}
+
+	// Payment define a merchant payment. Its value is held on the wallet of
+	// the payer until it is captured, in full or in part, voided or expired.
+	Payment struct {
+		id         vo.Uuid
+		payer      vo.Uuid
+		payee      vo.Uuid
+		value      vo.Money
+		captured   vo.Amount
+		status     vo.PaymentStatus
+		transferID vo.Uuid
+		createdAt  time.Time
+		expiresAt  time.Time
+		updatedAt  time.Time
+	}
+)
+
+// NewPayment create new authorized Payment
+func NewPayment(ID vo.Uuid, payerID vo.Uuid, payeeID vo.Uuid, value vo.Money, createdAt time.Time, expiresAt time.Time) Payment {
+	return Payment{
+		id:        ID,
+		payer:     payerID,
+		payee:     payeeID,
+		value:     value,
+		status:    vo.PaymentAuthorized,
+		createdAt: createdAt,
+		expiresAt: expiresAt,
+		updatedAt: createdAt,
+	}
+}
+
+// RestorePayment rebuilds a stored Payment
+func RestorePayment(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	payeeID vo.Uuid,
+	value vo.Money,
+	captured vo.Amount,
+	status vo.PaymentStatus,
+	transferID vo.Uuid,
+	createdAt time.Time,
+	expiresAt time.Time,
+	updatedAt time.Time,
+) Payment {
+	return Payment{
+		id:         ID,
+		payer:      payerID,
+		payee:      payeeID,
+		value:      value,
+		captured:   captured,
+		status:     status,
+		transferID: transferID,
+		createdAt:  createdAt,
+		expiresAt:  expiresAt,
+		updatedAt:  updatedAt,
+	}
+}
+
+// Capture captures the value of the authorized payment, made by the transfer
+// of the given ID. The rest of the authorized value is released.
+func (p *Payment) Capture(value vo.Amount, transferID vo.Uuid, at time.Time) error {
+	if p.status != vo.PaymentAuthorized {
+		return ErrPaymentStatusTransition
+	}
+
+	if !at.Before(p.expiresAt) {
+		return ErrPaymentExpired
+	}
+
+	if value.Value() <= 0 || value.Value() > p.value.Amount().Value() {
+		return ErrInvalidCaptureValue
+	}
+
+	p.status = vo.PaymentCaptured
+	p.captured = value
+	p.transferID = transferID
+	p.updatedAt = at
+
+	return nil
+}
+
+// Void releases the authorized payment
+func (p *Payment) Void(at time.Time) error {
+	if p.status != vo.PaymentAuthorized {
+		return ErrPaymentStatusTransition
+	}
+
+	p.status = vo.PaymentVoided
+	p.updatedAt = at
+
+	return nil
+}
+
+// Expire releases the authorized payment nobody captured before it expired
+func (p *Payment) Expire(at time.Time) error {
+	if p.status != vo.PaymentAuthorized || at.Before(p.expiresAt) {
+		return ErrPaymentStatusTransition
+	}
+
+	p.status = vo.PaymentExpired
+	p.updatedAt = at
+
+	return nil
+}
+
+// ID returns the id property
+func (p Payment) ID() vo.Uuid {
+	return p.id
+}
+
+// Payer returns the payer property
+func (p Payment) Payer() vo.Uuid {
+	return p.payer
+}
+
+// Payee returns the merchant paid
+func (p Payment) Payee() vo.Uuid {
+	return p.payee
+}
+
+// Value returns the authorized value, held on the wallet of the payer while the payment is authorized
+func (p Payment) Value() vo.Money {
+	return p.value
+}
+
+// Captured returns the captured value, zero until the payment is captured
+func (p Payment) Captured() vo.Amount {
+	return p.captured
+}
+
+// Status returns the status property
+func (p Payment) Status() vo.PaymentStatus {
+	return p.status
+}
+
+// TransferID returns the ID of the transfer of the capture, empty until the payment is captured
+func (p Payment) TransferID() vo.Uuid {
+	return p.transferID
+}
+
+// CreatedAt returns the createdAt property
+func (p Payment) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// ExpiresAt returns when the hold of the payment is released if it was not captured
+func (p Payment) ExpiresAt() time.Time {
+	return p.expiresAt
+}
+
+// UpdatedAt returns the updatedAt property
+func (p Payment) UpdatedAt() time.Time {
+	return p.updatedAt
+}
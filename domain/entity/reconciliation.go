@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrReconciliationLocked = errors.New("reconciliation locked by another run")
+
+	ErrFindWalletBaselines = errors.New("error fetching wallet baselines")
+
+	ErrSaveWalletBaselines = errors.New("error saving wallet baselines")
+
+	ErrFindReconciliationCheckpoint = errors.New("error fetching reconciliation checkpoint")
+
+	ErrSaveReconciliationCheckpoint = errors.New("error saving reconciliation checkpoint")
+
+	ErrLockReconciliation = errors.New("error locking reconciliation")
+)
+
+type (
+	// ReconciliationRepositoryBaseliner define the operations on the wallet baselines.
+	// Baselines returns the latest baseline of every wallet at or before the time.
+	// SaveBaselines stores the baselines, replacing the ones of the same wallet and time.
+	ReconciliationRepositoryBaseliner interface {
+		Baselines(ctx context.Context, at time.Time) ([]WalletBaseline, error)
+		SaveBaselines(context.Context, []WalletBaseline) error
+	}
+
+	// ReconciliationRepositoryCheckpointer define the operations on the
+	// checkpoints, the ends of the days reconciled the baselines of every
+	// wallet moved were saved at. Checkpoint returns the latest checkpoint at
+	// or before the time, zero when there is none. SaveCheckpoint saves the
+	// baselines, then the checkpoint.
+	ReconciliationRepositoryCheckpointer interface {
+		Checkpoint(ctx context.Context, at time.Time) (time.Time, error)
+		SaveCheckpoint(ctx context.Context, at time.Time, baselines []WalletBaseline) error
+	}
+
+	// ReconciliationRepositoryLocker define the lock of the reconciliation,
+	// held by a single owner until it unlocks it or until it expires. Lock
+	// returns ErrReconciliationLocked when another owner holds it at the time.
+	ReconciliationRepositoryLocker interface {
+		Lock(ctx context.Context, owner string, at, expiresAt time.Time) error
+		Unlock(ctx context.Context, owner string) error
+	}
+
+	// ReconciliationRepository groups the operations on the reconciliation state
+	ReconciliationRepository interface {
+		ReconciliationRepositoryBaseliner
+		ReconciliationRepositoryCheckpointer
+		ReconciliationRepositoryLocker
+	}
+
+	// WalletBaseline define the balance of a wallet at a time, the movements
+	// made before being included. The reconciliation sums the movements made
+	// since to the baseline.
+	WalletBaseline struct {
+		user     vo.Uuid
+		currency vo.Currency
+		purpose  vo.WalletPurpose
+		amount   int64
+		at       time.Time
+	}
+)
+
+// NewWalletBaseline create new WalletBaseline
+func NewWalletBaseline(user vo.Uuid, currency vo.Currency, purpose vo.WalletPurpose, amount int64, at time.Time) WalletBaseline {
+	return WalletBaseline{
+		user:     user,
+		currency: currency,
+		purpose:  purpose,
+		amount:   amount,
+		at:       at.UTC().Truncate(time.Millisecond),
+	}
+}
+
+// User returns the user property
+func (b WalletBaseline) User() vo.Uuid {
+	return b.user
+}
+
+// Currency returns the currency property
+func (b WalletBaseline) Currency() vo.Currency {
+	return b.currency
+}
+
+// Purpose returns the purpose property
+func (b WalletBaseline) Purpose() vo.WalletPurpose {
+	return b.purpose
+}
+
+// Amount returns the amount property, negative when the wallet was expected overdrawn
+func (b WalletBaseline) Amount() int64 {
+	return b.amount
+}
+
+// At returns the at property
+func (b WalletBaseline) At() time.Time {
+	return b.at
+}
@@ -0,0 +1,209 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	// ErrReviewRequired is returned by the authorizers holding a transfer for a manual review
+	ErrReviewRequired = errors.New("transfer requires manual review")
+
+	ErrNotFoundReview = errors.New("not found review")
+
+	ErrCreateReview = errors.New("error creating review")
+
+	ErrFindReview = errors.New("error fetching review")
+
+	ErrUpdateReview = errors.New("error updating review")
+
+	ErrReviewStatusTransition = errors.New("review is already decided")
+
+	ErrInvalidReviewPolicy = errors.New("invalid review policy")
+)
+
+type (
+	// ReviewRepositoryCreator define the operation of creating a review entity
+	ReviewRepositoryCreator interface {
+		Create(context.Context, Review) (Review, error)
+	}
+
+	// ReviewRepositoryFinder define the search operations of review entities
+	ReviewRepositoryFinder interface {
+		FindByID(context.Context, vo.Uuid) (Review, error)
+		// FindByStatus returns up to limit reviews in the status, oldest first
+		FindByStatus(ctx context.Context, status vo.ReviewStatus, limit int) ([]Review, error)
+		// FindExpired returns up to limit pending reviews expired at the time, oldest first
+		FindExpired(ctx context.Context, at time.Time, limit int) ([]Review, error)
+	}
+
+	// ReviewRepositoryUpdater define the update operation of a review entity.
+	// Update saves the decision of a review still pending in the repository,
+	// ErrConcurrentModification is returned otherwise.
+	ReviewRepositoryUpdater interface {
+		Update(context.Context, Review) error
+	}
+
+	// ReviewRepository groups the operations on review entities
+	ReviewRepository interface {
+		ReviewRepositoryCreator
+		ReviewRepositoryFinder
+		ReviewRepositoryUpdater
+	}
+
+	// ReviewPolicy define how long a review waits for a decision and the
+	// decision taken when it expires
+	ReviewPolicy struct {
+		timeout   time.Duration
+		onTimeout vo.ReviewStatus
+	}
+
+	// Review define the manual review of a held transfer
+	Review struct {
+		id         vo.Uuid
+		transferID vo.Uuid
+		payer      vo.Uuid
+		value      vo.Money
+		reason     string
+		status     vo.ReviewStatus
+		reviewer   string
+		note       string
+		createdAt  time.Time
+		expiresAt  time.Time
+		decidedAt  time.Time
+	}
+)
+
+// NewReviewPolicy create new ReviewPolicy
+func NewReviewPolicy(timeout time.Duration, onTimeout vo.ReviewStatus) (ReviewPolicy, error) {
+	if timeout <= 0 || (onTimeout != vo.ReviewApproved && onTimeout != vo.ReviewRejected) {
+		return ReviewPolicy{}, ErrInvalidReviewPolicy
+	}
+
+	return ReviewPolicy{timeout: timeout, onTimeout: onTimeout}, nil
+}
+
+// Timeout returns the timeout property
+func (p ReviewPolicy) Timeout() time.Duration {
+	return p.timeout
+}
+
+// OnTimeout returns the decision taken on the expired reviews
+func (p ReviewPolicy) OnTimeout() vo.ReviewStatus {
+	return p.onTimeout
+}
+
+// NewReview create new pending review of the held transfer
+func NewReview(ID vo.Uuid, transfer Transfer, reason string, createdAt time.Time, expiresAt time.Time) Review {
+	return Review{
+		id:         ID,
+		transferID: transfer.ID(),
+		payer:      transfer.Payer(),
+		value:      transfer.Value(),
+		reason:     reason,
+		status:     vo.ReviewPending,
+		createdAt:  createdAt,
+		expiresAt:  expiresAt,
+	}
+}
+
+// RestoreReview rebuilds a stored Review
+func RestoreReview(
+	ID vo.Uuid,
+	transferID vo.Uuid,
+	payerID vo.Uuid,
+	value vo.Money,
+	reason string,
+	status vo.ReviewStatus,
+	reviewer string,
+	note string,
+	createdAt time.Time,
+	expiresAt time.Time,
+	decidedAt time.Time,
+) Review {
+	return Review{
+		id:         ID,
+		transferID: transferID,
+		payer:      payerID,
+		value:      value,
+		reason:     reason,
+		status:     status,
+		reviewer:   reviewer,
+		note:       note,
+		createdAt:  createdAt,
+		expiresAt:  expiresAt,
+		decidedAt:  decidedAt,
+	}
+}
+
+// Decide approves or rejects the pending review
+func (r *Review) Decide(status vo.ReviewStatus, reviewer string, note string, at time.Time) error {
+	if r.status != vo.ReviewPending || (status != vo.ReviewApproved && status != vo.ReviewRejected) {
+		return ErrReviewStatusTransition
+	}
+
+	r.status = status
+	r.reviewer = reviewer
+	r.note = note
+	r.decidedAt = at
+
+	return nil
+}
+
+// ID returns the id property
+func (r Review) ID() vo.Uuid {
+	return r.id
+}
+
+// TransferID returns the transferID property
+func (r Review) TransferID() vo.Uuid {
+	return r.transferID
+}
+
+// Payer returns the payer property
+func (r Review) Payer() vo.Uuid {
+	return r.payer
+}
+
+// Value returns the value property
+func (r Review) Value() vo.Money {
+	return r.value
+}
+
+// Reason returns why the transfer was held
+func (r Review) Reason() string {
+	return r.reason
+}
+
+// Status returns the status property
+func (r Review) Status() vo.ReviewStatus {
+	return r.status
+}
+
+// Reviewer returns who decided the review, empty while it is pending
+func (r Review) Reviewer() string {
+	return r.reviewer
+}
+
+// Note returns the note of the reviewer
+func (r Review) Note() string {
+	return r.note
+}
+
+// CreatedAt returns the createdAt property
+func (r Review) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// ExpiresAt returns when the review is decided as the policy says if nobody did
+func (r Review) ExpiresAt() time.Time {
+	return r.expiresAt
+}
+
+// DecidedAt returns the decidedAt property, zero while the review is pending
+func (r Review) DecidedAt() time.Time {
+	return r.decidedAt
+}
@@ -0,0 +1,300 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrInvalidRiskRule = errors.New("invalid risk rule")
+
+	ErrNotFoundRiskAssessment = errors.New("not found risk assessment")
+
+	ErrCreateRiskAssessment = errors.New("error creating risk assessment")
+
+	ErrFindRiskAssessment = errors.New("error fetching risk assessment")
+)
+
+type (
+	// RiskAssessmentRepositoryCreator define the operation of creating a risk assessment entity
+	RiskAssessmentRepositoryCreator interface {
+		Create(context.Context, RiskAssessment) (RiskAssessment, error)
+	}
+
+	// RiskAssessmentRepositoryFinder define the search operations for risk assessment entities.
+	// FindByTransferID returns the latest assessment of the transfer.
+	RiskAssessmentRepositoryFinder interface {
+		FindByTransferID(context.Context, vo.Uuid) (RiskAssessment, error)
+	}
+
+	// RiskAssessmentRepository groups the risk assessment repository ports
+	RiskAssessmentRepository interface {
+		RiskAssessmentRepositoryCreator
+		RiskAssessmentRepositoryFinder
+	}
+
+	// RiskRule define the score added by a signal. The window bounds the
+	// history the signal looks at and the threshold is the factor of the
+	// average for AMOUNT_ABOVE_AVERAGE, the number of payees for MANY_PAYEES.
+	RiskRule struct {
+		signal    vo.RiskSignal
+		score     int
+		window    time.Duration
+		threshold float64
+	}
+
+	// RiskPolicy define the rules, one per signal, the score from which the
+	// transfers are held for a manual review and the one from which they are denied
+	RiskPolicy struct {
+		rules       []RiskRule
+		reviewScore int
+		denyScore   int
+	}
+
+	// RiskFacts define what the rules know about a transfer, gathered over the windows of the rules
+	RiskFacts struct {
+		// NewPayees is the number of payees of the transfer the payer did not pay
+		NewPayees int
+		// History is the number of transfers of the payer and Average their average value
+		History int
+		Average vo.Amount
+		// RecentPayees is the number of payees of the payer, the ones of the transfer included
+		RecentPayees int
+		// EmailChangedAt is when the email of the payer changed, zero if it never did
+		EmailChangedAt time.Time
+	}
+
+	// RiskHit define a rule that scored a transfer
+	RiskHit struct {
+		signal vo.RiskSignal
+		score  int
+		detail string
+	}
+
+	// RiskAssessment define the risk assessment entity, the decision taken on a
+	// transfer and the rules that led to it
+	RiskAssessment struct {
+		id         vo.Uuid
+		transferID vo.Uuid
+		payer      vo.Uuid
+		score      int
+		decision   vo.RiskDecision
+		hits       []RiskHit
+		createdAt  time.Time
+	}
+)
+
+// NewRiskRule create new RiskRule
+func NewRiskRule(signal vo.RiskSignal, score int, window time.Duration, threshold float64) (RiskRule, error) {
+	if score <= 0 || window < 0 {
+		return RiskRule{}, ErrInvalidRiskRule
+	}
+
+	switch signal {
+	case vo.NewPayeeSignal:
+	case vo.AmountAboveAverageSignal:
+		if window == 0 || threshold <= 1 {
+			return RiskRule{}, ErrInvalidRiskRule
+		}
+	case vo.ManyPayeesSignal:
+		if window == 0 || threshold < 1 {
+			return RiskRule{}, ErrInvalidRiskRule
+		}
+	case vo.EmailChangedSignal:
+		if window == 0 {
+			return RiskRule{}, ErrInvalidRiskRule
+		}
+	default:
+		return RiskRule{}, vo.ErrInvalidRiskSignal
+	}
+
+	return RiskRule{
+		signal:    signal,
+		score:     score,
+		window:    window,
+		threshold: threshold,
+	}, nil
+}
+
+// Signal returns the signal property
+func (r RiskRule) Signal() vo.RiskSignal {
+	return r.signal
+}
+
+// Window returns the window property, zero meaning the whole history
+func (r RiskRule) Window() time.Duration {
+	return r.window
+}
+
+// evaluate returns the hit of the rule on a transfer of value, if any
+func (r RiskRule) evaluate(value vo.Money, facts RiskFacts, at time.Time) (RiskHit, bool) {
+	hit := RiskHit{signal: r.signal, score: r.score}
+
+	switch r.signal {
+	case vo.NewPayeeSignal:
+		if facts.NewPayees == 0 {
+			return RiskHit{}, false
+		}
+		hit.detail = fmt.Sprintf("%d new payees", facts.NewPayees)
+	case vo.AmountAboveAverageSignal:
+		average := facts.Average.Value()
+		if facts.History == 0 || float64(value.Amount().Value()) <= r.threshold*float64(average) {
+			return RiskHit{}, false
+		}
+		hit.detail = fmt.Sprintf("value %d above %gx the average %d", value.Amount().Value(), r.threshold, average)
+	case vo.ManyPayeesSignal:
+		if float64(facts.RecentPayees) <= r.threshold {
+			return RiskHit{}, false
+		}
+		hit.detail = fmt.Sprintf("%d payees in %s", facts.RecentPayees, r.window)
+	case vo.EmailChangedSignal:
+		if facts.EmailChangedAt.IsZero() || at.Sub(facts.EmailChangedAt) >= r.window {
+			return RiskHit{}, false
+		}
+		hit.detail = fmt.Sprintf("email changed at %s", facts.EmailChangedAt.UTC().Format(time.RFC3339))
+	default:
+		return RiskHit{}, false
+	}
+
+	return hit, true
+}
+
+// NewRiskPolicy create new RiskPolicy, no transfer being held when reviewScore is zero
+func NewRiskPolicy(rules []RiskRule, reviewScore int, denyScore int) (RiskPolicy, error) {
+	if denyScore <= 0 || reviewScore < 0 || reviewScore >= denyScore {
+		return RiskPolicy{}, ErrInvalidRiskRule
+	}
+
+	signals := map[vo.RiskSignal]bool{}
+	for _, r := range rules {
+		if signals[r.signal] {
+			return RiskPolicy{}, ErrInvalidRiskRule
+		}
+		signals[r.signal] = true
+	}
+
+	return RiskPolicy{
+		rules:       append([]RiskRule(nil), rules...),
+		reviewScore: reviewScore,
+		denyScore:   denyScore,
+	}, nil
+}
+
+// Rules returns a copy of the rules
+func (p RiskPolicy) Rules() []RiskRule {
+	return append([]RiskRule(nil), p.rules...)
+}
+
+// Assess scores the transfer with the rules, holding it for a review or denying
+// it when the score reaches the review or the deny score
+func (p RiskPolicy) Assess(ID vo.Uuid, transfer Transfer, facts RiskFacts, at time.Time) RiskAssessment {
+	assessment := RiskAssessment{
+		id:         ID,
+		transferID: transfer.ID(),
+		payer:      transfer.Payer(),
+		decision:   vo.RiskApprove,
+		createdAt:  at,
+	}
+
+	for _, r := range p.rules {
+		hit, ok := r.evaluate(transfer.Value(), facts, at)
+		if !ok {
+			continue
+		}
+		assessment.hits = append(assessment.hits, hit)
+		assessment.score += hit.score
+	}
+
+	switch {
+	case assessment.score >= p.denyScore:
+		assessment.decision = vo.RiskDeny
+	case p.reviewScore > 0 && assessment.score >= p.reviewScore:
+		assessment.decision = vo.RiskReview
+	}
+
+	return assessment
+}
+
+// NewRiskHit create new RiskHit
+func NewRiskHit(signal vo.RiskSignal, score int, detail string) RiskHit {
+	return RiskHit{
+		signal: signal,
+		score:  score,
+		detail: detail,
+	}
+}
+
+// Signal returns the signal property
+func (h RiskHit) Signal() vo.RiskSignal {
+	return h.signal
+}
+
+// Score returns the score property
+func (h RiskHit) Score() int {
+	return h.score
+}
+
+// Detail returns the detail property
+func (h RiskHit) Detail() string {
+	return h.detail
+}
+
+// RestoreRiskAssessment rebuilds a stored RiskAssessment
+func RestoreRiskAssessment(
+	ID vo.Uuid,
+	transferID vo.Uuid,
+	payerID vo.Uuid,
+	score int,
+	decision vo.RiskDecision,
+	hits []RiskHit,
+	createdAt time.Time,
+) RiskAssessment {
+	return RiskAssessment{
+		id:         ID,
+		transferID: transferID,
+		payer:      payerID,
+		score:      score,
+		decision:   decision,
+		hits:       append([]RiskHit(nil), hits...),
+		createdAt:  createdAt,
+	}
+}
+
+// ID returns the id property
+func (a RiskAssessment) ID() vo.Uuid {
+	return a.id
+}
+
+// TransferID returns the transferID property
+func (a RiskAssessment) TransferID() vo.Uuid {
+	return a.transferID
+}
+
+// Payer returns the payer property
+func (a RiskAssessment) Payer() vo.Uuid {
+	return a.payer
+}
+
+// Score returns the score property
+func (a RiskAssessment) Score() int {
+	return a.score
+}
+
+// Decision returns the decision property
+func (a RiskAssessment) Decision() vo.RiskDecision {
+	return a.decision
+}
+
+// Hits returns a copy of the hits
+func (a RiskAssessment) Hits() []RiskHit {
+	return append([]RiskHit(nil), a.hits...)
+}
+
+// CreatedAt returns the createdAt property
+func (a RiskAssessment) CreatedAt() time.Time {
+	return a.createdAt
+}
@@ -0,0 +1,252 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+var (
+	ErrNotFoundSchedule = errors.New("not found schedule")
+
+	ErrCreateSchedule = errors.New("error creating schedule")
+
+	ErrFindSchedule = errors.New("error fetching schedule")
+
+	ErrUpdateSchedule = errors.New("error updating schedule")
+
+	ErrScheduleInPast = errors.New("schedule must start in the future")
+
+	ErrScheduleStatusTransition = errors.New("schedule status does not allow the operation")
+)
+
+type (
+	// ScheduleRepositoryCreator define the operation of creating a schedule entity
+	ScheduleRepositoryCreator interface {
+		Create(context.Context, Schedule) (Schedule, error)
+	}
+
+	// ScheduleRepositoryFinder define the search operations of schedule entities
+	ScheduleRepositoryFinder interface {
+		FindByID(context.Context, vo.Uuid) (Schedule, error)
+		FindByPayer(context.Context, vo.Uuid) ([]Schedule, error)
+		// FindDue returns up to limit active schedules whose next run is at or before the time
+		FindDue(ctx context.Context, at time.Time, limit int) ([]Schedule, error)
+	}
+
+	// ScheduleRepositoryUpdater define the update operation of a schedule entity
+	ScheduleRepositoryUpdater interface {
+		Update(context.Context, Schedule) error
+	}
+
+	// ScheduleRepository groups the operations on schedule entities
+	ScheduleRepository interface {
+		ScheduleRepositoryCreator
+		ScheduleRepositoryFinder
+		ScheduleRepositoryUpdater
+	}
+
+	// Schedule define a transfer executed at a future date, once or recurrently
+	Schedule struct {
+		id          vo.Uuid
+		payer       vo.Uuid
+		payee       vo.Uuid
+		value       vo.Money
+		recurrence  vo.Recurrence
+		status      vo.ScheduleStatus
+		nextRunAt   time.Time
+		occurrences int
+		lastError   string
+		createdAt   time.Time
+		updatedAt   time.Time
+	}
+)
+
+// NewSchedule create new active schedule whose first occurrence is at or after startAt
+func NewSchedule(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	payeeID vo.Uuid,
+	value vo.Money,
+	recurrence vo.Recurrence,
+	startAt time.Time,
+	createdAt time.Time,
+) (Schedule, error) {
+	if payerID.Equals(payeeID) {
+		return Schedule{}, ErrSamePayerAndPayee
+	}
+
+	if startAt.Before(createdAt) {
+		return Schedule{}, ErrScheduleInPast
+	}
+
+	return Schedule{
+		id:         ID,
+		payer:      payerID,
+		payee:      payeeID,
+		value:      value,
+		recurrence: recurrence,
+		status:     vo.ScheduleActive,
+		nextRunAt:  recurrence.First(startAt),
+		createdAt:  createdAt,
+		updatedAt:  createdAt,
+	}, nil
+}
+
+// RestoreSchedule create a schedule from its stored state
+func RestoreSchedule(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	payeeID vo.Uuid,
+	value vo.Money,
+	recurrence vo.Recurrence,
+	status vo.ScheduleStatus,
+	nextRunAt time.Time,
+	occurrences int,
+	lastError string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Schedule {
+	return Schedule{
+		id:          ID,
+		payer:       payerID,
+		payee:       payeeID,
+		value:       value,
+		recurrence:  recurrence,
+		status:      status,
+		nextRunAt:   nextRunAt,
+		occurrences: occurrences,
+		lastError:   lastError,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+	}
+}
+
+// Pause stops the executions until Resume
+func (s *Schedule) Pause(at time.Time) error {
+	if s.status != vo.ScheduleActive {
+		return ErrScheduleStatusTransition
+	}
+
+	s.status = vo.SchedulePaused
+	s.updatedAt = at
+
+	return nil
+}
+
+// Resume restarts the executions, skipping the occurrences missed while paused.
+// The schedule completes instead when its last occurrence was missed.
+func (s *Schedule) Resume(at time.Time) error {
+	if s.status != vo.SchedulePaused {
+		return ErrScheduleStatusTransition
+	}
+
+	s.updatedAt = at
+
+	for s.nextRunAt.Before(at) {
+		next := s.recurrence.Next(s.nextRunAt)
+		if next.IsZero() || s.recurrence.Ended(s.occurrences, next) {
+			s.status = vo.ScheduleCompleted
+			return nil
+		}
+		s.nextRunAt = next
+	}
+
+	s.status = vo.ScheduleActive
+
+	return nil
+}
+
+// Cancel stops the executions for good
+func (s *Schedule) Cancel(at time.Time) error {
+	if s.status != vo.ScheduleActive && s.status != vo.SchedulePaused {
+		return ErrScheduleStatusTransition
+	}
+
+	s.status = vo.ScheduleCanceled
+	s.updatedAt = at
+
+	return nil
+}
+
+// Advance records the execution of the current occurrence, err being the reason
+// it failed if any, and moves to the next one or completes the schedule
+func (s *Schedule) Advance(err error, at time.Time) {
+	s.occurrences++
+	s.updatedAt = at
+
+	s.lastError = ""
+	if err != nil {
+		s.lastError = err.Error()
+	}
+
+	next := s.recurrence.Next(s.nextRunAt)
+	if next.IsZero() || s.recurrence.Ended(s.occurrences, next) {
+		s.status = vo.ScheduleCompleted
+		return
+	}
+
+	s.nextRunAt = next
+}
+
+// Due reports whether the current occurrence must run at the given time
+func (s Schedule) Due(at time.Time) bool {
+	return s.status == vo.ScheduleActive && !s.nextRunAt.After(at)
+}
+
+// ID returns the id property
+func (s Schedule) ID() vo.Uuid {
+	return s.id
+}
+
+// Payer returns the payer property
+func (s Schedule) Payer() vo.Uuid {
+	return s.payer
+}
+
+// Payee returns the payee property
+func (s Schedule) Payee() vo.Uuid {
+	return s.payee
+}
+
+// Value returns the value property
+func (s Schedule) Value() vo.Money {
+	return s.value
+}
+
+// Recurrence returns the recurrence property
+func (s Schedule) Recurrence() vo.Recurrence {
+	return s.recurrence
+}
+
+// Status returns the status property
+func (s Schedule) Status() vo.ScheduleStatus {
+	return s.status
+}
+
+// NextRunAt returns the time of the current occurrence
+func (s Schedule) NextRunAt() time.Time {
+	return s.nextRunAt
+}
+
+// Occurrences returns the number of occurrences already executed
+func (s Schedule) Occurrences() int {
+	return s.occurrences
+}
+
+// LastError returns why the last occurrence failed, empty if it succeeded
+func (s Schedule) LastError() string {
+	return s.lastError
+}
+
+// CreatedAt returns the createdAt property
+func (s Schedule) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// UpdatedAt returns the updatedAt property
+func (s Schedule) UpdatedAt() time.Time {
+	return s.updatedAt
+}
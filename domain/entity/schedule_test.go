@@ -0,0 +1,137 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/google/uuid"
+)
+
+func TestScheduleResume(t *testing.T) {
+	var (
+		start = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		daily = mustRecurrence(t, vo.DAILY, time.Time{}, 0)
+	)
+
+	tests := []struct {
+		name       string
+		recurrence vo.Recurrence
+		resumeAt   time.Time
+		status     vo.ScheduleStatus
+		nextRunAt  time.Time
+	}{
+		{
+			name:       "once before its occurrence",
+			recurrence: vo.NewRecurrenceOnce(),
+			resumeAt:   start.Add(-time.Hour),
+			status:     vo.ScheduleActive,
+			nextRunAt:  start,
+		},
+		{
+			name:       "once after its occurrence",
+			recurrence: vo.NewRecurrenceOnce(),
+			resumeAt:   start.Add(time.Hour),
+			status:     vo.ScheduleCompleted,
+			nextRunAt:  start,
+		},
+		{
+			name:       "recurrent skips the missed occurrences",
+			recurrence: daily,
+			resumeAt:   start.AddDate(0, 0, 2).Add(time.Hour),
+			status:     vo.ScheduleActive,
+			nextRunAt:  start.AddDate(0, 0, 3),
+		},
+		{
+			name:       "recurrent ended while paused",
+			recurrence: mustRecurrence(t, vo.DAILY, start.AddDate(0, 0, 2), 0),
+			resumeAt:   start.AddDate(0, 0, 5),
+			status:     vo.ScheduleCompleted,
+			nextRunAt:  start.AddDate(0, 0, 2),
+		},
+		{
+			name:       "recurrent resumed on its last occurrence",
+			recurrence: mustRecurrence(t, vo.DAILY, start.AddDate(0, 0, 2), 0),
+			resumeAt:   start.AddDate(0, 0, 1).Add(time.Hour),
+			status:     vo.ScheduleActive,
+			nextRunAt:  start.AddDate(0, 0, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := entity.NewSchedule(
+				newUuid(t),
+				newUuid(t),
+				newUuid(t),
+				vo.NewMoneyBRL(vo.NewAmountTest(100)),
+				tt.recurrence,
+				start,
+				start.Add(-24*time.Hour),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Pause(start.Add(-2 * time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Resume(tt.resumeAt); err != nil {
+				t.Fatal(err)
+			}
+
+			if s.Status() != tt.status {
+				t.Errorf("status = %s, want %s", s.Status(), tt.status)
+			}
+			if !s.NextRunAt().Equal(tt.nextRunAt) {
+				t.Errorf("next run at = %s, want %s", s.NextRunAt(), tt.nextRunAt)
+			}
+			if s.Due(tt.resumeAt) {
+				t.Error("due right after the resume")
+			}
+		})
+	}
+}
+
+func TestScheduleResumeNotPaused(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	s, err := entity.NewSchedule(
+		newUuid(t),
+		newUuid(t),
+		newUuid(t),
+		vo.NewMoneyBRL(vo.NewAmountTest(100)),
+		vo.NewRecurrenceOnce(),
+		start,
+		start,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Resume(start); err != entity.ErrScheduleStatusTransition {
+		t.Errorf("Resume() = %v, want %v", err, entity.ErrScheduleStatusTransition)
+	}
+}
+
+func mustRecurrence(t *testing.T, f vo.Frequency, endAt time.Time, count int) vo.Recurrence {
+	t.Helper()
+
+	r, err := vo.NewRecurrence(f, 0, endAt, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func newUuid(t *testing.T) vo.Uuid {
+	t.Helper()
+
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
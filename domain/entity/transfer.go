@@ -12,25 +12,120 @@ var (
 	ErrCreateTransfer = errors.New("error creating transfer")
 
 	ErrUnauthorizedTransfer = errors.New("unauthorized transfer")
+
+	ErrSamePayerAndPayee = errors.New("payer and payee must be different users")
+
+	ErrTransferAlreadyExists = errors.New("transfer already exists")
+
+	ErrNoTransferLegs = errors.New("transfer must have at least one payee")
+
+	ErrDuplicatePayee = errors.New("payee must appear once in a split transfer")
+
+	ErrEmptyTransferLeg = errors.New("transfer leg value must be greater than zero")
+
+	ErrNotFoundTransfer = errors.New("not found transfer")
+
+	ErrFindTransfer = errors.New("error fetching transfer")
+
+	ErrUpdateTransfer = errors.New("error updating transfer")
+
+	ErrListTransfers = errors.New("error listing transfers")
 )
 
 type (
-	// TransferRepositoryCreator define the operation of creating a transfer entity
+	// TransferRepositoryCreator define the operation of creating a transfer entity.
+	// Create returns ErrTransferAlreadyExists when the ID is already used.
 	TransferRepositoryCreator interface {
 		Create(context.Context, Transfer) (Transfer, error)
 		WithTransaction(context.Context, func(context.Context) error) error
 	}
 
-	// Transfer define the transfer entity
+	// TransferRepositoryFinder define the queries on the transfers history
+	TransferRepositoryFinder interface {
+		// SumByPayer returns the number and the value of the transfers, the
+		// withdrawals and the authorized payments of the payer in the currency
+		// created in [from, to)
+		SumByPayer(ctx context.Context, payerID vo.Uuid, currency vo.Currency, from, to time.Time) (TransferUsage, error)
+		// FindPayees returns the distinct payees, the ones of the split legs included,
+		// of the transfers of the payer created in [from, to)
+		FindPayees(ctx context.Context, payerID vo.Uuid, from, to time.Time) ([]vo.Uuid, error)
+		// FindByID returns ErrNotFoundTransfer when the transfer does not exist
+		FindByID(context.Context, vo.Uuid) (Transfer, error)
+	}
+
+	// TransferRepositoryLister define the listing of the transfers history.
+	// List returns the transfers matching the filter, the oldest first.
+	TransferRepositoryLister interface {
+		List(context.Context, TransferFilter) ([]Transfer, error)
+	}
+
+	// TransferFilter selects the transfers listed. The zero values match every transfer.
+	TransferFilter struct {
+		// UserID matches the transfers of the user as payer or as payee, split legs included
+		UserID vo.Uuid
+		// From and To match the transfers created in [From, To)
+		From   time.Time
+		To     time.Time
+		Offset int
+		Limit  int
+	}
+
+	// TransferRepositoryUpdater define the update operation of a transfer entity.
+	// UpdateStatus returns ErrNotFoundTransfer when the transfer does not exist.
+	TransferRepositoryUpdater interface {
+		UpdateStatus(ctx context.Context, ID vo.Uuid, status vo.TransferStatus) error
+	}
+
+	// TransferUsage define the number and the value of transfers
+	TransferUsage struct {
+		count int
+		value vo.Amount
+	}
+
+	// Transfer define the transfer entity. The value of a split transfer is
+	// divided between the payees of its legs. A held transfer is debited from
+	// the payer but only credited to the payees once its review approves it.
+	// The payer is debited from its source wallet and the payees credited on
+	// their destination wallet, both of the currency of the value.
 	Transfer struct {
-		id        vo.Uuid
-		payer     vo.Uuid
-		payee     vo.Uuid
-		value     vo.Money
-		createdAt time.Time
+		id           vo.Uuid
+		payer        vo.Uuid
+		value        vo.Money
+		source       vo.WalletPurpose
+		destination  vo.WalletPurpose
+		legs         []TransferLeg
+		transferType vo.TransferType
+		status       vo.TransferStatus
+		createdAt    time.Time
+	}
+
+	// TransferLeg define the part of a transfer credited to a payee. The
+	// payee receives the value minus the fee.
+	TransferLeg struct {
+		payee vo.Uuid
+		value vo.Money
+		fee   Fee
 	}
 )
 
+// NewTransferUsage create new TransferUsage
+func NewTransferUsage(count int, value vo.Amount) TransferUsage {
+	return TransferUsage{
+		count: count,
+		value: value,
+	}
+}
+
+// Count returns the count property
+func (u TransferUsage) Count() int {
+	return u.count
+}
+
+// Value returns the value property
+func (u TransferUsage) Value() vo.Amount {
+	return u.value
+}
+
 // NewTramsfer create new transfer
 func NewTransfer(
 	ID vo.Uuid,
@@ -40,14 +135,128 @@ func NewTransfer(
 	createdAt time.Time,
 ) Transfer {
 	return Transfer{
-		id:        ID,
-		payer:     payerID,
-		payee:     payeeID,
-		value:     value,
-		createdAt: createdAt,
+		id:           ID,
+		payer:        payerID,
+		value:        value,
+		source:       vo.MainWallet,
+		destination:  vo.MainWallet,
+		legs:         []TransferLeg{NewTransferLeg(payeeID, value)},
+		transferType: vo.DirectTransfer,
+		status:       vo.TransferCompleted,
+		createdAt:    createdAt,
+	}
+}
+
+// NewSplitTransfer create new transfer whose value is the sum of the legs, every
+// payee getting a single leg of a positive value
+func NewSplitTransfer(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	legs []TransferLeg,
+	createdAt time.Time,
+) (Transfer, error) {
+	payees := make(map[vo.Uuid]bool, len(legs))
+	for _, leg := range legs {
+		if leg.value.Amount().Value() <= 0 {
+			return Transfer{}, ErrEmptyTransferLeg
+		}
+		if payees[leg.payee] {
+			return Transfer{}, ErrDuplicatePayee
+		}
+		payees[leg.payee] = true
+	}
+
+	return newTransferOfLegs(ID, payerID, legs, createdAt)
+}
+
+// newTransferOfLegs create new split transfer whose value is the sum of the legs
+func newTransferOfLegs(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	legs []TransferLeg,
+	createdAt time.Time,
+) (Transfer, error) {
+	if len(legs) == 0 {
+		return Transfer{}, ErrNoTransferLegs
+	}
+
+	value := vo.NewMoney(legs[0].value.Currency(), vo.Amount{})
+	for _, leg := range legs {
+		if payerID.Equals(leg.payee) {
+			return Transfer{}, ErrSamePayerAndPayee
+		}
+		value = value.Add(leg.value.Amount())
+	}
+
+	return Transfer{
+		id:           ID,
+		payer:        payerID,
+		value:        value,
+		source:       vo.MainWallet,
+		destination:  vo.MainWallet,
+		legs:         append([]TransferLeg(nil), legs...),
+		transferType: vo.SplitTransfer,
+		status:       vo.TransferCompleted,
+		createdAt:    createdAt,
+	}, nil
+}
+
+// RestoreTransfer rebuilds a stored Transfer, whose value is the sum of the legs
+func RestoreTransfer(
+	ID vo.Uuid,
+	payerID vo.Uuid,
+	legs []TransferLeg,
+	transferType vo.TransferType,
+	status vo.TransferStatus,
+	createdAt time.Time,
+) (Transfer, error) {
+	t, err := newTransferOfLegs(ID, payerID, legs, createdAt)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	t.transferType = transferType
+	t.status = status
+
+	return t, nil
+}
+
+// NewTransferLeg create new TransferLeg
+func NewTransferLeg(payeeID vo.Uuid, value vo.Money) TransferLeg {
+	return TransferLeg{
+		payee: payeeID,
+		value: value,
+		fee:   Fee{amount: vo.NewMoney(value.Currency(), vo.Amount{})},
 	}
 }
 
+// WithType returns a copy of the transfer requested as transferType
+func (t Transfer) WithType(transferType vo.TransferType) Transfer {
+	t.transferType = transferType
+	return t
+}
+
+// WithStatus returns a copy of the transfer in the status
+func (t Transfer) WithStatus(status vo.TransferStatus) Transfer {
+	t.status = status
+	return t
+}
+
+// WithWallets returns a copy of the transfer debited from the source wallet
+// of the payer and credited on the destination wallet of the payees
+func (t Transfer) WithWallets(source, destination vo.WalletPurpose) Transfer {
+	t.source = source
+	t.destination = destination
+	return t
+}
+
+// WithFee returns a copy of the transfer charging fee on the leg at index
+func (t Transfer) WithFee(index int, fee Fee) Transfer {
+	t.legs = t.Legs()
+	t.legs[index].fee = fee
+	return t
+}
+
 // ID returns the id property
 func (t Transfer) ID() vo.Uuid {
 	return t.id
@@ -58,9 +267,23 @@ func (t Transfer) Payer() vo.Uuid {
 	return t.payer
 }
 
-// Payee returns the payee property
+// Payee returns the payee of the first leg, the only one unless the transfer is split
 func (t Transfer) Payee() vo.Uuid {
-	return t.payee
+	if len(t.legs) == 0 {
+		return vo.Uuid{}
+	}
+
+	return t.legs[0].payee
+}
+
+// Legs returns a copy of the legs
+func (t Transfer) Legs() []TransferLeg {
+	return append([]TransferLeg(nil), t.legs...)
+}
+
+// IsSplit reports whether the value is divided between several payees
+func (t Transfer) IsSplit() bool {
+	return len(t.legs) > 1
 }
 
 // Value returns the value property
@@ -68,7 +291,74 @@ func (t Transfer) Value() vo.Money {
 	return t.value
 }
 
+// Source returns the purpose of the wallet of the payer debited
+func (t Transfer) Source() vo.WalletPurpose {
+	return t.source
+}
+
+// Destination returns the purpose of the wallets of the payees credited
+func (t Transfer) Destination() vo.WalletPurpose {
+	return t.destination
+}
+
+// Type returns the transferType property
+func (t Transfer) Type() vo.TransferType {
+	return t.transferType
+}
+
+// Fee returns the sum of the fees of the legs
+func (t Transfer) Fee() vo.Money {
+	fee := vo.NewMoney(t.value.Currency(), vo.Amount{})
+	for _, leg := range t.legs {
+		fee = fee.Add(leg.fee.amount.Amount())
+	}
+
+	return fee
+}
+
+// FeeAccount returns the user credited with the fees of the legs, empty when
+// none was recorded
+func (t Transfer) FeeAccount() vo.Uuid {
+	for _, leg := range t.legs {
+		if leg.fee.account.Value() != "" {
+			return leg.fee.account
+		}
+	}
+
+	return vo.Uuid{}
+}
+
+// Net returns the value credited to the payees
+func (t Transfer) Net() vo.Money {
+	return t.value.Sub(t.Fee().Amount())
+}
+
+// Status returns the status property
+func (t Transfer) Status() vo.TransferStatus {
+	return t.status
+}
+
 // CreatedAt returns the createdAt property
 func (t Transfer) CreatedAt() time.Time {
 	return t.createdAt
 }
+
+// Payee returns the payee property
+func (l TransferLeg) Payee() vo.Uuid {
+	return l.payee
+}
+
+// Value returns the value property
+func (l TransferLeg) Value() vo.Money {
+	return l.value
+}
+
+// Fee returns the fee property
+func (l TransferLeg) Fee() Fee {
+	return l.fee
+}
+
+// Net returns the value credited to the payee
+func (l TransferLeg) Net() vo.Money {
+	return l.value.Sub(l.fee.amount.Amount())
+}
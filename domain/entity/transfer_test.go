@@ -0,0 +1,64 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+func TestNewSplitTransfer(t *testing.T) {
+	var (
+		payer = newUuid(t)
+		a     = newUuid(t)
+		b     = newUuid(t)
+	)
+
+	tests := []struct {
+		name string
+		legs []entity.TransferLeg
+		err  error
+	}{
+		{
+			name: "distinct payees",
+			legs: []entity.TransferLeg{leg(a, 60), leg(b, 40)},
+		},
+		{
+			name: "no legs",
+			err:  entity.ErrNoTransferLegs,
+		},
+		{
+			name: "duplicate payee",
+			legs: []entity.TransferLeg{leg(a, 60), leg(b, 20), leg(a, 20)},
+			err:  entity.ErrDuplicatePayee,
+		},
+		{
+			name: "empty leg",
+			legs: []entity.TransferLeg{leg(a, 100), leg(b, 0)},
+			err:  entity.ErrEmptyTransferLeg,
+		},
+		{
+			name: "payer as payee",
+			legs: []entity.TransferLeg{leg(a, 60), leg(payer, 40)},
+			err:  entity.ErrSamePayerAndPayee,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer, err := entity.NewSplitTransfer(newUuid(t), payer, tt.legs, time.Now())
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NewSplitTransfer() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && transfer.Value().Amount().Value() != 100 {
+				t.Errorf("value = %d, want 100", transfer.Value().Amount().Value())
+			}
+		})
+	}
+}
+
+func leg(payee vo.Uuid, value int64) entity.TransferLeg {
+	return entity.NewTransferLeg(payee, vo.NewMoneyBRL(vo.NewAmountTest(value)))
+}
@@ -18,6 +18,26 @@ var (
 	ErrCreateUser = errors.New("error creating user")
 
 	ErrFindUserByID = errors.New("error fetching user by ID")
+
+	ErrFindUser = errors.New("error fetching user")
+
+	ErrCorruptedUser = errors.New("stored user is corrupted")
+
+	ErrConcurrentModification = errors.New("user was modified concurrently")
+
+	ErrNotFoundWallet = errors.New("user has no wallet of the currency for the purpose")
+
+	ErrWalletAlreadyExists = errors.New("user already has a wallet of the currency for the purpose")
+
+	ErrCreateWallet = errors.New("error creating wallet")
+
+	ErrInitialBalanceRestricted = errors.New("only admins may create a user with an initial balance")
+
+	ErrListUsers = errors.New("error listing users")
+
+	ErrInvalidAdjustmentValue = errors.New("wallet adjustment value must be positive")
+
+	ErrAdjustmentReasonRequired = errors.New("wallet adjustment requires a reason")
 )
 
 type (
@@ -26,27 +46,69 @@ type (
 		Create(context.Context, User) (User, error)
 	}
 
-	// UserRepositoryFinder defines the search operation for a user entity
+	// UserRepositoryFinder defines the search operations for a user entity.
+	// FindByEmail and FindByDocument return the oldest user of the email or the
+	// document, ErrNotFoundUser when there is none. A stored user which is no
+	// longer valid is reported as ErrCorruptedUser.
 	UserRepositoryFinder interface {
 		FindByID(context.Context, vo.Uuid) (User, error)
+		FindByEmail(context.Context, vo.Email) (User, error)
+		FindByDocument(context.Context, vo.Document) (User, error)
+	}
+
+	// UserRepositoryLister defines the listing of the user entities matching the filter, the oldest first
+	UserRepositoryLister interface {
+		List(context.Context, UserFilter) ([]User, error)
 	}
 
-	// UserRepositoryUpdated defines the update operation of a user entity wallet
+	// UserFilter selects the users listed. The zero values match every user.
+	UserFilter struct {
+		Type vo.TypeUser
+		// From and To match the users created in [From, To)
+		From   time.Time
+		To     time.Time
+		Offset int
+		Limit  int
+	}
+
+	// UserRepositoryUpdated defines the update operation of a user entity wallet.
+	// The update saves the money and the held amount of the wallet of the same
+	// currency and purpose only if the stored wallet is still at its version,
+	// ErrConcurrentModification is returned otherwise.
 	UserRepositoryUpdater interface {
-		UpdateWallet(context.Context, vo.Uuid, vo.Money) error
+		UpdateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error
+	}
+
+	// UserWalletRepositoryCreator defines the operation of adding a wallet to
+	// a user entity. CreateWallet returns ErrWalletAlreadyExists when the user
+	// already has a wallet of the currency for the purpose.
+	UserWalletRepositoryCreator interface {
+		CreateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error
+	}
+
+	// UserRepository groups the operations on user entities
+	UserRepository interface {
+		UserRepositoryCreator
+		UserRepositoryFinder
+		UserRepositoryLister
+		UserRepositoryUpdater
+		UserWalletRepositoryCreator
 	}
 
 	// User define the user entity
 	User struct {
-		id        vo.Uuid
-		fullName  vo.FullName
-		email     vo.Email
-		password  vo.Password
-		document  vo.Document
-		wallet    *vo.Wallet
+		id       vo.Uuid
+		fullName vo.FullName
+		email    vo.Email
+		password vo.Password
+		document vo.Document
+		// wallets starts with the wallet the user was created with
+		wallets   []*vo.Wallet
 		typeUser  vo.TypeUser
 		roles     vo.Roles
 		createdAt time.Time
+		// emailChangedAt is when the email last changed, zero if it never did
+		emailChangedAt time.Time
 	}
 )
 
@@ -103,7 +165,7 @@ func NewCommonUser(
 		email:    email,
 		password: password,
 		document: document,
-		wallet:   wallet,
+		wallets:  []*vo.Wallet{wallet},
 		roles: vo.Roles{
 			CanTransfer: true,
 		},
@@ -128,7 +190,7 @@ func NewMerchantUser(
 		email:    email,
 		password: password,
 		document: document,
-		wallet:   wallet,
+		wallets:  []*vo.Wallet{wallet},
 		roles: vo.Roles{
 			CanTransfer: false,
 		},
@@ -137,20 +199,83 @@ func NewMerchantUser(
 	}
 }
 
-// Withdraw remove value of money of wallet
-func (u *User) Withdraw(money vo.Money) error {
-	if u.Wallet().Money().Amount().Value() < money.Amount().Value() {
+// Withdraw remove value of money of the wallet of its currency for the
+// purpose, the held money cannot be withdrawn
+func (u *User) Withdraw(money vo.Money, purpose vo.WalletPurpose) error {
+	wallet, err := u.FindWallet(money.Currency(), purpose)
+	if err != nil {
+		return err
+	}
+
+	if wallet.Available().Amount().Value() < money.Amount().Value() {
 		return ErrUserInsufficientBalance
 	}
 
-	u.Wallet().Sub(money.Amount())
+	wallet.Sub(money.Amount())
+
+	return nil
+}
+
+// Hold sets value of money of the main wallet of its currency aside for a payment
+func (u *User) Hold(money vo.Money) error {
+	wallet, err := u.FindWallet(money.Currency(), vo.MainWallet)
+	if err != nil {
+		return err
+	}
+
+	if wallet.Available().Amount().Value() < money.Amount().Value() {
+		return ErrUserInsufficientBalance
+	}
+
+	wallet.Hold(money.Amount())
+
+	return nil
+}
+
+// Release gives back value of money held for a payment
+func (u *User) Release(money vo.Money) error {
+	wallet, err := u.FindWallet(money.Currency(), vo.MainWallet)
+	if err != nil {
+		return err
+	}
+
+	wallet.Release(money.Amount())
+
+	return nil
+}
+
+// Deposit add value of money of the wallet of its currency for the purpose
+func (u *User) Deposit(money vo.Money, purpose vo.WalletPurpose) error {
+	wallet, err := u.FindWallet(money.Currency(), purpose)
+	if err != nil {
+		return err
+	}
+
+	wallet.Add(money.Amount())
+
+	return nil
+}
+
+// AddWallet gives the user a new wallet, one of each currency for each purpose
+func (u *User) AddWallet(wallet *vo.Wallet) error {
+	if _, err := u.FindWallet(wallet.Money().Currency(), wallet.Purpose()); err == nil {
+		return ErrWalletAlreadyExists
+	}
+
+	u.wallets = append(u.wallets, wallet)
 
 	return nil
 }
 
-// Deposit add value of money of wallet
-func (u *User) Deposit(money vo.Money) {
-	u.Wallet().Add(money.Amount())
+// FindWallet returns the wallet of the currency for the purpose, ErrNotFoundWallet when the user has none
+func (u User) FindWallet(currency vo.Currency, purpose vo.WalletPurpose) (*vo.Wallet, error) {
+	for _, w := range u.wallets {
+		if w.Is(currency, purpose) {
+			return w, nil
+		}
+	}
+
+	return nil, ErrNotFoundWallet
 }
 
 // CanTransfer returns whether it is possible to transfer
@@ -162,6 +287,19 @@ func (u User) CanTransfer() error {
 	return vo.ErrNotAllowedTypeUser
 }
 
+// WithEmailChangedAt returns the user with the time its email last changed
+func (u User) WithEmailChangedAt(at time.Time) User {
+	u.emailChangedAt = at
+	return u
+}
+
+// WithWallets returns the user owning the wallets, the first one being the
+// wallet it was created with
+func (u User) WithWallets(wallets []*vo.Wallet) User {
+	u.wallets = append([]*vo.Wallet(nil), wallets...)
+	return u
+}
+
 // ID return the id property
 func (u User) ID() vo.Uuid {
 	return u.id
@@ -192,9 +330,18 @@ func (u User) TypeUser() vo.TypeUser {
 	return u.typeUser
 }
 
-// Wallet return the wallet property
+// Wallet return the wallet the user was created with
 func (u User) Wallet() *vo.Wallet {
-	return u.wallet
+	if len(u.wallets) == 0 {
+		return nil
+	}
+
+	return u.wallets[0]
+}
+
+// Wallets return the wallets of the user, the one it was created with first
+func (u User) Wallets() []*vo.Wallet {
+	return append([]*vo.Wallet(nil), u.wallets...)
 }
 
 // Document return the document property
@@ -206,3 +353,8 @@ func (u User) Document() vo.Document {
 func (u User) CreatedAt() time.Time {
 	return u.createdAt
 }
+
+// EmailChangedAt return the emailChangedAt property
+func (u User) EmailChangedAt() time.Time {
+	return u.emailChangedAt
+}
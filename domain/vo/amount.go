@@ -34,6 +34,11 @@ func (a Amount) Value() int64 {
 	return a.value
 }
 
+// Percentage returns the given basis points of the Amount, 1% being 100, rounded half up
+func (a Amount) Percentage(basisPoints int64) Amount {
+	return Amount{value: (a.value*basisPoints + fullPercentage/2) / fullPercentage}
+}
+
 // String returns string representation of the Amount
 func (a Amount) String() string {
 	return strconv.FormatInt(a.value, 10)
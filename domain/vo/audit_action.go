@@ -0,0 +1,78 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// ReviewApprovedAction is the approval of a held transfer
+	ReviewApprovedAction AuditAction = "REVIEW_APPROVED"
+	// ReviewRejectedAction is the rejection of a held transfer
+	ReviewRejectedAction AuditAction = "REVIEW_REJECTED"
+	// WalletCreditedAction is the manual credit of a wallet by an operator
+	WalletCreditedAction AuditAction = "WALLET_CREDITED"
+	// WalletDebitedAction is the manual debit of a wallet by an operator
+	WalletDebitedAction AuditAction = "WALLET_DEBITED"
+	// UserCreatedAction is the creation of a user
+	UserCreatedAction AuditAction = "USER_CREATED"
+	// TransferCreatedAction is the creation of a transfer, made or held for a review
+	TransferCreatedAction AuditAction = "TRANSFER_CREATED"
+	// TransferRefundedAction is the refund of the payer of a rejected transfer
+	TransferRefundedAction AuditAction = "TRANSFER_REFUNDED"
+	// WithdrawalRefundedAction is the refund of a withdrawal the payment provider failed
+	WithdrawalRefundedAction AuditAction = "WITHDRAWAL_REFUNDED"
+)
+
+const (
+	UserTarget     AuditTarget = "USER"
+	TransferTarget AuditTarget = "TRANSFER"
+	ReviewTarget   AuditTarget = "REVIEW"
+	FundingTarget  AuditTarget = "FUNDING"
+)
+
+var (
+	ErrInvalidAuditAction = errors.New("invalid audit action")
+	ErrInvalidAuditTarget = errors.New("invalid audit target")
+)
+
+type (
+	// AuditAction define the actions recorded in the audit log. The roles of a
+	// user follow its type, recorded in the snapshot of its creation, and no use
+	// case changes them: there is no role change to record.
+	AuditAction string
+
+	// AuditTarget define the kinds of entities the actions recorded in the audit log are taken on
+	AuditTarget string
+)
+
+// NewAuditAction create new AuditAction
+func NewAuditAction(value string) (AuditAction, error) {
+	switch a := AuditAction(strings.ToUpper(value)); a {
+	case ReviewApprovedAction, ReviewRejectedAction, WalletCreditedAction, WalletDebitedAction,
+		UserCreatedAction, TransferCreatedAction, TransferRefundedAction, WithdrawalRefundedAction:
+		return a, nil
+	}
+
+	return "", ErrInvalidAuditAction
+}
+
+// String return string representation of the AuditAction
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// NewAuditTarget create new AuditTarget
+func NewAuditTarget(value string) (AuditTarget, error) {
+	switch t := AuditTarget(strings.ToUpper(value)); t {
+	case UserTarget, TransferTarget, ReviewTarget, FundingTarget:
+		return t, nil
+	}
+
+	return "", ErrInvalidAuditTarget
+}
+
+// String return string representation of the AuditTarget
+func (t AuditTarget) String() string {
+	return string(t)
+}
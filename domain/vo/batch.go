@@ -0,0 +1,93 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// BatchAtomic executes every transfer of the batch or none of them
+	BatchAtomic BatchMode = "ATOMIC"
+	// BatchBestEffort executes each transfer of the batch on its own
+	BatchBestEffort BatchMode = "BEST_EFFORT"
+)
+
+const (
+	BatchPending            BatchStatus = "PENDING"
+	BatchProcessing         BatchStatus = "PROCESSING"
+	BatchCompleted          BatchStatus = "COMPLETED"
+	BatchPartiallyCompleted BatchStatus = "PARTIALLY_COMPLETED"
+	BatchFailed             BatchStatus = "FAILED"
+)
+
+const (
+	BatchItemPending   BatchItemStatus = "PENDING"
+	BatchItemSucceeded BatchItemStatus = "SUCCEEDED"
+	BatchItemFailed    BatchItemStatus = "FAILED"
+	// BatchItemSkipped is a transfer rolled back because another one of its atomic batch failed
+	BatchItemSkipped BatchItemStatus = "SKIPPED"
+)
+
+var (
+	ErrInvalidBatchMode = errors.New("invalid batch mode")
+
+	ErrInvalidBatchStatus = errors.New("invalid batch status")
+
+	ErrInvalidBatchItemStatus = errors.New("invalid batch item status")
+)
+
+type (
+	// BatchMode define how the transfers of a batch are executed
+	BatchMode string
+
+	// BatchStatus define the states of a batch
+	BatchStatus string
+
+	// BatchItemStatus define the states of a transfer of a batch
+	BatchItemStatus string
+)
+
+// NewBatchMode create new BatchMode
+func NewBatchMode(value string) (BatchMode, error) {
+	switch m := BatchMode(strings.ToUpper(value)); m {
+	case BatchAtomic, BatchBestEffort:
+		return m, nil
+	}
+
+	return "", ErrInvalidBatchMode
+}
+
+// String return string representation of the BatchMode
+func (m BatchMode) String() string {
+	return string(m)
+}
+
+// NewBatchStatus create new BatchStatus
+func NewBatchStatus(value string) (BatchStatus, error) {
+	switch s := BatchStatus(strings.ToUpper(value)); s {
+	case BatchPending, BatchProcessing, BatchCompleted, BatchPartiallyCompleted, BatchFailed:
+		return s, nil
+	}
+
+	return "", ErrInvalidBatchStatus
+}
+
+// String return string representation of the BatchStatus
+func (s BatchStatus) String() string {
+	return string(s)
+}
+
+// NewBatchItemStatus create new BatchItemStatus
+func NewBatchItemStatus(value string) (BatchItemStatus, error) {
+	switch s := BatchItemStatus(strings.ToUpper(value)); s {
+	case BatchItemPending, BatchItemSucceeded, BatchItemFailed, BatchItemSkipped:
+		return s, nil
+	}
+
+	return "", ErrInvalidBatchItemStatus
+}
+
+// String return string representation of the BatchItemStatus
+func (s BatchItemStatus) String() string {
+	return string(s)
+}
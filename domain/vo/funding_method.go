@@ -0,0 +1,39 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// BankTransferMethod moves the money from or to a bank account
+	BankTransferMethod FundingMethod = "BANK_TRANSFER"
+	// BoletoMethod collects the money with a boleto paid by the user
+	BoletoMethod FundingMethod = "BOLETO"
+	// CardMethod charges or refunds a card of the user
+	CardMethod FundingMethod = "CARD"
+)
+
+var (
+	ErrInvalidFundingMethod = errors.New("invalid funding method")
+)
+
+type (
+	// FundingMethod define how the payment provider moves the money of a funding
+	FundingMethod string
+)
+
+// NewFundingMethod create new FundingMethod
+func NewFundingMethod(value string) (FundingMethod, error) {
+	switch m := FundingMethod(strings.ToUpper(value)); m {
+	case BankTransferMethod, BoletoMethod, CardMethod:
+		return m, nil
+	}
+
+	return "", ErrInvalidFundingMethod
+}
+
+// String return string representation of the FundingMethod
+func (m FundingMethod) String() string {
+	return string(m)
+}
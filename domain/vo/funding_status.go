@@ -0,0 +1,39 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// FundingPending is a funding the payment provider did not settle yet
+	FundingPending FundingStatus = "PENDING"
+	// FundingSettled is a funding whose money was moved by the payment provider
+	FundingSettled FundingStatus = "SETTLED"
+	// FundingFailed is a funding the payment provider could not make
+	FundingFailed FundingStatus = "FAILED"
+)
+
+var (
+	ErrInvalidFundingStatus = errors.New("invalid funding status")
+)
+
+type (
+	// FundingStatus define the states of a deposit or a withdrawal
+	FundingStatus string
+)
+
+// NewFundingStatus create new FundingStatus
+func NewFundingStatus(value string) (FundingStatus, error) {
+	switch s := FundingStatus(strings.ToUpper(value)); s {
+	case FundingPending, FundingSettled, FundingFailed:
+		return s, nil
+	}
+
+	return "", ErrInvalidFundingStatus
+}
+
+// String return string representation of the FundingStatus
+func (s FundingStatus) String() string {
+	return string(s)
+}
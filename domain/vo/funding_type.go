@@ -0,0 +1,37 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// Deposit is money entering a wallet from outside the system
+	Deposit FundingType = "DEPOSIT"
+	// Withdrawal is money paid out of a wallet
+	Withdrawal FundingType = "WITHDRAWAL"
+)
+
+var (
+	ErrInvalidFundingType = errors.New("invalid funding type")
+)
+
+type (
+	// FundingType define the direction of a funding
+	FundingType string
+)
+
+// NewFundingType create new FundingType
+func NewFundingType(value string) (FundingType, error) {
+	switch t := FundingType(strings.ToUpper(value)); t {
+	case Deposit, Withdrawal:
+		return t, nil
+	}
+
+	return "", ErrInvalidFundingType
+}
+
+// String return string representation of the FundingType
+func (t FundingType) String() string {
+	return string(t)
+}
@@ -0,0 +1,24 @@
+package vo
+
+const (
+	// TransactionLimit caps the value of a single transfer
+	TransactionLimit LimitKind = "TRANSACTION"
+	// DailyLimit caps the value transferred in a calendar day
+	DailyLimit LimitKind = "DAILY"
+	// MonthlyLimit caps the value transferred in a calendar month
+	MonthlyLimit LimitKind = "MONTHLY"
+	// VelocityLimit caps the number of transfers in a window of time
+	VelocityLimit LimitKind = "VELOCITY"
+	// NightLimit caps the value transferred during the night
+	NightLimit LimitKind = "NIGHT"
+)
+
+type (
+	// LimitKind define the limits of the transfers of a payer
+	LimitKind string
+)
+
+// String return string representation of the LimitKind
+func (k LimitKind) String() string {
+	return string(k)
+}
@@ -0,0 +1,41 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// PaymentAuthorized is a payment holding its value on the wallet of the payer
+	PaymentAuthorized PaymentStatus = "AUTHORIZED"
+	// PaymentCaptured is a payment whose captured value was transferred to the merchant
+	PaymentCaptured PaymentStatus = "CAPTURED"
+	// PaymentVoided is a payment released before being captured
+	PaymentVoided PaymentStatus = "VOIDED"
+	// PaymentExpired is a payment released because nobody captured it in time
+	PaymentExpired PaymentStatus = "EXPIRED"
+)
+
+var (
+	ErrInvalidPaymentStatus = errors.New("invalid payment status")
+)
+
+type (
+	// PaymentStatus define the states of a merchant payment
+	PaymentStatus string
+)
+
+// NewPaymentStatus create new PaymentStatus
+func NewPaymentStatus(value string) (PaymentStatus, error) {
+	switch s := PaymentStatus(strings.ToUpper(value)); s {
+	case PaymentAuthorized, PaymentCaptured, PaymentVoided, PaymentExpired:
+		return s, nil
+	}
+
+	return "", ErrInvalidPaymentStatus
+}
+
+// String return string representation of the PaymentStatus
+func (s PaymentStatus) String() string {
+	return string(s)
+}
@@ -0,0 +1,163 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	ONCE    Frequency = "ONCE"
+	DAILY   Frequency = "DAILY"
+	WEEKLY  Frequency = "WEEKLY"
+	MONTHLY Frequency = "MONTHLY"
+)
+
+var (
+	ErrInvalidFrequency = errors.New("invalid frequency")
+
+	ErrInvalidDayOfMonth = errors.New("day of month must be between 1 and 31")
+
+	ErrInvalidOccurrenceCount = errors.New("occurrence count must not be negative")
+)
+
+type (
+	// Frequency define how often a recurrence repeats
+	Frequency string
+
+	// Recurrence define when the occurrences of a schedule happen
+	Recurrence struct {
+		frequency  Frequency
+		dayOfMonth int
+		endAt      time.Time
+		count      int
+	}
+)
+
+// NewFrequency create new Frequency
+func NewFrequency(value string) (Frequency, error) {
+	switch f := Frequency(strings.ToUpper(value)); f {
+	case ONCE, DAILY, WEEKLY, MONTHLY:
+		return f, nil
+	}
+
+	return "", ErrInvalidFrequency
+}
+
+// String return string representation of the Frequency
+func (f Frequency) String() string {
+	return string(f)
+}
+
+// NewRecurrence create new Recurrence. dayOfMonth is only used by MONTHLY and is
+// clamped to the last day of the shorter months. A zero endAt or count means
+// the recurrence never ends.
+func NewRecurrence(frequency Frequency, dayOfMonth int, endAt time.Time, count int) (Recurrence, error) {
+	if _, err := NewFrequency(frequency.String()); err != nil {
+		return Recurrence{}, err
+	}
+
+	if frequency == MONTHLY && (dayOfMonth < 1 || dayOfMonth > 31) {
+		return Recurrence{}, ErrInvalidDayOfMonth
+	}
+
+	if count < 0 {
+		return Recurrence{}, ErrInvalidOccurrenceCount
+	}
+
+	if frequency != MONTHLY {
+		dayOfMonth = 0
+	}
+
+	return Recurrence{
+		frequency:  frequency,
+		dayOfMonth: dayOfMonth,
+		endAt:      endAt,
+		count:      count,
+	}, nil
+}
+
+// NewRecurrenceOnce create new Recurrence with a single occurrence
+func NewRecurrenceOnce() Recurrence {
+	return Recurrence{frequency: ONCE}
+}
+
+// Frequency returns the frequency property
+func (r Recurrence) Frequency() Frequency {
+	return r.frequency
+}
+
+// DayOfMonth returns the dayOfMonth property
+func (r Recurrence) DayOfMonth() int {
+	return r.dayOfMonth
+}
+
+// EndAt returns the endAt property
+func (r Recurrence) EndAt() time.Time {
+	return r.endAt
+}
+
+// Count returns the count property
+func (r Recurrence) Count() int {
+	return r.count
+}
+
+// First returns the first occurrence at or after start
+func (r Recurrence) First(start time.Time) time.Time {
+	if r.frequency != MONTHLY {
+		return start
+	}
+
+	first := r.onDay(start.Year(), start.Month(), start)
+	if first.Before(start) {
+		return r.onDay(start.Year(), start.Month()+1, start)
+	}
+
+	return first
+}
+
+// Next returns the occurrence following previous, the zero time for ONCE
+func (r Recurrence) Next(previous time.Time) time.Time {
+	switch r.frequency {
+	case DAILY:
+		return previous.AddDate(0, 0, 1)
+	case WEEKLY:
+		return previous.AddDate(0, 0, 7)
+	case MONTHLY:
+		return r.onDay(previous.Year(), previous.Month()+1, previous)
+	}
+
+	return time.Time{}
+}
+
+// Ended reports whether no occurrence follows the given number of occurrences
+// when the next one would happen at next
+func (r Recurrence) Ended(occurrences int, next time.Time) bool {
+	switch {
+	case r.frequency == ONCE:
+		return occurrences >= 1
+	case r.count > 0 && occurrences >= r.count:
+		return true
+	case !r.endAt.IsZero() && next.After(r.endAt):
+		return true
+	}
+
+	return false
+}
+
+// onDay returns the day of month of the recurrence in the given month, at the clock of at
+func (r Recurrence) onDay(year int, month time.Month, at time.Time) time.Time {
+	// normalizes month overflows, e.g. the 13th month of a year
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, at.Location())
+
+	day := r.dayOfMonth
+	if last := firstOfMonth.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return time.Date(
+		firstOfMonth.Year(), firstOfMonth.Month(), day,
+		at.Hour(), at.Minute(), at.Second(), at.Nanosecond(),
+		at.Location(),
+	)
+}
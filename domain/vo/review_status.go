@@ -0,0 +1,36 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	ReviewPending  ReviewStatus = "PENDING"
+	ReviewApproved ReviewStatus = "APPROVED"
+	ReviewRejected ReviewStatus = "REJECTED"
+)
+
+var (
+	ErrInvalidReviewStatus = errors.New("invalid review status")
+)
+
+type (
+	// ReviewStatus define the states of the review of a held transfer
+	ReviewStatus string
+)
+
+// NewReviewStatus create new ReviewStatus
+func NewReviewStatus(value string) (ReviewStatus, error) {
+	switch s := ReviewStatus(strings.ToUpper(value)); s {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+		return s, nil
+	}
+
+	return "", ErrInvalidReviewStatus
+}
+
+// String return string representation of the ReviewStatus
+func (s ReviewStatus) String() string {
+	return string(s)
+}
@@ -0,0 +1,67 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	RiskApprove RiskDecision = "APPROVE"
+	RiskReview  RiskDecision = "REVIEW"
+	RiskDeny    RiskDecision = "DENY"
+)
+
+const (
+	// NewPayeeSignal is a transfer to a payee the payer never paid
+	NewPayeeSignal RiskSignal = "NEW_PAYEE"
+	// AmountAboveAverageSignal is a transfer far above the average of the payer
+	AmountAboveAverageSignal RiskSignal = "AMOUNT_ABOVE_AVERAGE"
+	// ManyPayeesSignal is a payer paying many payees in a short window
+	ManyPayeesSignal RiskSignal = "MANY_PAYEES"
+	// EmailChangedSignal is a payer whose email changed recently
+	EmailChangedSignal RiskSignal = "EMAIL_CHANGED"
+)
+
+var (
+	ErrInvalidRiskDecision = errors.New("invalid risk decision")
+
+	ErrInvalidRiskSignal = errors.New("invalid risk signal")
+)
+
+type (
+	// RiskDecision define the outcomes of the risk assessment of a transfer
+	RiskDecision string
+
+	// RiskSignal define the behaviors scored by the risk rules
+	RiskSignal string
+)
+
+// NewRiskDecision create new RiskDecision
+func NewRiskDecision(value string) (RiskDecision, error) {
+	switch d := RiskDecision(strings.ToUpper(value)); d {
+	case RiskApprove, RiskReview, RiskDeny:
+		return d, nil
+	}
+
+	return "", ErrInvalidRiskDecision
+}
+
+// String return string representation of the RiskDecision
+func (d RiskDecision) String() string {
+	return string(d)
+}
+
+// NewRiskSignal create new RiskSignal
+func NewRiskSignal(value string) (RiskSignal, error) {
+	switch s := RiskSignal(strings.ToUpper(value)); s {
+	case NewPayeeSignal, AmountAboveAverageSignal, ManyPayeesSignal, EmailChangedSignal:
+		return s, nil
+	}
+
+	return "", ErrInvalidRiskSignal
+}
+
+// String return string representation of the RiskSignal
+func (s RiskSignal) String() string {
+	return string(s)
+}
@@ -0,0 +1,37 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	ScheduleActive    ScheduleStatus = "ACTIVE"
+	SchedulePaused    ScheduleStatus = "PAUSED"
+	ScheduleCanceled  ScheduleStatus = "CANCELED"
+	ScheduleCompleted ScheduleStatus = "COMPLETED"
+)
+
+var (
+	ErrInvalidScheduleStatus = errors.New("invalid schedule status")
+)
+
+type (
+	// ScheduleStatus define the states of a schedule
+	ScheduleStatus string
+)
+
+// NewScheduleStatus create new ScheduleStatus
+func NewScheduleStatus(value string) (ScheduleStatus, error) {
+	switch s := ScheduleStatus(strings.ToUpper(value)); s {
+	case ScheduleActive, SchedulePaused, ScheduleCanceled, ScheduleCompleted:
+		return s, nil
+	}
+
+	return "", ErrInvalidScheduleStatus
+}
+
+// String return string representation of the ScheduleStatus
+func (s ScheduleStatus) String() string {
+	return string(s)
+}
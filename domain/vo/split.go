@@ -0,0 +1,123 @@
+package vo
+
+import (
+	"errors"
+	"sort"
+)
+
+// fullPercentage is 100% in basis points
+const fullPercentage = 10000
+
+var (
+	ErrInvalidPercentage = errors.New("percentage must be greater than 0 and at most 100")
+
+	ErrSplitMismatch = errors.New("split does not add up to the transfer value")
+
+	ErrEmptySplitShare = errors.New("split share must be greater than zero")
+)
+
+// SplitShare define the part of a split payment going to a payee, either an
+// absolute amount or a percentage
+type SplitShare struct {
+	amount      Amount
+	basisPoints int64
+	percentage  bool
+}
+
+// NewAmountShare create new SplitShare of an absolute amount
+func NewAmountShare(amount Amount) SplitShare {
+	return SplitShare{amount: amount}
+}
+
+// NewPercentageShare create new SplitShare of a percentage given in basis points, 1% being 100
+func NewPercentageShare(basisPoints int64) (SplitShare, error) {
+	if basisPoints <= 0 || basisPoints > fullPercentage {
+		return SplitShare{}, ErrInvalidPercentage
+	}
+
+	return SplitShare{basisPoints: basisPoints, percentage: true}, nil
+}
+
+// IsPercentage reports whether the share is a percentage
+func (s SplitShare) IsPercentage() bool {
+	return s.percentage
+}
+
+// Amount returns the absolute amount of the share, zero for a percentage
+func (s SplitShare) Amount() Amount {
+	return s.amount
+}
+
+// BasisPoints returns the percentage of the share in basis points, zero for an amount
+func (s SplitShare) BasisPoints() int64 {
+	return s.basisPoints
+}
+
+// Allocate splits total between the shares, to the cent. The absolute amounts
+// are taken first and the percentages, which must add up to 100%, split the
+// rest. Without percentages the amounts must add up to total. The cents left
+// by rounding down the percentages go to the largest remainders, the first
+// shares winning the ties. A share allocated nothing is an ErrEmptySplitShare.
+func Allocate(total Amount, shares []SplitShare) ([]Amount, error) {
+	var (
+		rest        = total.Value()
+		basisPoints int64
+		allocated   = make([]Amount, len(shares))
+	)
+
+	for i, s := range shares {
+		if s.percentage {
+			basisPoints += s.basisPoints
+			continue
+		}
+		rest -= s.amount.Value()
+		allocated[i] = s.amount
+	}
+
+	switch {
+	case rest < 0:
+		return nil, ErrSplitMismatch
+	case basisPoints == 0 && rest != 0:
+		return nil, ErrSplitMismatch
+	case basisPoints == 0:
+		return allocated, checkAllocated(allocated)
+	case basisPoints != fullPercentage:
+		return nil, ErrSplitMismatch
+	}
+
+	type remainder struct {
+		index int
+		value int64
+	}
+
+	var (
+		remainders []remainder
+		left       = rest
+	)
+	for i, s := range shares {
+		if !s.percentage {
+			continue
+		}
+		share := rest * s.basisPoints
+		allocated[i] = Amount{value: share / fullPercentage}
+		left -= allocated[i].value
+		remainders = append(remainders, remainder{index: i, value: share % fullPercentage})
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool { return remainders[a].value > remainders[b].value })
+	for i := int64(0); i < left; i++ {
+		allocated[remainders[i].index].value++
+	}
+
+	return allocated, checkAllocated(allocated)
+}
+
+func checkAllocated(allocated []Amount) error {
+	for _, a := range allocated {
+		if a.value <= 0 {
+			return ErrEmptySplitShare
+		}
+	}
+
+	return nil
+}
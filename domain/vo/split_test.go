@@ -0,0 +1,91 @@
+package vo_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int64
+		shares []vo.SplitShare
+		want   []int64
+		err    error
+	}{
+		{
+			name:   "amounts",
+			total:  100,
+			shares: []vo.SplitShare{amountShare(60), amountShare(40)},
+			want:   []int64{60, 40},
+		},
+		{
+			name:   "amounts not adding up",
+			total:  100,
+			shares: []vo.SplitShare{amountShare(60), amountShare(30)},
+			err:    vo.ErrSplitMismatch,
+		},
+		{
+			name:   "remainders to the largest then the first",
+			total:  100,
+			shares: []vo.SplitShare{percentageShare(t, 3333), percentageShare(t, 3333), percentageShare(t, 3334)},
+			want:   []int64{33, 33, 34},
+		},
+		{
+			name:   "percentages of the rest",
+			total:  101,
+			shares: []vo.SplitShare{amountShare(1), percentageShare(t, 5000), percentageShare(t, 5000)},
+			want:   []int64{1, 50, 50},
+		},
+		{
+			name:   "percentage rounded to nothing",
+			total:  1,
+			shares: []vo.SplitShare{percentageShare(t, 5000), percentageShare(t, 5000)},
+			err:    vo.ErrEmptySplitShare,
+		},
+		{
+			name:   "empty amount",
+			total:  100,
+			shares: []vo.SplitShare{amountShare(100), amountShare(0)},
+			err:    vo.ErrEmptySplitShare,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vo.Allocate(vo.NewAmountTest(tt.total), tt.shares)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Allocate() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			values := make([]int64, len(got))
+			for i, a := range got {
+				values[i] = a.Value()
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("Allocate() = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func amountShare(value int64) vo.SplitShare {
+	return vo.NewAmountShare(vo.NewAmountTest(value))
+}
+
+func percentageShare(t *testing.T, basisPoints int64) vo.SplitShare {
+	t.Helper()
+
+	s, err := vo.NewPercentageShare(basisPoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
@@ -0,0 +1,39 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// TransferCompleted is a transfer credited to its payees
+	TransferCompleted TransferStatus = "COMPLETED"
+	// TransferHeld is a transfer debited from the payer, waiting for a review to be credited
+	TransferHeld TransferStatus = "HELD"
+	// TransferRejected is a held transfer refunded to the payer
+	TransferRejected TransferStatus = "REJECTED"
+)
+
+var (
+	ErrInvalidTransferStatus = errors.New("invalid transfer status")
+)
+
+type (
+	// TransferStatus define the states of a transfer
+	TransferStatus string
+)
+
+// NewTransferStatus create new TransferStatus
+func NewTransferStatus(value string) (TransferStatus, error) {
+	switch s := TransferStatus(strings.ToUpper(value)); s {
+	case TransferCompleted, TransferHeld, TransferRejected:
+		return s, nil
+	}
+
+	return "", ErrInvalidTransferStatus
+}
+
+// String return string representation of the TransferStatus
+func (s TransferStatus) String() string {
+	return string(s)
+}
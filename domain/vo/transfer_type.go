@@ -0,0 +1,45 @@
+package vo
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// DirectTransfer is a transfer requested to a single payee
+	DirectTransfer TransferType = "DIRECT"
+	// SplitTransfer is a transfer divided between several payees
+	SplitTransfer TransferType = "SPLIT"
+	// ScheduledTransfer is an occurrence of a schedule
+	ScheduledTransfer TransferType = "SCHEDULED"
+	// BatchTransfer is a transfer of a batch
+	BatchTransfer TransferType = "BATCH"
+	// PaymentTransfer is the capture of a merchant payment
+	PaymentTransfer TransferType = "PAYMENT"
+	// WithdrawalTransfer is a withdrawal submitted to the authorizer, it is never stored
+	WithdrawalTransfer TransferType = "WITHDRAWAL"
+)
+
+var (
+	ErrInvalidTransferType = errors.New("invalid transfer type")
+)
+
+type (
+	// TransferType define how a transfer was requested
+	TransferType string
+)
+
+// NewTransferType create new TransferType
+func NewTransferType(value string) (TransferType, error) {
+	switch t := TransferType(strings.ToUpper(value)); t {
+	case DirectTransfer, SplitTransfer, ScheduledTransfer, BatchTransfer, PaymentTransfer, WithdrawalTransfer:
+		return t, nil
+	}
+
+	return "", ErrInvalidTransferType
+}
+
+// String return string representation of the TransferType
+func (t TransferType) String() string {
+	return string(t)
+}
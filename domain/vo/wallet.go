@@ -1,20 +1,60 @@
 package vo
 
-// Wallet structure
+// Wallet structure. Its money is the ledger balance, the held amount is set
+// aside for the payments authorized but not captured yet. A wallet is keyed
+// by the currency of its money and its purpose.
 type Wallet struct {
-	money Money
+	money   Money
+	purpose WalletPurpose
+	held    Amount
+	version int64
 }
 
-// NewWallet create new Wallet
+// NewWallet create new main Wallet
 func NewWallet(money Money) *Wallet {
-	return &Wallet{money: money}
+	return &Wallet{money: money, purpose: MainWallet}
 }
 
-// Money return value money
+// NewWalletWithPurpose create new Wallet used for the purpose
+func NewWalletWithPurpose(money Money, purpose WalletPurpose) *Wallet {
+	return &Wallet{money: money, purpose: purpose}
+}
+
+// RestoreWallet rebuilds a Wallet read from the storage at the given version
+func RestoreWallet(money Money, purpose WalletPurpose, held Amount, version int64) *Wallet {
+	return &Wallet{money: money, purpose: purpose, held: held, version: version}
+}
+
+// Money return value money, the ledger balance
 func (w Wallet) Money() Money {
 	return w.money
 }
 
+// Purpose return the purpose of the wallet
+func (w Wallet) Purpose() WalletPurpose {
+	return w.purpose
+}
+
+// Is reports whether the wallet is the one of the currency used for the purpose
+func (w Wallet) Is(currency Currency, purpose WalletPurpose) bool {
+	return w.money.Currency() == currency && w.purpose == purpose
+}
+
+// Held return the amount held by the authorized payments
+func (w Wallet) Held() Amount {
+	return w.held
+}
+
+// Available return the money which can be spent, the ledger balance less the held amount
+func (w Wallet) Available() Money {
+	return w.money.Sub(w.held)
+}
+
+// Version return the stored version the wallet was read at, used for optimistic locking
+func (w Wallet) Version() int64 {
+	return w.version
+}
+
 // Add value in money value amount
 func (w *Wallet) Add(amount Amount) Money {
 	w.money = w.money.Add(amount)
@@ -27,10 +67,20 @@ func (w *Wallet) Sub(amount Amount) Money {
 	return w.money
 }
 
+// Hold sets the amount aside, reducing the available money but not the ledger balance
+func (w *Wallet) Hold(amount Amount) {
+	w.held = Amount{value: w.held.value + amount.value}
+}
+
+// Release gives back the amount held
+func (w *Wallet) Release(amount Amount) {
+	w.held = Amount{value: w.held.value - amount.value}
+}
+
 // Equals check that two wallet are the same
 func (w *Wallet) Equals(value Value) bool {
 	o, ok := value.(*Wallet)
-	return ok && w.money == o.money
+	return ok && w.money == o.money && w.purpose == o.purpose && w.held == o.held
 }
 
 func (w *Wallet) NewMoney(money Money) {
@@ -0,0 +1,43 @@
+package vo
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MainWallet is the purpose of the wallet used unless another one is addressed
+	MainWallet WalletPurpose = "main"
+)
+
+var (
+	ErrInvalidWalletPurpose = errors.New("invalid wallet purpose")
+
+	walletPurposeRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+)
+
+type (
+	// WalletPurpose define what a wallet is used for, like "savings". A user
+	// owns at most one wallet of a currency for each purpose.
+	WalletPurpose string
+)
+
+// NewWalletPurpose create new WalletPurpose, MainWallet when empty
+func NewWalletPurpose(value string) (WalletPurpose, error) {
+	if value == "" {
+		return MainWallet, nil
+	}
+
+	p := WalletPurpose(strings.ToLower(value))
+	if !walletPurposeRegex.MatchString(string(p)) {
+		return "", ErrInvalidWalletPurpose
+	}
+
+	return p, nil
+}
+
+// String return string representation of the WalletPurpose
+func (p WalletPurpose) String() string {
+	return string(p)
+}
@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dungnguyen/clean-architecture/adapter/cli"
+	adapterlogger "github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+	"github.com/dungnguyen/clean-architecture/infrastructure/metrics"
+	"github.com/dungnguyen/clean-architecture/infrastructure/pricing"
+	"github.com/dungnguyen/clean-architecture/infrastructure/queue"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// adminUserAgent is the user agent of the actions of the admin tool in the audit log
+const adminUserAgent = "admin-cli"
+
+// Admin define the admin command-line tool, operating the storage selected by
+// STORAGE_DRIVER through the use cases of the application
+type Admin struct {
+	storage  *storage
+	logger   adapterlogger.Logger
+	queue    *queue.RabbitMQHandler
+	pricing  entity.Pricing
+	commands []cli.Command
+}
+
+// NewAdmin create new Admin with its dependencies, the audited changes being
+// made by the actor. The configuration is read from the environment variables.
+func NewAdmin(actor string) (*Admin, error) {
+	a := &Admin{logger: logger.NewLogrus()}
+
+	p, err := pricing.NewPricing()
+	if err != nil {
+		return nil, err
+	}
+	a.pricing = p
+
+	st, err := newStorage(storageDriver())
+	if err != nil {
+		return nil, err
+	}
+	a.storage = st
+
+	if os.Getenv("RABBITMQ_URI") != "" {
+		if a.queue, err = queue.NewRabbitMQHandler(); err != nil {
+			_ = st.close(context.Background())
+			return nil, err
+		}
+	}
+
+	a.commands = a.newCommands(actor)
+
+	return a, nil
+}
+
+// Run runs the command named by the first argument, writing its output in the
+// format. The actions it appends to the audit log share a correlation ID.
+func (a *Admin) Run(ctx context.Context, w io.Writer, format string, args []string) error {
+	p, err := cli.NewPrinter(w, format)
+	if err != nil {
+		return errors.Wrap(cli.ErrUsage, err.Error())
+	}
+
+	if len(args) == 0 {
+		return errors.Wrap(cli.ErrUsage, "a command is required")
+	}
+
+	ctx = usecase.ContextWithAuditOrigin(ctx, entity.AuditOrigin{
+		CorrelationID: uuid.New().String(),
+		UserAgent:     adminUserAgent,
+	})
+
+	for _, c := range a.commands {
+		if c.Name == args[0] {
+			return c.Run(ctx, args[1:], p)
+		}
+	}
+
+	return errors.Wrap(cli.ErrUsage, fmt.Sprintf("unknown command %q", args[0]))
+}
+
+// Usage writes the commands with their arguments
+func (a *Admin) Usage(w io.Writer) {
+	for _, c := range a.commands {
+		fmt.Fprintf(w, "  %s\n    \t%s\n", strings.TrimSpace(c.Name+" "+c.Usage), c.Summary)
+	}
+}
+
+// Close disconnects from the dependencies
+func (a *Admin) Close(ctx context.Context) error {
+	if a.queue != nil {
+		_ = a.queue.Close()
+	}
+
+	return a.storage.close(ctx)
+}
+
+func (a *Admin) newCommands(actor string) []cli.Command {
+	var (
+		listUsers = usecase.NewListUsersInteractor(
+			a.storage.users,
+			presenter.NewListUsersPresenter())
+		listTransfers = usecase.NewListTransfersInteractor(
+			a.storage.transferLister,
+			presenter.NewListTransfersPresenter())
+		adjustWallet = usecase.NewAdjustWalletInteractor(
+			a.storage.transferCreator,
+			a.storage.users,
+			a.storage.users,
+			a.storage.audit,
+			presenter.NewAdjustWalletPresenter())
+		notifier = newTransferNotifier(
+			metrics.Dummy{},
+			newProducer(a.queue, metrics.Dummy{}, a.logger),
+			a.logger)
+		reindex  cli.Reindexer
+		migrator cli.Migrator
+	)
+	if db := a.storage.mongo; db != nil {
+		reindex = db.EnsureIndexes
+		migrator = db
+	}
+
+	return []cli.Command{
+		cli.NewCreateUserCommand(usecase.NewCreateUserInteractor(
+			a.storage.transferCreator,
+			a.storage.users,
+			a.storage.audit,
+			presenter.NewCreateUserPresenter()), actor),
+		cli.NewShowUserCommand(usecase.NewFindUserByIDInteractor(
+			a.storage.users,
+			presenter.NewFindUserByIDPresenter())),
+		cli.NewAdjustWalletCommand(adjustWallet, usecase.CreditWallet, actor),
+		cli.NewAdjustWalletCommand(adjustWallet, usecase.DebitWallet, actor),
+		cli.NewListTransfersCommand(listTransfers),
+		cli.NewReplayOutboxCommand(usecase.NewReplayNotificationsInteractor(
+			a.storage.transferLister,
+			presenter.NewReplayNotificationsPresenter(),
+			notifier)),
+		cli.NewMigrateCommand(migrator),
+		cli.NewReindexCommand(reindex),
+		cli.NewExportCommand(listUsers, listTransfers),
+		cli.NewReconcileCommand(newReconcileUseCase(a.storage, a.pricing)),
+		cli.NewAuditCommand(usecase.NewVerifyAuditInteractor(a.storage.audit)),
+	}
+}
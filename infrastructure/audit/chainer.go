@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var (
+	defaultInterval  = time.Second
+	defaultBatchSize = 500
+)
+
+type (
+	// Option is the Chainer options
+	Option func(*Chainer)
+
+	// Chainer periodically chains the pending audit entries until it is stopped.
+	// The chainers of several instances do not fork the chain, the one losing
+	// the race leaving the entries to the other.
+	Chainer struct {
+		uc        usecase.ChainAuditUseCase
+		log       logger.Logger
+		logKey    string
+		interval  time.Duration
+		batchSize int
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewChainer create new Chainer with its dependencies
+func NewChainer(uc usecase.ChainAuditUseCase, l logger.Logger, opts ...Option) *Chainer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &Chainer{
+		uc:        uc,
+		log:       l,
+		logKey:    "audit_chainer",
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// WithInterval defines how often the pending entries are looked for
+func WithInterval(d time.Duration) Option {
+	return func(c *Chainer) {
+		c.interval = d
+	}
+}
+
+// WithBatchSize defines how many entries are chained at once
+func WithBatchSize(n int) Option {
+	return func(c *Chainer) {
+		c.batchSize = n
+	}
+}
+
+// Run chains the pending entries every interval and blocks until Stop is called
+func (c *Chainer) Run() error {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.tick()
+
+		select {
+		case <-c.stop:
+			// the entries appended until the stop are chained before leaving
+			c.tick()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the running execution to finish, or cancels it when ctx is done
+func (c *Chainer) Stop(ctx context.Context) error {
+	c.once.Do(func() { close(c.stop) })
+
+	select {
+	case <-c.done:
+		c.cancel()
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		return ctx.Err()
+	}
+}
+
+// tick chains batches of pending entries until none is left or the chaining fails
+func (c *Chainer) tick() {
+	for {
+		output, err := c.uc.Execute(c.ctx, usecase.ChainAuditInput{Limit: c.batchSize})
+		if err != nil {
+			c.log.WithFields(logger.Fields{
+				"key":     c.logKey,
+				"chained": output.Chained,
+				"error":   err.Error(),
+			}).Errorf("failed to chain the audit entries")
+			return
+		}
+
+		if output.Chained < c.batchSize {
+			return
+		}
+	}
+}
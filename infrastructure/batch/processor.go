@@ -0,0 +1,149 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var (
+	defaultInterval = 30 * time.Second
+	defaultLimit    = 10
+)
+
+type (
+	// Option is the Processor options
+	Option func(*Processor)
+
+	// Processor executes the batches of transfers as soon as they are dispatched.
+	// It also looks for unfinished batches every interval, which resumes the
+	// batches interrupted by a restart.
+	Processor struct {
+		uc       usecase.ProcessBatchTransfersUseCase
+		log      logger.Logger
+		logKey   string
+		interval time.Duration
+		limit    int
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		wake   chan struct{}
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewProcessor create new Processor with its dependencies
+func NewProcessor(uc usecase.ProcessBatchTransfersUseCase, l logger.Logger, opts ...Option) *Processor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Processor{
+		uc:       uc,
+		log:      l,
+		logKey:   "batch_processor",
+		interval: defaultInterval,
+		limit:    defaultLimit,
+		ctx:      ctx,
+		cancel:   cancel,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(p)
+	}
+
+	return p
+}
+
+// WithInterval defines how often the unfinished batches are looked for
+func WithInterval(d time.Duration) Option {
+	return func(p *Processor) {
+		p.interval = d
+	}
+}
+
+// WithLimit defines how many batches are loaded at once
+func WithLimit(n int) Option {
+	return func(p *Processor) {
+		p.limit = n
+	}
+}
+
+// Dispatch wakes the processor up, it never blocks
+func (p *Processor) Dispatch(_ context.Context, _ entity.Batch) {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes the batches when dispatched or every interval and blocks until Stop is called
+func (p *Processor) Run() error {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.drain()
+
+		select {
+		case <-p.stop:
+			return nil
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the running batch to finish, or cancels it when ctx is done
+func (p *Processor) Stop(ctx context.Context) error {
+	p.once.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// drain processes the unfinished batches until none is left or a batch is deferred
+func (p *Processor) drain() {
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		output, err := p.uc.Execute(p.ctx, usecase.ProcessBatchTransfersInput{Limit: p.limit})
+
+		fields := logger.Fields{
+			"key":      p.logKey,
+			"finished": output.Finished,
+			"deferred": output.Deferred,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			p.log.WithFields(fields).Errorf("failed to process batches of transfers")
+			return
+		}
+
+		if output.Finished > 0 {
+			p.log.WithFields(fields).Infof("processed batches of transfers")
+		}
+
+		if output.Finished < p.limit {
+			return
+		}
+	}
+}
@@ -2,47 +2,1343 @@ package database
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/pkg/errors"
 )
 
-type UserInMen struct {
-	users []*entity.User
+type (
+	// InMemoryHandler define the in-memory database, safe for concurrent use.
+	// Transactions are serialized, isolated from the reads made outside of
+	// them and rolled back by replaying an undo log.
+	InMemoryHandler struct {
+		mu        sync.RWMutex
+		users     map[string]entity.User
+		transfers map[string]entity.Transfer
+		schedules map[string]entity.Schedule
+		batches   map[string]entity.Batch
+		reviews   map[string]entity.Review
+		payments  map[string]entity.Payment
+		fundings  map[string]entity.Funding
+		// risks are kept in the order they were assessed
+		risks []entity.RiskAssessment
+		// audit is kept in the order the entries were chained
+		audit []entity.AuditEntry
+		// auditPending are the entries appended and not yet chained
+		auditPending []entity.AuditEntry
+		// baselines are keyed by their wallet and time
+		baselines map[baselineKey]entity.WalletBaseline
+		// checkpoints are the times the reconciliation was checkpointed at
+		checkpoints map[time.Time]bool
+		// lock is the lock of the reconciliation, free when its owner is empty
+		lock reconciliationLock
+
+		// txMu is held for the whole transaction and by every write done outside
+		// of one, its read lock by every read done outside of one
+		txMu sync.RWMutex
+	}
+
+	inMemoryTx struct {
+		handler *InMemoryHandler
+		undo    []func()
+	}
+
+	inMemoryTxKey struct{}
+
+	baselineKey struct {
+		user     string
+		currency string
+		purpose  string
+		at       time.Time
+	}
+
+	reconciliationLock struct {
+		owner     string
+		expiresAt time.Time
+	}
+
+	// UserInMen implements the user repository ports on top of InMemoryHandler
+	UserInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// TransferInMen implements the transfer repository ports on top of InMemoryHandler
+	TransferInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// ScheduleInMen implements the schedule repository ports on top of InMemoryHandler
+	ScheduleInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// BatchInMen implements the batch repository ports on top of InMemoryHandler
+	BatchInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// RiskInMen implements the risk assessment repository ports on top of InMemoryHandler
+	RiskInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// ReviewInMen implements the review repository ports on top of InMemoryHandler
+	ReviewInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// AuditInMen implements the audit repository ports on top of InMemoryHandler
+	AuditInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// PaymentInMen implements the payment repository ports on top of InMemoryHandler
+	PaymentInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// FundingInMen implements the funding repository ports on top of InMemoryHandler
+	FundingInMen struct {
+		handler *InMemoryHandler
+	}
+
+	// ReconciliationInMen implements the reconciliation repository ports on top of InMemoryHandler
+	ReconciliationInMen struct {
+		handler *InMemoryHandler
+	}
+)
+
+// NewInMemoryHandler create new empty InMemoryHandler
+func NewInMemoryHandler() *InMemoryHandler {
+	return &InMemoryHandler{
+		users:       map[string]entity.User{},
+		transfers:   map[string]entity.Transfer{},
+		schedules:   map[string]entity.Schedule{},
+		batches:     map[string]entity.Batch{},
+		reviews:     map[string]entity.Review{},
+		payments:    map[string]entity.Payment{},
+		fundings:    map[string]entity.Funding{},
+		baselines:   map[baselineKey]entity.WalletBaseline{},
+		checkpoints: map[time.Time]bool{},
+	}
+}
+
+// NewUserInMen create new UserInMen with its dependencies
+func NewUserInMen(handler *InMemoryHandler) *UserInMen {
+	return &UserInMen{handler: handler}
+}
+
+// NewTransferInMen create new TransferInMen with its dependencies
+func NewTransferInMen(handler *InMemoryHandler) *TransferInMen {
+	return &TransferInMen{handler: handler}
+}
+
+// NewScheduleInMen create new ScheduleInMen with its dependencies
+func NewScheduleInMen(handler *InMemoryHandler) *ScheduleInMen {
+	return &ScheduleInMen{handler: handler}
+}
+
+// NewBatchInMen create new BatchInMen with its dependencies
+func NewBatchInMen(handler *InMemoryHandler) *BatchInMen {
+	return &BatchInMen{handler: handler}
+}
+
+// NewRiskInMen create new RiskInMen with its dependencies
+func NewRiskInMen(handler *InMemoryHandler) *RiskInMen {
+	return &RiskInMen{handler: handler}
 }
 
-func (u *UserInMen) Create(_ context.Context, user entity.User) (entity.User, error) {
-	u.users = append(u.users, &user)
+// NewReviewInMen create new ReviewInMen with its dependencies
+func NewReviewInMen(handler *InMemoryHandler) *ReviewInMen {
+	return &ReviewInMen{handler: handler}
+}
+
+// NewAuditInMen create new AuditInMen with its dependencies
+func NewAuditInMen(handler *InMemoryHandler) *AuditInMen {
+	return &AuditInMen{handler: handler}
+}
+
+// NewPaymentInMen create new PaymentInMen with its dependencies
+func NewPaymentInMen(handler *InMemoryHandler) *PaymentInMen {
+	return &PaymentInMen{handler: handler}
+}
+
+// NewFundingInMen create new FundingInMen with its dependencies
+func NewFundingInMen(handler *InMemoryHandler) *FundingInMen {
+	return &FundingInMen{handler: handler}
+}
+
+// NewReconciliationInMen create new ReconciliationInMen with its dependencies
+func NewReconciliationInMen(handler *InMemoryHandler) *ReconciliationInMen {
+	return &ReconciliationInMen{handler: handler}
+}
+
+// Ping always succeeds, it exists to match the other handlers
+func (h *InMemoryHandler) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close drops every stored entity
+func (h *InMemoryHandler) Close(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.users = map[string]entity.User{}
+	h.transfers = map[string]entity.Transfer{}
+	h.schedules = map[string]entity.Schedule{}
+	h.batches = map[string]entity.Batch{}
+	h.reviews = map[string]entity.Review{}
+	h.payments = map[string]entity.Payment{}
+	h.fundings = map[string]entity.Funding{}
+	h.baselines = map[baselineKey]entity.WalletBaseline{}
+	h.checkpoints = map[time.Time]bool{}
+	h.lock = reconciliationLock{}
+	h.risks = nil
+	h.audit = nil
+	h.auditPending = nil
+
+	return nil
+}
+
+// rlock takes the read locks, returning their unlock. Outside a transaction the
+// read waits for the running transaction, whose writes are only seen once
+// committed; inside one the locks it holds are enough.
+func (h *InMemoryHandler) rlock(ctx context.Context) func() {
+	if tx, ok := ctx.Value(inMemoryTxKey{}).(*inMemoryTx); ok && tx.handler == h {
+		h.mu.RLock()
+		return h.mu.RUnlock
+	}
+
+	h.txMu.RLock()
+	h.mu.RLock()
+	return func() {
+		h.mu.RUnlock()
+		h.txMu.RUnlock()
+	}
+}
+
+// write runs fn holding the write locks. Inside a transaction the undo function
+// returned by fn is kept to roll back, outside of one the write is serialized
+// with the running transactions.
+func (h *InMemoryHandler) write(ctx context.Context, fn func() (func(), error)) error {
+	tx, ok := ctx.Value(inMemoryTxKey{}).(*inMemoryTx)
+	if !ok || tx.handler != h {
+		h.txMu.Lock()
+		defer h.txMu.Unlock()
+	}
+
+	h.mu.Lock()
+	undo, err := fn()
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if ok && tx.handler == h && undo != nil {
+		tx.undo = append(tx.undo, undo)
+	}
+
+	return nil
+}
+
+// Create stores a copy of the user
+func (u *UserInMen) Create(ctx context.Context, user entity.User) (entity.User, error) {
+	stored, err := cloneUser(user)
+	if err != nil {
+		return entity.User{}, errors.Wrap(err, entity.ErrCreateUser.Error())
+	}
+
+	err = u.handler.write(ctx, func() (func(), error) {
+		var (
+			id            = user.ID().Value()
+			previous, had = u.handler.users[id]
+		)
+		u.handler.users[id] = stored
+
+		return func() {
+			if had {
+				u.handler.users[id] = previous
+				return
+			}
+			delete(u.handler.users, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
 
 	return user, nil
 }
 
-func (u *UserInMen) FindByID(_ context.Context, ID vo.Uuid) (entity.User, error) {
-	for _, user := range u.users {
-		if user.ID() == ID {
-			return *user, nil
+// FindByID returns a copy of the user, entity.ErrNotFoundUser when it does not exist
+func (u *UserInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.User, error) {
+	unlock := u.handler.rlock(ctx)
+	user, ok := u.handler.users[ID.Value()]
+	unlock()
+
+	if !ok {
+		return entity.User{}, entity.ErrNotFoundUser
+	}
+
+	found, err := cloneUser(user)
+	if err != nil {
+		return entity.User{}, errors.Wrap(err, entity.ErrFindUserByID.Error())
+	}
+
+	return found, nil
+}
+
+// FindByEmail returns a copy of the oldest user of the email, entity.ErrNotFoundUser when there is none
+func (u *UserInMen) FindByEmail(ctx context.Context, email vo.Email) (entity.User, error) {
+	return u.findOldest(ctx, func(user entity.User) bool {
+		return user.Email().Value() == email.Value()
+	})
+}
+
+// FindByDocument returns a copy of the oldest user of the document, entity.ErrNotFoundUser when there is none
+func (u *UserInMen) FindByDocument(ctx context.Context, doc vo.Document) (entity.User, error) {
+	return u.findOldest(ctx, func(user entity.User) bool {
+		return user.Document().Type() == doc.Type() && user.Document().Value() == doc.Value()
+	})
+}
+
+// List returns copies of the users matching the filter, oldest first
+func (u *UserInMen) List(ctx context.Context, f entity.UserFilter) ([]entity.User, error) {
+	users := u.sorted(ctx, func(user entity.User) bool {
+		if f.Type != "" && user.TypeUser().ToUpper() != f.Type.ToUpper() {
+			return false
+		}
+		if !f.From.IsZero() && user.CreatedAt().Before(f.From) {
+			return false
 		}
+		return f.To.IsZero() || user.CreatedAt().Before(f.To)
+	})
+
+	from, to := page(len(users), f.Offset, f.Limit)
+	listed := make([]entity.User, 0, to-from)
+	for _, user := range users[from:to] {
+		found, err := cloneUser(user)
+		if err != nil {
+			return nil, errors.Wrap(err, entity.ErrListUsers.Error())
+		}
+		listed = append(listed, found)
+	}
+
+	return listed, nil
+}
+
+// findOldest returns a copy of the oldest user matching, entity.ErrNotFoundUser when there is none
+func (u *UserInMen) findOldest(ctx context.Context, match func(entity.User) bool) (entity.User, error) {
+	users := u.sorted(ctx, match)
+	if len(users) == 0 {
+		return entity.User{}, entity.ErrNotFoundUser
+	}
+
+	found, err := cloneUser(users[0])
+	if err != nil {
+		return entity.User{}, errors.Wrap(err, entity.ErrFindUser.Error())
 	}
 
-	return entity.User{}, nil
+	return found, nil
 }
 
-func (u *UserInMen) UpdateWallet(_ context.Context, ID vo.Uuid, money vo.Money) error {
-	for _, user := range u.users {
-		if user.ID() == ID {
-			user.Wallet().NewMoney(vo.NewMoneyBRL(money.Amount()))
+// sorted returns the users matching, oldest first
+func (u *UserInMen) sorted(ctx context.Context, match func(entity.User) bool) []entity.User {
+	unlock := u.handler.rlock(ctx)
+	users := make([]entity.User, 0, len(u.handler.users))
+	for _, user := range u.handler.users {
+		if match(user) {
+			users = append(users, user)
 		}
 	}
+	unlock()
 
-	return nil
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt().Equal(users[j].CreatedAt()) {
+			return users[i].CreatedAt().Before(users[j].CreatedAt())
+		}
+		return users[i].ID().Value() < users[j].ID().Value()
+	})
+
+	return users
+}
+
+// UpdateWallet replaces the money and the held amount of the user wallet of the same currency and purpose
+// when it is still at the version of the wallet
+func (u *UserInMen) UpdateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	return u.handler.write(ctx, func() (func(), error) {
+		user, ok := u.handler.users[ID.Value()]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundUser, entity.ErrUpdateUserWallet.Error())
+		}
+
+		wallets := user.Wallets()
+		i := walletIndex(wallets, wallet)
+		if i < 0 {
+			return nil, errors.Wrap(entity.ErrNotFoundWallet, entity.ErrUpdateUserWallet.Error())
+		}
+
+		if wallets[i].Version() != wallet.Version() {
+			return nil, errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateUserWallet.Error())
+		}
+
+		wallets[i] = vo.RestoreWallet(wallet.Money(), wallet.Purpose(), wallet.Held(), wallet.Version()+1)
+		u.handler.users[ID.Value()] = user.WithWallets(wallets)
+
+		return func() {
+			u.handler.users[ID.Value()] = user
+		}, nil
+	})
+}
+
+// CreateWallet adds a copy of the wallet to the user, entity.ErrWalletAlreadyExists when it has one of the
+// same currency and purpose
+func (u *UserInMen) CreateWallet(ctx context.Context, ID vo.Uuid, wallet *vo.Wallet) error {
+	return u.handler.write(ctx, func() (func(), error) {
+		user, ok := u.handler.users[ID.Value()]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundUser, entity.ErrCreateWallet.Error())
+		}
+
+		wallets := user.Wallets()
+		if walletIndex(wallets, wallet) >= 0 {
+			return nil, errors.Wrap(entity.ErrWalletAlreadyExists, entity.ErrCreateWallet.Error())
+		}
+
+		wallets = append(wallets, vo.RestoreWallet(wallet.Money(), wallet.Purpose(), wallet.Held(), wallet.Version()))
+		u.handler.users[ID.Value()] = user.WithWallets(wallets)
+
+		return func() {
+			u.handler.users[ID.Value()] = user
+		}, nil
+	})
+}
+
+// walletIndex returns the index of the wallet of the same currency and purpose as wallet, -1 when there is none
+func walletIndex(wallets []*vo.Wallet, wallet *vo.Wallet) int {
+	for i, w := range wallets {
+		if w.Is(wallet.Money().Currency(), wallet.Purpose()) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Create stores the transfer, entity.ErrTransferAlreadyExists when its ID is used
+func (t *TransferInMen) Create(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error) {
+	err := t.handler.write(ctx, func() (func(), error) {
+		id := transfer.ID().Value()
+		if _, ok := t.handler.transfers[id]; ok {
+			return nil, errors.Wrap(entity.ErrTransferAlreadyExists, entity.ErrCreateTransfer.Error())
+		}
+		t.handler.transfers[id] = transfer
+
+		return func() {
+			delete(t.handler.transfers, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return transfer, nil
 }
 
-type TransferInMen struct {
-	Transfer []*entity.Transfer
+// SumByPayer returns the number and the value of the transfers, the withdrawals and
+// the payments held of the payer in the currency created in [from, to), the rejected
+// and failed ones excluded. A captured payment is counted by its transfer.
+func (t *TransferInMen) SumByPayer(
+	ctx context.Context,
+	payerID vo.Uuid,
+	currency vo.Currency,
+	from, to time.Time,
+) (entity.TransferUsage, error) {
+	unlock := t.handler.rlock(ctx)
+	defer unlock()
+
+	var (
+		count int
+		value int64
+	)
+	for _, transfer := range t.handler.transfers {
+		if !paidIn(transfer, payerID, from, to) || !transfer.Value().Currency().Equals(currency) {
+			continue
+		}
+		count++
+		value += transfer.Value().Amount().Value()
+	}
+	for _, funding := range t.handler.fundings {
+		if !withdrawnIn(funding, payerID, from, to) || !funding.Value().Currency().Equals(currency) {
+			continue
+		}
+		count++
+		value += funding.Value().Amount().Value()
+	}
+	for _, payment := range t.handler.payments {
+		if !heldIn(payment, payerID, from, to) || !payment.Value().Currency().Equals(currency) {
+			continue
+		}
+		count++
+		value += payment.Value().Amount().Value()
+	}
+
+	amount, err := vo.NewAmount(value)
+	if err != nil {
+		return entity.TransferUsage{}, err
+	}
+
+	return entity.NewTransferUsage(count, amount), nil
+}
+
+// FindPayees returns the distinct payees of the transfers of the payer created
+// in [from, to), the rejected ones excluded
+func (t *TransferInMen) FindPayees(ctx context.Context, payerID vo.Uuid, from, to time.Time) ([]vo.Uuid, error) {
+	unlock := t.handler.rlock(ctx)
+	defer unlock()
+
+	var (
+		payees []vo.Uuid
+		seen   = map[string]bool{}
+	)
+	for _, transfer := range t.handler.transfers {
+		if !paidIn(transfer, payerID, from, to) {
+			continue
+		}
+		for _, leg := range transfer.Legs() {
+			if seen[leg.Payee().Value()] {
+				continue
+			}
+			seen[leg.Payee().Value()] = true
+			payees = append(payees, leg.Payee())
+		}
+	}
+
+	return payees, nil
 }
 
-func (t *TransferInMen) Create(_ context.Context, transfer entity.Transfer) (entity.Transfer, error) {
-	t.Transfer = append(t.Transfer, &transfer)
+// FindByID returns the transfer, entity.ErrNotFoundTransfer when it does not exist
+func (t *TransferInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Transfer, error) {
+	unlock := t.handler.rlock(ctx)
+	defer unlock()
+
+	transfer, ok := t.handler.transfers[ID.Value()]
+	if !ok {
+		return entity.Transfer{}, entity.ErrNotFoundTransfer
+	}
 
 	return transfer, nil
 }
+
+// List returns the transfers matching the filter, oldest first
+func (t *TransferInMen) List(ctx context.Context, f entity.TransferFilter) ([]entity.Transfer, error) {
+	unlock := t.handler.rlock(ctx)
+	var transfers []entity.Transfer
+	for _, transfer := range t.handler.transfers {
+		if matches(transfer, f) {
+			transfers = append(transfers, transfer)
+		}
+	}
+	unlock()
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if !transfers[i].CreatedAt().Equal(transfers[j].CreatedAt()) {
+			return transfers[i].CreatedAt().Before(transfers[j].CreatedAt())
+		}
+		return transfers[i].ID().Value() < transfers[j].ID().Value()
+	})
+
+	from, to := page(len(transfers), f.Offset, f.Limit)
+
+	return transfers[from:to], nil
+}
+
+// matches reports whether the transfer is selected by the filter
+func matches(transfer entity.Transfer, f entity.TransferFilter) bool {
+	if !f.From.IsZero() && transfer.CreatedAt().Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !transfer.CreatedAt().Before(f.To) {
+		return false
+	}
+	if f.UserID.Value() == "" || transfer.Payer().Equals(f.UserID) {
+		return true
+	}
+
+	for _, leg := range transfer.Legs() {
+		if leg.Payee().Equals(f.UserID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// page returns the bounds of the page of n items starting at offset, every
+// item after it when the limit is not positive
+func page(n, offset, limit int) (int, int) {
+	if offset > n {
+		offset = n
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || offset+limit > n {
+		return offset, n
+	}
+
+	return offset, offset + limit
+}
+
+// UpdateStatus replaces the status of the transfer
+func (t *TransferInMen) UpdateStatus(ctx context.Context, ID vo.Uuid, status vo.TransferStatus) error {
+	return t.handler.write(ctx, func() (func(), error) {
+		transfer, ok := t.handler.transfers[ID.Value()]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundTransfer, entity.ErrUpdateTransfer.Error())
+		}
+		t.handler.transfers[ID.Value()] = transfer.WithStatus(status)
+
+		return func() {
+			t.handler.transfers[ID.Value()] = transfer
+		}, nil
+	})
+}
+
+// paidIn reports whether the transfer was made by the payer in [from, to) and not rejected
+func paidIn(transfer entity.Transfer, payerID vo.Uuid, from, to time.Time) bool {
+	return transfer.Payer().Equals(payerID) && transfer.Status() != vo.TransferRejected &&
+		!transfer.CreatedAt().Before(from) && transfer.CreatedAt().Before(to)
+}
+
+func withdrawnIn(funding entity.Funding, userID vo.Uuid, from, to time.Time) bool {
+	return funding.Type() == vo.Withdrawal && funding.User().Equals(userID) && funding.Status() != vo.FundingFailed &&
+		!funding.CreatedAt().Before(from) && funding.CreatedAt().Before(to)
+}
+
+func heldIn(payment entity.Payment, payerID vo.Uuid, from, to time.Time) bool {
+	return payment.Status() == vo.PaymentAuthorized && payment.Payer().Equals(payerID) &&
+		!payment.CreatedAt().Before(from) && payment.CreatedAt().Before(to)
+}
+
+// WithTransaction runs fn with the other transactions and writes blocked,
+// every write made through the context given to fn is undone if fn fails
+func (t *TransferInMen) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if tx, ok := ctx.Value(inMemoryTxKey{}).(*inMemoryTx); ok && tx.handler == t.handler {
+		return fn(ctx)
+	}
+
+	t.handler.txMu.Lock()
+	defer t.handler.txMu.Unlock()
+
+	tx := &inMemoryTx{handler: t.handler}
+	if err := fn(context.WithValue(ctx, inMemoryTxKey{}, tx)); err != nil {
+		t.handler.mu.Lock()
+		for i := len(tx.undo) - 1; i >= 0; i-- {
+			tx.undo[i]()
+		}
+		t.handler.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Create stores the schedule
+func (s *ScheduleInMen) Create(ctx context.Context, schedule entity.Schedule) (entity.Schedule, error) {
+	err := s.handler.write(ctx, func() (func(), error) {
+		id := schedule.ID().Value()
+		if _, ok := s.handler.schedules[id]; ok {
+			return nil, errors.Wrap(errors.New("schedule already exists"), entity.ErrCreateSchedule.Error())
+		}
+		s.handler.schedules[id] = schedule
+
+		return func() {
+			delete(s.handler.schedules, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	return schedule, nil
+}
+
+// FindByID returns the schedule, entity.ErrNotFoundSchedule when it does not exist
+func (s *ScheduleInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Schedule, error) {
+	unlock := s.handler.rlock(ctx)
+	defer unlock()
+
+	schedule, ok := s.handler.schedules[ID.Value()]
+	if !ok {
+		return entity.Schedule{}, entity.ErrNotFoundSchedule
+	}
+
+	return schedule, nil
+}
+
+// FindByPayer returns the schedules of the payer, oldest first
+func (s *ScheduleInMen) FindByPayer(ctx context.Context, payerID vo.Uuid) ([]entity.Schedule, error) {
+	return s.filter(ctx, func(schedule entity.Schedule) bool {
+		return schedule.Payer().Equals(payerID)
+	}, func(a, b entity.Schedule) bool {
+		return a.CreatedAt().Before(b.CreatedAt())
+	}, 0), nil
+}
+
+// FindDue returns up to limit schedules due at the time, earliest runs first
+func (s *ScheduleInMen) FindDue(ctx context.Context, at time.Time, limit int) ([]entity.Schedule, error) {
+	return s.filter(ctx, func(schedule entity.Schedule) bool {
+		return schedule.Due(at)
+	}, func(a, b entity.Schedule) bool {
+		return a.NextRunAt().Before(b.NextRunAt())
+	}, limit), nil
+}
+
+// Update replaces the stored schedule
+func (s *ScheduleInMen) Update(ctx context.Context, schedule entity.Schedule) error {
+	return s.handler.write(ctx, func() (func(), error) {
+		id := schedule.ID().Value()
+		previous, ok := s.handler.schedules[id]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundSchedule, entity.ErrUpdateSchedule.Error())
+		}
+		s.handler.schedules[id] = schedule
+
+		return func() {
+			s.handler.schedules[id] = previous
+		}, nil
+	})
+}
+
+// filter returns the sorted schedules matching keep, at most limit when it is positive
+func (s *ScheduleInMen) filter(ctx context.Context, keep func(entity.Schedule) bool, less func(a, b entity.Schedule) bool, limit int) []entity.Schedule {
+	unlock := s.handler.rlock(ctx)
+	var schedules []entity.Schedule
+	for _, schedule := range s.handler.schedules {
+		if keep(schedule) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	unlock()
+
+	sort.Slice(schedules, func(i, j int) bool { return less(schedules[i], schedules[j]) })
+	if limit > 0 && len(schedules) > limit {
+		schedules = schedules[:limit]
+	}
+
+	return schedules
+}
+
+// Create stores a copy of the batch
+func (b *BatchInMen) Create(ctx context.Context, batch entity.Batch) (entity.Batch, error) {
+	err := b.handler.write(ctx, func() (func(), error) {
+		id := batch.ID().Value()
+		if _, ok := b.handler.batches[id]; ok {
+			return nil, errors.Wrap(errors.New("batch already exists"), entity.ErrCreateBatch.Error())
+		}
+		b.handler.batches[id] = cloneBatch(batch)
+
+		return func() {
+			delete(b.handler.batches, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Batch{}, err
+	}
+
+	return batch, nil
+}
+
+// FindByID returns a copy of the batch, entity.ErrNotFoundBatch when it does not exist
+func (b *BatchInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Batch, error) {
+	unlock := b.handler.rlock(ctx)
+	defer unlock()
+
+	batch, ok := b.handler.batches[ID.Value()]
+	if !ok {
+		return entity.Batch{}, entity.ErrNotFoundBatch
+	}
+
+	return cloneBatch(batch), nil
+}
+
+// FindUnfinished returns copies of up to limit unfinished batches, oldest first
+func (b *BatchInMen) FindUnfinished(ctx context.Context, limit int) ([]entity.Batch, error) {
+	unlock := b.handler.rlock(ctx)
+	var batches []entity.Batch
+	for _, batch := range b.handler.batches {
+		if !batch.Finished() {
+			batches = append(batches, cloneBatch(batch))
+		}
+	}
+	unlock()
+
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt().Before(batches[j].CreatedAt()) })
+	if limit > 0 && len(batches) > limit {
+		batches = batches[:limit]
+	}
+
+	return batches, nil
+}
+
+// Update replaces the stored batch
+func (b *BatchInMen) Update(ctx context.Context, batch entity.Batch) error {
+	return b.handler.write(ctx, func() (func(), error) {
+		id := batch.ID().Value()
+		previous, ok := b.handler.batches[id]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundBatch, entity.ErrUpdateBatch.Error())
+		}
+		b.handler.batches[id] = cloneBatch(batch)
+
+		return func() {
+			b.handler.batches[id] = previous
+		}, nil
+	})
+}
+
+// UpdateItem replaces the stored batch, the whole batch being stored at once
+func (b *BatchInMen) UpdateItem(ctx context.Context, batch entity.Batch, _ int) error {
+	return b.Update(ctx, batch)
+}
+
+// cloneBatch copies the batch so that the stored items are never shared with the callers
+// Create stores the risk assessment
+func (r *RiskInMen) Create(ctx context.Context, assessment entity.RiskAssessment) (entity.RiskAssessment, error) {
+	err := r.handler.write(ctx, func() (func(), error) {
+		for _, a := range r.handler.risks {
+			if a.ID().Equals(assessment.ID()) {
+				return nil, errors.Wrap(errors.New("risk assessment already exists"), entity.ErrCreateRiskAssessment.Error())
+			}
+		}
+
+		n := len(r.handler.risks)
+		r.handler.risks = append(r.handler.risks, assessment)
+
+		return func() {
+			r.handler.risks = r.handler.risks[:n]
+		}, nil
+	})
+	if err != nil {
+		return entity.RiskAssessment{}, err
+	}
+
+	return assessment, nil
+}
+
+// FindByTransferID returns the latest assessment of the transfer, entity.ErrNotFoundRiskAssessment when there is none
+func (r *RiskInMen) FindByTransferID(ctx context.Context, transferID vo.Uuid) (entity.RiskAssessment, error) {
+	unlock := r.handler.rlock(ctx)
+	defer unlock()
+
+	for i := len(r.handler.risks) - 1; i >= 0; i-- {
+		if r.handler.risks[i].TransferID().Equals(transferID) {
+			return r.handler.risks[i], nil
+		}
+	}
+
+	return entity.RiskAssessment{}, entity.ErrNotFoundRiskAssessment
+}
+
+// Create stores the review
+func (r *ReviewInMen) Create(ctx context.Context, review entity.Review) (entity.Review, error) {
+	err := r.handler.write(ctx, func() (func(), error) {
+		id := review.ID().Value()
+		if _, ok := r.handler.reviews[id]; ok {
+			return nil, errors.Wrap(errors.New("review already exists"), entity.ErrCreateReview.Error())
+		}
+		r.handler.reviews[id] = review
+
+		return func() {
+			delete(r.handler.reviews, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return review, nil
+}
+
+// FindByID returns the review, entity.ErrNotFoundReview when it does not exist
+func (r *ReviewInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Review, error) {
+	unlock := r.handler.rlock(ctx)
+	defer unlock()
+
+	review, ok := r.handler.reviews[ID.Value()]
+	if !ok {
+		return entity.Review{}, entity.ErrNotFoundReview
+	}
+
+	return review, nil
+}
+
+// FindByStatus returns up to limit reviews in the status, oldest first
+func (r *ReviewInMen) FindByStatus(ctx context.Context, status vo.ReviewStatus, limit int) ([]entity.Review, error) {
+	return r.filter(ctx, func(review entity.Review) bool {
+		return review.Status() == status
+	}, limit), nil
+}
+
+// FindExpired returns up to limit pending reviews expired at the time, oldest first
+func (r *ReviewInMen) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Review, error) {
+	return r.filter(ctx, func(review entity.Review) bool {
+		return review.Status() == vo.ReviewPending && !review.ExpiresAt().After(at)
+	}, limit), nil
+}
+
+// Update replaces the stored review while it is pending
+func (r *ReviewInMen) Update(ctx context.Context, review entity.Review) error {
+	return r.handler.write(ctx, func() (func(), error) {
+		id := review.ID().Value()
+		previous, ok := r.handler.reviews[id]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundReview, entity.ErrUpdateReview.Error())
+		}
+		if previous.Status() != vo.ReviewPending {
+			return nil, errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateReview.Error())
+		}
+		r.handler.reviews[id] = review
+
+		return func() {
+			r.handler.reviews[id] = previous
+		}, nil
+	})
+}
+
+// filter returns the reviews matching keep, oldest first, at most limit when it is positive
+func (r *ReviewInMen) filter(ctx context.Context, keep func(entity.Review) bool, limit int) []entity.Review {
+	unlock := r.handler.rlock(ctx)
+	var reviews []entity.Review
+	for _, review := range r.handler.reviews {
+		if keep(review) {
+			reviews = append(reviews, review)
+		}
+	}
+	unlock()
+
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt().Before(reviews[j].CreatedAt()) })
+	if limit > 0 && len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+
+	return reviews
+}
+
+// Create appends the entry to the audit log, pending until it is chained
+func (a *AuditInMen) Create(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	err := a.handler.write(ctx, func() (func(), error) {
+		n := len(a.handler.auditPending)
+		a.handler.auditPending = append(a.handler.auditPending, entry)
+
+		return func() {
+			a.handler.auditPending = a.handler.auditPending[:n]
+		}, nil
+	})
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// ChainPending chains up to limit pending entries, the oldest first, to the last one chained
+func (a *AuditInMen) ChainPending(ctx context.Context, limit int) (int, error) {
+	var chained int
+	err := a.handler.write(ctx, func() (func(), error) {
+		pending := a.handler.auditPending
+		sort.SliceStable(pending, func(i, j int) bool { return appendedBefore(pending[i], pending[j]) })
+
+		n := len(pending)
+		if limit > 0 && limit < n {
+			n = limit
+		}
+
+		chain := a.handler.audit
+		for _, entry := range pending[:n] {
+			var last entity.AuditEntry
+			if len(chain) > 0 {
+				last = chain[len(chain)-1]
+			}
+			chain = append(chain, entry.Chain(last.Sequence()+1, last.Hash()))
+		}
+
+		previous := len(a.handler.audit)
+		a.handler.audit = chain
+		a.handler.auditPending = append([]entity.AuditEntry{}, pending[n:]...)
+		chained = n
+
+		return func() {
+			a.handler.auditPending = append(append([]entity.AuditEntry{}, pending[:n]...), a.handler.auditPending...)
+			a.handler.audit = a.handler.audit[:previous]
+		}, nil
+	})
+
+	return chained, err
+}
+
+// List returns the entries matching the filter, the chained ones in the order
+// of the chain then the pending ones, the oldest first
+func (a *AuditInMen) List(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	unlock := a.handler.rlock(ctx)
+	var entries, pending []entity.AuditEntry
+	for _, entry := range a.handler.audit {
+		if a.matches(entry, f) {
+			entries = append(entries, entry)
+		}
+	}
+	for _, entry := range a.handler.auditPending {
+		if a.matches(entry, f) {
+			pending = append(pending, entry)
+		}
+	}
+	unlock()
+
+	sort.SliceStable(pending, func(i, j int) bool { return appendedBefore(pending[i], pending[j]) })
+	entries = append(entries, pending...)
+
+	from, to := page(len(entries), f.Offset, f.Limit)
+	return append([]entity.AuditEntry{}, entries[from:to]...), nil
+}
+
+// appendedBefore reports whether the pending entry a is chained before b, by creation date then id
+func appendedBefore(a, b entity.AuditEntry) bool {
+	if !a.CreatedAt().Equal(b.CreatedAt()) {
+		return a.CreatedAt().Before(b.CreatedAt())
+	}
+
+	return a.ID().Value() < b.ID().Value()
+}
+
+func (a *AuditInMen) matches(entry entity.AuditEntry, f entity.AuditFilter) bool {
+	switch {
+	case f.Actor != "" && entry.Actor() != f.Actor,
+		f.Action != "" && entry.Action() != f.Action,
+		f.Target != "" && entry.Target() != f.Target,
+		f.SubjectID.Value() != "" && !entry.SubjectID().Equals(f.SubjectID),
+		f.CorrelationID != "" && entry.Origin().CorrelationID != f.CorrelationID,
+		!f.From.IsZero() && entry.CreatedAt().Before(f.From),
+		f.FromSequence > 0 && entry.Sequence() < f.FromSequence:
+		return false
+	}
+
+	return f.To.IsZero() || entry.CreatedAt().Before(f.To)
+}
+
+// Create stores the payment
+func (p *PaymentInMen) Create(ctx context.Context, payment entity.Payment) (entity.Payment, error) {
+	err := p.handler.write(ctx, func() (func(), error) {
+		id := payment.ID().Value()
+		if _, ok := p.handler.payments[id]; ok {
+			return nil, errors.Wrap(errors.New("payment already exists"), entity.ErrCreatePayment.Error())
+		}
+		p.handler.payments[id] = payment
+
+		return func() {
+			delete(p.handler.payments, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// FindByID returns the payment, entity.ErrNotFoundPayment when it does not exist
+func (p *PaymentInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Payment, error) {
+	unlock := p.handler.rlock(ctx)
+	defer unlock()
+
+	payment, ok := p.handler.payments[ID.Value()]
+	if !ok {
+		return entity.Payment{}, entity.ErrNotFoundPayment
+	}
+
+	return payment, nil
+}
+
+// FindExpired returns up to limit authorized payments expired at the time, oldest first
+func (p *PaymentInMen) FindExpired(ctx context.Context, at time.Time, limit int) ([]entity.Payment, error) {
+	unlock := p.handler.rlock(ctx)
+	var payments []entity.Payment
+	for _, payment := range p.handler.payments {
+		if payment.Status() == vo.PaymentAuthorized && !payment.ExpiresAt().After(at) {
+			payments = append(payments, payment)
+		}
+	}
+	unlock()
+
+	sort.Slice(payments, func(i, j int) bool { return payments[i].CreatedAt().Before(payments[j].CreatedAt()) })
+	if limit > 0 && len(payments) > limit {
+		payments = payments[:limit]
+	}
+
+	return payments, nil
+}
+
+// Update replaces the stored payment while it is authorized
+func (p *PaymentInMen) Update(ctx context.Context, payment entity.Payment) error {
+	return p.handler.write(ctx, func() (func(), error) {
+		id := payment.ID().Value()
+		previous, ok := p.handler.payments[id]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundPayment, entity.ErrUpdatePayment.Error())
+		}
+		if previous.Status() != vo.PaymentAuthorized {
+			return nil, errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdatePayment.Error())
+		}
+		p.handler.payments[id] = payment
+
+		return func() {
+			p.handler.payments[id] = previous
+		}, nil
+	})
+}
+
+// Create stores the funding
+func (f *FundingInMen) Create(ctx context.Context, funding entity.Funding) (entity.Funding, error) {
+	err := f.handler.write(ctx, func() (func(), error) {
+		id := funding.ID().Value()
+		if _, ok := f.handler.fundings[id]; ok {
+			return nil, errors.Wrap(errors.New("funding already exists"), entity.ErrCreateFunding.Error())
+		}
+		f.handler.fundings[id] = funding
+
+		return func() {
+			delete(f.handler.fundings, id)
+		}, nil
+	})
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	return funding, nil
+}
+
+// FindByID returns the funding, entity.ErrNotFoundFunding when it does not exist
+func (f *FundingInMen) FindByID(ctx context.Context, ID vo.Uuid) (entity.Funding, error) {
+	unlock := f.handler.rlock(ctx)
+	defer unlock()
+
+	funding, ok := f.handler.fundings[ID.Value()]
+	if !ok {
+		return entity.Funding{}, entity.ErrNotFoundFunding
+	}
+
+	return funding, nil
+}
+
+// List returns the fundings matching the filter, the oldest first
+func (f *FundingInMen) List(ctx context.Context, filter entity.FundingFilter) ([]entity.Funding, error) {
+	unlock := f.handler.rlock(ctx)
+	var fundings []entity.Funding
+	for _, funding := range f.handler.fundings {
+		switch {
+		case filter.UserID.Value() != "" && !funding.User().Equals(filter.UserID),
+			!filter.From.IsZero() && funding.CreatedAt().Before(filter.From),
+			!filter.To.IsZero() && !funding.CreatedAt().Before(filter.To),
+			!filter.UpdatedFrom.IsZero() && funding.UpdatedAt().Before(filter.UpdatedFrom):
+			continue
+		}
+		fundings = append(fundings, funding)
+	}
+	unlock()
+
+	sort.Slice(fundings, func(i, j int) bool {
+		if !fundings[i].CreatedAt().Equal(fundings[j].CreatedAt()) {
+			return fundings[i].CreatedAt().Before(fundings[j].CreatedAt())
+		}
+		return fundings[i].ID().Value() < fundings[j].ID().Value()
+	})
+
+	from, to := page(len(fundings), filter.Offset, filter.Limit)
+
+	return fundings[from:to], nil
+}
+
+// Update replaces the stored funding while it is pending
+func (f *FundingInMen) Update(ctx context.Context, funding entity.Funding) error {
+	return f.handler.write(ctx, func() (func(), error) {
+		id := funding.ID().Value()
+		previous, ok := f.handler.fundings[id]
+		if !ok {
+			return nil, errors.Wrap(entity.ErrNotFoundFunding, entity.ErrUpdateFunding.Error())
+		}
+		if previous.Status() != vo.FundingPending {
+			return nil, errors.Wrap(entity.ErrConcurrentModification, entity.ErrUpdateFunding.Error())
+		}
+		f.handler.fundings[id] = funding
+
+		return func() {
+			f.handler.fundings[id] = previous
+		}, nil
+	})
+}
+
+// Baselines returns the latest baseline of every wallet at or before the time
+func (r *ReconciliationInMen) Baselines(ctx context.Context, at time.Time) ([]entity.WalletBaseline, error) {
+	unlock := r.handler.rlock(ctx)
+	latest := map[baselineKey]entity.WalletBaseline{}
+	for _, b := range r.handler.baselines {
+		if b.At().After(at) {
+			continue
+		}
+
+		wallet := baselineKey{user: b.User().Value(), currency: b.Currency().String(), purpose: b.Purpose().String()}
+		if previous, ok := latest[wallet]; !ok || b.At().After(previous.At()) {
+			latest[wallet] = b
+		}
+	}
+	unlock()
+
+	baselines := make([]entity.WalletBaseline, 0, len(latest))
+	for _, b := range latest {
+		baselines = append(baselines, b)
+	}
+
+	sort.Slice(baselines, func(i, j int) bool {
+		a, b := baselines[i], baselines[j]
+		if a.User().Value() != b.User().Value() {
+			return a.User().Value() < b.User().Value()
+		}
+		if a.Currency() != b.Currency() {
+			return a.Currency().String() < b.Currency().String()
+		}
+		return a.Purpose().String() < b.Purpose().String()
+	})
+
+	return baselines, nil
+}
+
+// SaveBaselines stores the baselines, replacing the ones of the same wallet and time
+func (r *ReconciliationInMen) SaveBaselines(ctx context.Context, baselines []entity.WalletBaseline) error {
+	return r.handler.write(ctx, func() (func(), error) {
+		return r.saveBaselines(baselines), nil
+	})
+}
+
+// saveBaselines stores the baselines holding the write lock, returning the undo
+func (r *ReconciliationInMen) saveBaselines(baselines []entity.WalletBaseline) func() {
+	previous := map[baselineKey]entity.WalletBaseline{}
+	for _, b := range baselines {
+		k := baselineKey{user: b.User().Value(), currency: b.Currency().String(), purpose: b.Purpose().String(), at: b.At()}
+		// the zero baseline of a wallet not saved yet is deleted by the undo
+		if _, seen := previous[k]; !seen {
+			previous[k] = r.handler.baselines[k]
+		}
+		r.handler.baselines[k] = b
+	}
+
+	return func() {
+		for k, p := range previous {
+			if p.At().IsZero() {
+				delete(r.handler.baselines, k)
+				continue
+			}
+			r.handler.baselines[k] = p
+		}
+	}
+}
+
+// Checkpoint returns the latest checkpoint at or before the time
+func (r *ReconciliationInMen) Checkpoint(ctx context.Context, at time.Time) (time.Time, error) {
+	unlock := r.handler.rlock(ctx)
+	defer unlock()
+
+	var latest time.Time
+	for checkpoint := range r.handler.checkpoints {
+		if !checkpoint.After(at) && checkpoint.After(latest) {
+			latest = checkpoint
+		}
+	}
+
+	return latest, nil
+}
+
+// SaveCheckpoint stores the baselines and the checkpoint
+func (r *ReconciliationInMen) SaveCheckpoint(ctx context.Context, at time.Time, baselines []entity.WalletBaseline) error {
+	checkpoint := at.UTC().Truncate(time.Millisecond)
+
+	return r.handler.write(ctx, func() (func(), error) {
+		undoBaselines := r.saveBaselines(baselines)
+
+		existed := r.handler.checkpoints[checkpoint]
+		r.handler.checkpoints[checkpoint] = true
+
+		return func() {
+			if !existed {
+				delete(r.handler.checkpoints, checkpoint)
+			}
+			undoBaselines()
+		}, nil
+	})
+}
+
+// Lock takes the lock of the reconciliation when it expired or is already held by the owner
+func (r *ReconciliationInMen) Lock(_ context.Context, owner string, at, expiresAt time.Time) error {
+	r.handler.mu.Lock()
+	defer r.handler.mu.Unlock()
+
+	if lock := r.handler.lock; lock.owner != "" && lock.owner != owner && lock.expiresAt.After(at) {
+		return entity.ErrReconciliationLocked
+	}
+
+	r.handler.lock = reconciliationLock{owner: owner, expiresAt: expiresAt}
+
+	return nil
+}
+
+// Unlock releases the lock of the reconciliation held by the owner
+func (r *ReconciliationInMen) Unlock(_ context.Context, owner string) error {
+	r.handler.mu.Lock()
+	defer r.handler.mu.Unlock()
+
+	if r.handler.lock.owner == owner {
+		r.handler.lock = reconciliationLock{}
+	}
+
+	return nil
+}
+
+func cloneBatch(b entity.Batch) entity.Batch {
+	return entity.RestoreBatch(
+		b.ID(),
+		b.Payer(),
+		b.Mode(),
+		b.Status(),
+		b.Items(),
+		b.CreatedAt(),
+		b.UpdatedAt(),
+	)
+}
+
+// cloneUser copies the user so that the stored wallets are never shared with the callers
+func cloneUser(u entity.User) (entity.User, error) {
+	wallets := make([]*vo.Wallet, 0, len(u.Wallets()))
+	for _, w := range u.Wallets() {
+		wallets = append(wallets, vo.RestoreWallet(w.Money(), w.Purpose(), w.Held(), w.Version()))
+	}
+
+	clone, err := entity.NewUser(
+		u.ID(),
+		u.FullName(),
+		u.Email(),
+		u.Password(),
+		u.Document(),
+		wallets[0],
+		u.TypeUser(),
+		u.CreatedAt(),
+	)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return clone.WithWallets(wallets).WithEmailChangedAt(u.EmailChangedAt()), nil
+}
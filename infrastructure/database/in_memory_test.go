@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/google/uuid"
+)
+
+// TestInMemoryTransactionIsolation checks the reads made outside a transaction
+// wait for it and only see its writes once committed
+func TestInMemoryTransactionIsolation(t *testing.T) {
+	rollback := errors.New("rollback")
+
+	tests := []struct {
+		name    string
+		err     error
+		balance int64
+	}{
+		{name: "committed", balance: 40},
+		{name: "rolled back", err: rollback, balance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			h := NewInMemoryHandler()
+			users := NewUserInMen(h)
+			transfers := NewTransferInMen(h)
+
+			id, err := vo.NewUuid(uuid.New().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := entity.NewUser(
+				id,
+				vo.NewFullName("Isolated User"),
+				vo.NewEmailTest("isolated@example.com"),
+				vo.NewPassword("secret"),
+				vo.NewDocumentTest(vo.CPF, "070.910.549-45"),
+				vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(100))),
+				vo.COMMON,
+				time.Now(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := users.Create(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+
+			written, commit := make(chan struct{}), make(chan struct{})
+			done := make(chan error, 1)
+			go func() {
+				done <- transfers.WithTransaction(ctx, func(txCtx context.Context) error {
+					if err := users.UpdateWallet(txCtx, id, vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(40)))); err != nil {
+						return err
+					}
+
+					// the transaction reads its own writes
+					if balance(txCtx, t, users, id) != 40 {
+						t.Error("write not seen inside the transaction")
+					}
+
+					close(written)
+					<-commit
+					return tt.err
+				})
+			}()
+			<-written
+
+			read := make(chan int64, 1)
+			go func() { read <- balance(ctx, t, users, id) }()
+
+			select {
+			case got := <-read:
+				t.Fatalf("balance %d read before the transaction ended", got)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(commit)
+			if err := <-done; !errors.Is(err, tt.err) {
+				t.Fatalf("WithTransaction() = %v, want %v", err, tt.err)
+			}
+			if got := <-read; got != tt.balance {
+				t.Errorf("balance = %d, want %d", got, tt.balance)
+			}
+		})
+	}
+}
+
+func balance(ctx context.Context, t *testing.T, users *UserInMen, id vo.Uuid) int64 {
+	t.Helper()
+
+	user, err := users.FindByID(ctx, id)
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+
+	return user.Wallet().Money().Amount().Value()
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS users (
+    id              UUID PRIMARY KEY,
+    full_name       TEXT        NOT NULL,
+    email           TEXT        NOT NULL,
+    password        TEXT        NOT NULL,
+    document_type   TEXT        NOT NULL,
+    document_value  TEXT        NOT NULL,
+    wallet_currency CHAR(3)     NOT NULL,
+    wallet_amount   BIGINT      NOT NULL CHECK (wallet_amount >= 0),
+    can_transfer    BOOLEAN     NOT NULL,
+    type            TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL
+);
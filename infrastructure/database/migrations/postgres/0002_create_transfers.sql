@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS transfers (
+    id         UUID PRIMARY KEY,
+    payer_id   UUID        NOT NULL REFERENCES users (id),
+    payee_id   UUID        NOT NULL REFERENCES users (id),
+    currency   CHAR(3)     NOT NULL,
+    value      BIGINT      NOT NULL CHECK (value >= 0),
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS transfers_payer_id_idx ON transfers (payer_id);
+CREATE INDEX IF NOT EXISTS transfers_payee_id_idx ON transfers (payee_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS schedules (
+    id               UUID PRIMARY KEY,
+    payer_id         UUID        NOT NULL REFERENCES users (id),
+    payee_id         UUID        NOT NULL REFERENCES users (id),
+    currency         CHAR(3)     NOT NULL,
+    value            BIGINT      NOT NULL CHECK (value >= 0),
+    frequency        TEXT        NOT NULL,
+    day_of_month     INTEGER     NOT NULL DEFAULT 0,
+    end_at           TIMESTAMPTZ,
+    occurrence_limit INTEGER     NOT NULL DEFAULT 0,
+    status           TEXT        NOT NULL,
+    next_run_at      TIMESTAMPTZ NOT NULL,
+    occurrences      INTEGER     NOT NULL DEFAULT 0,
+    last_error       TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS schedules_payer_id_idx ON schedules (payer_id);
+CREATE INDEX IF NOT EXISTS schedules_due_idx ON schedules (status, next_run_at);
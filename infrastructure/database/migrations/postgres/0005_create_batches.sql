@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS batches (
+    id         UUID PRIMARY KEY,
+    payer_id   UUID        NOT NULL REFERENCES users (id),
+    mode       TEXT        NOT NULL,
+    status     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS batches_unfinished_idx ON batches (status, created_at);
+
+CREATE TABLE IF NOT EXISTS batch_transfers (
+    batch_id    UUID    NOT NULL REFERENCES batches (id),
+    position    INTEGER NOT NULL,
+    transfer_id UUID    NOT NULL,
+    payee_id    UUID    NOT NULL REFERENCES users (id),
+    currency    CHAR(3) NOT NULL,
+    value       BIGINT  NOT NULL CHECK (value >= 0),
+    status      TEXT    NOT NULL,
+    reason      TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (batch_id, position)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE transfers ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'DIRECT';
+ALTER TABLE transfers ADD COLUMN fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0);
+ALTER TABLE transfers ADD COLUMN fee_rule_id TEXT;
+ALTER TABLE transfers ADD COLUMN fee_rule_version INTEGER;
+
+ALTER TABLE transfer_legs ADD COLUMN fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0);
+ALTER TABLE transfer_legs ADD COLUMN fee_rule_id TEXT;
+ALTER TABLE transfer_legs ADD COLUMN fee_rule_version INTEGER;
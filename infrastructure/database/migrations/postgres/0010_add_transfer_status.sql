@@ -0,0 +1 @@
+ALTER TABLE transfers ADD COLUMN status TEXT NOT NULL DEFAULT 'COMPLETED';
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    id          UUID PRIMARY KEY,
+    transfer_id UUID        NOT NULL REFERENCES transfers (id),
+    payer_id    UUID        NOT NULL REFERENCES users (id),
+    currency    CHAR(3)     NOT NULL,
+    value       BIGINT      NOT NULL CHECK (value >= 0),
+    reason      TEXT        NOT NULL DEFAULT '',
+    status      TEXT        NOT NULL,
+    reviewer    TEXT        NOT NULL DEFAULT '',
+    note        TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    decided_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reviews_status_idx ON reviews (status, created_at);
+CREATE INDEX IF NOT EXISTS reviews_expires_at_idx ON reviews (status, expires_at);
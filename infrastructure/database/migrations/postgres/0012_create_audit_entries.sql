@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS audit_entries (
+    id         UUID PRIMARY KEY,
+    action     TEXT        NOT NULL,
+    actor      TEXT        NOT NULL,
+    subject_id UUID        NOT NULL,
+    detail     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_entries_subject_id_idx ON audit_entries (subject_id, created_at);
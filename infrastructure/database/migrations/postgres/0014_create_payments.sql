@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id          UUID PRIMARY KEY,
+    payer_id    UUID        NOT NULL REFERENCES users (id),
+    payee_id    UUID        NOT NULL REFERENCES users (id),
+    currency    CHAR(3)     NOT NULL,
+    value       BIGINT      NOT NULL CHECK (value > 0),
+    captured    BIGINT      NOT NULL DEFAULT 0 CHECK (captured >= 0 AND captured <= value),
+    status      TEXT        NOT NULL,
+    transfer_id UUID        REFERENCES transfers (id),
+    created_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS payments_expires_at_idx ON payments (status, expires_at);
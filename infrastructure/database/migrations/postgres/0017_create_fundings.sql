@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS fundings (
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id),
+    type       TEXT        NOT NULL,
+    method     TEXT        NOT NULL,
+    currency   CHAR(3)     NOT NULL,
+    value      BIGINT      NOT NULL CHECK (value > 0),
+    wallet     TEXT        NOT NULL,
+    status     TEXT        NOT NULL,
+    reference  TEXT        NOT NULL DEFAULT '',
+    reason     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS fundings_user_id_idx ON fundings (user_id, created_at);
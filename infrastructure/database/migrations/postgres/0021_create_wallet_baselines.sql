@@ -0,0 +1,9 @@
+-- the balance of a wallet at a time, the reconciliation summing the movements made since
+CREATE TABLE IF NOT EXISTS wallet_baselines (
+    user_id     UUID        NOT NULL,
+    currency    CHAR(3)     NOT NULL,
+    purpose     TEXT        NOT NULL,
+    amount      BIGINT      NOT NULL,
+    baseline_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, currency, purpose, baseline_at)
+);
@@ -0,0 +1,14 @@
+-- the ends of the days reconciled, the baselines of the wallets moved being saved at them
+CREATE TABLE IF NOT EXISTS reconciliation_checkpoints (
+    checkpoint_at TIMESTAMPTZ PRIMARY KEY
+);
+
+-- the lock of the reconciliation, held by a single run until it expires
+CREATE TABLE IF NOT EXISTS reconciliation_locks (
+    name       TEXT PRIMARY KEY,
+    owner      TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- the reconciliation reads the fundings updated since its last checkpoint
+CREATE INDEX IF NOT EXISTS fundings_updated_at_idx ON fundings (updated_at);
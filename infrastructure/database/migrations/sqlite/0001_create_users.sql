@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS users (
+    id              TEXT PRIMARY KEY,
+    full_name       TEXT     NOT NULL,
+    email           TEXT     NOT NULL,
+    password        TEXT     NOT NULL,
+    document_type   TEXT     NOT NULL,
+    document_value  TEXT     NOT NULL,
+    wallet_currency TEXT     NOT NULL,
+    wallet_amount   INTEGER  NOT NULL CHECK (wallet_amount >= 0),
+    can_transfer    BOOLEAN  NOT NULL,
+    type            TEXT     NOT NULL,
+    created_at      DATETIME NOT NULL
+);
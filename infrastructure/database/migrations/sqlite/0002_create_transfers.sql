@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS transfers (
+    id         TEXT PRIMARY KEY,
+    payer_id   TEXT     NOT NULL REFERENCES users (id),
+    payee_id   TEXT     NOT NULL REFERENCES users (id),
+    currency   TEXT     NOT NULL,
+    value      INTEGER  NOT NULL CHECK (value >= 0),
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS transfers_payer_id_idx ON transfers (payer_id);
+CREATE INDEX IF NOT EXISTS transfers_payee_id_idx ON transfers (payee_id);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN wallet_version INTEGER NOT NULL DEFAULT 0;
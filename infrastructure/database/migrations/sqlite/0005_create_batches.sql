@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS batches (
+    id         TEXT PRIMARY KEY,
+    payer_id   TEXT     NOT NULL REFERENCES users (id),
+    mode       TEXT     NOT NULL,
+    status     TEXT     NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS batches_unfinished_idx ON batches (status, created_at);
+
+CREATE TABLE IF NOT EXISTS batch_transfers (
+    batch_id    TEXT    NOT NULL REFERENCES batches (id),
+    position    INTEGER NOT NULL,
+    transfer_id TEXT    NOT NULL,
+    payee_id    TEXT    NOT NULL REFERENCES users (id),
+    currency    TEXT    NOT NULL,
+    value       INTEGER NOT NULL CHECK (value >= 0),
+    status      TEXT    NOT NULL,
+    reason      TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (batch_id, position)
+);
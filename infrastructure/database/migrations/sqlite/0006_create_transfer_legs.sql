@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS transfer_legs (
+    transfer_id TEXT    NOT NULL REFERENCES transfers (id),
+    position    INTEGER NOT NULL,
+    payee_id    TEXT    NOT NULL REFERENCES users (id),
+    currency    TEXT    NOT NULL,
+    value       INTEGER NOT NULL CHECK (value >= 0),
+    PRIMARY KEY (transfer_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS transfer_legs_payee_id_idx ON transfer_legs (payee_id);
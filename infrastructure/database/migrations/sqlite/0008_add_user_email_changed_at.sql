@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN email_changed_at DATETIME;
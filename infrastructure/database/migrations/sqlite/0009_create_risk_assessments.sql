@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS risk_assessments (
+    id          TEXT PRIMARY KEY,
+    transfer_id TEXT     NOT NULL,
+    payer_id    TEXT     NOT NULL REFERENCES users (id),
+    score       INTEGER  NOT NULL,
+    decision    TEXT     NOT NULL,
+    created_at  DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS risk_assessments_transfer_id_idx ON risk_assessments (transfer_id, created_at);
+
+CREATE TABLE IF NOT EXISTS risk_hits (
+    assessment_id TEXT    NOT NULL REFERENCES risk_assessments (id),
+    position      INTEGER NOT NULL,
+    signal        TEXT    NOT NULL,
+    score         INTEGER NOT NULL,
+    detail        TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (assessment_id, position)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    id          TEXT PRIMARY KEY,
+    transfer_id TEXT     NOT NULL REFERENCES transfers (id),
+    payer_id    TEXT     NOT NULL REFERENCES users (id),
+    currency    TEXT     NOT NULL,
+    value       INTEGER  NOT NULL CHECK (value >= 0),
+    reason      TEXT     NOT NULL DEFAULT '',
+    status      TEXT     NOT NULL,
+    reviewer    TEXT     NOT NULL DEFAULT '',
+    note        TEXT     NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    decided_at  DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS reviews_status_idx ON reviews (status, created_at);
+CREATE INDEX IF NOT EXISTS reviews_expires_at_idx ON reviews (status, expires_at);
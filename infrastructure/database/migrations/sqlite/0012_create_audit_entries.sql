@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS audit_entries (
+    id         TEXT PRIMARY KEY,
+    action     TEXT     NOT NULL,
+    actor      TEXT     NOT NULL,
+    subject_id TEXT     NOT NULL,
+    detail     TEXT     NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_entries_subject_id_idx ON audit_entries (subject_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN wallet_held INTEGER NOT NULL DEFAULT 0 CHECK (wallet_held >= 0);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id          TEXT PRIMARY KEY,
+    payer_id    TEXT     NOT NULL REFERENCES users (id),
+    payee_id    TEXT     NOT NULL REFERENCES users (id),
+    currency    TEXT     NOT NULL,
+    value       INTEGER  NOT NULL CHECK (value > 0),
+    captured    INTEGER  NOT NULL DEFAULT 0 CHECK (captured >= 0 AND captured <= value),
+    status      TEXT     NOT NULL,
+    transfer_id TEXT     REFERENCES transfers (id),
+    created_at  DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    updated_at  DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS payments_expires_at_idx ON payments (status, expires_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS wallets (
+    user_id  TEXT    NOT NULL REFERENCES users (id),
+    currency TEXT    NOT NULL,
+    purpose  TEXT    NOT NULL,
+    position INTEGER NOT NULL,
+    amount   INTEGER NOT NULL CHECK (amount >= 0),
+    held     INTEGER NOT NULL DEFAULT 0 CHECK (held >= 0),
+    version  INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, currency, purpose)
+);
+
+INSERT INTO wallets (user_id, currency, purpose, position, amount, held, version)
+SELECT id, wallet_currency, 'main', 0, wallet_amount, wallet_held, wallet_version FROM users;
+
+ALTER TABLE users DROP COLUMN wallet_currency;
+ALTER TABLE users DROP COLUMN wallet_amount;
+ALTER TABLE users DROP COLUMN wallet_held;
+ALTER TABLE users DROP COLUMN wallet_version;
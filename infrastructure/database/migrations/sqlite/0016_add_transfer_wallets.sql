@@ -0,0 +1,2 @@
+ALTER TABLE transfers ADD COLUMN source_wallet TEXT NOT NULL DEFAULT 'main';
+ALTER TABLE transfers ADD COLUMN destination_wallet TEXT NOT NULL DEFAULT 'main';
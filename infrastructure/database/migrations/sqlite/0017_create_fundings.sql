@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS fundings (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT     NOT NULL REFERENCES users (id),
+    type       TEXT     NOT NULL,
+    method     TEXT     NOT NULL,
+    currency   TEXT     NOT NULL,
+    value      INTEGER  NOT NULL CHECK (value > 0),
+    wallet     TEXT     NOT NULL,
+    status     TEXT     NOT NULL,
+    reference  TEXT     NOT NULL DEFAULT '',
+    reason     TEXT     NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS fundings_user_id_idx ON fundings (user_id, created_at);
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS users_email_idx ON users (email, created_at);
+CREATE INDEX IF NOT EXISTS users_document_idx ON users (document_type, document_value, created_at);
+CREATE INDEX IF NOT EXISTS users_type_idx ON users (type, created_at);
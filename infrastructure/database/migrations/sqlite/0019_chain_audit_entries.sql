@@ -0,0 +1,16 @@
+ALTER TABLE audit_entries ADD COLUMN target TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN before_snapshot TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN after_snapshot TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN correlation_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+-- the entries appended before the log was chained have no sequence
+ALTER TABLE audit_entries ADD COLUMN sequence INTEGER;
+ALTER TABLE audit_entries ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_entries ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+
+-- SQLite cannot add a UNIQUE column
+CREATE UNIQUE INDEX IF NOT EXISTS audit_entries_sequence_idx ON audit_entries (sequence);
+CREATE INDEX IF NOT EXISTS audit_entries_actor_idx ON audit_entries (actor, created_at);
+CREATE INDEX IF NOT EXISTS audit_entries_action_idx ON audit_entries (action, created_at);
+CREATE INDEX IF NOT EXISTS audit_entries_correlation_id_idx ON audit_entries (correlation_id);
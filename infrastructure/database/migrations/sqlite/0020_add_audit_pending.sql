@@ -0,0 +1,4 @@
+-- the entries are appended pending and chained afterwards, out of the transaction appending them
+ALTER TABLE audit_entries ADD COLUMN pending BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS audit_entries_pending_idx ON audit_entries (created_at, id) WHERE pending;
@@ -0,0 +1,9 @@
+-- the balance of a wallet at a time, the reconciliation summing the movements made since
+CREATE TABLE IF NOT EXISTS wallet_baselines (
+    user_id     TEXT     NOT NULL,
+    currency    TEXT     NOT NULL,
+    purpose     TEXT     NOT NULL,
+    amount      INTEGER  NOT NULL,
+    baseline_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, currency, purpose, baseline_at)
+);
@@ -0,0 +1,2 @@
+-- the user credited with the fees of the transfer, NULL for the transfers recorded before
+ALTER TABLE transfers ADD COLUMN fee_account TEXT;
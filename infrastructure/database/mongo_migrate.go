@@ -0,0 +1,369 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// migrationsCollection records the migrations applied to the database
+	migrationsCollection = "migrations"
+	// migrationLockCollection holds the lock of the runner applying the migrations
+	migrationLockCollection = "migrations_lock"
+	migrationLockID         = "lock"
+	// migrationLockTTL is how long a lock is held before another runner may take
+	// it over, in case its runner died without releasing it
+	migrationLockTTL = 10 * time.Minute
+	// migrationLockRefresh is how often the runner extends the lock it holds
+	migrationLockRefresh = migrationLockTTL / 3
+	// migrationLockPoll is how often a runner tries to take the lock held by another one
+	migrationLockPoll = time.Second
+
+	// server error codes
+	namespaceNotFoundCode = 26
+	indexNotFoundCode     = 27
+)
+
+var (
+	// ErrMigrationLocked is returned when another runner held the migration lock until the context ended
+	ErrMigrationLocked = errors.New("migrations are locked by another runner")
+	// ErrMigrationLockLost is returned when the runner could not extend its lock before it expired
+	ErrMigrationLockLost = errors.New("migration lock lost")
+)
+
+type (
+	// MongoMigration define a versioned change of the MongoDB collections.
+	// Indexes are created after Up and dropped before Down.
+	MongoMigration struct {
+		Version     string
+		Description string
+		Indexes     map[string][]mongo.IndexModel
+		Up          func(context.Context, *mongo.Database) error
+		Down        func(context.Context, *mongo.Database) error
+	}
+
+	// MigrationStatus define whether a migration is applied to the database
+	MigrationStatus struct {
+		Version     string     `json:"version"`
+		Description string     `json:"description"`
+		AppliedAt   *time.Time `json:"applied_at"`
+	}
+
+	// Bson data
+	appliedMigrationBSON struct {
+		Version     string    `bson:"_id"`
+		Description string    `bson:"description"`
+		AppliedAt   time.Time `bson:"applied_at"`
+	}
+)
+
+// Migrate applies, in order, the migrations not yet recorded in the migrations
+// collection and returns their versions
+func (m *MongoHandler) Migrate(ctx context.Context) ([]string, error) {
+	var versions []string
+	err := m.withMigrationLock(ctx, func(ctx context.Context) error {
+		applied, err := m.appliedMigrations(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range mongoMigrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, migration); err != nil {
+				return err
+			}
+			versions = append(versions, migration.Version)
+		}
+
+		return nil
+	})
+
+	return versions, err
+}
+
+// Rollback reverts the last steps migrations applied, latest first, and returns their versions
+func (m *MongoHandler) Rollback(ctx context.Context, steps int) ([]string, error) {
+	var versions []string
+	err := m.withMigrationLock(ctx, func(ctx context.Context) error {
+		applied, err := m.appliedMigrations(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(mongoMigrations) - 1; i >= 0 && len(versions) < steps; i-- {
+			migration := mongoMigrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if err := m.revert(ctx, migration); err != nil {
+				return err
+			}
+			versions = append(versions, migration.Version)
+		}
+
+		return nil
+	})
+
+	return versions, err
+}
+
+// Migrations returns the status of every migration, in order
+func (m *MongoHandler) Migrations(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(mongoMigrations))
+	for _, migration := range mongoMigrations {
+		status := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if at, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// EnsureIndexes creates the indexes of the applied migrations missing from the
+// collections and returns the names of all of them by collection. Creating an
+// existing index does nothing.
+func (m *MongoHandler) EnsureIndexes(ctx context.Context) (map[string][]string, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string][]string{}
+	for _, migration := range mongoMigrations {
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		created, err := m.createIndexes(ctx, migration.Indexes)
+		if err != nil {
+			return nil, err
+		}
+		for collection, n := range created {
+			names[collection] = append(names[collection], n...)
+		}
+	}
+
+	return names, nil
+}
+
+func (m *MongoHandler) apply(ctx context.Context, migration MongoMigration) error {
+	if migration.Up != nil {
+		if err := migration.Up(ctx, m.db); err != nil {
+			return errors.Wrapf(err, "failed to apply migration %s", migration.Version)
+		}
+	}
+
+	if _, err := m.createIndexes(ctx, migration.Indexes); err != nil {
+		return errors.Wrapf(err, "failed to apply migration %s", migration.Version)
+	}
+
+	_, err := m.db.Collection(migrationsCollection).InsertOne(ctx, appliedMigrationBSON{
+		Version:     migration.Version,
+		Description: migration.Description,
+		AppliedAt:   time.Now().UTC(),
+	})
+
+	return errors.Wrapf(err, "failed to record migration %s", migration.Version)
+}
+
+func (m *MongoHandler) revert(ctx context.Context, migration MongoMigration) error {
+	for collection, models := range migration.Indexes {
+		for _, model := range models {
+			_, err := m.db.Collection(collection).Indexes().DropOne(ctx, indexName(model))
+			if err != nil && !isCommandError(err, indexNotFoundCode, namespaceNotFoundCode) {
+				return errors.Wrapf(err, "failed to revert migration %s", migration.Version)
+			}
+		}
+	}
+
+	if migration.Down != nil {
+		if err := migration.Down(ctx, m.db); err != nil {
+			return errors.Wrapf(err, "failed to revert migration %s", migration.Version)
+		}
+	}
+
+	_, err := m.db.Collection(migrationsCollection).DeleteOne(ctx, bson.M{"_id": migration.Version})
+
+	return errors.Wrapf(err, "failed to unrecord migration %s", migration.Version)
+}
+
+// appliedMigrations returns when the applied migrations were applied, by version
+func (m *MongoHandler) appliedMigrations(ctx context.Context) (map[string]time.Time, error) {
+	cursor, err := m.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list applied migrations")
+	}
+
+	var docs []appliedMigrationBSON
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, errors.Wrap(err, "failed to list applied migrations")
+	}
+
+	applied := make(map[string]time.Time, len(docs))
+	for _, doc := range docs {
+		applied[doc.Version] = doc.AppliedAt
+	}
+
+	return applied, nil
+}
+
+func (m *MongoHandler) createIndexes(ctx context.Context, indexes map[string][]mongo.IndexModel) (map[string][]string, error) {
+	collections := make([]string, 0, len(indexes))
+	for collection := range indexes {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+
+	names := map[string][]string{}
+	for _, collection := range collections {
+		created, err := m.db.Collection(collection).Indexes().CreateMany(ctx, indexes[collection])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create the indexes of "+collection)
+		}
+		names[collection] = created
+	}
+
+	return names, nil
+}
+
+// withMigrationLock runs fn holding the migration lock, waiting for the runner
+// holding it to release it or for its lock to expire. The lock is extended while
+// fn runs, the context given to fn ends when it could not be.
+func (m *MongoHandler) withMigrationLock(ctx context.Context, fn func(context.Context) error) error {
+	owner := uuid.New().String()
+	locks := m.db.Collection(migrationLockCollection)
+
+	for {
+		now := time.Now().UTC()
+		// the lock document is only matched once expired, when it exists the
+		// upsert fails on its _id as long as another runner holds it
+		_, err := locks.UpdateOne(
+			ctx,
+			bson.M{"_id": migrationLockID, "expires_at": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": owner, "locked_at": now, "expires_at": now.Add(migrationLockTTL)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return errors.Wrap(err, "failed to lock migrations")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrMigrationLocked
+		case <-time.After(migrationLockPoll):
+		}
+	}
+
+	defer func() {
+		// released even when ctx ended so that the next runner does not wait for the lock to expire
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = locks.DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": owner})
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lost := make(chan error, 1)
+	go func() {
+		lost <- m.refreshMigrationLock(ctx, owner)
+		cancel()
+	}()
+
+	err := fn(ctx)
+	cancel()
+	if refreshErr := <-lost; refreshErr != nil {
+		return refreshErr
+	}
+
+	return err
+}
+
+// refreshMigrationLock extends the lock of the owner every migrationLockRefresh
+// until ctx ends. It fails when the lock could not be extended before it expired.
+func (m *MongoHandler) refreshMigrationLock(ctx context.Context, owner string) error {
+	locks := m.db.Collection(migrationLockCollection)
+	expiresAt := time.Now().Add(migrationLockTTL)
+
+	ticker := time.NewTicker(migrationLockRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		now := time.Now().UTC()
+		res, err := locks.UpdateOne(
+			ctx,
+			bson.M{"_id": migrationLockID, "owner": owner},
+			bson.M{"$set": bson.M{"expires_at": now.Add(migrationLockTTL)}},
+		)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err == nil && res.MatchedCount == 0:
+			return ErrMigrationLockLost
+		case err == nil:
+			expiresAt = now.Add(migrationLockTTL)
+		case now.After(expiresAt):
+			return errors.Wrap(ErrMigrationLockLost, err.Error())
+		}
+	}
+}
+
+// indexName returns the name of the index, the one MongoDB gives it when it has none
+func indexName(model mongo.IndexModel) string {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name
+	}
+
+	keys, _ := model.Keys.(bson.D)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", k.Key, k.Value))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// isCommandError reports whether err is a server error of one of the codes
+func isCommandError(err error, codes ...int32) bool {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if cmdErr.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
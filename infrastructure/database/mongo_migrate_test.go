@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestMongoMigrate checks that connecting applies no migration, so that the
+// reverted ones stay pending. It runs against a disposable database when MONGODB_URI is set.
+func TestMongoMigrate(t *testing.T) {
+	if os.Getenv("MONGODB_URI") == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+	t.Setenv("MONGODB_DATABASE", "migrate_"+uuid.New().String()[:8])
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	h := newTestMongoHandler(t)
+	t.Cleanup(func() { _ = h.Db().Drop(context.Background()) })
+
+	if pending := pendingMigrations(ctx, t, h); pending != len(mongoMigrations) {
+		t.Fatalf("pending after connecting = %d, want %d", pending, len(mongoMigrations))
+	}
+
+	if _, err := h.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if _, err := h.Rollback(ctx, 1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	if pending := pendingMigrations(ctx, t, newTestMongoHandler(t)); pending != 1 {
+		t.Errorf("pending after reconnecting = %d, want the reverted one", pending)
+	}
+}
+
+func newTestMongoHandler(t *testing.T) *MongoHandler {
+	t.Helper()
+
+	h, err := NewMongoHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Disconnect(context.Background()) })
+
+	return h
+}
+
+func pendingMigrations(ctx context.Context, t *testing.T, h *MongoHandler) int {
+	t.Helper()
+
+	statuses, err := h.Migrations(ctx)
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+
+	var pending int
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,336 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoMigrations are the migrations of the MongoDB collections, in the order
+// they are applied. A migration is never changed once released, a new one is
+// added instead.
+var mongoMigrations = []MongoMigration{
+	{
+		Version:     "0001_rename_transfer_fields",
+		Description: "store the payer, the payee and the creation date of the transfers as payer_id, payee_id and created_at",
+		Up:          renameTransferFields,
+		Down:        restoreTransferFields,
+	},
+	{
+		Version:     "0002_create_indexes",
+		Description: "create the indexes of the queries made by the repositories",
+		Indexes: map[string][]mongo.IndexModel{
+			"users": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}},
+			},
+			"transfers": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "payer_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "payee_id", Value: 1}}},
+				{Keys: bson.D{{Key: "legs.payee", Value: 1}}},
+				{Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}},
+			},
+			"schedules": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "payer_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}}},
+			},
+			"batches": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+			},
+			"risk_assessments": {
+				{Keys: bson.D{{Key: "transfer_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			},
+			"reviews": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+			},
+			"payments": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+			},
+			"fundings": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			},
+		},
+	},
+	{
+		Version:     "0003_add_schema_validators",
+		Description: "validate the users and the transfers written",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for collection, schema := range map[string]bson.M{"users": userSchema, "transfers": transferSchema} {
+				if err := setValidator(ctx, db, collection, bson.M{"$jsonSchema": schema}, "moderate"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, collection := range []string{"users", "transfers"} {
+				if err := setValidator(ctx, db, collection, bson.M{}, "strict"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     "0004_index_user_lookups",
+		Description: "create the indexes of the users found by email or by document and listed by type",
+		Indexes: map[string][]mongo.IndexModel{
+			"users": {
+				{Keys: bson.D{{Key: "email", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "document.type", Value: 1}, {Key: "document.value", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: 1}}},
+			},
+		},
+	},
+	{
+		Version:     "0005_index_audit_entries",
+		Description: "chain the audit entries by a unique sequence and create the indexes of their filters",
+		Indexes: map[string][]mongo.IndexModel{
+			"audit_entries": {
+				{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				// the entries appended before the log was chained have no sequence
+				{Keys: bson.D{{Key: "sequence", Value: 1}}, Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"sequence": bson.M{"$exists": true}})},
+				{Keys: bson.D{{Key: "subject_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "correlation_id", Value: 1}}},
+			},
+		},
+	},
+	{
+		Version:     "0006_index_fundings",
+		Description: "create the indexes listing the fundings, of a user or all of them",
+		Indexes: map[string][]mongo.IndexModel{
+			"fundings": {
+				{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				{Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}},
+			},
+		},
+	},
+	{
+		Version:     "0007_index_pending_audit_entries",
+		Description: "create the index of the audit entries appended and not yet chained",
+		Indexes: map[string][]mongo.IndexModel{
+			"audit_entries": {
+				{Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}, Options: options.Index().
+					SetName("pending_created_at_id").
+					SetPartialFilterExpression(bson.M{"pending": true})},
+			},
+		},
+	},
+	{
+		Version:     "0008_index_wallet_baselines",
+		Description: "create the unique index of the wallet baselines the reconciliation sums the movements to",
+		Indexes: map[string][]mongo.IndexModel{
+			"wallet_baselines": {
+				{Keys: bson.D{
+					{Key: "user_id", Value: 1},
+					{Key: "currency", Value: 1},
+					{Key: "purpose", Value: 1},
+					{Key: "baseline_at", Value: 1},
+				}, Options: options.Index().SetName("user_id_currency_purpose_baseline_at").SetUnique(true)},
+			},
+		},
+	},
+	{
+		Version:     "0009_index_reconciliation_checkpoints",
+		Description: "create the indexes of the reconciliation checkpoints and of the fundings updated since one",
+		Indexes: map[string][]mongo.IndexModel{
+			"reconciliation_checkpoints": {
+				{Keys: bson.D{{Key: "checkpoint_at", Value: 1}}, Options: options.Index().SetUnique(true)},
+			},
+			"fundings": {
+				{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+			},
+		},
+	},
+}
+
+var (
+	// integer matches the int64 values, stored as 32-bit integers when written by other clients
+	integer = bson.A{"int", "long"}
+
+	userSchema = bson.M{
+		"bsonType": "object",
+		"required": bson.A{"id", "full_name", "email", "password", "document", "type", "created_at"},
+		"properties": bson.M{
+			"id":        bson.M{"bsonType": "string"},
+			"full_name": bson.M{"bsonType": "string"},
+			"email":     bson.M{"bsonType": "string"},
+			"password":  bson.M{"bsonType": "string"},
+			"document": bson.M{
+				"bsonType": "object",
+				"required": bson.A{"type", "value"},
+				"properties": bson.M{
+					"type":  bson.M{"bsonType": "string"},
+					"value": bson.M{"bsonType": "string"},
+				},
+			},
+			"wallets": bson.M{
+				"bsonType": "array",
+				"items": bson.M{
+					"bsonType": "object",
+					"required": bson.A{"currency", "purpose", "amount", "held", "version"},
+					"properties": bson.M{
+						"currency": bson.M{"bsonType": "string"},
+						"purpose":  bson.M{"bsonType": "string"},
+						"amount":   bson.M{"bsonType": integer},
+						"held":     bson.M{"bsonType": integer},
+						"version":  bson.M{"bsonType": integer},
+					},
+				},
+			},
+			"type":             bson.M{"bsonType": "string"},
+			"created_at":       bson.M{"bsonType": "date"},
+			"email_changed_at": bson.M{"bsonType": "date"},
+		},
+	}
+
+	transferSchema = bson.M{
+		"bsonType": "object",
+		"required": bson.A{"id", "payer_id", "payee_id", "value", "currency", "status", "created_at"},
+		"properties": bson.M{
+			"id":       bson.M{"bsonType": "string"},
+			"payer_id": bson.M{"bsonType": "string"},
+			"payee_id": bson.M{"bsonType": "string"},
+			"value":    bson.M{"bsonType": integer},
+			"currency": bson.M{"bsonType": "string"},
+			"type":     bson.M{"bsonType": "string"},
+			"status":   bson.M{"bsonType": "string"},
+			"fee":      bson.M{"bsonType": integer},
+			"legs": bson.M{
+				"bsonType": "array",
+				"items": bson.M{
+					"bsonType": "object",
+					"required": bson.A{"payee", "value"},
+					"properties": bson.M{
+						"payee": bson.M{"bsonType": "string"},
+						"value": bson.M{"bsonType": integer},
+						"fee":   bson.M{"bsonType": integer},
+					},
+				},
+			},
+			"created_at": bson.M{"bsonType": "date"},
+		},
+	}
+)
+
+// renameTransferFields moves the fields the transfers were stored with, the
+// lowercased names of the Go fields, to snake case. created_at, a string, is
+// replaced by the date of timestamp, parsed from it when the document has none.
+func renameTransferFields(ctx context.Context, db *mongo.Database) error {
+	transfers := db.Collection("transfers")
+
+	// the indexes made on the old fields by the reindex command of the admin CLI
+	for _, name := range []string{"payerid_1_timestamp_1", "payeeid_1", "timestamp_1_id_1"} {
+		if _, err := transfers.Indexes().DropOne(ctx, name); err != nil && !isCommandError(err, indexNotFoundCode, namespaceNotFoundCode) {
+			return errors.Wrap(err, "failed to drop index "+name)
+		}
+	}
+
+	cursor, err := transfers.Find(
+		ctx,
+		bson.M{"timestamp": bson.M{"$exists": false}, "createdat": bson.M{"$type": "string"}},
+		options.Find().SetProjection(bson.M{"createdat": 1}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the transfers without timestamp")
+	}
+
+	var docs []struct {
+		ID        interface{} `bson:"_id"`
+		CreatedAt string      `bson:"createdat"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return errors.Wrap(err, "failed to find the transfers without timestamp")
+	}
+
+	for _, doc := range docs {
+		at, err := parseTimeString(doc.CreatedAt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the creation date of transfer %v", doc.ID)
+		}
+
+		if _, err := transfers.UpdateByID(ctx, doc.ID, bson.M{"$set": bson.M{"timestamp": at.UTC()}}); err != nil {
+			return errors.Wrapf(err, "failed to set the timestamp of transfer %v", doc.ID)
+		}
+	}
+
+	_, err = transfers.UpdateMany(
+		ctx,
+		bson.M{"payerid": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"payer_id":   "$payerid",
+				"payee_id":   "$payeeid",
+				"created_at": "$timestamp",
+			}}},
+			{{Key: "$unset", Value: bson.A{"payerid", "payeeid", "createdat", "timestamp"}}},
+		},
+	)
+
+	return errors.Wrap(err, "failed to rename the fields of the transfers")
+}
+
+// restoreTransferFields moves the fields of the transfers back to the names
+// renameTransferFields changed, created_at being formatted as time.Time.String
+// does for UTC dates
+func restoreTransferFields(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("transfers").UpdateMany(
+		ctx,
+		bson.M{"payer_id": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"payerid":   "$payer_id",
+				"payeeid":   "$payee_id",
+				"timestamp": "$created_at",
+				"createdat": bson.M{"$dateToString": bson.M{
+					"date":   "$created_at",
+					"format": "%Y-%m-%d %H:%M:%S.%L +0000 UTC",
+				}},
+			}}},
+			{{Key: "$unset", Value: bson.A{"payer_id", "payee_id", "created_at"}}},
+		},
+	)
+
+	return errors.Wrap(err, "failed to restore the fields of the transfers")
+}
+
+// parseTimeString parses a date formatted by time.Time.String, dropping the
+// monotonic clock reading it may end with
+func parseTimeString(s string) (time.Time, error) {
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+
+	return time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", s)
+}
+
+// setValidator sets the validator of the collection, creating it when it does not exist
+func setValidator(ctx context.Context, db *mongo.Database, collection string, validator bson.M, level string) error {
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: level},
+	}).Err()
+	if isCommandError(err, namespaceNotFoundCode) {
+		err = db.CreateCollection(ctx, collection, options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel(level))
+	}
+
+	return errors.Wrap(err, "failed to set the validator of "+collection)
+}
@@ -2,12 +2,13 @@ package database
 
 import (
 	"context"
-	"log"
 	"os"
 	"time"
 
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // MongoHandler define the MongoDb handler
@@ -16,26 +17,27 @@ type MongoHandler struct {
 	client *mongo.Client
 }
 
-// NewMongoHandler create new MongoHander
-func NewMongoHandler() *MongoHandler {
+// NewMongoHandler create new MongoHander for the MONGODB_DATABASE database, its migrations are applied by Migrate
+func NewMongoHandler() (*MongoHandler, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
 	clientOpts := options.Client().ApplyURI(os.Getenv("MONGODB_URI"))
 	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.Wrap(err, "failed to connect to mongodb")
 	}
 
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatal(err)
+		_ = client.Disconnect(ctx)
+		return nil, errors.Wrap(err, "failed to ping mongodb")
 	}
 
 	return &MongoHandler{
 		db:     client.Database(os.Getenv("MONGODB_DATABASE")),
 		client: client,
-	}
+	}, nil
 }
 
 // Client return the client property
@@ -47,3 +49,13 @@ func (m *MongoHandler) Client() *mongo.Client {
 func (m *MongoHandler) Db() *mongo.Database {
 	return m.db
 }
+
+// Disconnect closes the sockets to the MongoDB deployment
+func (m *MongoHandler) Disconnect(ctx context.Context) error {
+	return m.client.Disconnect(ctx)
+}
+
+// Ping verifies that the primary of the MongoDB deployment is reachable
+func (m *MongoHandler) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, readpref.Primary())
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// NewPostgresHandler create new SQLHandler connected to POSTGRES_DSN with the schema migrated
+func NewPostgresHandler() (*SQLHandler, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	db, err := sql.Open(DriverPostgres, os.Getenv("POSTGRES_DSN"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open postgres")
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(25)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "failed to ping postgres")
+	}
+
+	h := &SQLHandler{db: db, driver: DriverPostgres}
+	if err := h.migrate(ctx, postgresMigrations, "migrations/postgres"); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return h, nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SQL drivers
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type (
+	// SQLHandler define the handler of the databases accessed through database/sql
+	SQLHandler struct {
+		db     *sql.DB
+		driver string
+	}
+
+	// migrator define the statements that differ between the drivers when migrating
+	migrator struct {
+		createTable string
+		lock        string
+		insert      string
+	}
+)
+
+var migrators = map[string]migrator{
+	DriverPostgres: {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		lock:   `SELECT pg_advisory_xact_lock(7263514892)`,
+		insert: `INSERT INTO schema_migrations (version) VALUES ($1)`,
+	},
+	DriverSQLite: {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		insert: `INSERT INTO schema_migrations (version) VALUES (?)`,
+	},
+}
+
+// DB returns the db property
+func (h *SQLHandler) DB() *sql.DB {
+	return h.db
+}
+
+// Driver returns the driver property
+func (h *SQLHandler) Driver() string {
+	return h.driver
+}
+
+// Ping verifies that the database is reachable
+func (h *SQLHandler) Ping(ctx context.Context) error {
+	return h.db.PingContext(ctx)
+}
+
+// Close closes the database and prevents new queries from starting
+func (h *SQLHandler) Close(_ context.Context) error {
+	return h.db.Close()
+}
+
+// migrate applies, in lexical order and inside a single transaction, the .sql
+// files of dir not yet recorded in the schema_migrations table
+func (h *SQLHandler) migrate(ctx context.Context, migrations fs.FS, dir string) error {
+	m, ok := migrators[h.driver]
+	if !ok {
+		return errors.Errorf("no migrator for driver %s", h.driver)
+	}
+
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read migrations")
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".sql") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin migration")
+	}
+	defer tx.Rollback()
+
+	if m.lock != "" {
+		if _, err := tx.ExecContext(ctx, m.lock); err != nil {
+			return errors.Wrap(err, "failed to lock migrations")
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, m.createTable); err != nil {
+		return errors.Wrap(err, "failed to create schema_migrations")
+	}
+
+	applied := map[string]bool{}
+	rows, err := tx.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return errors.Wrap(err, "failed to list applied migrations")
+	}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return errors.Wrap(err, "failed to list applied migrations")
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to list applied migrations")
+	}
+
+	for _, f := range files {
+		version := strings.TrimSuffix(f, ".sql")
+		if applied[version] {
+			continue
+		}
+
+		stmt, err := fs.ReadFile(migrations, path.Join(dir, f))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read migration %s", f)
+		}
+
+		if _, err := tx.ExecContext(ctx, string(stmt)); err != nil {
+			return errors.Wrapf(err, "failed to apply migration %s", f)
+		}
+
+		if _, err := tx.ExecContext(ctx, m.insert, version); err != nil {
+			return errors.Wrapf(err, "failed to record migration %s", f)
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "failed to commit migrations")
+}
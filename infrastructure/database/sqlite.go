@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	_ "modernc.org/sqlite"
+)
+
+const defaultSQLiteDSN = "file::memory:"
+
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// NewSQLiteHandler create new SQLHandler for the SQLITE_DSN database with the schema migrated.
+// SQLITE_DSN is a file path or URI, an in-memory database is used when it is empty.
+func NewSQLiteHandler() (*SQLHandler, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	dsn := os.Getenv("SQLITE_DSN")
+	if dsn == "" {
+		dsn = defaultSQLiteDSN
+	}
+
+	db, err := sql.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open sqlite")
+	}
+
+	// a single connection serializes the transactions, which SQLite would otherwise
+	// reject with SQLITE_BUSY, and keeps an in-memory database alive
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	for _, pragma := range []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA busy_timeout = 5000",
+	} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			_ = db.Close()
+			return nil, errors.Wrapf(err, "failed to set sqlite %s", pragma)
+		}
+	}
+
+	h := &SQLHandler{db: db, driver: DriverSQLite}
+	if err := h.migrate(ctx, sqliteMigrations, "migrations/sqlite"); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return h, nil
+}
@@ -5,6 +5,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/metrics"
+	inframetrics "github.com/dungnguyen/clean-architecture/infrastructure/metrics"
 )
 
 type (
@@ -15,15 +18,26 @@ type (
 		Execute(func() (interface{}, error)) (interface{}, error)
 	}
 
+	// BreakerState is implemented by the breakers able to report their state.
+	BreakerState interface {
+		// State returns "closed", "half-open" or "open".
+		State() string
+	}
+
 	// CircuitBreaker is the application http transport.
 	CircuitBreaker struct {
 		rt      http.RoundTripper
 		breaker Breaker
+		metrics metrics.Metrics
+		target  string
 	}
+
+	// CircuitBreakerOption is the circuit breaker options
+	CircuitBreakerOption func(*CircuitBreaker)
 )
 
 // NewCircuitBreaker returns a new configured CircuitBreaker with circuit breaker.
-func NewCircuitBreaker(cb Breaker) *CircuitBreaker {
+func NewCircuitBreaker(cb Breaker, opts ...CircuitBreakerOption) *CircuitBreaker {
 	rt := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   10 * time.Second,
@@ -32,9 +46,22 @@ func NewCircuitBreaker(cb Breaker) *CircuitBreaker {
 		}).DialContext,
 	}
 
-	return &CircuitBreaker{
+	c := &CircuitBreaker{
 		rt:      rt,
 		breaker: cb,
+		metrics: inframetrics.Dummy{},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// WithBreakerMetrics records the breaker state under the given target name
+func WithBreakerMetrics(m metrics.Metrics, target string) CircuitBreakerOption {
+	return func(c *CircuitBreaker) {
+		c.metrics = m
+		c.target = target
 	}
 }
 
@@ -54,6 +81,10 @@ func (t *CircuitBreaker) RoundTrip(r *http.Request) (*http.Response, error) {
 		return res, err
 	})
 
+	if s, ok := t.breaker.(BreakerState); ok {
+		t.metrics.SetBreakerState(t.target, s.State())
+	}
+
 	if err != nil {
 		return nil, err
 	}
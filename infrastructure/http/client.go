@@ -1,6 +1,9 @@
 package http
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type (
 	// Client is the http wrapper for the application
@@ -15,6 +18,6 @@ func NewClient(r *Request) *Client {
 }
 
 // Get execute a GET http request
-func (c *Client) Get(url string) (*http.Response, error) {
-	return c.req.Do(http.MethodGet, url, "application/json", nil)
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	return c.req.Do(ctx, http.MethodGet, url, "application/json", nil)
 }
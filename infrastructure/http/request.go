@@ -6,6 +6,15 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/adapter/metrics"
+	inframetrics "github.com/dungnguyen/clean-architecture/infrastructure/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -18,38 +27,73 @@ type (
 
 	// Request is the application http request
 	Request struct {
-		client *http.Client
+		client  *http.Client
+		metrics metrics.Metrics
+		tracer  trace.Tracer
+		target  string
 	}
 )
 
 // NewRequest return a new configured Request
 func NewRequest(opts ...RequestOption) *Request {
-	r := &Request{client: new(http.Client)}
+	r := &Request{
+		client:  new(http.Client),
+		metrics: inframetrics.Dummy{},
+		tracer:  otel.Tracer("github.com/dungnguyen/clean-architecture/infrastructure/http"),
+	}
 	for _, o := range opts {
 		o(r)
 	}
 	return r
 }
 
-// Do is a convenient method for executing http request
-func (r *Request) Do(method, url, contentType string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
+// Do is a convenient method for executing http request.
+// The request is traced and carries the trace context and correlation id of ctx.
+func (r *Request) Do(ctx context.Context, method, url, contentType string, body io.Reader) (*http.Response, error) {
+	ctx, span := r.tracer.Start(
+		ctx,
+		fmt.Sprintf("HTTP %s %s", method, r.target),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", method),
+			attribute.String("http.url", url),
+			attribute.String("peer.service", r.target),
+		),
+	)
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("failed to create request %v", err)
 	}
 
 	req.Header.Set("Content-Type", contentType)
+	if id := logger.CorrelationIDFromContext(ctx); id != "" {
+		req.Header.Set("X-Correlation-Id", id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	if r.client.Timeout == 0 {
 		r.client.Timeout = defaultTimeout
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), r.client.Timeout)
-	defer cancel()
+	start := time.Now()
+	res, err := r.client.Do(req)
 
-	req = req.WithContext(ctx)
+	var status int
+	if res != nil {
+		status = res.StatusCode
+		span.SetAttributes(attribute.Int("http.status_code", status))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	r.metrics.ObserveOutboundRequest(r.target, method, status, time.Since(start))
 
-	return r.client.Do(req)
+	return res, err
 }
 
 // WithCircuitBreaker receive the http.RoundTripper implementation
@@ -66,6 +110,14 @@ func WithRetry(rt http.RoundTripper) RequestOption {
 	}
 }
 
+// WithMetrics records the outbound requests under the given target name, also used to name the spans
+func WithMetrics(m metrics.Metrics, target string) RequestOption {
+	return func(r *Request) {
+		r.metrics = m
+		r.target = target
+	}
+}
+
 func WithTimeout(t time.Duration) RequestOption {
 	return func(r *Request) {
 		r.client.Timeout = t
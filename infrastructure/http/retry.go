@@ -6,6 +6,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/metrics"
+	inframetrics "github.com/dungnguyen/clean-architecture/infrastructure/metrics"
 )
 
 var (
@@ -19,14 +22,19 @@ type (
 		sleep       time.Duration
 		statusCodes []int
 		rt          http.RoundTripper
+		metrics     metrics.Metrics
+		target      string
 	}
 
+	// RetryOption is the retry options
+	RetryOption func(*Retry)
+
 	// Func is the function to be executed and eventually retried.
 	Func func() error
 )
 
 // NewRetry returns a new configured CircuitBreaker with retry.
-func NewRetry(attempts int, statusCode []int, sleep time.Duration) *Retry {
+func NewRetry(attempts int, statusCode []int, sleep time.Duration, opts ...RetryOption) *Retry {
 	rt := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   10 * time.Second,
@@ -35,11 +43,24 @@ func NewRetry(attempts int, statusCode []int, sleep time.Duration) *Retry {
 		}).DialContext,
 	}
 
-	return &Retry{
+	r := &Retry{
 		attempts:    attempts,
 		sleep:       sleep,
 		statusCodes: statusCode,
 		rt:          rt,
+		metrics:     inframetrics.Dummy{},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// WithRetryMetrics counts the retry attempts under the given target name
+func WithRetryMetrics(m metrics.Metrics, target string) RetryOption {
+	return func(r *Retry) {
+		r.metrics = m
+		r.target = target
 	}
 }
 
@@ -63,7 +84,13 @@ func (r *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
 		return res, err
 	}
 
+	var attempt int
 	err = retry(func() error {
+		attempt++
+		if attempt > 1 {
+			r.metrics.IncRetryAttempt(r.target)
+		}
+
 		var err error
 		res, err = fn()
 		return err
@@ -1,110 +1,1035 @@
 package infrastructure
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/adapter/api/handler"
+	"github.com/dungnguyen/clean-architecture/adapter/api/middleware"
+	"github.com/dungnguyen/clean-architecture/adapter/cli"
+	"github.com/dungnguyen/clean-architecture/adapter/health"
 	adapterhttp "github.com/dungnguyen/clean-architecture/adapter/http"
 	adapterlogger "github.com/dungnguyen/clean-architecture/adapter/logger"
+	adaptermetrics "github.com/dungnguyen/clean-architecture/adapter/metrics"
 	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/adapter/provider"
 	adapterqueue "github.com/dungnguyen/clean-architecture/adapter/queue"
-	"github.com/dungnguyen/clean-architecture/adapter/repository"
-	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/audit"
+	"github.com/dungnguyen/clean-architecture/infrastructure/batch"
 	infrahttp "github.com/dungnguyen/clean-architecture/infrastructure/http"
+	"github.com/dungnguyen/clean-architecture/infrastructure/lifecycle"
+	"github.com/dungnguyen/clean-architecture/infrastructure/limits"
 	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+	"github.com/dungnguyen/clean-architecture/infrastructure/metrics"
+	"github.com/dungnguyen/clean-architecture/infrastructure/payment"
+	"github.com/dungnguyen/clean-architecture/infrastructure/pricing"
 	"github.com/dungnguyen/clean-architecture/infrastructure/queue"
+	"github.com/dungnguyen/clean-architecture/infrastructure/reconciliation"
+	"github.com/dungnguyen/clean-architecture/infrastructure/review"
+	"github.com/dungnguyen/clean-architecture/infrastructure/risk"
 	"github.com/dungnguyen/clean-architecture/infrastructure/router"
+	"github.com/dungnguyen/clean-architecture/infrastructure/scheduler"
+	"github.com/dungnguyen/clean-architecture/infrastructure/tracing"
 	"github.com/dungnguyen/clean-architecture/usecase"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-// HTTPServer define an application structure
-type HTTPServer struct {
-	database *database.MongoHandler
-	logger   adapterlogger.Logger
-	router   router.Router
-	queue    *queue.RabbitMQHandler
+const (
+	// PaymentProviderSimulated is the PAYMENT_PROVIDER of the simulated provider, for development only
+	PaymentProviderSimulated = "simulated"
+
+	// providerCallbackTolerance is how old a signed callback of the payment provider may be
+	providerCallbackTolerance = 5 * time.Minute
+	// migrateTimeout is how long the server waits for its migrations, the ones another instance is running included
+	migrateTimeout = 15 * time.Minute
+)
+
+var ErrNoPaymentProvider = errors.New("no payment provider configured, set PAYMENT_PROVIDER")
+
+type (
+	// HTTPServer define an application structure
+	HTTPServer struct {
+		storage *storage
+		logger  adapterlogger.Logger
+		router  router.Router
+		queue   *queue.RabbitMQHandler
+		metrics *metrics.Prometheus
+		tracer  *sdktrace.TracerProvider
+		batches *batch.Processor
+		pricing entity.Pricing
+		limits  entity.LimitPolicy
+		risk    entity.RiskPolicy
+		review  entity.ReviewPolicy
+		// holdTimeout is how long an authorized payment holds its value
+		holdTimeout time.Duration
+
+		driver     string
+		authorizer usecase.Authorizer
+		notifier   usecase.Notifier
+		provider   usecase.PaymentProvider
+		// adminToken is the bearer token of the /admin routes
+		adminToken string
+		// providerSecret signs the callbacks of the payment provider
+		providerSecret string
+	}
+
+	// Option is the HTTPServer options
+	Option func(*HTTPServer)
+)
+
+// NewHTTPServer create new HTTPServer with its dependencies.
+// Without options the configuration is read from the environment variables.
+func NewHTTPServer(opts ...Option) (*HTTPServer, error) {
+	proxies, err := trustedProxies()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &HTTPServer{
+		logger:         logger.NewLogrus(),
+		router:         router.NewMux(proxies),
+		metrics:        metrics.NewPrometheus(),
+		driver:         storageDriver(),
+		adminToken:     os.Getenv("ADMIN_TOKEN"),
+		providerSecret: os.Getenv("PAYMENT_PROVIDER_SECRET"),
+	}
+	for _, o := range opts {
+		o(a)
+	}
+
+	if a.provider == nil {
+		pp, err := paymentProvider(a.logger)
+		if err != nil {
+			return nil, err
+		}
+		a.provider = pp
+	}
+	if a.adminToken == "" {
+		a.logger.Warnf("ADMIN_TOKEN is empty, the admin routes reject every request")
+	}
+	if a.providerSecret == "" {
+		a.logger.Warnf("PAYMENT_PROVIDER_SECRET is empty, the payment provider callbacks are rejected")
+	}
+
+	p, err := pricing.NewPricing()
+	if err != nil {
+		return nil, err
+	}
+	a.pricing = p
+
+	l, err := limits.NewLimitPolicy()
+	if err != nil {
+		return nil, err
+	}
+	a.limits = l
+
+	r, err := risk.NewRiskPolicy()
+	if err != nil {
+		return nil, err
+	}
+	a.risk = r
+
+	rp, err := review.NewReviewPolicy()
+	if err != nil {
+		return nil, err
+	}
+	a.review = rp
+
+	ht, err := payment.NewHoldTimeout()
+	if err != nil {
+		return nil, err
+	}
+	a.holdTimeout = ht
+
+	tp, err := tracing.NewTracerProvider(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	a.tracer = tp
+
+	st, err := newStorage(a.driver)
+	if err != nil {
+		return nil, err
+	}
+	a.storage = st
+
+	if migrateOnStart() {
+		ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+		versions, err := st.migrate(ctx)
+		cancel()
+		if err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = st.close(ctx)
+
+			return nil, err
+		}
+		if len(versions) > 0 {
+			a.logger.WithFields(adapterlogger.Fields{"versions": versions}).Infof("applied the pending migrations")
+		}
+	}
+
+	if os.Getenv("RABBITMQ_URI") != "" {
+		a.queue, err = queue.NewRabbitMQHandler()
+	}
+	if err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = st.close(ctx)
+
+		return nil, err
+	}
+
+	a.batches = a.batchProcessor()
+	a.routes()
+
+	return a, nil
+}
+
+// WithStorageDriver selects the storage driver instead of STORAGE_DRIVER
+func WithStorageDriver(driver string) Option {
+	return func(a *HTTPServer) {
+		a.driver = driver
+	}
+}
+
+// WithLogger replaces the logger
+func WithLogger(l adapterlogger.Logger) Option {
+	return func(a *HTTPServer) {
+		a.logger = l
+	}
+}
+
+// WithAuthorizer replaces the authorizer service called through AUTHORIZER_URI
+func WithAuthorizer(authorizer usecase.Authorizer) Option {
+	return func(a *HTTPServer) {
+		a.authorizer = authorizer
+	}
+}
+
+// WithNotifier replaces the notification service called through NOTIFY_URI
+func WithNotifier(notifier usecase.Notifier) Option {
+	return func(a *HTTPServer) {
+		a.notifier = notifier
+	}
 }
 
-// NewHTTPServer create new HTTPServer with its dependencies
-func NewHTTPServer() *HTTPServer {
-	return &HTTPServer{
-		database: database.NewMongoHandler(),
-		logger:   logger.NewLogrus(),
-		router:   router.NewMux(),
-		queue:    queue.NewRabbitMQHandler(),
+// WithPaymentProvider replaces the PAYMENT_PROVIDER payment provider of the deposits and the withdrawals
+func WithPaymentProvider(provider usecase.PaymentProvider) Option {
+	return func(a *HTTPServer) {
+		a.provider = provider
+	}
+}
+
+// WithAdminToken replaces the ADMIN_TOKEN bearer token of the admin routes
+func WithAdminToken(token string) Option {
+	return func(a *HTTPServer) {
+		a.adminToken = token
+	}
+}
+
+// Handler returns the HTTP handler of the application, to serve it without Start
+func (a HTTPServer) Handler() http.Handler {
+	return a.router.Handler()
+}
+
+// Close disconnects from the dependencies, for the servers used through Handler
+func (a HTTPServer) Close(ctx context.Context) error {
+	var errs []error
+	if a.queue != nil {
+		if err := a.queue.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := a.storage.close(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := a.tracer.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return &lifecycle.StopError{Errors: errs}
+	}
+
+	return nil
+}
+
+// Start run the application until a termination signal is received, then
+// drains the HTTP connections and disconnects from the dependencies
+func (a HTTPServer) Start() error {
+	manager := lifecycle.NewManager(a.logger, lifecycle.WithShutdownTimeout(shutdownTimeout()))
+	manager.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: a.tracer.Shutdown,
+	})
+	manager.Append(lifecycle.Hook{
+		Name:   a.storage.driver,
+		OnStop: a.storage.close,
+	})
+	if a.queue != nil {
+		manager.Append(lifecycle.Hook{
+			Name: "rabbitmq",
+			OnStop: func(_ context.Context) error {
+				return a.queue.Close()
+			},
+		})
+	}
+	if interval := auditChainInterval(); interval > 0 {
+		worker := a.auditChainer(interval)
+		manager.Append(lifecycle.Hook{
+			Name:   "audit_chainer",
+			Serve:  worker.Run,
+			OnStop: worker.Stop,
+		})
 	}
+	if interval := schedulerInterval(); interval > 0 {
+		worker := a.scheduler(interval)
+		manager.Append(lifecycle.Hook{
+			Name:   "scheduler",
+			Serve:  worker.Run,
+			OnStop: worker.Stop,
+		})
+	}
+	if interval := reviewInterval(); interval > 0 {
+		worker := a.reviewExpirer(interval)
+		manager.Append(lifecycle.Hook{
+			Name:   "review_expirer",
+			Serve:  worker.Run,
+			OnStop: worker.Stop,
+		})
+	}
+	if interval := paymentInterval(); interval > 0 {
+		worker := a.paymentExpirer(interval)
+		manager.Append(lifecycle.Hook{
+			Name:   "payment_expirer",
+			Serve:  worker.Run,
+			OnStop: worker.Stop,
+		})
+	}
+	if at, ok := reconciliationTime(); ok {
+		worker := a.reconciliationJob(at)
+		manager.Append(lifecycle.Hook{
+			Name:   "reconciliation",
+			Serve:  worker.Run,
+			OnStop: worker.Stop,
+		})
+	}
+	manager.Append(lifecycle.Hook{
+		Name:   "batch_processor",
+		Serve:  a.batches.Run,
+		OnStop: a.batches.Stop,
+	})
+	manager.Append(lifecycle.Hook{
+		Name: "http_server",
+		Serve: func() error {
+			a.logger.WithFields(adapterlogger.Fields{"port": os.Getenv("APP_PORT")}).Infof("Starting HTTP Server")
+			return a.router.SERVE(os.Getenv("APP_PORT"))
+		},
+		OnStop: a.router.Shutdown,
+	})
+
+	return manager.Run(context.Background())
 }
 
-// Start run the application
-func (a HTTPServer) Start() {
-	a.router.GET("health", healthCheck)
+func (a HTTPServer) routes() {
+	a.router.USE(middleware.NewMetrics(a.metrics).Execute)
+	a.router.USE(middleware.NewTracing().Execute)
+	a.router.GET("/metrics", a.metrics.Handler().ServeHTTP)
 
-	a.router.POST("/users", a.createUserHandler())
+	probes := a.healthHandler()
+	a.router.GET("/health", probes.Live)
+	a.router.GET("/health/live", probes.Live)
+	a.router.GET("/health/ready", probes.Ready)
+
+	a.router.POST("/users", a.createUserHandler(false))
 	a.router.GET("/users/{user_id}", a.findUserByIDHandler())
+	a.router.POST("/users/{user_id}/wallets", a.createWalletHandler())
 
 	a.router.POST("/transfers", a.createTransferHandler())
+	a.router.POST("/transfers/split", a.createSplitTransferHandler())
+	a.router.POST("/transfers/batch", a.createBatchTransferHandler())
+	a.router.GET("/transfers/batch/{batch_id}", a.findBatchTransferHandler())
+
+	a.router.POST("/schedules", a.scheduleTransferHandler())
+	a.router.GET("/users/{user_id}/schedules", a.listSchedulesHandler())
+	a.router.POST("/schedules/{schedule_id}/pause", a.changeScheduleStatusHandler(usecase.PauseSchedule))
+	a.router.POST("/schedules/{schedule_id}/resume", a.changeScheduleStatusHandler(usecase.ResumeSchedule))
+	a.router.POST("/schedules/{schedule_id}/cancel", a.changeScheduleStatusHandler(usecase.CancelSchedule))
+
+	a.router.POST("/deposits", a.depositHandler())
+	a.router.POST("/withdrawals", a.withdrawalHandler())
+
+	// only the payment provider calls back, with signed requests
+	callback := middleware.NewProviderSignature(a.providerSecret, providerCallbackTolerance).Execute
+	a.router.POST("/fundings/{funding_id}/settle", callback(a.settleFundingHandler(usecase.SettleFunding)).ServeHTTP)
+	a.router.POST("/fundings/{funding_id}/fail", callback(a.settleFundingHandler(usecase.FailFunding)).ServeHTTP)
 
-	a.logger.WithFields(adapterlogger.Fields{"port": os.Getenv("APP_PORT")}).Infof("Starting HTTP Server")
-	a.router.SERVE(os.Getenv("APP_PORT"))
+	// every /admin route requires the admin token
+	admin := middleware.NewAdminAuth(a.adminToken).Execute
+	a.router.POST("/admin/users", admin(a.createUserHandler(true)).ServeHTTP)
+	a.router.GET("/admin/reviews", admin(a.listReviewsHandler()).ServeHTTP)
+	a.router.POST("/admin/reviews/{review_id}/approve", admin(a.decideReviewHandler(usecase.ApproveReview)).ServeHTTP)
+	a.router.POST("/admin/reviews/{review_id}/reject", admin(a.decideReviewHandler(usecase.RejectReview)).ServeHTTP)
+	a.router.GET("/admin/audit", admin(a.listAuditEntriesHandler()).ServeHTTP)
+	a.router.GET("/admin/audit/verify", admin(a.verifyAuditHandler()).ServeHTTP)
+
+	a.router.POST("/payments", a.authorizePaymentHandler())
+	a.router.POST("/payments/{payment_id}/capture", a.capturePaymentHandler())
+	a.router.POST("/payments/{payment_id}/void", a.voidPaymentHandler())
 }
 
 func (a HTTPServer) createTransferHandler() http.HandlerFunc {
-	authorizer := adapterhttp.NewAuthorizer(
+	return handler.NewCreateTransferHandler(a.createTransferUseCase(), a.logger).Handle
+}
+
+func (a HTTPServer) createTransferUseCase() usecase.CreateTransferUseCase {
+	uc := usecase.NewCreateTransferInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.users,
+		a.storage.users,
+		a.storage.reviews,
+		a.storage.audit,
+		presenter.NewCreateTransferPresenter(),
+		a.transferAuthorizer(),
+		a.transferNotifier(),
+		a.pricing,
+		a.limits,
+		a.review,
+	)
+
+	return adaptermetrics.NewCreateTransferUseCase(uc, a.metrics)
+}
+
+func (a HTTPServer) createSplitTransferHandler() http.HandlerFunc {
+	uc := usecase.NewCreateSplitTransferInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.users,
+		a.storage.users,
+		a.storage.reviews,
+		a.storage.audit,
+		presenter.NewCreateSplitTransferPresenter(),
+		a.transferAuthorizer(),
+		a.transferNotifier(),
+		a.pricing,
+		a.limits,
+		a.review,
+	)
+
+	return handler.NewCreateSplitTransferHandler(adaptermetrics.NewCreateSplitTransferUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) createBatchTransferHandler() http.HandlerFunc {
+	uc := usecase.NewCreateBatchTransferInteractor(
+		a.storage.batches,
+		a.storage.users,
+		presenter.NewCreateBatchTransferPresenter(),
+		a.batches,
+	)
+
+	return handler.NewCreateBatchTransferHandler(adaptermetrics.NewCreateBatchTransferUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) findBatchTransferHandler() http.HandlerFunc {
+	uc := usecase.NewFindBatchTransferInteractor(
+		a.storage.batches,
+		presenter.NewFindBatchTransferPresenter(),
+	)
+
+	return handler.NewFindBatchTransferHandler(adaptermetrics.NewFindBatchTransferUseCase(uc, a.metrics), a.logger).Handle
+}
+
+// batchProcessor returns the worker executing the batches of transfers
+func (a HTTPServer) batchProcessor() *batch.Processor {
+	uc := usecase.NewProcessBatchTransfersInteractor(
+		a.storage.batches,
+		a.storage.batches,
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.users,
+		a.storage.users,
+		a.storage.audit,
+		a.transferAuthorizer(),
+		a.transferNotifier(),
+		a.pricing,
+		a.limits,
+	)
+
+	return batch.NewProcessor(adaptermetrics.NewProcessBatchTransfersUseCase(uc, a.metrics), a.logger)
+}
+
+func (a HTTPServer) scheduleTransferHandler() http.HandlerFunc {
+	uc := usecase.NewScheduleTransferInteractor(
+		a.storage.schedules,
+		a.storage.users,
+		presenter.NewScheduleTransferPresenter(),
+	)
+
+	return handler.NewScheduleTransferHandler(adaptermetrics.NewScheduleTransferUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) listSchedulesHandler() http.HandlerFunc {
+	uc := usecase.NewListSchedulesInteractor(
+		a.storage.schedules,
+		presenter.NewListSchedulesPresenter(),
+	)
+
+	return handler.NewListSchedulesHandler(adaptermetrics.NewListSchedulesUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) changeScheduleStatusHandler(action usecase.ScheduleAction) http.HandlerFunc {
+	uc := usecase.NewChangeScheduleStatusInteractor(
+		a.storage.schedules,
+		a.storage.schedules,
+		presenter.NewChangeScheduleStatusPresenter(),
+	)
+
+	return handler.NewChangeScheduleStatusHandler(
+		adaptermetrics.NewChangeScheduleStatusUseCase(uc, a.metrics),
+		action,
+		a.logger,
+	).Handle
+}
+
+// scheduler returns the worker executing the due schedules
+func (a HTTPServer) scheduler(interval time.Duration) *scheduler.Scheduler {
+	uc := usecase.NewExecuteDueSchedulesInteractor(
+		a.storage.schedules,
+		a.storage.schedules,
+		a.createTransferUseCase(),
+	)
+
+	return scheduler.NewScheduler(
+		adaptermetrics.NewExecuteDueSchedulesUseCase(uc, a.metrics),
+		a.logger,
+		scheduler.WithInterval(interval),
+	)
+}
+
+func (a HTTPServer) listReviewsHandler() http.HandlerFunc {
+	uc := usecase.NewListReviewsInteractor(
+		a.storage.reviews,
+		presenter.NewListReviewsPresenter(),
+	)
+
+	return handler.NewListReviewsHandler(adaptermetrics.NewListReviewsUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) listAuditEntriesHandler() http.HandlerFunc {
+	uc := usecase.NewListAuditEntriesInteractor(
+		a.storage.audit,
+		presenter.NewListAuditEntriesPresenter(),
+	)
+
+	return handler.NewListAuditEntriesHandler(adaptermetrics.NewListAuditEntriesUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) verifyAuditHandler() http.HandlerFunc {
+	uc := usecase.NewVerifyAuditInteractor(a.storage.audit)
+
+	return handler.NewVerifyAuditHandler(adaptermetrics.NewVerifyAuditUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) decideReviewHandler(action usecase.ReviewAction) http.HandlerFunc {
+	uc := usecase.NewDecideReviewInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.transferUpdater,
+		a.storage.users,
+		a.storage.users,
+		a.storage.reviews,
+		a.storage.reviews,
+		a.storage.audit,
+		presenter.NewDecideReviewPresenter(),
+		a.transferNotifier(),
+		a.pricing,
+	)
+
+	return handler.NewDecideReviewHandler(
+		adaptermetrics.NewDecideReviewUseCase(uc, a.metrics),
+		action,
+		a.logger,
+	).Handle
+}
+
+// auditChainer returns the worker chaining the audit entries appended by the use cases
+func (a HTTPServer) auditChainer(interval time.Duration) *audit.Chainer {
+	return audit.NewChainer(
+		adaptermetrics.NewChainAuditUseCase(usecase.NewChainAuditInteractor(a.storage.audit), a.metrics),
+		a.logger,
+		audit.WithInterval(interval),
+	)
+}
+
+// reviewExpirer returns the worker deciding the expired reviews as the review policy says
+func (a HTTPServer) reviewExpirer(interval time.Duration) *review.Expirer {
+	uc := usecase.NewExpireReviewsInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.transferUpdater,
+		a.storage.users,
+		a.storage.users,
+		a.storage.reviews,
+		a.storage.reviews,
+		a.storage.audit,
+		a.transferNotifier(),
+		a.pricing,
+		a.review,
+	)
+
+	return review.NewExpirer(
+		adaptermetrics.NewExpireReviewsUseCase(uc, a.metrics),
+		a.logger,
+		review.WithInterval(interval),
+	)
+}
+
+// reconciliationJob returns the worker reconciling the previous day every day
+// at the time, writing its reports to RECONCILIATION_REPORT_DIR when set. A
+// single instance reconciles the day, holding the lock of the reconciliation.
+func (a HTTPServer) reconciliationJob(at time.Duration) *reconciliation.Job {
+	uc := adaptermetrics.NewReconcileUseCase(newReconcileUseCase(a.storage, a.pricing), a.metrics)
+
+	opts := []reconciliation.Option{reconciliation.WithTime(at)}
+	if dir := os.Getenv("RECONCILIATION_REPORT_DIR"); dir != "" {
+		opts = append(opts, reconciliation.WithReports(dir, cli.WriteReconciliationReport))
+	}
+
+	return reconciliation.NewJob(uc, a.logger, opts...)
+}
+
+func (a HTTPServer) authorizePaymentHandler() http.HandlerFunc {
+	uc := usecase.NewAuthorizePaymentInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.users,
+		a.storage.users,
+		a.storage.payments,
+		presenter.NewAuthorizePaymentPresenter(),
+		a.transferAuthorizer(),
+		a.limits,
+		a.holdTimeout,
+	)
+
+	return handler.NewAuthorizePaymentHandler(adaptermetrics.NewAuthorizePaymentUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) capturePaymentHandler() http.HandlerFunc {
+	uc := usecase.NewCapturePaymentInteractor(
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.users,
+		a.storage.payments,
+		a.storage.payments,
+		a.storage.audit,
+		presenter.NewCapturePaymentPresenter(),
+		a.transferNotifier(),
+		a.pricing,
+	)
+
+	return handler.NewCapturePaymentHandler(adaptermetrics.NewCapturePaymentUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) voidPaymentHandler() http.HandlerFunc {
+	uc := usecase.NewVoidPaymentInteractor(
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.users,
+		a.storage.payments,
+		a.storage.payments,
+		presenter.NewVoidPaymentPresenter(),
+	)
+
+	return handler.NewVoidPaymentHandler(adaptermetrics.NewVoidPaymentUseCase(uc, a.metrics), a.logger).Handle
+}
+
+// paymentExpirer returns the worker releasing the holds of the expired payments
+func (a HTTPServer) paymentExpirer(interval time.Duration) *payment.Expirer {
+	uc := usecase.NewExpirePaymentsInteractor(
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.users,
+		a.storage.payments,
+		a.storage.payments,
+	)
+
+	return payment.NewExpirer(
+		adaptermetrics.NewExpirePaymentsUseCase(uc, a.metrics),
+		a.logger,
+		payment.WithInterval(interval),
+	)
+}
+
+func (a HTTPServer) depositHandler() http.HandlerFunc {
+	uc := usecase.NewDepositInteractor(
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.users,
+		a.storage.fundings,
+		a.storage.fundings,
+		a.storage.fundings,
+		a.storage.audit,
+		presenter.NewDepositPresenter(),
+		a.provider,
+	)
+
+	return handler.NewDepositHandler(adaptermetrics.NewDepositUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) withdrawalHandler() http.HandlerFunc {
+	uc := usecase.NewWithdrawalInteractor(
+		a.storage.transferCreator,
+		a.storage.transferFinder,
+		a.storage.users,
+		a.storage.users,
+		a.storage.fundings,
+		a.storage.fundings,
+		a.storage.fundings,
+		a.storage.audit,
+		a.transferAuthorizer(),
+		a.limits,
+		presenter.NewWithdrawalPresenter(),
+		a.provider,
+	)
+
+	return handler.NewWithdrawalHandler(adaptermetrics.NewWithdrawalUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) settleFundingHandler(action usecase.FundingAction) http.HandlerFunc {
+	uc := usecase.NewSettleFundingInteractor(
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.users,
+		a.storage.fundings,
+		a.storage.fundings,
+		a.storage.audit,
+		presenter.NewSettleFundingPresenter(),
+	)
+
+	return handler.NewSettleFundingHandler(
+		adaptermetrics.NewSettleFundingUseCase(uc, a.metrics),
+		action,
+		a.logger,
+	).Handle
+}
+
+// transferAuthorizer returns the authorizer option, the AUTHORIZER_URI service
+// otherwise. With risk rules the transfers are scored by the risk authorizer
+// first, both answers being combined as AUTHORIZER_MODE says.
+func (a HTTPServer) transferAuthorizer() usecase.Authorizer {
+	authorizer := a.remoteAuthorizer()
+	if len(a.risk.Rules()) == 0 {
+		return authorizer
+	}
+
+	return usecase.NewCompositeAuthorizer(
+		authorizerMode(),
+		usecase.NewRiskAuthorizer(a.risk, a.storage.transferFinder, a.storage.users, a.storage.risks),
+		authorizer,
+	)
+}
+
+// remoteAuthorizer returns the authorizer option, the AUTHORIZER_URI service otherwise
+func (a HTTPServer) remoteAuthorizer() usecase.Authorizer {
+	if a.authorizer != nil {
+		return a.authorizer
+	}
+
+	return adapterhttp.NewAuthorizer(
 		infrahttp.NewClient(
 			infrahttp.NewRequest(
-				infrahttp.WithRetry(infrahttp.NewRetry(3, []int{http.StatusInternalServerError}, 400*time.Millisecond)),
+				infrahttp.WithRetry(infrahttp.NewRetry(
+					3,
+					[]int{http.StatusInternalServerError},
+					400*time.Millisecond,
+					infrahttp.WithRetryMetrics(a.metrics, "authorizer"),
+				)),
 				infrahttp.WithTimeout(5*time.Second),
+				infrahttp.WithMetrics(a.metrics, "authorizer"),
 			),
 		),
 		a.logger,
 	)
+}
+
+// transferNotifier returns the notifier option, the NOTIFY_URI service otherwise
+func (a HTTPServer) transferNotifier() usecase.Notifier {
+	if a.notifier != nil {
+		return a.notifier
+	}
 
-	notifier := adapterhttp.NewNotifier(
+	return newTransferNotifier(a.metrics, a.producer(), a.logger)
+}
+
+// newTransferNotifier returns the notifier of the NOTIFY_URI service, the
+// notifications failing being published by the producer
+func newTransferNotifier(m adaptermetrics.Metrics, p adapterqueue.Producer, l adapterlogger.Logger) usecase.Notifier {
+	return adapterhttp.NewNotifier(
 		infrahttp.NewClient(
 			infrahttp.NewRequest(
-				infrahttp.WithRetry(infrahttp.NewRetry(3, []int{http.StatusInternalServerError}, 400*time.Millisecond)),
+				infrahttp.WithRetry(infrahttp.NewRetry(
+					3,
+					[]int{http.StatusInternalServerError},
+					400*time.Millisecond,
+					infrahttp.WithRetryMetrics(m, "notifier"),
+				)),
 				infrahttp.WithTimeout(5*time.Second),
+				infrahttp.WithMetrics(m, "notifier"),
 			),
 		),
-		adapterqueue.NewProducer(a.queue.Channel(), a.queue.Queue().Name, a.logger),
-		a.logger,
+		p,
+		l,
 	)
-
-	uc := usecase.NewCreateTransferInteractor(
-		repository.NewCreateTransferRepository(a.database),
-		repository.NewUpdateUserWalletRepository(a.database),
-		repository.NewFindUserByIDRepository(a.database),
-		presenter.NewCreateTransferPresenter(),
-		authorizer,
-		notifier,
-	)
-
-	return handler.NewCreateTransferHandler(uc, a.logger).Handle
 }
 
-func (a HTTPServer) createUserHandler() http.HandlerFunc {
+// createUserHandler returns the handler creating the users, with an initial balance only for the admins
+func (a HTTPServer) createUserHandler(admin bool) http.HandlerFunc {
 	uc := usecase.NewCreateUserInteractor(
-		repository.NewCreateUserRepository(a.database),
+		a.storage.transferCreator,
+		a.storage.users,
+		a.storage.audit,
 		presenter.NewCreateUserPresenter())
 
-	return handler.NewCreateUserHandler(uc, a.logger).Handle
+	return handler.NewCreateUserHandler(adaptermetrics.NewCreateUserUseCase(uc, a.metrics), admin, a.logger).Handle
 }
 
 func (a HTTPServer) findUserByIDHandler() http.HandlerFunc {
 	uc := usecase.NewFindUserByIDInteractor(
-		repository.NewFindUserByIDRepository(a.database),
+		a.storage.users,
 		presenter.NewFindUserByIDPresenter())
 
-	return handler.NewFindUserByIDHandler(uc, a.logger).Handle
+	return handler.NewFindUserByIDHandler(adaptermetrics.NewFindUserByIDUseCase(uc, a.metrics), a.logger).Handle
+}
+
+func (a HTTPServer) createWalletHandler() http.HandlerFunc {
+	uc := usecase.NewCreateWalletInteractor(
+		a.storage.users,
+		a.storage.users,
+		presenter.NewCreateWalletPresenter())
+
+	return handler.NewCreateWalletHandler(adaptermetrics.NewCreateWalletUseCase(uc, a.metrics), a.logger).Handle
 }
 
-func healthCheck(w http.ResponseWriter, _ *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(struct {
-		Status string `json:"status"`
-	}{Status: http.StatusText(http.StatusOK)})
+// producer returns the RabbitMQ producer, or a producer that only logs when RABBITMQ_URI is empty
+func (a HTTPServer) producer() adapterqueue.Producer {
+	return newProducer(a.queue, a.metrics, a.logger)
+}
+
+// newReconcileUseCase returns the reconciliation of the wallets of the storage, the
+// fees of the transfers recorded without their account being credited as the pricing says
+func newReconcileUseCase(st *storage, p entity.Pricing) usecase.ReconcileUseCase {
+	return usecase.NewReconcileInteractor(
+		st.users,
+		st.transferFinder,
+		st.transferLister,
+		st.fundings,
+		st.audit,
+		st.reviews,
+		st.reconciliation,
+		p,
+	)
+}
+
+// newProducer returns the producer of the RabbitMQ queue, or a producer that only logs without queue
+func newProducer(q *queue.RabbitMQHandler, m adaptermetrics.Metrics, l adapterlogger.Logger) adapterqueue.Producer {
+	if q == nil {
+		return adapterqueue.NewLogProducer(l)
+	}
+
+	return adapterqueue.NewProducer(q.Channel(), q.Queue().Name, l, m)
+}
+
+func (a HTTPServer) healthHandler() handler.HealthHandler {
+	checkers := []health.Checker{
+		a.storage.checker(),
+		adapterhttp.NewAuthorizerChecker(
+			infrahttp.NewClient(
+				infrahttp.NewRequest(infrahttp.WithTimeout(2 * time.Second)),
+			),
+		),
+	}
+	if a.queue != nil {
+		checkers = append(checkers, health.NewCheckerFunc("rabbitmq", a.queue.Ping))
+	}
+
+	h := health.NewHealth(checkers)
+
+	return handler.NewHealthHandler(h, a.logger)
+}
+
+// schedulerInterval reads SCHEDULER_INTERVAL (e.g. "30s"), falling back to one minute.
+// A zero or negative interval disables the scheduler.
+func schedulerInterval() time.Duration {
+	v := os.Getenv("SCHEDULER_INTERVAL")
+	if v == "" {
+		return time.Minute
+	}
+
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Minute
+	}
+
+	return t
+}
+
+// auditChainInterval reads AUDIT_CHAIN_INTERVAL (e.g. "500ms"), falling back to one second.
+// A zero or negative interval disables the chaining of the audit entries.
+func auditChainInterval() time.Duration {
+	v := os.Getenv("AUDIT_CHAIN_INTERVAL")
+	if v == "" {
+		return time.Second
+	}
+
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Second
+	}
+
+	return t
+}
+
+// reviewInterval reads REVIEW_INTERVAL (e.g. "30s"), falling back to one minute.
+// A zero or negative interval disables the expiration of the reviews.
+func reviewInterval() time.Duration {
+	v := os.Getenv("REVIEW_INTERVAL")
+	if v == "" {
+		return time.Minute
+	}
+
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Minute
+	}
+
+	return t
+}
+
+// paymentInterval reads PAYMENT_EXPIRY_INTERVAL (e.g. "30s"), falling back to one minute.
+// A zero or negative interval disables the expiration of the payments.
+func paymentInterval() time.Duration {
+	v := os.Getenv("PAYMENT_EXPIRY_INTERVAL")
+	if v == "" {
+		return time.Minute
+	}
+
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Minute
+	}
+
+	return t
+}
+
+// reconciliationTime reads RECONCILIATION_AT (e.g. "01:30"), the UTC time the
+// previous day is reconciled, falling back to 01:00. "off" disables the reconciliation.
+func reconciliationTime() (time.Duration, bool) {
+	v := os.Getenv("RECONCILIATION_AT")
+	if v == "off" {
+		return 0, false
+	}
+
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return time.Hour, true
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// trustedProxies reads TRUSTED_PROXIES, the comma separated IPs or CIDRs of the
+// proxies whose X-Forwarded-For is read for the client IP audited. Without any,
+// the audited IP is the one the request comes from.
+func trustedProxies() ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, v := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", v)
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", v)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// authorizerMode reads AUTHORIZER_MODE, falling back to ALL_MUST_APPROVE
+func authorizerMode() usecase.AuthorizerMode {
+	mode, err := usecase.NewAuthorizerMode(os.Getenv("AUTHORIZER_MODE"))
+	if err != nil {
+		return usecase.AllMustApprove
+	}
+
+	return mode
+}
+
+// paymentProvider returns the payment provider PAYMENT_PROVIDER names. Only
+// the simulated one exists so far, the other providers are given with
+// WithPaymentProvider. Moving no money, it is for development and must be
+// chosen explicitly: the deposits would otherwise be credited without any payment.
+func paymentProvider(log adapterlogger.Logger) (usecase.PaymentProvider, error) {
+	switch name := os.Getenv("PAYMENT_PROVIDER"); name {
+	case PaymentProviderSimulated:
+		log.Warnf("the simulated payment provider moves no money, use it for development only")
+		return provider.NewSimulatedProvider(simulatedProviderStatus()), nil
+	case "":
+		return nil, ErrNoPaymentProvider
+	default:
+		return nil, fmt.Errorf("invalid payment provider %q, the only one available is %q", name, PaymentProviderSimulated)
+	}
+}
+
+// simulatedProviderStatus reads SIMULATED_PROVIDER_STATUS, how the simulated
+// payment provider answers the fundings, falling back to PENDING so that only
+// the signed callbacks settle them
+func simulatedProviderStatus() vo.FundingStatus {
+	status, err := vo.NewFundingStatus(os.Getenv("SIMULATED_PROVIDER_STATUS"))
+	if err != nil {
+		return vo.FundingPending
+	}
+
+	return status
+}
+
+// migrateOnStart reads MIGRATE_ON_START, whether the server applies the pending
+// migrations when it starts, true unless set to false. The migrations are then
+// left to the admin migrate command, which the other commands never run.
+func migrateOnStart() bool {
+	migrate, err := strconv.ParseBool(os.Getenv("MIGRATE_ON_START"))
+	return err != nil || migrate
+}
+
+// shutdownTimeout reads SHUTDOWN_TIMEOUT (e.g. "30s"), falling back to 30 seconds
+func shutdownTimeout() time.Duration {
+	t, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if err != nil || t <= 0 {
+		return 30 * time.Second
+	}
+
+	return t
 }
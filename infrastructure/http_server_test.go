@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/adapter/provider"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+	"github.com/gorilla/mux"
+)
+
+func TestAdminRoutesRequireToken(t *testing.T) {
+	a, err := NewHTTPServer(
+		WithStorageDriver(StorageMemory),
+		WithLogger(logger.Dummy{}),
+		WithPaymentProvider(provider.NewSimulatedProvider(vo.FundingPending)),
+		WithAdminToken("secret"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+
+	routes, ok := a.Handler().(*mux.Router)
+	if !ok {
+		t.Fatalf("handler is a %T, want a *mux.Router", a.Handler())
+	}
+
+	var checked int
+	err = routes.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(template, "/admin") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+
+		path := strings.NewReplacer("{review_id}", "4b6e1f0e-5a3c-4f2a-9d3e-2f1c0b9a8d7e").Replace(template)
+		for _, method := range methods {
+			for _, authorization := range []string{"", "Bearer wrong"} {
+				r := httptest.NewRequest(method, path, strings.NewReader("{}"))
+				if authorization != "" {
+					r.Header.Set("Authorization", authorization)
+				}
+				w := httptest.NewRecorder()
+				a.Handler().ServeHTTP(w, r)
+
+				if w.Code != http.StatusUnauthorized {
+					t.Errorf("%s %s with %q = %d, want %d", method, template, authorization, w.Code, http.StatusUnauthorized)
+				}
+			}
+			checked++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked == 0 {
+		t.Fatal("no admin route found")
+	}
+}
+
+func TestPaymentProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		err    string
+		warned bool
+	}{
+		{name: "simulated", value: PaymentProviderSimulated, warned: true},
+		{name: "required", err: ErrNoPaymentProvider.Error()},
+		{name: "unknown", value: "stripe", err: `invalid payment provider "stripe", the only one available is "simulated"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAYMENT_PROVIDER", tt.value)
+			t.Setenv("SIMULATED_PROVIDER_STATUS", "")
+
+			log := &warnRecorder{}
+			pp, err := paymentProvider(log)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("paymentProvider() = %v, want %s", err, tt.err)
+				}
+			} else if err != nil || pp == nil {
+				t.Fatalf("paymentProvider() = %v, %v", pp, err)
+			}
+
+			if warned := len(log.warnings) > 0; warned != tt.warned {
+				t.Errorf("warnings = %v, want warned %v", log.warnings, tt.warned)
+			}
+		})
+	}
+
+	t.Setenv("SIMULATED_PROVIDER_STATUS", "")
+	if status := simulatedProviderStatus(); status != vo.FundingPending {
+		t.Errorf("simulated provider status = %s, want %s", status, vo.FundingPending)
+	}
+}
+
+// warnRecorder records the warnings logged
+type warnRecorder struct {
+	logger.Dummy
+	warnings []string
+}
+
+func (l *warnRecorder) Warnf(format string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
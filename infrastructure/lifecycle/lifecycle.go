@@ -0,0 +1,199 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+)
+
+var (
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+type (
+	// Hook define how a component is started and stopped.
+	// All functions are optional.
+	Hook struct {
+		Name string
+
+		// OnStart is called in the order the hooks were appended and must not block
+		OnStart func(context.Context) error
+
+		// Serve runs in its own goroutine after every OnStart succeeded.
+		// It must block until the component stops, an error triggers the shutdown.
+		Serve func() error
+
+		// OnStop is called in the reverse order the hooks were appended
+		OnStop func(context.Context) error
+	}
+
+	// Option is the Manager options
+	Option func(*Manager)
+
+	// Manager starts the application components in order, waits for a termination
+	// signal and stops them in reverse order within the shutdown deadline
+	Manager struct {
+		hooks           []Hook
+		shutdownTimeout time.Duration
+		signals         []os.Signal
+		log             logger.Logger
+		logKey          string
+	}
+
+	// StopError aggregate the errors returned by the hooks while stopping
+	StopError struct {
+		Errors []error
+	}
+)
+
+// NewManager create new Manager with its dependencies
+func NewManager(l logger.Logger, opts ...Option) *Manager {
+	m := &Manager{
+		shutdownTimeout: defaultShutdownTimeout,
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		log:             l,
+		logKey:          "lifecycle",
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithShutdownTimeout defines the deadline to stop all components
+func WithShutdownTimeout(t time.Duration) Option {
+	return func(m *Manager) {
+		m.shutdownTimeout = t
+	}
+}
+
+// WithSignals defines the signals that trigger the shutdown
+func WithSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.signals = signals
+	}
+}
+
+// Append registers a hook, the order matters
+func (m *Manager) Append(h Hook) {
+	m.hooks = append(m.hooks, h)
+}
+
+// Run starts every component and blocks until the context is canceled, a signal
+// is received or a component fails. The components are always stopped before returning.
+func (m *Manager) Run(ctx context.Context) error {
+	for i, h := range m.hooks {
+		if h.OnStart == nil {
+			continue
+		}
+
+		if err := h.OnStart(ctx); err != nil {
+			m.log.WithFields(logger.Fields{
+				"key":       m.logKey,
+				"component": h.Name,
+				"error":     err.Error(),
+			}).Errorf("failed to start component")
+
+			if stopErr := m.stop(m.hooks[:i]); stopErr != nil {
+				return fmt.Errorf("start %s: %v (%v)", h.Name, err, stopErr)
+			}
+
+			return fmt.Errorf("start %s: %w", h.Name, err)
+		}
+	}
+
+	errCh := make(chan error, len(m.hooks))
+	for _, h := range m.hooks {
+		if h.Serve == nil {
+			continue
+		}
+
+		go func(h Hook) {
+			if err := h.Serve(); err != nil {
+				errCh <- fmt.Errorf("serve %s: %w", h.Name, err)
+			}
+		}(h)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, m.signals...)
+	defer signal.Stop(sigCh)
+
+	var runErr error
+	select {
+	case sig := <-sigCh:
+		m.log.WithFields(logger.Fields{
+			"key":    m.logKey,
+			"signal": sig.String(),
+		}).Infof("received signal, shutting down")
+	case <-ctx.Done():
+		m.log.WithFields(logger.Fields{
+			"key": m.logKey,
+		}).Infof("context done, shutting down")
+	case runErr = <-errCh:
+		m.log.WithFields(logger.Fields{
+			"key":   m.logKey,
+			"error": runErr.Error(),
+		}).Errorf("component failed, shutting down")
+	}
+
+	if err := m.stop(m.hooks); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%v (%v)", runErr, err)
+		}
+		return err
+	}
+
+	return runErr
+}
+
+func (m *Manager) stop(hooks []Hook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+
+		if err := h.OnStop(ctx); err != nil {
+			m.log.WithFields(logger.Fields{
+				"key":       m.logKey,
+				"component": h.Name,
+				"error":     err.Error(),
+			}).Errorf("failed to stop component")
+
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+
+		m.log.WithFields(logger.Fields{
+			"key":       m.logKey,
+			"component": h.Name,
+		}).Infof("component stopped")
+	}
+
+	if len(errs) > 0 {
+		return &StopError{Errors: errs}
+	}
+
+	return nil
+}
+
+// Error returns the message of every aggregated error
+func (e *StopError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,147 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/infrastructure/lifecycle"
+	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+)
+
+// recorder records the calls of the hooks in order
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// hook returns a hook recording its calls, failing the start or the stop with the errors.
+// Its Serve blocks until it is stopped.
+func (r *recorder) hook(name string, startErr, stopErr error) lifecycle.Hook {
+	stopped := make(chan struct{})
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		Serve: func() error {
+			<-stopped
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				r.record("stop " + name + " without deadline")
+			}
+			r.record("stop " + name)
+			close(stopped)
+			return stopErr
+		},
+	}
+}
+
+func newManager() *lifecycle.Manager {
+	return lifecycle.NewManager(logger.Dummy{}, lifecycle.WithShutdownTimeout(time.Second), lifecycle.WithSignals(syscall.SIGUSR1))
+}
+
+func TestManagerStopsInReverseOrder(t *testing.T) {
+	r := &recorder{}
+	m := newManager()
+	m.Append(r.hook("database", nil, nil))
+	m.Append(r.hook("queue", nil, nil))
+	m.Append(r.hook("http", nil, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := m.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"start database", "start queue", "start http", "stop http", "stop queue", "stop database"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %v, want %v", r.calls, want)
+	}
+}
+
+func TestManagerStopsStartedOnStartFailure(t *testing.T) {
+	failure := errors.New("unreachable")
+
+	r := &recorder{}
+	m := newManager()
+	m.Append(r.hook("database", nil, nil))
+	m.Append(r.hook("queue", nil, nil))
+	m.Append(r.hook("http", failure, nil))
+	m.Append(r.hook("scheduler", nil, nil))
+
+	if err := m.Run(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("Run() error = %v, want %v", err, failure)
+	}
+
+	want := []string{"start database", "start queue", "start http", "stop queue", "stop database"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %v, want %v", r.calls, want)
+	}
+}
+
+func TestManagerStopsOnServeFailure(t *testing.T) {
+	failure := errors.New("listener closed")
+
+	r := &recorder{}
+	m := newManager()
+	m.Append(r.hook("database", nil, nil))
+	m.Append(lifecycle.Hook{
+		Name:  "http",
+		Serve: func() error { return failure },
+	})
+
+	if err := m.Run(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("Run() error = %v, want %v", err, failure)
+	}
+
+	want := []string{"start database", "stop database"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %v, want %v", r.calls, want)
+	}
+}
+
+func TestManagerAggregatesStopErrors(t *testing.T) {
+	var (
+		queueErr = errors.New("queue")
+		httpErr  = errors.New("http")
+	)
+
+	r := &recorder{}
+	m := newManager()
+	m.Append(r.hook("database", nil, nil))
+	m.Append(r.hook("queue", nil, queueErr))
+	m.Append(r.hook("http", nil, httpErr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.Run(ctx)
+
+	var stopErr *lifecycle.StopError
+	if !errors.As(err, &stopErr) {
+		t.Fatalf("Run() error = %v, want a StopError", err)
+	}
+	if len(stopErr.Errors) != 2 || !errors.Is(stopErr.Errors[0], httpErr) || !errors.Is(stopErr.Errors[1], queueErr) {
+		t.Errorf("stop errors = %v, want http then queue", stopErr.Errors)
+	}
+
+	want := []string{"start database", "start queue", "start http", "stop http", "stop queue", "stop database"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %v, want %v", r.calls, want)
+	}
+}
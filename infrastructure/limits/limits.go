@@ -0,0 +1,191 @@
+package limits
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// config is the JSON document of the transfer limits, e.g.
+	//
+	//	{
+	//	  "timezone": "America/Sao_Paulo",
+	//	  "types": {
+	//	    "COMMON": {
+	//	      "transaction": 500000, "daily": 1000000, "monthly": 5000000,
+	//	      "velocity": {"count": 10, "window": "1m"},
+	//	      "night": {"start": "20:00", "end": "06:00", "amount": 100000},
+	//	      "currencies": {"USD": {"transaction": 100000, "daily": 200000}}
+	//	    }
+	//	  },
+	//	  "users": {
+	//	    "b2b5ef3c-5b7a-4b9e-9a0f-6f3f8c5e2a11": {"daily": 3000000}
+	//	  }
+	//	}
+	//
+	// The amounts are in cents of the currency of the transfer and the omitted
+	// limits are unlimited. The limits of a currency replace the others for the
+	// transfers in that currency, the usage being counted by currency. The
+	// limits of a user replace the ones of its type.
+	config struct {
+		Timezone string                  `json:"timezone"`
+		Types    map[string]limitsConfig `json:"types"`
+		Users    map[string]limitsConfig `json:"users"`
+	}
+
+	limitsConfig struct {
+		Transaction int64          `json:"transaction"`
+		Daily       int64          `json:"daily"`
+		Monthly     int64          `json:"monthly"`
+		Velocity    velocityConfig `json:"velocity"`
+		Night       nightConfig    `json:"night"`
+		// Currencies are the limits overridden for the transfers in a currency
+		Currencies map[string]limitsConfig `json:"currencies"`
+	}
+
+	velocityConfig struct {
+		Count  int    `json:"count"`
+		Window string `json:"window"`
+	}
+
+	nightConfig struct {
+		Start  string `json:"start"`
+		End    string `json:"end"`
+		Amount int64  `json:"amount"`
+	}
+)
+
+// NewLimitPolicy loads the limits of the LIMITS_FILE JSON document. The
+// transfers are unlimited when it is empty.
+func NewLimitPolicy() (entity.LimitPolicy, error) {
+	path := os.Getenv("LIMITS_FILE")
+	if path == "" {
+		return entity.LimitPolicy{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity.LimitPolicy{}, errors.Wrap(err, "failed to read limits")
+	}
+
+	return Parse(data)
+}
+
+// Parse returns the limit policy of a JSON document of limits
+func Parse(data []byte) (entity.LimitPolicy, error) {
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return entity.LimitPolicy{}, errors.Wrap(err, "failed to decode limits")
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return entity.LimitPolicy{}, errors.Wrap(err, "limits timezone")
+	}
+
+	types := make(map[vo.TypeUser]entity.Limits, len(c.Types))
+	for name, l := range c.Types {
+		typeUser, err := vo.NewTypeUser(name)
+		if err != nil {
+			return entity.LimitPolicy{}, errors.Wrapf(err, "limits of %q", name)
+		}
+
+		if types[typeUser], err = l.toEntity(); err != nil {
+			return entity.LimitPolicy{}, errors.Wrapf(err, "limits of %q", name)
+		}
+	}
+
+	users := make(map[string]entity.Limits, len(c.Users))
+	for id, l := range c.Users {
+		userID, err := vo.NewUuid(id)
+		if err != nil {
+			return entity.LimitPolicy{}, errors.Wrapf(err, "limits of %q", id)
+		}
+
+		if users[userID.Value()], err = l.toEntity(); err != nil {
+			return entity.LimitPolicy{}, errors.Wrapf(err, "limits of %q", id)
+		}
+	}
+
+	return entity.NewLimitPolicy(location, types, users), nil
+}
+
+func (l limitsConfig) toEntity() (entity.Limits, error) {
+	var amounts [3]vo.Amount
+	for i, v := range []int64{l.Transaction, l.Daily, l.Monthly} {
+		a, err := vo.NewAmount(v)
+		if err != nil {
+			return entity.Limits{}, err
+		}
+		amounts[i] = a
+	}
+
+	var velocity entity.Velocity
+	if l.Velocity.Count != 0 || l.Velocity.Window != "" {
+		window, err := time.ParseDuration(l.Velocity.Window)
+		if err != nil {
+			return entity.Limits{}, errors.Wrap(err, "velocity window")
+		}
+
+		if velocity, err = entity.NewVelocity(l.Velocity.Count, window); err != nil {
+			return entity.Limits{}, err
+		}
+	}
+
+	var night entity.Night
+	if l.Night.Amount != 0 || l.Night.Start != "" || l.Night.End != "" {
+		start, err := timeOfDay(l.Night.Start)
+		if err != nil {
+			return entity.Limits{}, errors.Wrap(err, "night start")
+		}
+
+		end, err := timeOfDay(l.Night.End)
+		if err != nil {
+			return entity.Limits{}, errors.Wrap(err, "night end")
+		}
+
+		amount, err := vo.NewAmount(l.Night.Amount)
+		if err != nil {
+			return entity.Limits{}, err
+		}
+
+		if night, err = entity.NewNight(start, end, amount); err != nil {
+			return entity.Limits{}, err
+		}
+	}
+
+	limits := entity.NewLimits(amounts[0], amounts[1], amounts[2], velocity, night)
+	for code, l := range l.Currencies {
+		currency, err := vo.NewCurrency(code)
+		if err != nil {
+			return entity.Limits{}, errors.Wrapf(err, "limits in %q", code)
+		}
+		if len(l.Currencies) > 0 {
+			return entity.Limits{}, errors.Wrapf(entity.ErrInvalidLimit, "limits in %q have currencies", code)
+		}
+
+		o, err := l.toEntity()
+		if err != nil {
+			return entity.Limits{}, errors.Wrapf(err, "limits in %q", code)
+		}
+		limits = limits.WithCurrency(currency.Value(), o)
+	}
+
+	return limits, nil
+}
+
+// timeOfDay returns the duration since midnight of a "15:04" time
+func timeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,40 @@
+package limits
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+)
+
+func TestParseCurrencies(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{
+			name: "limits of a currency",
+			data: `{"timezone": "UTC", "types": {"COMMON": {"daily": 1000, "currencies": {"USD": {"daily": 100}}}}}`,
+		},
+		{
+			name: "unknown currency",
+			data: `{"timezone": "UTC", "types": {"COMMON": {"currencies": {"XYZ": {"daily": 100}}}}}`,
+			err:  vo.ErrInvalidCurrency,
+		},
+		{
+			name: "nested currencies",
+			data: `{"timezone": "UTC", "users": {"b2b5ef3c-5b7a-4b9e-9a0f-6f3f8c5e2a11": {"currencies": {"USD": {"currencies": {"BRL": {}}}}}}}`,
+			err:  entity.ErrInvalidLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); !errors.Is(err, tt.err) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
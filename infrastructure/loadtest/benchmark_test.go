@@ -0,0 +1,137 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+// benchmarkUsers is the number of wallets the benchmarked transfers are spread over
+const benchmarkUsers = 100
+
+// BenchmarkCreateTransfer executes sequential transfers between random users
+func BenchmarkCreateTransfer(b *testing.B) {
+	uc, users := newBenchmarkUseCase(b, benchmarkUsers)
+	rnd := rand.New(rand.NewSource(1))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		executeTransfer(b, uc, users, rnd)
+	}
+}
+
+// BenchmarkCreateTransferParallel executes transfers between random users from GOMAXPROCS goroutines
+func BenchmarkCreateTransferParallel(b *testing.B) {
+	uc, users := newBenchmarkUseCase(b, benchmarkUsers)
+
+	var seed int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(atomic.AddInt64(&seed, 1)))
+		for pb.Next() {
+			executeTransfer(b, uc, users, rnd)
+		}
+	})
+}
+
+// BenchmarkCreateTransferContended executes parallel transfers between two users only
+func BenchmarkCreateTransferContended(b *testing.B) {
+	uc, users := newBenchmarkUseCase(b, 2)
+
+	var seed int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(atomic.AddInt64(&seed, 1)))
+		for pb.Next() {
+			executeTransfer(b, uc, users, rnd)
+		}
+	})
+}
+
+func newBenchmarkUseCase(b *testing.B, n int) (usecase.CreateTransferUseCase, []vo.Uuid) {
+	b.Helper()
+
+	var (
+		db    = database.NewInMemoryHandler()
+		repo  = database.NewUserInMen(db)
+		ctx   = context.Background()
+		users = make([]vo.Uuid, n)
+	)
+	for i := range users {
+		id := newUuid()
+		u, err := entity.NewUser(
+			id,
+			vo.NewFullName(fmt.Sprintf("Benchmark %d", i)),
+			vo.NewEmailTest(fmt.Sprintf("benchmark.%d@example.com", i)),
+			vo.NewPassword("secret"),
+			vo.NewDocumentTest(vo.CPF, "070.910.549-45"),
+			vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(1<<40))),
+			vo.COMMON,
+			time.Now(),
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if _, err := repo.Create(ctx, u); err != nil {
+			b.Fatal(err)
+		}
+		users[i] = id
+	}
+
+	transfers := database.NewTransferInMen(db)
+	uc := usecase.NewCreateTransferInteractor(
+		transfers,
+		transfers,
+		repo,
+		repo,
+		database.NewReviewInMen(db),
+		database.NewAuditInMen(db),
+		presenter.NewCreateTransferPresenter(),
+		authorizeAll{},
+		discardNotifier{},
+		entity.Pricing{},
+		entity.LimitPolicy{},
+		entity.ReviewPolicy{},
+	)
+
+	return uc, users
+}
+
+func executeTransfer(b *testing.B, uc usecase.CreateTransferUseCase, users []vo.Uuid, rnd *rand.Rand) {
+	payer := rnd.Intn(len(users))
+	payee := rnd.Intn(len(users) - 1)
+	if payee >= payer {
+		payee++
+	}
+
+	_, err := uc.Execute(context.Background(), usecase.CreateTransferInput{
+		ID:       newUuid(),
+		PayerID:  users[payer],
+		PayeeID:  users[payee],
+		Value:    vo.NewMoneyBRL(vo.NewAmountTest(1)),
+		CreateAt: time.Now(),
+	})
+	if err != nil {
+		// Error rather than Fatal, it is called from the RunParallel goroutines
+		b.Error(err)
+	}
+}
+
+func newUuid() vo.Uuid {
+	id, _ := vo.NewUuid(uuid.New().String())
+	return id
+}
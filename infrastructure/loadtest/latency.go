@@ -0,0 +1,58 @@
+package loadtest
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Latencies holds the durations of the requests
+type Latencies []time.Duration
+
+// Percentile returns the duration under which p percent of the requests completed,
+// using the nearest-rank method
+func (l Latencies) Percentile(p float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+
+	sorted := make(Latencies, len(l))
+	copy(sorted, l)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// Max returns the slowest request
+func (l Latencies) Max() time.Duration {
+	var max time.Duration
+	for _, d := range l {
+		if d > max {
+			max = d
+		}
+	}
+
+	return max
+}
+
+// Mean returns the average duration
+func (l Latencies) Mean() time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+
+	var sum time.Duration
+	for _, d := range l {
+		sum += d
+	}
+
+	return sum / time.Duration(len(l))
+}
@@ -0,0 +1,324 @@
+// Package loadtest drives the HTTP API with concurrent transfers and checks that
+// the balances stay consistent.
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/api/handler"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+type (
+	// Config define the load generated by Run
+	Config struct {
+		// Users is the number of users created before the transfers
+		Users int
+		// Balance is the initial amount of every wallet
+		Balance int64
+		// Transfers is the number of transfers sent
+		Transfers int
+		// Concurrency is the number of transfers in flight
+		Concurrency int
+		// MaxValue is the upper bound of the random transfer values
+		MaxValue int64
+		// Seed makes the generated transfers reproducible
+		Seed int64
+		// AdminToken is the bearer token of the admin routes creating the users
+		AdminToken string
+	}
+
+	// Report summarizes the transfers sent by Run
+	Report struct {
+		Transfers int
+		// Succeeded transfers were answered 201
+		Succeeded int
+		// Rejected transfers were answered with another status
+		Rejected int
+		// Errors are the transfers whose outcome is unknown because the request failed
+		Errors    int
+		Statuses  map[int]int
+		Duration  time.Duration
+		Latencies Latencies
+	}
+
+	// InvariantError lists every balance inconsistency found after the load
+	InvariantError struct {
+		Violations []string
+	}
+
+	plannedTransfer struct {
+		payer, payee int
+		value        int64
+	}
+
+	result struct {
+		status  int
+		err     error
+		latency time.Duration
+	}
+
+	client struct {
+		baseURL    string
+		adminToken string
+		http       *http.Client
+	}
+)
+
+// DefaultConfig returns a load small enough to run in a few seconds against the in-memory storage
+func DefaultConfig() Config {
+	return Config{
+		Users:       50,
+		Balance:     1000,
+		Transfers:   5000,
+		Concurrency: 32,
+		MaxValue:    50,
+		Seed:        time.Now().UnixNano(),
+	}
+}
+
+// Run creates the users, sends random transfers between them from concurrent workers
+// and checks the final balances: the money is conserved, no wallet is negative and,
+// when every request got an answer, each balance matches the succeeded transfers.
+// The returned error is an *InvariantError when a check fails.
+func Run(ctx context.Context, baseURL string, cfg Config) (Report, error) {
+	if cfg.Users < 2 {
+		return Report{}, fmt.Errorf("loadtest: at least 2 users are needed, got %d", cfg.Users)
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxValue < 1 {
+		cfg.MaxValue = 1
+	}
+
+	c := client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		adminToken: cfg.AdminToken,
+		http:       &http.Client{Timeout: 30 * time.Second},
+	}
+
+	users := make([]string, cfg.Users)
+	for i := range users {
+		id, err := c.createUser(ctx, i, cfg.Balance)
+		if err != nil {
+			return Report{}, err
+		}
+		users[i] = id
+	}
+
+	plan := planTransfers(cfg)
+	results := make([]result, len(plan))
+
+	var (
+		wg    sync.WaitGroup
+		next  = make(chan int)
+		start = time.Now()
+	)
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				t := plan[i]
+				begin := time.Now()
+				status, err := c.createTransfer(ctx, users[t.payer], users[t.payee], t.value)
+				results[i] = result{status: status, err: err, latency: time.Since(begin)}
+			}
+		}()
+	}
+	for i := range plan {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	report := Report{
+		Transfers: len(plan),
+		Statuses:  map[int]int{},
+		Duration:  time.Since(start),
+		Latencies: make(Latencies, 0, len(plan)),
+	}
+	expected := make([]int64, cfg.Users)
+	for i := range expected {
+		expected[i] = cfg.Balance
+	}
+	for i, r := range results {
+		report.Latencies = append(report.Latencies, r.latency)
+		switch {
+		case r.err != nil:
+			report.Errors++
+		case r.status == http.StatusCreated:
+			report.Succeeded++
+			report.Statuses[r.status]++
+			expected[plan[i].payer] -= plan[i].value
+			expected[plan[i].payee] += plan[i].value
+		default:
+			report.Rejected++
+			report.Statuses[r.status]++
+		}
+	}
+
+	balances := make([]int64, cfg.Users)
+	for i, id := range users {
+		b, err := c.balance(ctx, id)
+		if err != nil {
+			return report, err
+		}
+		balances[i] = b
+	}
+
+	return report, checkInvariants(cfg, users, balances, expected, report.Errors == 0)
+}
+
+// Throughput returns the transfers answered per second
+func (r Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.Transfers) / r.Duration.Seconds()
+}
+
+// Error returns one line per violation
+func (e *InvariantError) Error() string {
+	return "loadtest: invariants violated:\n\t" + strings.Join(e.Violations, "\n\t")
+}
+
+func planTransfers(cfg Config) []plannedTransfer {
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+
+	plan := make([]plannedTransfer, cfg.Transfers)
+	for i := range plan {
+		payer := rnd.Intn(cfg.Users)
+		payee := rnd.Intn(cfg.Users - 1)
+		if payee >= payer {
+			payee++
+		}
+
+		plan[i] = plannedTransfer{
+			payer: payer,
+			payee: payee,
+			value: rnd.Int63n(cfg.MaxValue) + 1,
+		}
+	}
+
+	return plan
+}
+
+func checkInvariants(cfg Config, users []string, balances, expected []int64, exact bool) error {
+	var (
+		violations []string
+		total      int64
+	)
+	for i, b := range balances {
+		total += b
+		if b < 0 {
+			violations = append(violations, fmt.Sprintf("negative balance: user %s has %d", users[i], b))
+		}
+		if exact && b != expected[i] {
+			violations = append(violations, fmt.Sprintf("lost update: user %s has %d, want %d", users[i], b, expected[i]))
+		}
+	}
+
+	if want := cfg.Balance * int64(cfg.Users); total != want {
+		violations = append(violations, fmt.Sprintf("money not conserved: total is %d, want %d", total, want))
+	}
+
+	if len(violations) > 0 {
+		return &InvariantError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (c client) createUser(ctx context.Context, n int, balance int64) (string, error) {
+	req := handler.CreateUserRequest{
+		FullName: fmt.Sprintf("Load Test %d", n),
+		Email:    fmt.Sprintf("load.test.%d@example.com", n),
+		Password: "secret",
+		Document: handler.CreateUserDocumentRequest{Type: "CPF", Value: "070.910.549-45"},
+		Wallet:   handler.CreateUserWalletRequest{Currency: "BRL", Amount: balance},
+		Type:     "common",
+	}
+
+	var out usecase.CreateUserOutput
+	if err := c.do(ctx, http.MethodPost, "/admin/users", req, http.StatusCreated, &out); err != nil {
+		return "", fmt.Errorf("loadtest: create user: %w", err)
+	}
+
+	return out.ID, nil
+}
+
+func (c client) createTransfer(ctx context.Context, payer, payee string, value int64) (int, error) {
+	body, err := json.Marshal(handler.CreateTransferRequest{PayerID: payer, PayeeID: payee, Value: value})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/transfers", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
+
+func (c client) balance(ctx context.Context, ID string) (int64, error) {
+	var out usecase.FindUserByIDOutput
+	if err := c.do(ctx, http.MethodGet, "/users/"+ID, nil, http.StatusOK, &out); err != nil {
+		return 0, fmt.Errorf("loadtest: find user %s: %w", ID, err)
+	}
+
+	return out.Wallet.Amount, nil
+}
+
+func (c client) do(ctx context.Context, method, path string, in interface{}, status int, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if c.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != status {
+		msg, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("status %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
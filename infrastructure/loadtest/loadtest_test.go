@@ -0,0 +1,91 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRunInvariants sends concurrent transfers to the application served in-process
+// and checks that no balance was lost, run it with -race to check the storage too
+func TestRunInvariants(t *testing.T) {
+	cfg := Config{
+		Users:       10,
+		Balance:     100,
+		Transfers:   1000,
+		Concurrency: 16,
+		MaxValue:    30,
+		Seed:        1,
+	}
+	if testing.Short() {
+		cfg.Transfers = 200
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	server, err := NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close(ctx)
+	cfg.AdminToken = server.AdminToken()
+
+	report, err := Run(ctx, server.URL(), cfg)
+
+	var invariants *InvariantError
+	if errors.As(err, &invariants) {
+		for _, v := range invariants.Violations {
+			t.Error(v)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Errors > 0 {
+		t.Errorf("%d transfers got no answer", report.Errors)
+	}
+	if report.Succeeded == 0 {
+		t.Errorf("no transfer succeeded, statuses %v", report.Statuses)
+	}
+}
+
+func TestCheckInvariants(t *testing.T) {
+	cfg := Config{Users: 2, Balance: 10}
+	users := []string{"a", "b"}
+
+	tests := []struct {
+		name      string
+		balances  []int64
+		expected  []int64
+		exact     bool
+		violation int
+	}{
+		{name: "consistent", balances: []int64{4, 16}, expected: []int64{4, 16}, exact: true},
+		{name: "lost update", balances: []int64{4, 16}, expected: []int64{5, 15}, exact: true, violation: 2},
+		{name: "unknown outcomes", balances: []int64{4, 16}, expected: []int64{5, 15}},
+		{name: "money created", balances: []int64{10, 11}, expected: []int64{10, 11}, violation: 1},
+		{name: "negative balance", balances: []int64{-1, 21}, expected: []int64{-1, 21}, violation: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkInvariants(cfg, users, tt.balances, tt.expected, tt.exact)
+
+			var got int
+			var invariants *InvariantError
+			if errors.As(err, &invariants) {
+				got = len(invariants.Violations)
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.violation {
+				t.Errorf("violations = %d, want %d: %v", got, tt.violation, err)
+			}
+		})
+	}
+}
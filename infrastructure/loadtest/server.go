@@ -0,0 +1,74 @@
+package loadtest
+
+import (
+	"context"
+	"net/http/httptest"
+
+	"github.com/dungnguyen/clean-architecture/adapter/provider"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure"
+	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+	"github.com/google/uuid"
+)
+
+type (
+	// Server is the application served in-process against the in-memory storage
+	Server struct {
+		app        *infrastructure.HTTPServer
+		http       *httptest.Server
+		adminToken string
+	}
+
+	authorizeAll struct{}
+
+	discardNotifier struct{}
+)
+
+// NewServer starts the application on a local port with the in-memory storage,
+// an authorizer that accepts every transfer, a notifier that sends nothing, the
+// simulated payment provider and a random admin token
+func NewServer() (*Server, error) {
+	adminToken := uuid.New().String()
+	app, err := infrastructure.NewHTTPServer(
+		infrastructure.WithStorageDriver(infrastructure.StorageMemory),
+		infrastructure.WithLogger(logger.Dummy{}),
+		infrastructure.WithAuthorizer(authorizeAll{}),
+		infrastructure.WithNotifier(discardNotifier{}),
+		infrastructure.WithPaymentProvider(provider.NewSimulatedProvider(vo.FundingPending)),
+		infrastructure.WithAdminToken(adminToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		app:        app,
+		http:       httptest.NewServer(app.Handler()),
+		adminToken: adminToken,
+	}, nil
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.http.URL
+}
+
+// AdminToken returns the bearer token of the admin routes
+func (s *Server) AdminToken() string {
+	return s.adminToken
+}
+
+// Close stops the server and drops the stored entities
+func (s *Server) Close(ctx context.Context) error {
+	s.http.Close()
+	return s.app.Close(ctx)
+}
+
+// Authorized accepts every transfer
+func (authorizeAll) Authorized(_ context.Context, _ entity.Transfer) (bool, error) {
+	return true, nil
+}
+
+// Notify does nothing
+func (discardNotifier) Notify(_ context.Context, _ entity.Transfer) {}
@@ -1,30 +1,107 @@
 package logger
 
 import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/dungnguyen/clean-architecture/adapter/logger"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Output formats
+	FormatJSON = "json"
+	FormatText = "text"
+
+	redacted = "[REDACTED]"
+)
+
+var (
+	defaultRedactedFields = []string{"email", "document", "password"}
 )
 
-// NewLogrus return the instance of logrusWrapper logger
-func NewLogrus() *logrusWrapper {
+type (
+	// Option is the logrusWrapper options
+	Option func(*logrusWrapper)
+)
+
+// NewLogrus return the instance of logrusWrapper logger.
+// The level and format default to the LOG_LEVEL and LOG_FORMAT variables, info and json otherwise.
+func NewLogrus(opts ...Option) *logrusWrapper {
 	log := logrus.New()
-	log.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
 
-	return &logrusWrapper{
-		log: log,
+	l := &logrusWrapper{
+		log:      log,
+		redacted: newRedactedFields(defaultRedactedFields),
+	}
+
+	WithLevel(os.Getenv("LOG_LEVEL"))(l)
+	WithFormat(os.Getenv("LOG_FORMAT"))(l)
+	for _, o := range opts {
+		o(l)
+	}
+
+	return l
+}
+
+// WithLevel defines the minimum level logged, unknown levels are ignored
+func WithLevel(level string) Option {
+	return func(l *logrusWrapper) {
+		lvl, err := logrus.ParseLevel(level)
+		if err != nil {
+			return
+		}
+		l.log.SetLevel(lvl)
+	}
+}
+
+// WithFormat defines the output format, json or text
+func WithFormat(format string) Option {
+	return func(l *logrusWrapper) {
+		switch strings.ToLower(format) {
+		case FormatText:
+			l.log.SetFormatter(&logrus.TextFormatter{
+				FullTimestamp:   true,
+				TimestampFormat: "2006-01-02 15:04:05",
+			})
+		default:
+			l.log.SetFormatter(&logrus.JSONFormatter{
+				TimestampFormat: "2006-01-02 15:04:05",
+			})
+		}
+	}
+}
+
+// WithOutput defines where the logs are written
+func WithOutput(w io.Writer) Option {
+	return func(l *logrusWrapper) {
+		l.log.SetOutput(w)
+	}
+}
+
+// WithRedactedFields replaces the redacted field names, the match is case-insensitive
+func WithRedactedFields(fields ...string) Option {
+	return func(l *logrusWrapper) {
+		l.redacted = newRedactedFields(fields)
 	}
 }
 
 type logrusWrapper struct {
-	log *logrus.Logger
+	log      *logrus.Logger
+	redacted redactedFields
 }
 
 func NewLogrusLogger(log *logrus.Logger) {
 
 }
 
+func (l *logrusWrapper) Debugf(format string, args ...interface{}) {
+	l.log.Debugf(format, args...)
+}
+
 func (l *logrusWrapper) Infof(format string, args ...interface{}) {
 	l.log.Infof(format, args...)
 }
@@ -39,18 +116,32 @@ func (l *logrusWrapper) Errorf(format string, args ...interface{}) {
 
 func (l *logrusWrapper) WithFields(fields logger.Fields) logger.Logger {
 	return &logrusEntry{
-		entry: l.log.WithFields(convertToLogrusFields(fields)),
+		entry:    l.log.WithFields(l.redacted.convert(fields)),
+		redacted: l.redacted,
 	}
 }
 
 func (l *logrusWrapper) WithError(err error) logger.Logger {
 	return &logrusEntry{
-		entry: l.log.WithError(err),
+		entry:    l.log.WithError(err),
+		redacted: l.redacted,
+	}
+}
+
+func (l *logrusWrapper) WithContext(ctx context.Context) logger.Logger {
+	return &logrusEntry{
+		entry:    l.log.WithContext(ctx).WithFields(contextFields(ctx)),
+		redacted: l.redacted,
 	}
 }
 
 type logrusEntry struct {
-	entry *logrus.Entry
+	entry    *logrus.Entry
+	redacted redactedFields
+}
+
+func (l *logrusEntry) Debugf(format string, args ...interface{}) {
+	l.entry.Debugf(format, args...)
 }
 
 func (l *logrusEntry) Infof(format string, args ...interface{}) {
@@ -67,19 +158,61 @@ func (l *logrusEntry) Errorf(format string, args ...interface{}) {
 
 func (l *logrusEntry) WithFields(fields logger.Fields) logger.Logger {
 	return &logrusEntry{
-		entry: l.entry.WithFields(convertToLogrusFields(fields)),
+		entry:    l.entry.WithFields(l.redacted.convert(fields)),
+		redacted: l.redacted,
 	}
 }
 
 func (l *logrusEntry) WithError(err error) logger.Logger {
 	return &logrusEntry{
-		entry: l.entry.WithError(err),
+		entry:    l.entry.WithError(err),
+		redacted: l.redacted,
+	}
+}
+
+func (l *logrusEntry) WithContext(ctx context.Context) logger.Logger {
+	return &logrusEntry{
+		entry:    l.entry.WithContext(ctx).WithFields(contextFields(ctx)),
+		redacted: l.redacted,
+	}
+}
+
+// contextFields returns the correlation and trace IDs carried by ctx
+func contextFields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+
+	if id := logger.CorrelationIDFromContext(ctx); id != "" {
+		fields["correlation_id"] = id
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields["trace_id"] = sc.TraceID().String()
+		fields["span_id"] = sc.SpanID().String()
+	}
+
+	return fields
+}
+
+// redactedFields holds the lowercased names of the fields whose values must not be logged
+type redactedFields map[string]struct{}
+
+func newRedactedFields(fields []string) redactedFields {
+	r := make(redactedFields, len(fields))
+	for _, f := range fields {
+		r[strings.ToLower(f)] = struct{}{}
 	}
+
+	return r
 }
 
-func convertToLogrusFields(fields logger.Fields) logrus.Fields {
+func (r redactedFields) convert(fields logger.Fields) logrus.Fields {
 	logrusFields := logrus.Fields{}
 	for index, field := range fields {
+		if _, ok := r[strings.ToLower(index)]; ok {
+			logrusFields[index] = redacted
+			continue
+		}
+
 		logrusFields[index] = field
 	}
 
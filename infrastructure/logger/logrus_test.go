@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	adapterlogger "github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/infrastructure/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLogrusRedaction(t *testing.T) {
+	fields := adapterlogger.Fields{
+		"key":      "create_user",
+		"email":    "jane@example.com",
+		"Document": "070.910.549-45",
+		"PASSWORD": "secret",
+		"name":     "Jane",
+	}
+
+	tests := []struct {
+		name     string
+		opts     []logger.Option
+		log      func(l adapterlogger.Logger)
+		redacted []string
+		kept     []string
+	}{
+		{
+			name:     "default fields, any case",
+			log:      func(l adapterlogger.Logger) { l.WithFields(fields).Infof("user created") },
+			redacted: []string{"email", "Document", "PASSWORD"},
+			kept:     []string{"key", "name"},
+		},
+		{
+			name: "fields added to an entry",
+			log: func(l adapterlogger.Logger) {
+				l.WithContext(context.Background()).WithFields(adapterlogger.Fields{"key": "x"}).WithFields(fields).Infof("user created")
+			},
+			redacted: []string{"email", "Document", "PASSWORD"},
+			kept:     []string{"key", "name"},
+		},
+		{
+			name:     "configured fields",
+			opts:     []logger.Option{logger.WithRedactedFields("Name")},
+			log:      func(l adapterlogger.Logger) { l.WithFields(fields).Infof("user created") },
+			redacted: []string{"name"},
+			kept:     []string{"key", "email", "Document", "PASSWORD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(logger.NewLogrus(append([]logger.Option{logger.WithOutput(&buf), logger.WithFormat(logger.FormatJSON)}, tt.opts...)...))
+
+			entry := decode(t, &buf)
+			for _, f := range tt.redacted {
+				if entry[f] != "[REDACTED]" {
+					t.Errorf("%s = %v, want redacted", f, entry[f])
+				}
+			}
+			for _, f := range tt.kept {
+				if entry[f] != fields[f] {
+					t.Errorf("%s = %v, want %v", f, entry[f], fields[f])
+				}
+			}
+		})
+	}
+}
+
+func TestLogrusContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewLogrus(logger.WithOutput(&buf), logger.WithFormat(logger.FormatJSON))
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	ctx = adapterlogger.ContextWithCorrelationID(ctx, "correlation")
+
+	l.WithContext(ctx).Infof("request served")
+
+	entry := decode(t, &buf)
+	want := map[string]string{
+		"correlation_id": "correlation",
+		"trace_id":       span.SpanContext().TraceID().String(),
+		"span_id":        span.SpanContext().SpanID().String(),
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %s", k, entry[k], v)
+		}
+	}
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log %q: %v", buf.String(), err)
+	}
+
+	return entry
+}
@@ -0,0 +1,13 @@
+package metrics
+
+import "time"
+
+type Dummy struct{}
+
+func (d Dummy) ObserveHTTPRequest(_, _ string, _ int, _ time.Duration)     {}
+func (d Dummy) ObserveUseCase(_, _ string, _ time.Duration)                {}
+func (d Dummy) ObserveOutboundRequest(_, _ string, _ int, _ time.Duration) {}
+func (d Dummy) IncRetryAttempt(_ string)                                   {}
+func (d Dummy) SetBreakerState(_, _ string)                                {}
+func (d Dummy) IncQueuePublish(_, _ string)                                {}
+func (d Dummy) SetReconciliation(_ map[string]int, _ time.Time)            {}
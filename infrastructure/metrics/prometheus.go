@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var breakerStates = []string{"closed", "half-open", "open"}
+
+// Prometheus define the Prometheus implementation of the metrics port
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	useCaseExecutions   *prometheus.CounterVec
+	useCaseDuration     *prometheus.HistogramVec
+	outboundRequests    *prometheus.CounterVec
+	outboundDuration    *prometheus.HistogramVec
+	outboundRetries     *prometheus.CounterVec
+	breakerState        *prometheus.GaugeVec
+	queuePublishedTotal *prometheus.CounterVec
+	reconciliation      *prometheus.GaugeVec
+	reconciledAt        prometheus.Gauge
+}
+
+// NewPrometheus create new Prometheus with its own registry
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of the HTTP requests by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		useCaseExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "usecase_executions_total",
+			Help: "Total number of use case executions by result.",
+		}, []string{"usecase", "result"}),
+		useCaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "usecase_duration_seconds",
+			Help:    "Duration of the use case executions by result.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"usecase", "result"}),
+		outboundRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "outbound_http_requests_total",
+			Help: "Total number of outbound HTTP requests by target, method and status.",
+		}, []string{"target", "method", "status"}),
+		outboundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "outbound_http_request_duration_seconds",
+			Help:    "Latency of the outbound HTTP requests, retries included.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"target", "method"}),
+		outboundRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "outbound_http_retry_attempts_total",
+			Help: "Total number of outbound HTTP request retries by target.",
+		}, []string{"target"}),
+		breakerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "circuit_breaker_state",
+			Help: "Current circuit breaker state by target, 1 for the active state.",
+		}, []string{"target", "state"}),
+		queuePublishedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "queue_published_messages_total",
+			Help: "Total number of messages published by queue and result.",
+		}, []string{"queue", "result"}),
+		reconciliation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reconciliation_discrepancies",
+			Help: "Number of discrepancies found by the last reconciliation by issue.",
+		}, []string{"issue"}),
+		reconciledAt: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "reconciliation_last_run_timestamp_seconds",
+			Help: "Unix time of the last reconciliation completed.",
+		}),
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.httpRequests,
+		p.httpDuration,
+		p.useCaseExecutions,
+		p.useCaseDuration,
+		p.outboundRequests,
+		p.outboundDuration,
+		p.outboundRetries,
+		p.breakerState,
+		p.queuePublishedTotal,
+		p.reconciliation,
+		p.reconciledAt,
+	)
+
+	return p
+}
+
+// Handler returns the http.Handler exposing the registry in the Prometheus text format
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+func (p *Prometheus) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	p.httpRequests.WithLabelValues(method, route, code).Inc()
+	p.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (p *Prometheus) ObserveUseCase(useCase, result string, duration time.Duration) {
+	p.useCaseExecutions.WithLabelValues(useCase, result).Inc()
+	p.useCaseDuration.WithLabelValues(useCase, result).Observe(duration.Seconds())
+}
+
+func (p *Prometheus) ObserveOutboundRequest(target, method string, status int, duration time.Duration) {
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+
+	p.outboundRequests.WithLabelValues(target, method, code).Inc()
+	p.outboundDuration.WithLabelValues(target, method).Observe(duration.Seconds())
+}
+
+func (p *Prometheus) IncRetryAttempt(target string) {
+	p.outboundRetries.WithLabelValues(target).Inc()
+}
+
+func (p *Prometheus) SetBreakerState(target, state string) {
+	for _, s := range breakerStates {
+		var v float64
+		if s == state {
+			v = 1
+		}
+		p.breakerState.WithLabelValues(target, s).Set(v)
+	}
+}
+
+func (p *Prometheus) IncQueuePublish(queue, result string) {
+	p.queuePublishedTotal.WithLabelValues(queue, result).Inc()
+}
+
+func (p *Prometheus) SetReconciliation(discrepancies map[string]int, at time.Time) {
+	for issue, n := range discrepancies {
+		p.reconciliation.WithLabelValues(issue).Set(float64(n))
+	}
+	p.reconciledAt.Set(float64(at.Unix()))
+}
@@ -0,0 +1,138 @@
+package payment
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+)
+
+type (
+	// Option is the Expirer options
+	Option func(*Expirer)
+
+	// Expirer periodically releases the holds of the expired payments until it is stopped
+	Expirer struct {
+		uc        usecase.ExpirePaymentsUseCase
+		log       logger.Logger
+		logKey    string
+		interval  time.Duration
+		batchSize int
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewExpirer create new Expirer with its dependencies
+func NewExpirer(uc usecase.ExpirePaymentsUseCase, l logger.Logger, opts ...Option) *Expirer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e := &Expirer{
+		uc:        uc,
+		log:       l,
+		logKey:    "payment_expirer",
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e
+}
+
+// WithInterval defines how often the expired payments are looked for
+func WithInterval(d time.Duration) Option {
+	return func(e *Expirer) {
+		e.interval = d
+	}
+}
+
+// WithBatchSize defines how many payments are loaded at once
+func WithBatchSize(n int) Option {
+	return func(e *Expirer) {
+		e.batchSize = n
+	}
+}
+
+// Run expires the payments every interval and blocks until Stop is called
+func (e *Expirer) Run() error {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick()
+
+		select {
+		case <-e.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the running execution to finish, or cancels it when ctx is done
+func (e *Expirer) Stop(ctx context.Context) error {
+	e.once.Do(func() { close(e.stop) })
+
+	select {
+	case <-e.done:
+		e.cancel()
+		return nil
+	case <-ctx.Done():
+		e.cancel()
+		return ctx.Err()
+	}
+}
+
+// tick expires batches of payments until none is left or a release fails
+func (e *Expirer) tick() {
+	for {
+		select {
+		case <-e.stop:
+			return
+		default:
+		}
+
+		output, err := e.uc.Execute(e.ctx, usecase.ExpirePaymentsInput{
+			At:    time.Now(),
+			Limit: e.batchSize,
+		})
+
+		fields := logger.Fields{
+			"key":     e.logKey,
+			"expired": output.Expired,
+			"failed":  output.Failed,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			e.log.WithFields(fields).Errorf("failed to expire payments")
+			return
+		}
+
+		if output.Expired > 0 {
+			e.log.WithFields(fields).Infof("expired payments")
+		}
+
+		if output.Expired < e.batchSize {
+			return
+		}
+	}
+}
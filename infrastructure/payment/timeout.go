@@ -0,0 +1,31 @@
+package payment
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultHoldTimeout is how long a payment holds its value when PAYMENT_HOLD_TIMEOUT is empty
+const defaultHoldTimeout = 7 * 24 * time.Hour
+
+// NewHoldTimeout reads PAYMENT_HOLD_TIMEOUT (e.g. "72h", seven days by
+// default), how long an authorized payment may be captured before its hold
+// is released
+func NewHoldTimeout() (time.Duration, error) {
+	v := os.Getenv("PAYMENT_HOLD_TIMEOUT")
+	if v == "" {
+		return defaultHoldTimeout, nil
+	}
+
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse PAYMENT_HOLD_TIMEOUT")
+	}
+	if t <= 0 {
+		return 0, errors.Errorf("invalid PAYMENT_HOLD_TIMEOUT %q", v)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,162 @@
+package pricing
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// config is the JSON document of the fee rules, e.g.
+	//
+	//	{
+	//	  "account_id": "b2b5ef3c-5b7a-4b9e-9a0f-6f3f8c5e2a11",
+	//	  "rules": [
+	//	    {"id": "merchant", "version": 2, "payee_type": "MERCHANT", "percentage": 1.99, "min": 10},
+	//	    {"id": "merchant-batch", "version": 1, "payee_type": "MERCHANT", "transfer_type": "BATCH",
+	//	     "tiers": [{"up_to": 10000, "flat": 50}, {"percentage": 0.5}], "max": 1000}
+	//	  ]
+	//	}
+	//
+	// The amounts are in cents and the percentages have up to two decimals.
+	config struct {
+		AccountID string       `json:"account_id"`
+		Rules     []ruleConfig `json:"rules"`
+	}
+
+	ruleConfig struct {
+		ID           string       `json:"id"`
+		Version      int          `json:"version"`
+		PayeeType    string       `json:"payee_type"`
+		Currency     string       `json:"currency"`
+		TransferType string       `json:"transfer_type"`
+		Flat         int64        `json:"flat"`
+		Percentage   float64      `json:"percentage"`
+		Tiers        []tierConfig `json:"tiers"`
+		Min          int64        `json:"min"`
+		Max          int64        `json:"max"`
+	}
+
+	tierConfig struct {
+		UpTo       int64   `json:"up_to"`
+		Flat       int64   `json:"flat"`
+		Percentage float64 `json:"percentage"`
+	}
+)
+
+// NewPricing loads the fee rules of the FEE_RULES_FILE JSON document. No fee
+// is charged when it is empty.
+func NewPricing() (entity.Pricing, error) {
+	path := os.Getenv("FEE_RULES_FILE")
+	if path == "" {
+		return entity.Pricing{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity.Pricing{}, errors.Wrap(err, "failed to read fee rules")
+	}
+
+	return Parse(data)
+}
+
+// Parse returns the pricing of a JSON document of fee rules
+func Parse(data []byte) (entity.Pricing, error) {
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return entity.Pricing{}, errors.Wrap(err, "failed to decode fee rules")
+	}
+
+	rules := make([]entity.FeeRule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		rule, err := r.toEntity()
+		if err != nil {
+			return entity.Pricing{}, errors.Wrapf(err, "fee rule %q", r.ID)
+		}
+		rules = append(rules, rule)
+	}
+
+	var account vo.Uuid
+	if c.AccountID != "" {
+		var err error
+		if account, err = vo.NewUuid(c.AccountID); err != nil {
+			return entity.Pricing{}, errors.Wrap(err, "fee account")
+		}
+	}
+
+	return entity.NewPricing(account, rules)
+}
+
+func (r ruleConfig) toEntity() (entity.FeeRule, error) {
+	var (
+		payeeType    vo.TypeUser
+		currency     vo.TypeCurrency
+		transferType vo.TransferType
+		err          error
+	)
+	if r.PayeeType != "" {
+		if payeeType, err = vo.NewTypeUser(r.PayeeType); err != nil {
+			return entity.FeeRule{}, err
+		}
+	}
+	if r.Currency != "" {
+		c, err := vo.NewCurrency(r.Currency)
+		if err != nil {
+			return entity.FeeRule{}, err
+		}
+		currency = c.Value()
+	}
+	if r.TransferType != "" {
+		if transferType, err = vo.NewTransferType(r.TransferType); err != nil {
+			return entity.FeeRule{}, err
+		}
+	}
+
+	// a rule without tiers has a single one of its flat and percentage prices
+	tiers := r.Tiers
+	if len(tiers) == 0 {
+		tiers = []tierConfig{{Flat: r.Flat, Percentage: r.Percentage}}
+	} else if r.Flat != 0 || r.Percentage != 0 {
+		return entity.FeeRule{}, entity.ErrInvalidFeeRule
+	}
+
+	feeTiers := make([]entity.FeeTier, 0, len(tiers))
+	for _, t := range tiers {
+		tier, err := t.toEntity()
+		if err != nil {
+			return entity.FeeRule{}, err
+		}
+		feeTiers = append(feeTiers, tier)
+	}
+
+	min, err := vo.NewAmount(r.Min)
+	if err != nil {
+		return entity.FeeRule{}, err
+	}
+
+	max, err := vo.NewAmount(r.Max)
+	if err != nil {
+		return entity.FeeRule{}, err
+	}
+
+	return entity.NewFeeRule(r.ID, r.Version, payeeType, currency, transferType, feeTiers, min, max)
+}
+
+func (t tierConfig) toEntity() (entity.FeeTier, error) {
+	upTo, err := vo.NewAmount(t.UpTo)
+	if err != nil {
+		return entity.FeeTier{}, err
+	}
+
+	flat, err := vo.NewAmount(t.Flat)
+	if err != nil {
+		return entity.FeeTier{}, err
+	}
+
+	return entity.NewFeeTier(upTo, flat, int64(math.Round(t.Percentage*100)))
+}
@@ -1,29 +1,33 @@
 package queue
 
 import (
-	"log"
+	"context"
 	"os"
+	"sync/atomic"
 
+	"github.com/pkg/errors"
 	"github.com/streadway/amqp"
 )
 
 // RabbitMQHandler define the RabbitMQ handler
 type RabbitMQHandler struct {
-	conn    *amqp.Connection
-	queue   amqp.Queue
-	channel *amqp.Channel
+	conn          *amqp.Connection
+	queue         amqp.Queue
+	channel       *amqp.Channel
+	channelClosed *int32
 }
 
 // NewRabbitMQHandler create new RabbitMQHandler
-func NewRabbitMQHandler() *RabbitMQHandler {
+func NewRabbitMQHandler() (*RabbitMQHandler, error) {
 	conn, err := amqp.Dial(os.Getenv("RABBITMQ_URI"))
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.Wrap(err, "failed to connect to rabbitmq")
 	}
 
 	channel, err := conn.Channel()
 	if err != nil {
-		log.Fatal(err)
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to open rabbitmq channel")
 	}
 
 	queue, err := channel.QueueDeclare(
@@ -35,14 +39,23 @@ func NewRabbitMQHandler() *RabbitMQHandler {
 		nil,
 	)
 	if err != nil {
-		log.Fatal(err)
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to declare rabbitmq queue")
 	}
 
+	var channelClosed int32
+	notify := channel.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		<-notify
+		atomic.StoreInt32(&channelClosed, 1)
+	}()
+
 	return &RabbitMQHandler{
-		conn:    conn,
-		queue:   queue,
-		channel: channel,
-	}
+		conn:          conn,
+		queue:         queue,
+		channel:       channel,
+		channelClosed: &channelClosed,
+	}, nil
 }
 
 // Conn return the conn property
@@ -59,3 +72,29 @@ func (r RabbitMQHandler) Queue() amqp.Queue {
 func (r RabbitMQHandler) Channel() *amqp.Channel {
 	return r.channel
 }
+
+// Ping verifies that both the connection and the channel are open
+func (r RabbitMQHandler) Ping(_ context.Context) error {
+	if r.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
+
+	if atomic.LoadInt32(r.channelClosed) == 1 {
+		return errors.New("rabbitmq channel is closed")
+	}
+
+	return nil
+}
+
+// Close closes the channel and the connection with the server
+func (r RabbitMQHandler) Close() error {
+	if err := r.channel.Close(); err != nil && err != amqp.ErrClosed {
+		return errors.Wrap(err, "failed to close rabbitmq channel")
+	}
+
+	if err := r.conn.Close(); err != nil && err != amqp.ErrClosed {
+		return errors.Wrap(err, "failed to close rabbitmq connection")
+	}
+
+	return nil
+}
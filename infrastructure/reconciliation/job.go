@@ -0,0 +1,200 @@
+package reconciliation
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/pkg/errors"
+)
+
+// defaultAt is the time of the UTC day the previous one is reconciled
+var defaultAt = time.Hour
+
+// reportFormats are the formats the reports are written in
+var reportFormats = []string{"json", "csv"}
+
+type (
+	// Option is the Job options
+	Option func(*Job)
+
+	// ReportWriter writes the report of a reconciliation in the format, json or csv
+	ReportWriter func(w io.Writer, format string, output usecase.ReconcileOutput) error
+
+	// Job reconciles the previous day every day until it is stopped, saving
+	// its checkpoint. The instances run it at the same time, a single one
+	// reconciling the day. The discrepancies found are logged as errors.
+	Job struct {
+		uc        usecase.ReconcileUseCase
+		log       logger.Logger
+		logKey    string
+		at        time.Duration
+		reportDir string
+		write     ReportWriter
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewJob create new Job with its dependencies
+func NewJob(uc usecase.ReconcileUseCase, l logger.Logger, opts ...Option) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	j := &Job{
+		uc:     uc,
+		log:    l,
+		logKey: "reconciliation",
+		at:     defaultAt,
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(j)
+	}
+
+	return j
+}
+
+// WithTime defines when the previous day is reconciled, since the start of the UTC day
+func WithTime(at time.Duration) Option {
+	return func(j *Job) {
+		j.at = at
+	}
+}
+
+// WithReports defines the directory the reports of every day are written to, in every format
+func WithReports(dir string, write ReportWriter) Option {
+	return func(j *Job) {
+		j.reportDir = dir
+		j.write = write
+	}
+}
+
+// Run reconciles the previous day every day and blocks until Stop is called
+func (j *Job) Run() error {
+	defer close(j.done)
+
+	for {
+		timer := time.NewTimer(time.Until(j.next(time.Now())))
+
+		select {
+		case <-j.stop:
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		j.reconcile(time.Now())
+	}
+}
+
+// Stop waits for the running reconciliation to finish, or cancels it when ctx is done
+func (j *Job) Stop(ctx context.Context) error {
+	j.once.Do(func() { close(j.stop) })
+
+	select {
+	case <-j.done:
+		j.cancel()
+		return nil
+	case <-ctx.Done():
+		j.cancel()
+		return ctx.Err()
+	}
+}
+
+// next returns the next time a day is reconciled after now
+func (j *Job) next(now time.Time) time.Time {
+	next := now.UTC().Truncate(24 * time.Hour).Add(j.at)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+
+	return next
+}
+
+// reconcile reconciles the day before now and writes its reports, unless
+// another instance is reconciling it or already did
+func (j *Job) reconcile(now time.Time) {
+	output, err := j.uc.Execute(j.ctx, usecase.ReconcileInput{
+		Day:        now.UTC().AddDate(0, 0, -1),
+		At:         now,
+		Checkpoint: true,
+	})
+
+	fields := logger.Fields{
+		"key": j.logKey,
+		"day": output.Day,
+	}
+	if errors.Is(err, entity.ErrReconciliationLocked) || errors.Is(err, usecase.ErrDayReconciled) {
+		fields["reason"] = err.Error()
+		j.log.WithFields(fields).Infof("skipped reconciliation")
+		return
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		j.log.WithFields(fields).Errorf("failed to reconcile")
+		return
+	}
+
+	fields["wallets"] = output.Wallets
+	fields["moving"] = output.Moving
+	fields["baselined"] = output.Baselined
+	fields["transfers"] = output.Transfers
+	fields["discrepancies"] = len(output.Discrepancies)
+
+	if j.reportDir != "" {
+		paths, err := j.writeReports(output)
+		if err != nil {
+			fields["report_error"] = err.Error()
+		}
+		fields["reports"] = strings.Join(paths, ",")
+	}
+
+	if len(output.Discrepancies) > 0 {
+		j.log.WithFields(fields).Errorf("reconciliation found discrepancies")
+		return
+	}
+
+	j.log.WithFields(fields).Infof("reconciled wallets")
+}
+
+// writeReports writes the report of the day in every format, returning the paths written
+func (j *Job) writeReports(output usecase.ReconcileOutput) ([]string, error) {
+	var paths []string
+	for _, format := range reportFormats {
+		path := filepath.Join(j.reportDir, "reconciliation-"+output.Day+"."+format)
+		if err := j.writeReport(path, format, output); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func (j *Job) writeReport(path, format string, output usecase.ReconcileOutput) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := j.write(f, format, output); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
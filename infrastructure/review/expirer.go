@@ -0,0 +1,140 @@
+package review
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+)
+
+type (
+	// Option is the Expirer options
+	Option func(*Expirer)
+
+	// Expirer periodically decides the expired reviews until it is stopped
+	Expirer struct {
+		uc        usecase.ExpireReviewsUseCase
+		log       logger.Logger
+		logKey    string
+		interval  time.Duration
+		batchSize int
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewExpirer create new Expirer with its dependencies
+func NewExpirer(uc usecase.ExpireReviewsUseCase, l logger.Logger, opts ...Option) *Expirer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e := &Expirer{
+		uc:        uc,
+		log:       l,
+		logKey:    "review_expirer",
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e
+}
+
+// WithInterval defines how often the expired reviews are looked for
+func WithInterval(d time.Duration) Option {
+	return func(e *Expirer) {
+		e.interval = d
+	}
+}
+
+// WithBatchSize defines how many reviews are loaded at once
+func WithBatchSize(n int) Option {
+	return func(e *Expirer) {
+		e.batchSize = n
+	}
+}
+
+// Run decides the expired reviews every interval and blocks until Stop is called
+func (e *Expirer) Run() error {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick()
+
+		select {
+		case <-e.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the running execution to finish, or cancels it when ctx is done
+func (e *Expirer) Stop(ctx context.Context) error {
+	e.once.Do(func() { close(e.stop) })
+
+	select {
+	case <-e.done:
+		e.cancel()
+		return nil
+	case <-ctx.Done():
+		e.cancel()
+		return ctx.Err()
+	}
+}
+
+// tick decides batches of expired reviews until none is left or a decision fails
+func (e *Expirer) tick() {
+	for {
+		select {
+		case <-e.stop:
+			return
+		default:
+		}
+
+		output, err := e.uc.Execute(e.ctx, usecase.ExpireReviewsInput{
+			At:    time.Now(),
+			Limit: e.batchSize,
+		})
+
+		fields := logger.Fields{
+			"key":      e.logKey,
+			"approved": output.Approved,
+			"rejected": output.Rejected,
+			"failed":   output.Failed,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			e.log.WithFields(fields).Errorf("failed to decide expired reviews")
+			return
+		}
+
+		decided := output.Approved + output.Rejected
+		if decided > 0 {
+			e.log.WithFields(fields).Infof("decided expired reviews")
+		}
+
+		if decided < e.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package review
+
+import (
+	"os"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+)
+
+// defaultTimeout is how long a review waits for a reviewer when REVIEW_TIMEOUT is empty
+const defaultTimeout = 24 * time.Hour
+
+// NewReviewPolicy reads the policy of the reviews from REVIEW_TIMEOUT (e.g.
+// "24h", the default) and REVIEW_TIMEOUT_DECISION, APPROVE or REJECT (the
+// default), the decision taken on the reviews nobody decided in time.
+func NewReviewPolicy() (entity.ReviewPolicy, error) {
+	timeout := defaultTimeout
+	if v := os.Getenv("REVIEW_TIMEOUT"); v != "" {
+		t, err := time.ParseDuration(v)
+		if err != nil {
+			return entity.ReviewPolicy{}, errors.Wrap(err, "failed to parse REVIEW_TIMEOUT")
+		}
+		timeout = t
+	}
+
+	var onTimeout vo.ReviewStatus
+	switch v := os.Getenv("REVIEW_TIMEOUT_DECISION"); v {
+	case "", "REJECT":
+		onTimeout = vo.ReviewRejected
+	case "APPROVE":
+		onTimeout = vo.ReviewApproved
+	default:
+		return entity.ReviewPolicy{}, errors.Errorf("invalid REVIEW_TIMEOUT_DECISION %q", v)
+	}
+
+	return entity.NewReviewPolicy(timeout, onTimeout)
+}
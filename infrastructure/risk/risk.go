@@ -0,0 +1,101 @@
+package risk
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// config is the JSON document of the risk rules, e.g.
+	//
+	//	{
+	//	  "review_score": 60,
+	//	  "deny_score": 100,
+	//	  "rules": [
+	//	    {"signal": "NEW_PAYEE", "score": 20},
+	//	    {"signal": "AMOUNT_ABOVE_AVERAGE", "score": 40, "window": "720h", "factor": 5},
+	//	    {"signal": "MANY_PAYEES", "score": 50, "window": "1h", "count": 5},
+	//	    {"signal": "EMAIL_CHANGED", "score": 40, "window": "48h"}
+	//	  ]
+	//	}
+	//
+	// The transfers whose score reaches review_score are held for a manual
+	// review, the ones reaching deny_score are denied. Without review_score no
+	// transfer is held. Without window NEW_PAYEE looks at the whole history of
+	// the payer.
+	config struct {
+		ReviewScore int          `json:"review_score"`
+		DenyScore   int          `json:"deny_score"`
+		Rules       []ruleConfig `json:"rules"`
+	}
+
+	ruleConfig struct {
+		Signal string  `json:"signal"`
+		Score  int     `json:"score"`
+		Window string  `json:"window"`
+		Factor float64 `json:"factor"`
+		Count  int     `json:"count"`
+	}
+)
+
+// NewRiskPolicy loads the risk rules of the RISK_RULES_FILE JSON document.
+// The policy has no rule when it is empty.
+func NewRiskPolicy() (entity.RiskPolicy, error) {
+	path := os.Getenv("RISK_RULES_FILE")
+	if path == "" {
+		return entity.RiskPolicy{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity.RiskPolicy{}, errors.Wrap(err, "failed to read risk rules")
+	}
+
+	return Parse(data)
+}
+
+// Parse returns the risk policy of a JSON document of risk rules
+func Parse(data []byte) (entity.RiskPolicy, error) {
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return entity.RiskPolicy{}, errors.Wrap(err, "failed to decode risk rules")
+	}
+
+	rules := make([]entity.RiskRule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		rule, err := r.toEntity()
+		if err != nil {
+			return entity.RiskPolicy{}, errors.Wrapf(err, "risk rule %q", r.Signal)
+		}
+		rules = append(rules, rule)
+	}
+
+	return entity.NewRiskPolicy(rules, c.ReviewScore, c.DenyScore)
+}
+
+func (r ruleConfig) toEntity() (entity.RiskRule, error) {
+	signal, err := vo.NewRiskSignal(r.Signal)
+	if err != nil {
+		return entity.RiskRule{}, err
+	}
+
+	var window time.Duration
+	if r.Window != "" {
+		if window, err = time.ParseDuration(r.Window); err != nil {
+			return entity.RiskRule{}, errors.Wrap(err, "window")
+		}
+	}
+
+	threshold := r.Factor
+	if signal == vo.ManyPayeesSignal {
+		threshold = float64(r.Count)
+	}
+
+	return entity.NewRiskRule(signal, r.Score, window, threshold)
+}
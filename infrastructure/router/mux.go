@@ -1,9 +1,12 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/adapter/api/middleware"
@@ -12,11 +15,21 @@ import (
 
 type Mux struct {
 	router *mux.Router
+
+	mu       sync.Mutex
+	server   *http.Server
+	shutdown bool
 }
 
-func NewMux() *Mux {
+// NewMux create new Mux, the client IP audited being read from X-Forwarded-For
+// only for the requests of the trusted proxies
+func NewMux(trustedProxies []*net.IPNet) *Mux {
+	router := mux.NewRouter()
+	router.Use(middleware.NewCorrelationID().Execute)
+	router.Use(middleware.NewAuditOrigin(trustedProxies).Execute)
+
 	return &Mux{
-		router: mux.NewRouter(),
+		router: router,
 	}
 }
 
@@ -28,9 +41,19 @@ func (m *Mux) POST(uri string, f func(w http.ResponseWriter, r *http.Request)) {
 	m.router.HandleFunc(uri, f).Methods(http.MethodPost)
 }
 
-func (m *Mux) SERVE(port string) {
-	m.router.Use(middleware.NewCorrelationID().Execute)
+// USE appends a middleware executed for every matched route
+func (m *Mux) USE(mw func(http.Handler) http.Handler) {
+	m.router.Use(mw)
+}
 
+// Handler returns the router to serve it without SERVE
+func (m *Mux) Handler() http.Handler {
+	return m.router
+}
+
+// SERVE listens on the port and blocks until the server stops.
+// It returns nil when the server was stopped by Shutdown.
+func (m *Mux) SERVE(port string) error {
 	server := &http.Server{
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -38,5 +61,32 @@ func (m *Mux) SERVE(port string) {
 		Handler:      m.router,
 	}
 
-	log.Fatal(server.ListenAndServe())
+	m.mu.Lock()
+	if m.shutdown {
+		m.mu.Unlock()
+		return nil
+	}
+	m.server = server
+	m.mu.Unlock()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting new connections and waits for the in-flight
+// requests to finish or for the context deadline, whichever comes first
+func (m *Mux) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	server := m.server
+	m.shutdown = true
+	m.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Shutdown(ctx)
 }
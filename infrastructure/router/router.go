@@ -1,9 +1,15 @@
 package router
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type Router interface {
 	GET(uri string, f func(w http.ResponseWriter, r *http.Request))
 	POST(uri string, f func(w http.ResponseWriter, r *http.Request))
-	SERVE(port string)
+	USE(mw func(http.Handler) http.Handler)
+	SERVE(port string) error
+	Shutdown(ctx context.Context) error
+	Handler() http.Handler
 }
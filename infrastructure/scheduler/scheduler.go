@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/logger"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+var (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+)
+
+type (
+	// Option is the Scheduler options
+	Option func(*Scheduler)
+
+	// Scheduler periodically executes the due schedules until it is stopped
+	Scheduler struct {
+		uc        usecase.ExecuteDueSchedulesUseCase
+		log       logger.Logger
+		logKey    string
+		interval  time.Duration
+		batchSize int
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		stop   chan struct{}
+		done   chan struct{}
+		once   sync.Once
+	}
+)
+
+// NewScheduler create new Scheduler with its dependencies
+func NewScheduler(uc usecase.ExecuteDueSchedulesUseCase, l logger.Logger, opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		uc:        uc,
+		log:       l,
+		logKey:    "scheduler",
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// WithInterval defines how often the due schedules are looked for
+func WithInterval(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = d
+	}
+}
+
+// WithBatchSize defines how many schedules are loaded at once
+func WithBatchSize(n int) Option {
+	return func(s *Scheduler) {
+		s.batchSize = n
+	}
+}
+
+// Run executes the due schedules every interval and blocks until Stop is called
+func (s *Scheduler) Run() error {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick()
+
+		select {
+		case <-s.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the running execution to finish, or cancels it when ctx is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// tick executes batches of due schedules until none is left or an execution fails
+func (s *Scheduler) tick() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		output, err := s.uc.Execute(s.ctx, usecase.ExecuteDueSchedulesInput{
+			At:    time.Now(),
+			Limit: s.batchSize,
+		})
+
+		fields := logger.Fields{
+			"key":      s.logKey,
+			"executed": output.Executed,
+			"failed":   output.Failed,
+			"deferred": output.Deferred,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			s.log.WithFields(fields).Errorf("failed to execute due schedules")
+			return
+		}
+
+		processed := output.Executed + output.Failed
+		if processed > 0 {
+			s.log.WithFields(fields).Infof("executed due schedules")
+		}
+
+		if processed < s.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dungnguyen/clean-architecture/adapter/health"
+	"github.com/dungnguyen/clean-architecture/adapter/repository"
+	sqlrepository "github.com/dungnguyen/clean-architecture/adapter/repository/sql"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+)
+
+const (
+	// Storage drivers
+	StorageMongoDB  = "mongodb"
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+// storage groups the repositories of the storage driver selected by STORAGE_DRIVER
+type storage struct {
+	driver          string
+	users           entity.UserRepository
+	transferCreator entity.TransferRepositoryCreator
+	transferFinder  entity.TransferRepositoryFinder
+	transferUpdater entity.TransferRepositoryUpdater
+	transferLister  entity.TransferRepositoryLister
+	schedules       entity.ScheduleRepository
+	batches         entity.BatchRepository
+	risks           entity.RiskAssessmentRepository
+	reviews         entity.ReviewRepository
+	audit           entity.AuditRepository
+	payments        entity.PaymentRepository
+	fundings        entity.FundingRepository
+	reconciliation  entity.ReconciliationRepository
+	ping            func(context.Context) error
+	close           func(context.Context) error
+	// mongo is the handler of the mongodb driver, applying its migrations, nil for the other drivers
+	mongo *database.MongoHandler
+}
+
+func newStorage(driver string) (*storage, error) {
+	switch driver {
+	case "", StorageMongoDB:
+		db, err := database.NewMongoHandler()
+		if err != nil {
+			return nil, err
+		}
+
+		return &storage{
+			driver:          StorageMongoDB,
+			users:           repository.NewUserRepository(db),
+			transferCreator: repository.NewCreateTransferRepository(db),
+			transferFinder:  repository.NewFindTransferRepository(db),
+			transferUpdater: repository.NewUpdateTransferRepository(db),
+			transferLister:  repository.NewListTransferRepository(db),
+			schedules:       repository.NewScheduleRepository(db),
+			batches:         repository.NewBatchRepository(db),
+			risks:           repository.NewRiskRepository(db),
+			reviews:         repository.NewReviewRepository(db),
+			audit:           repository.NewAuditRepository(db),
+			payments:        repository.NewPaymentRepository(db),
+			fundings:        repository.NewFundingRepository(db),
+			reconciliation:  repository.NewReconciliationRepository(db),
+			ping:            db.Ping,
+			close:           db.Disconnect,
+			mongo:           db,
+		}, nil
+	case StorageMemory:
+		db := database.NewInMemoryHandler()
+		users := database.NewUserInMen(db)
+		transfers := database.NewTransferInMen(db)
+
+		return &storage{
+			driver:          StorageMemory,
+			users:           users,
+			transferCreator: transfers,
+			transferFinder:  transfers,
+			transferUpdater: transfers,
+			transferLister:  transfers,
+			schedules:       database.NewScheduleInMen(db),
+			batches:         database.NewBatchInMen(db),
+			risks:           database.NewRiskInMen(db),
+			reviews:         database.NewReviewInMen(db),
+			audit:           database.NewAuditInMen(db),
+			payments:        database.NewPaymentInMen(db),
+			fundings:        database.NewFundingInMen(db),
+			reconciliation:  database.NewReconciliationInMen(db),
+			ping:            db.Ping,
+			close:           db.Close,
+		}, nil
+	case StoragePostgres, StorageSQLite:
+		var (
+			db  *database.SQLHandler
+			err error
+		)
+		if driver == StoragePostgres {
+			db, err = database.NewPostgresHandler()
+		} else {
+			db, err = database.NewSQLiteHandler()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &storage{
+			driver:          driver,
+			users:           sqlrepository.NewUserRepository(db),
+			transferCreator: sqlrepository.NewCreateTransferRepository(db),
+			transferFinder:  sqlrepository.NewFindTransferRepository(db),
+			transferUpdater: sqlrepository.NewUpdateTransferRepository(db),
+			transferLister:  sqlrepository.NewListTransferRepository(db),
+			schedules:       sqlrepository.NewScheduleRepository(db),
+			batches:         sqlrepository.NewBatchRepository(db),
+			risks:           sqlrepository.NewRiskRepository(db),
+			reviews:         sqlrepository.NewReviewRepository(db),
+			audit:           sqlrepository.NewAuditRepository(db),
+			payments:        sqlrepository.NewPaymentRepository(db),
+			fundings:        sqlrepository.NewFundingRepository(db),
+			reconciliation:  sqlrepository.NewReconciliationRepository(db),
+			ping:            db.Ping,
+			close:           db.Close,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid storage driver %q", driver)
+}
+
+// storageDriver reads STORAGE_DRIVER, mongodb when empty
+func storageDriver() string {
+	return os.Getenv("STORAGE_DRIVER")
+}
+
+// migrate applies the pending migrations of the mongodb driver and returns their
+// versions. The sql drivers apply theirs, forward only, when they are opened.
+func (s *storage) migrate(ctx context.Context) ([]string, error) {
+	if s.mongo == nil {
+		return nil, nil
+	}
+
+	return s.mongo.Migrate(ctx)
+}
+
+// checker returns the readiness checker of the storage
+func (s *storage) checker() health.Checker {
+	return health.NewCheckerFunc(s.driver, s.ping)
+}
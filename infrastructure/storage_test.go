@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/repository/repositorytest"
+)
+
+// TestStorageConformance runs the conformance suite against every storage driver.
+// The memory and sqlite drivers always run, postgres runs when POSTGRES_DSN is set
+// and mongodb when MONGODB_URI is set, both against a disposable database.
+func TestStorageConformance(t *testing.T) {
+	tests := []struct {
+		driver string
+		env    string
+	}{
+		{driver: StorageMemory},
+		{driver: StorageSQLite},
+		{driver: StoragePostgres, env: "POSTGRES_DSN"},
+		{driver: StorageMongoDB, env: "MONGODB_URI"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.driver, func(t *testing.T) {
+			if tt.env != "" && os.Getenv(tt.env) == "" {
+				t.Skipf("%s is not set", tt.env)
+			}
+			if tt.driver == StorageSQLite {
+				t.Setenv("SQLITE_DSN", "file:"+filepath.Join(t.TempDir(), "conformance.db"))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			s := newTestStorage(ctx, t, tt.driver)
+
+			err := repositorytest.TestRepositories(ctx, repositorytest.Repositories{
+				UserCreator:     s.users,
+				UserFinder:      s.users,
+				UserUpdater:     s.users,
+				UserLister:      s.users,
+				Wallets:         s.users,
+				TransferCreator: s.transferCreator,
+				TransferFinder:  s.transferFinder,
+				TransferUpdater: s.transferUpdater,
+				TransferLister:  s.transferLister,
+				Schedules:       s.schedules,
+				Batches:         s.batches,
+				Risks:           s.risks,
+				Reviews:         s.reviews,
+				Audit:           s.audit,
+				Payments:        s.payments,
+				Fundings:        s.fundings,
+				Reconciliation:  s.reconciliation,
+			})
+
+			var conformance *repositorytest.ConformanceError
+			if errors.As(err, &conformance) {
+				for _, f := range conformance.Failures {
+					t.Error(f)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// newTestStorage opens the storage of the driver with its migrations applied,
+// closing it when the test ends
+func newTestStorage(ctx context.Context, t *testing.T, driver string) *storage {
+	t.Helper()
+
+	s, err := newStorage(driver)
+	if err != nil {
+		t.Fatalf("newStorage(%q): %v", driver, err)
+	}
+	t.Cleanup(func() {
+		if err := s.close(context.Background()); err != nil {
+			t.Errorf("close: %v", err)
+		}
+	})
+
+	if _, err := s.migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return s
+}
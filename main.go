@@ -1,7 +1,18 @@
 package main
 
-import "github.com/dungnguyen/clean-architecture/infrastructure"
+import (
+	"log"
+
+	"github.com/dungnguyen/clean-architecture/infrastructure"
+)
 
 func main() {
-	infrastructure.NewHTTPServer().Start()
+	server, err := infrastructure.NewHTTPServer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := server.Start(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	CreditWallet WalletAdjustment = "credit"
+	DebitWallet  WalletAdjustment = "debit"
+)
+
+type (
+	// WalletAdjustment define the manual corrections of a wallet balance
+	WalletAdjustment string
+
+	// Input port
+	AdjustWalletUseCase interface {
+		Execute(context.Context, AdjustWalletInput) (AdjustWalletOutput, error)
+	}
+
+	// Input data
+	AdjustWalletInput struct {
+		UserID     vo.Uuid
+		Adjustment WalletAdjustment
+		Value      vo.Money
+		Wallet     vo.WalletPurpose // vo.MainWallet when empty
+		// Reason is why the operator adjusted the wallet, it is required
+		Reason string
+		Actor  string
+		At     time.Time
+	}
+
+	// Output port
+	AdjustWalletPresenter interface {
+		Output(entity.AuditEntry, *vo.Wallet) AdjustWalletOutput
+	}
+
+	// Output data
+	AdjustWalletOutput struct {
+		ID        string                   `json:"id"`
+		UserID    string                   `json:"user_id"`
+		Action    string                   `json:"action"`
+		Actor     string                   `json:"actor"`
+		Detail    string                   `json:"detail"`
+		Wallet    FindUserByIDWalletOutput `json:"wallet"`
+		CreatedAt string                   `json:"created_at"`
+	}
+
+	adjustWalletInteractor struct {
+		transferExecutor
+		repoAuditCreator entity.AuditRepositoryCreator
+		pre              AdjustWalletPresenter
+	}
+)
+
+// NewAdjustWalletInteractor create new adjustWalletInteractor with its dependencies
+func NewAdjustWalletInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre AdjustWalletPresenter,
+) AdjustWalletUseCase {
+	return adjustWalletInteractor{
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+		},
+		repoAuditCreator: repoAuditCreator,
+		pre:              pre,
+	}
+}
+
+// Execute credits or debits the wallet of the user outside of any transfer,
+// appending the adjustment and its reason to the audit log
+func (a adjustWalletInteractor) Execute(ctx context.Context, i AdjustWalletInput) (AdjustWalletOutput, error) {
+	ctx, span := tracer.Start(ctx, "AdjustWalletInteractor.Execute", trace.WithAttributes(
+		attribute.String("user.id", i.UserID.Value()),
+		attribute.String("wallet.adjustment", string(i.Adjustment)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	action := vo.WalletCreditedAction
+	switch i.Adjustment {
+	case CreditWallet:
+	case DebitWallet:
+		action = vo.WalletDebitedAction
+	default:
+		recordError(span, vo.ErrInvalidAuditAction)
+		return a.pre.Output(entity.AuditEntry{}, nil), vo.ErrInvalidAuditAction
+	}
+
+	if i.Value.Amount().Value() <= 0 {
+		recordError(span, entity.ErrInvalidAdjustmentValue)
+		return a.pre.Output(entity.AuditEntry{}, nil), entity.ErrInvalidAdjustmentValue
+	}
+
+	if i.Reason == "" {
+		recordError(span, entity.ErrAdjustmentReasonRequired)
+		return a.pre.Output(entity.AuditEntry{}, nil), entity.ErrAdjustmentReasonRequired
+	}
+
+	var (
+		entry  entity.AuditEntry
+		wallet *vo.Wallet
+	)
+	err := a.retry(ctx, span, func() error {
+		return a.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			entry, wallet, err = a.adjust(sessCtx, i, action)
+			return err
+		})
+	})
+	if err != nil {
+		recordError(span, err)
+		return a.pre.Output(entity.AuditEntry{}, nil), err
+	}
+
+	return a.pre.Output(entry, wallet), nil
+}
+
+// adjust moves the money of the wallet and appends the adjustment to the
+// audit log. It must run in a transaction.
+func (a adjustWalletInteractor) adjust(
+	ctx context.Context,
+	i AdjustWalletInput,
+	action vo.AuditAction,
+) (entity.AuditEntry, *vo.Wallet, error) {
+	user, err := a.repoUserFinder.FindByID(ctx, i.UserID)
+	if err != nil {
+		return entity.AuditEntry{}, nil, err
+	}
+
+	purpose := walletPurpose(i.Wallet)
+
+	var before interface{}
+	if wallet, err := user.FindWallet(i.Value.Currency(), purpose); err == nil {
+		before = newWalletSnapshot(wallet)
+	}
+
+	if action == vo.WalletDebitedAction {
+		err = user.Withdraw(i.Value, purpose)
+	} else {
+		err = user.Deposit(i.Value, purpose)
+	}
+	if err != nil {
+		return entity.AuditEntry{}, nil, err
+	}
+
+	wallet, err := user.FindWallet(i.Value.Currency(), purpose)
+	if err != nil {
+		return entity.AuditEntry{}, nil, err
+	}
+
+	if err := a.repoUserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+		return entity.AuditEntry{}, nil, err
+	}
+
+	entry, err := appendAudit(ctx, a.repoAuditCreator, auditRecord{
+		action:    action,
+		actor:     i.Actor,
+		target:    vo.UserTarget,
+		subjectID: user.ID(),
+		detail:    fmt.Sprintf("%d %s %s: %s", i.Value.Amount().Value(), i.Value.Currency(), purpose, i.Reason),
+		before:    before,
+		after:     newWalletSnapshot(wallet),
+		at:        i.At,
+	})
+	if err != nil {
+		return entity.AuditEntry{}, nil, err
+	}
+
+	return entry, wallet, nil
+}
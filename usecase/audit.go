@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/google/uuid"
+)
+
+type (
+	auditOriginKey struct{}
+
+	// auditRecord is an action to append to the audit log. before and after
+	// are the snapshots of its subject, nil when not recorded.
+	auditRecord struct {
+		action    vo.AuditAction
+		actor     string
+		target    vo.AuditTarget
+		subjectID vo.Uuid
+		detail    string
+		before    interface{}
+		after     interface{}
+		at        time.Time
+	}
+
+	// userSnapshot is the state of a user recorded in the audit log, without its password
+	userSnapshot struct {
+		ID          string           `json:"id"`
+		FullName    string           `json:"full_name"`
+		Email       string           `json:"email"`
+		Document    string           `json:"document"`
+		Type        string           `json:"type"`
+		CanTransfer bool             `json:"can_transfer"`
+		Wallets     []walletSnapshot `json:"wallets"`
+	}
+
+	// walletSnapshot is the state of a wallet recorded in the audit log
+	walletSnapshot struct {
+		Currency string `json:"currency"`
+		Purpose  string `json:"purpose"`
+		Amount   int64  `json:"amount"`
+		Held     int64  `json:"held"`
+	}
+
+	// transferSnapshot is the state of a transfer recorded in the audit log
+	transferSnapshot struct {
+		ID          string `json:"id"`
+		Payer       string `json:"payer"`
+		Payee       string `json:"payee,omitempty"`
+		Type        string `json:"type"`
+		Status      string `json:"status"`
+		Currency    string `json:"currency"`
+		Value       int64  `json:"value"`
+		Fee         int64  `json:"fee"`
+		Source      string `json:"source_wallet"`
+		Destination string `json:"destination_wallet"`
+	}
+
+	// fundingSnapshot is the state of a funding recorded in the audit log
+	fundingSnapshot struct {
+		ID       string `json:"id"`
+		User     string `json:"user"`
+		Type     string `json:"type"`
+		Status   string `json:"status"`
+		Currency string `json:"currency"`
+		Value    int64  `json:"value"`
+		Wallet   string `json:"wallet"`
+		Reason   string `json:"reason,omitempty"`
+	}
+)
+
+// ContextWithAuditOrigin returns a copy of ctx carrying the origin of the
+// request, recorded with the actions it takes in the audit log
+func ContextWithAuditOrigin(ctx context.Context, origin entity.AuditOrigin) context.Context {
+	return context.WithValue(ctx, auditOriginKey{}, origin)
+}
+
+// auditOriginFromContext returns the origin of the request of ctx, empty when it has none
+func auditOriginFromContext(ctx context.Context) entity.AuditOrigin {
+	origin, _ := ctx.Value(auditOriginKey{}).(entity.AuditOrigin)
+	return origin
+}
+
+// appendAudit appends the record to the audit log with the origin of the request of ctx
+func appendAudit(ctx context.Context, repo entity.AuditRepositoryCreator, r auditRecord) (entity.AuditEntry, error) {
+	ID, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	before, err := auditSnapshot(r.before)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	after, err := auditSnapshot(r.after)
+	if err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	entry := entity.NewAuditEntry(ID, r.action, r.actor, r.subjectID, r.detail, r.at).
+		WithTarget(r.target).
+		WithSnapshots(before, after).
+		WithOrigin(auditOriginFromContext(ctx))
+
+	return repo.Create(ctx, entry)
+}
+
+// auditSnapshot returns the JSON of the snapshot, empty when there is none
+func auditSnapshot(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func newUserSnapshot(u entity.User) userSnapshot {
+	s := userSnapshot{
+		ID:          u.ID().Value(),
+		FullName:    u.FullName().Value(),
+		Email:       u.Email().Value(),
+		Document:    u.Document().Type().String() + " " + u.Document().Value(),
+		Type:        u.TypeUser().String(),
+		CanTransfer: u.Roles().CanTransfer,
+		Wallets:     make([]walletSnapshot, 0, len(u.Wallets())),
+	}
+	for _, w := range u.Wallets() {
+		s.Wallets = append(s.Wallets, newWalletSnapshot(w))
+	}
+
+	return s
+}
+
+func newWalletSnapshot(w *vo.Wallet) walletSnapshot {
+	return walletSnapshot{
+		Currency: w.Money().Currency().String(),
+		Purpose:  w.Purpose().String(),
+		Amount:   w.Money().Amount().Value(),
+		Held:     w.Held().Value(),
+	}
+}
+
+func newTransferSnapshot(t entity.Transfer) transferSnapshot {
+	return transferSnapshot{
+		ID:          t.ID().Value(),
+		Payer:       t.Payer().Value(),
+		Payee:       t.Payee().Value(),
+		Type:        t.Type().String(),
+		Status:      t.Status().String(),
+		Currency:    t.Value().Currency().String(),
+		Value:       t.Value().Amount().Value(),
+		Fee:         t.Fee().Amount().Value(),
+		Source:      t.Source().String(),
+		Destination: t.Destination().String(),
+	}
+}
+
+func newFundingSnapshot(f entity.Funding) fundingSnapshot {
+	return fundingSnapshot{
+		ID:       f.ID().Value(),
+		User:     f.User().Value(),
+		Type:     f.Type().String(),
+		Status:   f.Status().String(),
+		Currency: f.Value().Currency().String(),
+		Value:    f.Value().Amount().Value(),
+		Wallet:   f.Wallet().String(),
+		Reason:   f.Reason(),
+	}
+}
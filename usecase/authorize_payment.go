@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	AuthorizePaymentUseCase interface {
+		Execute(context.Context, AuthorizePaymentInput) (PaymentOutput, error)
+	}
+
+	// Input data
+	AuthorizePaymentInput struct {
+		ID       vo.Uuid
+		PayerID  vo.Uuid
+		PayeeID  vo.Uuid
+		Value    vo.Money
+		CreateAt time.Time
+	}
+
+	// Output port
+	AuthorizePaymentPresenter interface {
+		Output(entity.Payment) PaymentOutput
+	}
+
+	// Output data
+	PaymentOutput struct {
+		ID         string `json:"id"`
+		PayerID    string `json:"payer"`
+		PayeeID    string `json:"payee"`
+		Status     string `json:"status"`
+		Value      int64  `json:"value"`
+		Captured   int64  `json:"captured"`
+		TransferID string `json:"transfer_id,omitempty"`
+		CreatedAt  string `json:"created_at"`
+		ExpiresAt  string `json:"expires_at"`
+		UpdatedAt  string `json:"updated_at"`
+	}
+
+	authorizePaymentInteractor struct {
+		transferExecutor
+		repoPaymentCreator entity.PaymentRepositoryCreator
+		pre                AuthorizePaymentPresenter
+		holdTimeout        time.Duration
+	}
+)
+
+// NewAuthorizePaymentInteractor create new authorizePaymentInteractor with its dependencies
+func NewAuthorizePaymentInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoPaymentCreator entity.PaymentRepositoryCreator,
+	pre AuthorizePaymentPresenter,
+	authorizer Authorizer,
+	limits entity.LimitPolicy,
+	holdTimeout time.Duration,
+) AuthorizePaymentUseCase {
+	return authorizePaymentInteractor{
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoTransferFinder:  repoTransferFinder,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+			authorizer:          authorizer,
+			limits:              limits,
+		},
+		repoPaymentCreator: repoPaymentCreator,
+		pre:                pre,
+		holdTimeout:        holdTimeout,
+	}
+}
+
+// Execute authorizes the payment of the merchant, holding its value on the
+// wallet of the payer until it is captured, voided or expired. A payment the
+// authorizer requires a manual review of is refused.
+func (a authorizePaymentInteractor) Execute(ctx context.Context, i AuthorizePaymentInput) (PaymentOutput, error) {
+	ctx, span := tracer.Start(ctx, "AuthorizePaymentInteractor.Execute", trace.WithAttributes(
+		attribute.String("payment.id", i.ID.Value()),
+		attribute.String("payment.payer_id", i.PayerID.Value()),
+		attribute.String("payment.payee_id", i.PayeeID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if i.PayerID.Equals(i.PayeeID) {
+		recordError(span, entity.ErrSamePayerAndPayee)
+		return a.pre.Output(entity.Payment{}), entity.ErrSamePayerAndPayee
+	}
+
+	// the authorizers judge the payment as the transfer its full capture makes
+	err := a.authorize(ctx, entity.NewTransfer(i.ID, i.PayerID, i.PayeeID, i.Value, i.CreateAt).WithType(vo.PaymentTransfer))
+	if errors.Is(err, entity.ErrReviewRequired) {
+		err = errors.Wrap(entity.ErrUnauthorizedTransfer, err.Error())
+	}
+	if err != nil {
+		recordError(span, err)
+		return a.pre.Output(entity.Payment{}), err
+	}
+
+	payment := entity.NewPayment(i.ID, i.PayerID, i.PayeeID, i.Value, i.CreateAt, i.CreateAt.Add(a.holdTimeout))
+
+	err = a.retry(ctx, span, func() error {
+		return a.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			return a.hold(sessCtx, payment)
+		})
+	})
+	if err != nil {
+		recordError(span, err)
+		return a.pre.Output(entity.Payment{}), err
+	}
+
+	return a.pre.Output(payment), nil
+}
+
+// hold holds the value of the payment on the wallet of the payer and records
+// the payment. It must run in a transaction.
+func (a authorizePaymentInteractor) hold(ctx context.Context, payment entity.Payment) error {
+	payer, err := a.repoUserFinder.FindByID(ctx, payment.Payer())
+	if err != nil {
+		return err
+	}
+
+	if err := a.check(ctx, payer, payment.Value(), payment.CreatedAt()); err != nil {
+		return err
+	}
+
+	payee, err := a.repoUserFinder.FindByID(ctx, payment.Payee())
+	if err != nil {
+		return err
+	}
+
+	if payee.TypeUser() != vo.MERCHANT {
+		return entity.ErrPayeeNotMerchant
+	}
+
+	if err := payer.Hold(payment.Value()); err != nil {
+		return err
+	}
+
+	wallet, err := payer.FindWallet(payment.Value().Currency(), vo.MainWallet)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repoUserUpdater.UpdateWallet(ctx, payer.ID(), wallet); err != nil {
+		return err
+	}
+
+	_, err = a.repoPaymentCreator.Create(ctx, payment)
+	return err
+}
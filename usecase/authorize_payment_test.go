@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestAuthorizePaymentLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []int64
+		err       error
+		available int64
+	}{
+		{name: "within the daily limit", values: []int64{30, 20}, available: 50},
+		{name: "second hold over the daily limit", values: []int64{30, 30}, err: entity.ErrLimitExceeded, available: 70},
+		{name: "over the transaction limit", values: []int64{60}, err: entity.ErrLimitExceeded, available: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			transfers := database.NewTransferInMen(db)
+
+			payer := newUser(ctx, t, users, 100)
+			merchant := newUserOfType(ctx, t, users, 0, vo.MERCHANT)
+			limits := entity.NewLimitPolicy(time.UTC, map[vo.TypeUser]entity.Limits{
+				vo.COMMON: entity.NewLimits(vo.NewAmountTest(50), vo.NewAmountTest(50), vo.Amount{}, entity.Velocity{}, entity.Night{}),
+			}, nil)
+
+			uc := usecase.NewAuthorizePaymentInteractor(
+				transfers,
+				transfers,
+				users,
+				users,
+				database.NewPaymentInMen(db),
+				presenter.NewAuthorizePaymentPresenter(),
+				authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return true, nil }),
+				limits,
+				time.Hour,
+			)
+
+			var err error
+			for _, value := range tt.values {
+				_, err = uc.Execute(ctx, usecase.AuthorizePaymentInput{
+					ID:       newUuid(t),
+					PayerID:  payer.ID(),
+					PayeeID:  merchant.ID(),
+					Value:    vo.NewMoneyBRL(vo.NewAmountTest(value)),
+					CreateAt: time.Now(),
+				})
+				if err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+
+			got, err := users.FindByID(ctx, payer.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if available := got.Wallet().Available().Amount().Value(); available != tt.available {
+				t.Errorf("available balance = %d, want %d", available, tt.available)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// AllMustApprove authorizes a transfer when every authorizer approves it,
+	// an authorizer failing to answer denies it
+	AllMustApprove AuthorizerMode = "ALL_MUST_APPROVE"
+	// AnyDeny authorizes a transfer unless an authorizer denies it, the
+	// unavailable authorizers are skipped
+	AnyDeny AuthorizerMode = "ANY_DENY"
+)
+
+var (
+	// ErrAuthorizerUnavailable is returned by the authorizers failing to answer
+	ErrAuthorizerUnavailable = errors.New("authorizer unavailable")
+
+	ErrInvalidAuthorizerMode = errors.New("invalid authorizer mode")
+)
+
+type (
+	// AuthorizerMode define how a composite authorizer combines the answers of its authorizers
+	AuthorizerMode string
+
+	compositeAuthorizer struct {
+		mode        AuthorizerMode
+		authorizers []Authorizer
+	}
+)
+
+// NewAuthorizerMode create new AuthorizerMode
+func NewAuthorizerMode(value string) (AuthorizerMode, error) {
+	switch m := AuthorizerMode(value); m {
+	case AllMustApprove, AnyDeny:
+		return m, nil
+	}
+
+	return "", ErrInvalidAuthorizerMode
+}
+
+// NewCompositeAuthorizer create new compositeAuthorizer asking the authorizers in order
+func NewCompositeAuthorizer(mode AuthorizerMode, authorizers ...Authorizer) Authorizer {
+	return compositeAuthorizer{
+		mode:        mode,
+		authorizers: authorizers,
+	}
+}
+
+// Authorized authorizes a transfer with the answers of the authorizers. The
+// first denial is returned, the ones after it are not asked. A transfer an
+// authorizer requires a review of is held with entity.ErrReviewRequired when
+// no other authorizer denies it. With AnyDeny a transfer no authorizer
+// answered for is denied with ErrAuthorizerUnavailable.
+func (c compositeAuthorizer) Authorized(ctx context.Context, transfer entity.Transfer) (bool, error) {
+	ctx, span := tracer.Start(ctx, "CompositeAuthorizer.Authorized", trace.WithAttributes(
+		attribute.String("transfer.id", transfer.ID().Value()),
+		attribute.String("authorizer.mode", string(c.mode)),
+	))
+	defer span.End()
+
+	var (
+		answered    bool
+		unavailable error
+		review      error
+	)
+	for _, a := range c.authorizers {
+		ok, err := a.Authorized(ctx, transfer)
+		if c.mode == AnyDeny && errors.Is(err, ErrAuthorizerUnavailable) {
+			span.AddEvent("authorizer skipped", trace.WithAttributes(attribute.String("error", err.Error())))
+			unavailable = err
+			continue
+		}
+
+		if errors.Is(err, entity.ErrReviewRequired) {
+			if review == nil {
+				review = err
+			}
+			answered = true
+			continue
+		}
+
+		if err != nil || !ok {
+			recordError(span, err)
+			return false, err
+		}
+		answered = true
+	}
+
+	if !answered && unavailable != nil {
+		recordError(span, unavailable)
+		return false, unavailable
+	}
+
+	if review != nil {
+		return false, review
+	}
+
+	return true, nil
+}
@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestCompositeAuthorizer(t *testing.T) {
+	var (
+		failure = errors.New("failure")
+		approve = answer{ok: true}
+		deny    = answer{}
+		review  = answer{err: entity.ErrReviewRequired}
+		down    = answer{err: usecase.ErrAuthorizerUnavailable}
+		fail    = answer{err: failure}
+	)
+
+	tests := []struct {
+		name    string
+		mode    usecase.AuthorizerMode
+		answers []answer
+		ok      bool
+		err     error
+		asked   int
+	}{
+		{name: "all must approve, approved", mode: usecase.AllMustApprove, answers: []answer{approve, approve}, ok: true, asked: 2},
+		{name: "all must approve, denied", mode: usecase.AllMustApprove, answers: []answer{approve, deny, approve}, asked: 2},
+		{name: "all must approve, unavailable", mode: usecase.AllMustApprove, answers: []answer{down, approve}, err: usecase.ErrAuthorizerUnavailable, asked: 1},
+		{name: "all must approve, failed", mode: usecase.AllMustApprove, answers: []answer{approve, fail, approve}, err: failure, asked: 2},
+		{name: "all must approve, review", mode: usecase.AllMustApprove, answers: []answer{review, approve}, err: entity.ErrReviewRequired, asked: 2},
+		{name: "all must approve, denied after review", mode: usecase.AllMustApprove, answers: []answer{review, deny}, asked: 2},
+		{name: "any deny, approved", mode: usecase.AnyDeny, answers: []answer{approve, approve}, ok: true, asked: 2},
+		{name: "any deny, denied", mode: usecase.AnyDeny, answers: []answer{approve, deny, approve}, asked: 2},
+		{name: "any deny, unavailable skipped", mode: usecase.AnyDeny, answers: []answer{down, approve}, ok: true, asked: 2},
+		{name: "any deny, denied after unavailable", mode: usecase.AnyDeny, answers: []answer{down, deny}, asked: 2},
+		{name: "any deny, all unavailable", mode: usecase.AnyDeny, answers: []answer{down, down}, err: usecase.ErrAuthorizerUnavailable, asked: 2},
+		{name: "any deny, failed", mode: usecase.AnyDeny, answers: []answer{fail, approve}, err: failure, asked: 1},
+		{name: "any deny, review after unavailable", mode: usecase.AnyDeny, answers: []answer{down, review}, err: entity.ErrReviewRequired, asked: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				asked       int
+				authorizers []usecase.Authorizer
+			)
+			for _, a := range tt.answers {
+				a := a
+				authorizers = append(authorizers, authorizerFunc(func(context.Context, entity.Transfer) (bool, error) {
+					asked++
+					return a.ok, a.err
+				}))
+			}
+
+			ok, err := usecase.NewCompositeAuthorizer(tt.mode, authorizers...).Authorized(context.Background(), entity.Transfer{})
+			if ok != tt.ok || !errors.Is(err, tt.err) {
+				t.Errorf("Authorized() = %v, %v, want %v, %v", ok, err, tt.ok, tt.err)
+			}
+			if asked != tt.asked {
+				t.Errorf("asked = %d, want %d", asked, tt.asked)
+			}
+		})
+	}
+}
+
+// answer is what an authorizer answers
+type answer struct {
+	ok  bool
+	err error
+}
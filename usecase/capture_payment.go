@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	CapturePaymentUseCase interface {
+		Execute(context.Context, CapturePaymentInput) (PaymentOutput, error)
+	}
+
+	// Input data
+	CapturePaymentInput struct {
+		ID vo.Uuid
+		// Value is the value captured, the whole authorized value when zero
+		Value vo.Amount
+		At    time.Time
+	}
+
+	// Output port
+	CapturePaymentPresenter interface {
+		Output(entity.Payment) PaymentOutput
+	}
+
+	capturePaymentInteractor struct {
+		transferExecutor
+		repoPaymentFinder  entity.PaymentRepositoryFinder
+		repoPaymentUpdater entity.PaymentRepositoryUpdater
+		pre                CapturePaymentPresenter
+		notifier           Notifier
+	}
+)
+
+// NewCapturePaymentInteractor create new capturePaymentInteractor with its dependencies
+func NewCapturePaymentInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoPaymentFinder entity.PaymentRepositoryFinder,
+	repoPaymentUpdater entity.PaymentRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre CapturePaymentPresenter,
+	notifier Notifier,
+	pricing entity.Pricing,
+) CapturePaymentUseCase {
+	return capturePaymentInteractor{
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+			repoAuditCreator:    repoAuditCreator,
+			pricing:             pricing,
+		},
+		repoPaymentFinder:  repoPaymentFinder,
+		repoPaymentUpdater: repoPaymentUpdater,
+		pre:                pre,
+		notifier:           notifier,
+	}
+}
+
+// Execute captures the authorized payment, in full or in part. The captured
+// value is transferred to the merchant, charged with the fees of the pricing,
+// and the rest of the hold is released.
+func (c capturePaymentInteractor) Execute(ctx context.Context, i CapturePaymentInput) (PaymentOutput, error) {
+	ctx, span := tracer.Start(ctx, "CapturePaymentInteractor.Execute", trace.WithAttributes(
+		attribute.String("payment.id", i.ID.Value()),
+		attribute.Int64("payment.capture", i.Value.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var (
+		payment  entity.Payment
+		transfer entity.Transfer
+	)
+	err := c.retry(ctx, span, func() error {
+		return c.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			payment, transfer, err = c.capture(sessCtx, i)
+			return err
+		})
+	})
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Payment{}), err
+	}
+
+	c.notifier.Notify(ctx, transfer)
+
+	return c.pre.Output(payment), nil
+}
+
+// capture releases the hold of the payment, transfers the captured value to
+// the merchant and records both, appending the transfer to the audit log. It
+// must run in a transaction.
+func (c capturePaymentInteractor) capture(ctx context.Context, i CapturePaymentInput) (entity.Payment, entity.Transfer, error) {
+	payment, err := c.repoPaymentFinder.FindByID(ctx, i.ID)
+	if err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	value := i.Value
+	if value.Value() == 0 {
+		value = payment.Value().Amount()
+	}
+
+	transferID, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	if err := payment.Capture(value, transferID, i.At); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	payer, err := c.repoUserFinder.FindByID(ctx, payment.Payer())
+	if err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	// the payment was checked against the limits of the payer when it was authorized
+	if err := payer.Release(payment.Value()); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	transfer := entity.NewTransfer(
+		transferID,
+		payment.Payer(),
+		payment.Payee(),
+		vo.NewMoney(payment.Value().Currency(), value),
+		i.At,
+	).WithType(vo.PaymentTransfer)
+
+	if transfer, err = c.charge(ctx, transfer, payer, true); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	if transfer, err = c.repoTransferCreator.Create(ctx, transfer); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	if err := c.audit(ctx, transfer); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	if err := c.repoPaymentUpdater.Update(ctx, payment); err != nil {
+		return entity.Payment{}, entity.Transfer{}, err
+	}
+
+	return payment, transfer, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type (
+	// Input port
+	ChainAuditUseCase interface {
+		Execute(context.Context, ChainAuditInput) (ChainAuditOutput, error)
+	}
+
+	// Input data
+	ChainAuditInput struct {
+		Limit int
+	}
+
+	// Output data
+	ChainAuditOutput struct {
+		// Chained entries were appended to the chain
+		Chained int
+	}
+
+	chainAuditInteractor struct {
+		repoAuditChainer entity.AuditRepositoryChainer
+	}
+)
+
+// NewChainAuditInteractor create new chainAuditInteractor with its dependencies
+func NewChainAuditInteractor(repoAuditChainer entity.AuditRepositoryChainer) ChainAuditUseCase {
+	return chainAuditInteractor{
+		repoAuditChainer: repoAuditChainer,
+	}
+}
+
+// Execute chains up to i.Limit pending audit entries. The ones another chainer
+// chained meanwhile are left to it.
+func (c chainAuditInteractor) Execute(ctx context.Context, i ChainAuditInput) (ChainAuditOutput, error) {
+	ctx, span := tracer.Start(ctx, "ChainAuditInteractor.Execute")
+	defer span.End()
+
+	chained, err := c.repoAuditChainer.ChainPending(ctx, i.Limit)
+	span.SetAttributes(attribute.Int("audit.chained", chained))
+	if err != nil && !errors.Is(err, entity.ErrConcurrentModification) {
+		recordError(span, err)
+		return ChainAuditOutput{Chained: chained}, err
+	}
+
+	return ChainAuditOutput{Chained: chained}, nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	PauseSchedule  ScheduleAction = "pause"
+	ResumeSchedule ScheduleAction = "resume"
+	CancelSchedule ScheduleAction = "cancel"
+)
+
+type (
+	// ScheduleAction define the status changes of a schedule
+	ScheduleAction string
+
+	// Input port
+	ChangeScheduleStatusUseCase interface {
+		Execute(context.Context, ChangeScheduleStatusInput) (ScheduleOutput, error)
+	}
+
+	// Input data
+	ChangeScheduleStatusInput struct {
+		ID     vo.Uuid
+		Action ScheduleAction
+		At     time.Time
+	}
+
+	// Output port
+	ChangeScheduleStatusPresenter interface {
+		Output(entity.Schedule) ScheduleOutput
+	}
+
+	changeScheduleStatusInteractor struct {
+		repoScheduleFinder  entity.ScheduleRepositoryFinder
+		repoScheduleUpdater entity.ScheduleRepositoryUpdater
+		pre                 ChangeScheduleStatusPresenter
+	}
+)
+
+// NewChangeScheduleStatusInteractor create new changeScheduleStatusInteractor with its dependencies
+func NewChangeScheduleStatusInteractor(
+	repoScheduleFinder entity.ScheduleRepositoryFinder,
+	repoScheduleUpdater entity.ScheduleRepositoryUpdater,
+	pre ChangeScheduleStatusPresenter,
+) ChangeScheduleStatusUseCase {
+	return changeScheduleStatusInteractor{
+		repoScheduleFinder:  repoScheduleFinder,
+		repoScheduleUpdater: repoScheduleUpdater,
+		pre:                 pre,
+	}
+}
+
+// Execute orchestrate the use case
+func (c changeScheduleStatusInteractor) Execute(ctx context.Context, i ChangeScheduleStatusInput) (ScheduleOutput, error) {
+	ctx, span := tracer.Start(ctx, "ChangeScheduleStatusInteractor.Execute", trace.WithAttributes(
+		attribute.String("schedule.id", i.ID.Value()),
+		attribute.String("schedule.action", string(i.Action)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	schedule, err := c.repoScheduleFinder.FindByID(ctx, i.ID)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Schedule{}), err
+	}
+
+	switch i.Action {
+	case PauseSchedule:
+		err = schedule.Pause(i.At)
+	case ResumeSchedule:
+		err = schedule.Resume(i.At)
+	case CancelSchedule:
+		err = schedule.Cancel(i.At)
+	default:
+		err = entity.ErrScheduleStatusTransition
+	}
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Schedule{}), err
+	}
+
+	if err := c.repoScheduleUpdater.Update(ctx, schedule); err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Schedule{}), err
+	}
+
+	return c.pre.Output(schedule), nil
+}
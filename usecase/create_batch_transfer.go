@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// BatchDispatcher port, it starts the processing of the created batches
+	BatchDispatcher interface {
+		Dispatch(context.Context, entity.Batch)
+	}
+
+	// Input port
+	CreateBatchTransferUseCase interface {
+		Execute(context.Context, CreateBatchTransferInput) (BatchTransferOutput, error)
+	}
+
+	// Input data
+	CreateBatchTransferInput struct {
+		ID        vo.Uuid
+		PayerID   vo.Uuid
+		Mode      vo.BatchMode
+		Items     []CreateBatchTransferItemInput
+		CreatedAt time.Time
+	}
+
+	// Input data
+	CreateBatchTransferItemInput struct {
+		TransferID vo.Uuid
+		PayeeID    vo.Uuid
+		Value      vo.Money
+	}
+
+	// Output port
+	CreateBatchTransferPresenter interface {
+		Output(entity.Batch) BatchTransferOutput
+	}
+
+	// Output data
+	BatchTransferOutput struct {
+		ID        string                    `json:"id"`
+		PayerID   string                    `json:"payer"`
+		Mode      string                    `json:"mode"`
+		Status    string                    `json:"status"`
+		Value     int64                     `json:"value"`
+		Total     int                       `json:"total"`
+		Processed int                       `json:"processed"`
+		Succeeded int                       `json:"succeeded"`
+		Failed    int                       `json:"failed"`
+		Transfers []BatchTransferItemOutput `json:"transfers"`
+		CreatedAt string                    `json:"created_at"`
+		UpdatedAt string                    `json:"updated_at"`
+	}
+
+	// Output data
+	BatchTransferItemOutput struct {
+		ID      string `json:"id"`
+		PayeeID string `json:"payee"`
+		Value   int64  `json:"value"`
+		Status  string `json:"status"`
+		Reason  string `json:"reason,omitempty"`
+	}
+
+	createBatchTransferInteractor struct {
+		repoBatchCreator entity.BatchRepositoryCreator
+		repoUserFinder   entity.UserRepositoryFinder
+		pre              CreateBatchTransferPresenter
+		dispatcher       BatchDispatcher
+	}
+)
+
+// NewCreateBatchTransferInteractor create new createBatchTransferInteractor with its dependencies
+func NewCreateBatchTransferInteractor(
+	repoBatchCreator entity.BatchRepositoryCreator,
+	repoUserFinder entity.UserRepositoryFinder,
+	pre CreateBatchTransferPresenter,
+	dispatcher BatchDispatcher,
+) CreateBatchTransferUseCase {
+	return createBatchTransferInteractor{
+		repoBatchCreator: repoBatchCreator,
+		repoUserFinder:   repoUserFinder,
+		pre:              pre,
+		dispatcher:       dispatcher,
+	}
+}
+
+// Execute validates the whole batch up front, stores it as pending and hands
+// it to the dispatcher. The transfers are executed asynchronously.
+func (c createBatchTransferInteractor) Execute(ctx context.Context, i CreateBatchTransferInput) (BatchTransferOutput, error) {
+	ctx, span := tracer.Start(ctx, "CreateBatchTransferInteractor.Execute", trace.WithAttributes(
+		attribute.String("batch.id", i.ID.Value()),
+		attribute.String("batch.payer_id", i.PayerID.Value()),
+		attribute.String("batch.mode", i.Mode.String()),
+		attribute.Int("batch.size", len(i.Items)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	items := make([]entity.BatchItem, 0, len(i.Items))
+	for _, item := range i.Items {
+		items = append(items, entity.NewBatchItem(item.TransferID, item.PayeeID, item.Value))
+	}
+
+	batch, err := entity.NewBatch(i.ID, i.PayerID, i.Mode, items, i.CreatedAt)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Batch{}), err
+	}
+
+	if err := c.validate(ctx, batch); err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Batch{}), err
+	}
+
+	batch, err = c.repoBatchCreator.Create(ctx, batch)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Batch{}), err
+	}
+
+	c.dispatcher.Dispatch(ctx, batch)
+
+	return c.pre.Output(batch), nil
+}
+
+// validate checks that the payer can transfer and that every payee exists. The
+// balance is only checked for the atomic batches, which cannot be partially executed.
+func (c createBatchTransferInteractor) validate(ctx context.Context, batch entity.Batch) error {
+	payer, err := c.repoUserFinder.FindByID(ctx, batch.Payer())
+	if err != nil {
+		return err
+	}
+
+	if err := payer.CanTransfer(); err != nil {
+		return errors.Wrap(err, entity.ErrUnauthorizedTransfer.Error())
+	}
+
+	if batch.Mode() == vo.BatchAtomic {
+		wallet, err := payer.FindWallet(batch.Amount().Currency(), vo.MainWallet)
+		if err != nil {
+			return err
+		}
+
+		if wallet.Available().Amount().Value() < batch.Amount().Amount().Value() {
+			return entity.ErrUserInsufficientBalance
+		}
+	}
+
+	checked := map[string]bool{}
+	for _, item := range batch.Items() {
+		if checked[item.Payee().Value()] {
+			continue
+		}
+
+		if _, err := c.repoUserFinder.FindByID(ctx, item.Payee()); err != nil {
+			return errors.Wrap(err, "payee "+item.Payee().Value())
+		}
+		checked[item.Payee().Value()] = true
+	}
+
+	return nil
+}
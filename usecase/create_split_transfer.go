@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	CreateSplitTransferUseCase interface {
+		Execute(context.Context, CreateSplitTransferInput) (CreateTransferOutput, error)
+	}
+
+	// Input data
+	CreateSplitTransferInput struct {
+		ID       vo.Uuid
+		PayerID  vo.Uuid
+		Value    vo.Money
+		Legs     []CreateSplitTransferLegInput
+		CreateAt time.Time
+	}
+
+	// Input data
+	CreateSplitTransferLegInput struct {
+		PayeeID vo.Uuid
+		Share   vo.SplitShare
+	}
+
+	// Output port
+	CreateSplitTransferPresenter interface {
+		Output(entity.Transfer) CreateTransferOutput
+	}
+
+	createSplitTransferInteractor struct {
+		transferExecutor
+		pre      CreateSplitTransferPresenter
+		notifier Notifier
+	}
+)
+
+// NewCreateSplitTransferInteractor create new createSplitTransferInteractor with its dependencies
+func NewCreateSplitTransferInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoReviewCreator entity.ReviewRepositoryCreator,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre CreateSplitTransferPresenter,
+	authorizer Authorizer,
+	notifier Notifier,
+	pricing entity.Pricing,
+	limits entity.LimitPolicy,
+	review entity.ReviewPolicy,
+) CreateSplitTransferUseCase {
+	return createSplitTransferInteractor{
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoTransferFinder:  repoTransferFinder,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+			repoReviewCreator:   repoReviewCreator,
+			repoAuditCreator:    repoAuditCreator,
+			authorizer:          authorizer,
+			pricing:             pricing,
+			limits:              limits,
+			review:              review,
+		},
+		pre:      pre,
+		notifier: notifier,
+	}
+}
+
+// Execute allocates the value between the payees, then debits the payer once
+// and credits every payee in the same transaction, unless the transfer is held
+// for a review
+func (c createSplitTransferInteractor) Execute(ctx context.Context, i CreateSplitTransferInput) (CreateTransferOutput, error) {
+	ctx, span := tracer.Start(ctx, "CreateSplitTransferInteractor.Execute", trace.WithAttributes(
+		attribute.String("transfer.id", i.ID.Value()),
+		attribute.String("transfer.payer_id", i.PayerID.Value()),
+		attribute.Int("transfer.legs", len(i.Legs)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	shares := make([]vo.SplitShare, 0, len(i.Legs))
+	for _, leg := range i.Legs {
+		shares = append(shares, leg.Share)
+	}
+
+	amounts, err := vo.Allocate(i.Value.Amount(), shares)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Transfer{}), err
+	}
+
+	legs := make([]entity.TransferLeg, 0, len(i.Legs))
+	for n, leg := range i.Legs {
+		legs = append(legs, entity.NewTransferLeg(leg.PayeeID, vo.NewMoney(i.Value.Currency(), amounts[n])))
+	}
+
+	transfer, err := entity.NewSplitTransfer(i.ID, i.PayerID, legs, i.CreateAt)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Transfer{}), err
+	}
+
+	created, err := c.execute(ctx, span, transfer)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Transfer{}), err
+	}
+
+	if created.Status() == vo.TransferCompleted {
+		c.notifier.Notify(ctx, created)
+	}
+
+	return c.pre.Output(created), nil
+}
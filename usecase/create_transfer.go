@@ -2,14 +2,21 @@ package usecase
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// maxTransferAttempts bounds the executions of a transfer that conflicted with a concurrent one
+const maxTransferAttempts = 3
+
 type (
 	// Authorizer port
 	Authorizer interface {
@@ -28,11 +35,16 @@ type (
 
 	// Input data
 	CreateTransferInput struct {
-		ID       vo.Uuid
-		PayerID  vo.Uuid
-		PayeeID  vo.Uuid
-		Value    vo.Money
-		CreateAt time.Time
+		ID      vo.Uuid
+		PayerID vo.Uuid
+		PayeeID vo.Uuid
+		Value   vo.Money
+		Type    vo.TransferType // vo.DirectTransfer when empty
+		// Source and Destination are the purposes of the wallets of the payer
+		// and the payee, vo.MainWallet when empty
+		Source      vo.WalletPurpose
+		Destination vo.WalletPurpose
+		CreateAt    time.Time
 	}
 
 	// Output port
@@ -40,117 +52,466 @@ type (
 		Output(entity.Transfer) CreateTransferOutput
 	}
 
-	// Output data
+	// Output data. Value is the gross value debited from the source wallet of
+	// the payer, the payees are credited with the net value on their
+	// destination wallet unless the transfer is HELD for a review.
 	CreateTransferOutput struct {
-		ID        string `json:"id"`
-		PayerID   string `json:"payer"`
-		PayeeID   string `json:"payee"`
-		Value     int64  `json:"value"`
-		CreatedAt string `json:"created_at"`
+		ID                string              `json:"id"`
+		PayerID           string              `json:"payer"`
+		PayeeID           string              `json:"payee,omitempty"`
+		Type              string              `json:"type"`
+		Status            string              `json:"status"`
+		Currency          string              `json:"currency"`
+		SourceWallet      string              `json:"source_wallet"`
+		DestinationWallet string              `json:"destination_wallet"`
+		Value             int64               `json:"value"`
+		Gross             int64               `json:"gross"`
+		Fee               int64               `json:"fee"`
+		Net               int64               `json:"net"`
+		FeeRule           *FeeRuleOutput      `json:"fee_rule,omitempty"`
+		Legs              []TransferLegOutput `json:"legs,omitempty"`
+		CreatedAt         string              `json:"created_at"`
+	}
+
+	// Output data
+	TransferLegOutput struct {
+		PayeeID string         `json:"payee"`
+		Value   int64          `json:"value"`
+		Fee     int64          `json:"fee"`
+		Net     int64          `json:"net"`
+		FeeRule *FeeRuleOutput `json:"fee_rule,omitempty"`
+	}
+
+	// Output data
+	FeeRuleOutput struct {
+		ID      string `json:"id"`
+		Version int    `json:"version"`
 	}
 
 	createTransferInteractor struct {
+		transferExecutor
+		pre      CreateTransferPresenter
+		notifier Notifier
+	}
+
+	// userWallet is the wallet of a purpose of a user charged by a transfer
+	userWallet struct {
+		user    int
+		purpose vo.WalletPurpose
+	}
+
+	// transferExecutor moves the money of the transfers, shared by the use cases creating them
+	transferExecutor struct {
 		repoTransferCreator entity.TransferRepositoryCreator
+		repoTransferFinder  entity.TransferRepositoryFinder
 		repoUserUpdater     entity.UserRepositoryUpdater
 		repoUserFinder      entity.UserRepositoryFinder
-		pre                 CreateTransferPresenter
+		repoReviewCreator   entity.ReviewRepositoryCreator
+		repoAuditCreator    entity.AuditRepositoryCreator
 		authorizer          Authorizer
-		notifier            Notifier
+		pricing             entity.Pricing
+		limits              entity.LimitPolicy
+		review              entity.ReviewPolicy
 	}
 )
 
+// transferRefusals are the errors of the transfers refused for good, retrying them fails again
+var transferRefusals = []error{
+	entity.ErrUserInsufficientBalance,
+	entity.ErrUnauthorizedTransfer,
+	entity.ErrReviewRequired,
+	entity.ErrNotFoundUser,
+	entity.ErrSamePayerAndPayee,
+	entity.ErrLimitExceeded,
+	entity.ErrNotFoundWallet,
+	vo.ErrNotAllowedTypeUser,
+}
+
 // NewCreateTransferInteractor create new createTransferInteractor with its dependencies
 func NewCreateTransferInteractor(
 	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
 	repoUserUpdater entity.UserRepositoryUpdater,
 	repoUserFinder entity.UserRepositoryFinder,
+	repoReviewCreator entity.ReviewRepositoryCreator,
+	repoAuditCreator entity.AuditRepositoryCreator,
 	pre CreateTransferPresenter,
 	authorizer Authorizer,
 	notifier Notifier,
+	pricing entity.Pricing,
+	limits entity.LimitPolicy,
+	review entity.ReviewPolicy,
 ) CreateTransferUseCase {
 	return createTransferInteractor{
-		repoTransferCreator: repoTransferCreator,
-		repoUserUpdater:     repoUserUpdater,
-		repoUserFinder:      repoUserFinder,
-		pre:                 pre,
-		authorizer:          authorizer,
-		notifier:            notifier,
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoTransferFinder:  repoTransferFinder,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+			repoReviewCreator:   repoReviewCreator,
+			repoAuditCreator:    repoAuditCreator,
+			authorizer:          authorizer,
+			pricing:             pricing,
+			limits:              limits,
+			review:              review,
+		},
+		pre:      pre,
+		notifier: notifier,
 	}
 }
 
 // Execute orchestrate the use case
 func (c createTransferInteractor) Execute(ctx context.Context, i CreateTransferInput) (CreateTransferOutput, error) {
+	ctx, span := tracer.Start(ctx, "CreateTransferInteractor.Execute", trace.WithAttributes(
+		attribute.String("transfer.id", i.ID.Value()),
+		attribute.String("transfer.payer_id", i.PayerID.Value()),
+		attribute.String("transfer.payee_id", i.PayeeID.Value()),
+	))
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var (
-		transfer entity.Transfer
-		err      error
-	)
+	if i.PayerID.Equals(i.PayeeID) {
+		recordError(span, entity.ErrSamePayerAndPayee)
+		return c.pre.Output(entity.Transfer{}), entity.ErrSamePayerAndPayee
+	}
 
-	err = c.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
-		if err = c.process(sessCtx, i.PayerID, i.PayeeID, i.Value); err != nil {
+	t := entity.NewTransfer(i.ID, i.PayerID, i.PayeeID, i.Value, i.CreateAt)
+	if i.Type != "" {
+		t = t.WithType(i.Type)
+	}
+	if i.Source != "" || i.Destination != "" {
+		t = t.WithWallets(walletPurpose(i.Source), walletPurpose(i.Destination))
+	}
+
+	transfer, err := c.execute(ctx, span, t)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(entity.Transfer{}), err
+	}
+
+	if transfer.Status() == vo.TransferCompleted {
+		c.notifier.Notify(ctx, transfer)
+	}
+
+	return c.pre.Output(transfer), nil
+}
+
+// execute authorizes the transfer, then makes it. The transfer the authorizer
+// requires a manual review of is held instead.
+func (t transferExecutor) execute(ctx context.Context, span trace.Span, transfer entity.Transfer) (entity.Transfer, error) {
+	err := t.authorize(ctx, transfer)
+	held := errors.Is(err, entity.ErrReviewRequired)
+	if err != nil && !held {
+		return entity.Transfer{}, err
+	}
+
+	var reason string
+	if held {
+		reason = err.Error()
+		span.AddEvent("transfer held for review", trace.WithAttributes(
+			attribute.String("review.reason", reason),
+		))
+	}
+
+	var created entity.Transfer
+	err = t.retry(ctx, span, func() error {
+		var err error
+		if held {
+			created, err = t.hold(ctx, transfer, reason)
+		} else {
+			created, err = t.transfer(ctx, transfer)
+		}
+		return err
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return created, nil
+}
+
+// authorize asks the authorizer whether the transfer may be made. It runs
+// before the transaction moving the money, so that what the authorizer saves
+// is kept when the transfer is refused.
+func (t transferExecutor) authorize(ctx context.Context, transfer entity.Transfer) error {
+	ok, err := t.authorizer.Authorized(ctx, transfer)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return entity.ErrUnauthorizedTransfer
+	}
+
+	return nil
+}
+
+// retry runs fn again while it conflicts with a concurrent transaction, up to maxTransferAttempts
+func (t transferExecutor) retry(ctx context.Context, span trace.Span, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !errors.Is(err, entity.ErrConcurrentModification) || attempt == maxTransferAttempts {
 			return err
 		}
 
-		transfer, err = c.repoTransferCreator.Create(sessCtx, entity.NewTransfer(
-			i.ID,
-			i.PayerID,
-			i.PayeeID,
-			i.Value,
-			i.CreateAt,
+		span.AddEvent("retrying after concurrent modification", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
 		))
+
+		if err := sleep(ctx, retryBackoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// transfer moves the money and records the transfer in a single transaction
+func (t transferExecutor) transfer(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error) {
+	var created entity.Transfer
+
+	err := t.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+		var err error
+		created, err = t.move(sessCtx, transfer)
+		return err
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return created, nil
+}
+
+// move moves the money and records the transfer, appending its creation to
+// the audit log. It must run in a transaction.
+func (t transferExecutor) move(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error) {
+	transfer, err := t.process(ctx, transfer, true)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	if transfer, err = t.repoTransferCreator.Create(ctx, transfer); err != nil {
+		return entity.Transfer{}, err
+	}
+
+	if err := t.audit(ctx, transfer); err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return transfer, nil
+}
+
+// hold debits the payer and records the transfer as held, with the review
+// deciding whether the payees are credited, in a single transaction
+func (t transferExecutor) hold(ctx context.Context, transfer entity.Transfer, reason string) (entity.Transfer, error) {
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	var held entity.Transfer
+	err = t.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+		var err error
+		held, err = t.process(sessCtx, transfer.WithStatus(vo.TransferHeld), false)
 		if err != nil {
 			return err
 		}
 
-		ok, err := c.authorizer.Authorized(sessCtx, transfer)
-		if err != nil || !ok {
+		if held, err = t.repoTransferCreator.Create(sessCtx, held); err != nil {
 			return err
 		}
 
-		return nil
+		if err := t.audit(sessCtx, held); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		_, err = t.repoReviewCreator.Create(sessCtx, entity.NewReview(id, held, reason, now, now.Add(t.review.Timeout())))
+		return err
 	})
 	if err != nil {
-		return c.pre.Output(entity.Transfer{}), err
+		return entity.Transfer{}, err
 	}
 
-	c.notifier.Notify(ctx, transfer)
+	return held, nil
+}
 
-	return c.pre.Output(transfer), nil
+// audit appends the creation of the transfer by its payer to the audit log
+func (t transferExecutor) audit(ctx context.Context, transfer entity.Transfer) error {
+	_, err := appendAudit(ctx, t.repoAuditCreator, auditRecord{
+		action:    vo.TransferCreatedAction,
+		actor:     transfer.Payer().Value(),
+		target:    vo.TransferTarget,
+		subjectID: transfer.ID(),
+		after:     newTransferSnapshot(transfer),
+		at:        transfer.CreatedAt(),
+	})
+
+	return err
 }
 
-func (c createTransferInteractor) process(ctx context.Context, payerID vo.Uuid, payeeID vo.Uuid, value vo.Money) error {
-	payer, err := c.repoUserFinder.FindByID(ctx, payerID)
+// process checks the payer may make the transfer within its limits, then
+// charges it
+func (t transferExecutor) process(ctx context.Context, transfer entity.Transfer, credit bool) (entity.Transfer, error) {
+	payer, err := t.repoUserFinder.FindByID(ctx, transfer.Payer())
 	if err != nil {
-		return err
+		return entity.Transfer{}, err
 	}
 
+	if err := t.check(ctx, payer, transfer.Value(), transfer.CreatedAt()); err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return t.charge(ctx, transfer, payer, credit)
+}
+
+// check checks the payer may pay the value at the time within its limits
+func (t transferExecutor) check(ctx context.Context, payer entity.User, value vo.Money, at time.Time) error {
 	if err := payer.CanTransfer(); err != nil {
 		return errors.Wrap(err, entity.ErrUnauthorizedTransfer.Error())
 	}
 
-	payee, err := c.repoUserFinder.FindByID(ctx, payeeID)
-	if err != nil {
-		return err
+	// concurrent transfers of the payer conflict on its wallet, the retried one checks the limits again
+	return t.limits.For(payer, value.Currency()).Check(value, at, func(from, to time.Time) (entity.TransferUsage, error) {
+		return t.repoTransferFinder.SumByPayer(ctx, payer.ID(), value.Currency(), from, to)
+	})
+}
+
+// charge debits the payer once, credits the payee of every leg with its net
+// value and the fee account with the fees. Without credit only the payer is
+// debited. It returns the transfer charged with the fees of the pricing.
+func (t transferExecutor) charge(ctx context.Context, transfer entity.Transfer, payer entity.User, credit bool) (entity.Transfer, error) {
+	// a user credited several times, by several legs or with the fees, is found once
+	var (
+		users   = []entity.User{payer}
+		indexes = map[string]int{payer.ID().Value(): 0}
+	)
+	find := func(id vo.Uuid) (int, error) {
+		if i, ok := indexes[id.Value()]; ok {
+			return i, nil
+		}
+
+		user, err := t.repoUserFinder.FindByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		indexes[id.Value()] = len(users)
+		users = append(users, user)
+		return len(users) - 1, nil
 	}
 
-	err = payer.Withdraw(value)
-	if err != nil {
-		return err
+	// and each wallet moved is updated once
+	var (
+		wallets []userWallet
+		moved   = map[userWallet]bool{}
+	)
+	move := func(user int, purpose vo.WalletPurpose) {
+		if w := (userWallet{user: user, purpose: purpose}); !moved[w] {
+			moved[w] = true
+			wallets = append(wallets, w)
+		}
 	}
 
-	payee.Deposit(value)
+	if err := users[0].Withdraw(transfer.Value(), transfer.Source()); err != nil {
+		return entity.Transfer{}, err
+	}
+	move(0, transfer.Source())
 
-	err = c.repoUserUpdater.UpdateWallet(ctx, payerID, payer.Wallet().Money())
-	if err != nil {
-		return err
+	for i, leg := range transfer.Legs() {
+		payee, err := find(leg.Payee())
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		transfer = transfer.WithFee(i, t.pricing.Fee(users[payee].TypeUser(), leg.Value(), transfer.Type()))
+
+		// the payees and the fee account of a held transfer are credited once its review approves it
+		if !credit {
+			continue
+		}
+
+		if err := users[payee].Deposit(transfer.Legs()[i].Net(), transfer.Destination()); err != nil {
+			return entity.Transfer{}, err
+		}
+		move(payee, transfer.Destination())
 	}
 
-	err = c.repoUserUpdater.UpdateWallet(ctx, payeeID, payee.Wallet().Money())
-	if err != nil {
-		return err
+	if fee := transfer.Fee(); credit && fee.Amount().Value() > 0 {
+		account, err := find(feeAccount(transfer, t.pricing))
+		if errors.Is(err, entity.ErrNotFoundUser) {
+			return entity.Transfer{}, errors.Wrap(entity.ErrNotFoundFeeAccount, err.Error())
+		}
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		if err := users[account].Deposit(fee, vo.MainWallet); err != nil {
+			return entity.Transfer{}, errors.Wrap(entity.ErrNotFoundFeeAccount, err.Error())
+		}
+		move(account, vo.MainWallet)
 	}
 
-	return nil
+	for _, w := range wallets {
+		user := users[w.user]
+		wallet, err := user.FindWallet(transfer.Value().Currency(), w.purpose)
+		if err != nil {
+			return entity.Transfer{}, err
+		}
+
+		if err := t.repoUserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+			return entity.Transfer{}, err
+		}
+	}
+
+	return transfer, nil
+}
+
+// feeAccount returns the user credited with the fees of the transfer, the
+// account of the pricing for a transfer recorded without it
+func feeAccount(transfer entity.Transfer, pricing entity.Pricing) vo.Uuid {
+	if account := transfer.FeeAccount(); account.Value() != "" {
+		return account
+	}
+
+	return pricing.Account()
+}
+
+// walletPurpose returns the purpose, vo.MainWallet when empty
+func walletPurpose(purpose vo.WalletPurpose) vo.WalletPurpose {
+	if purpose == "" {
+		return vo.MainWallet
+	}
+
+	return purpose
+}
+
+// isTransferRefusal reports whether err refused the transfer for good
+func isTransferRefusal(err error) bool {
+	for _, r := range transferRefusals {
+		if errors.Is(err, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryBackoff returns a jittered delay growing with the attempt
+func retryBackoff(attempt int) time.Duration {
+	base := time.Duration(attempt) * 20 * time.Millisecond
+	return base + time.Duration(rand.Int63n(int64(base)))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
@@ -24,6 +26,10 @@ type (
 		Wallet    *vo.Wallet
 		Type      vo.TypeUser
 		CreatedAt time.Time
+		// Admin allows a wallet created with money, the others are funded by deposits
+		Admin bool
+		// Actor is who created the user in the audit log, the user itself when empty
+		Actor string
 	}
 
 	// Output port
@@ -62,24 +68,43 @@ type (
 	}
 
 	CreateUserInteractor struct {
-		repo entity.UserRepositoryCreator
-		pre  CreateUserPresenter
+		repoTransferCreator entity.TransferRepositoryCreator
+		repo                entity.UserRepositoryCreator
+		repoAuditCreator    entity.AuditRepositoryCreator
+		pre                 CreateUserPresenter
 	}
 )
 
 // NewCreateUserInteractor creates new createUserInteractor with its dependencies
-func NewCreateUserInteractor(repo entity.UserRepositoryCreator, pre CreateUserPresenter) CreateUserUseCase {
+func NewCreateUserInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repo entity.UserRepositoryCreator,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre CreateUserPresenter,
+) CreateUserUseCase {
 	return CreateUserInteractor{
-		repo: repo,
-		pre:  pre,
+		repoTransferCreator: repoTransferCreator,
+		repo:                repo,
+		repoAuditCreator:    repoAuditCreator,
+		pre:                 pre,
 	}
 }
 
-// Execute orchestrate the use case
+// Execute creates the user and appends its creation to the audit log in a single transaction
 func (c CreateUserInteractor) Execute(ctx context.Context, i CreateUserInput) (CreateUserOutput, error) {
+	ctx, span := tracer.Start(ctx, "CreateUserInteractor.Execute", trace.WithAttributes(
+		attribute.String("user.id", i.ID.Value()),
+	))
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	if !i.Admin && i.Wallet != nil && i.Wallet.Money().Amount().Value() > 0 {
+		recordError(span, entity.ErrInitialBalanceRestricted)
+		return c.pre.Output(entity.User{}), entity.ErrInitialBalanceRestricted
+	}
+
 	u, err := entity.NewUser(
 		i.ID,
 		i.FullName,
@@ -91,11 +116,34 @@ func (c CreateUserInteractor) Execute(ctx context.Context, i CreateUserInput) (C
 		i.CreatedAt,
 	)
 	if err != nil {
+		recordError(span, err)
 		return c.pre.Output(entity.User{}), err
 	}
 
-	user, err := c.repo.Create(ctx, u)
+	actor := i.Actor
+	if actor == "" {
+		actor = u.ID().Value()
+	}
+
+	var user entity.User
+	err = c.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+		var err error
+		if user, err = c.repo.Create(sessCtx, u); err != nil {
+			return err
+		}
+
+		_, err = appendAudit(sessCtx, c.repoAuditCreator, auditRecord{
+			action:    vo.UserCreatedAction,
+			actor:     actor,
+			target:    vo.UserTarget,
+			subjectID: user.ID(),
+			after:     newUserSnapshot(user),
+			at:        user.CreatedAt(),
+		})
+		return err
+	})
 	if err != nil {
+		recordError(span, err)
 		return c.pre.Output(entity.User{}), err
 	}
 
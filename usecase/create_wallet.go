@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	CreateWalletUseCase interface {
+		Execute(context.Context, CreateWalletInput) (CreateWalletOutput, error)
+	}
+
+	// Input data
+	CreateWalletInput struct {
+		UserID   vo.Uuid
+		Currency vo.Currency
+		Purpose  vo.WalletPurpose
+	}
+
+	// Output port
+	CreateWalletPresenter interface {
+		Output(vo.Uuid, vo.Wallet) CreateWalletOutput
+	}
+
+	// Output data
+	CreateWalletOutput struct {
+		UserID   string `json:"user_id"`
+		Currency string `json:"currency"`
+		Purpose  string `json:"purpose"`
+		Amount   int64  `json:"amount"`
+	}
+
+	createWalletInteractor struct {
+		repoUserFinder    entity.UserRepositoryFinder
+		repoWalletCreator entity.UserWalletRepositoryCreator
+		pre               CreateWalletPresenter
+	}
+)
+
+// NewCreateWalletInteractor create new createWalletInteractor with its dependencies
+func NewCreateWalletInteractor(
+	repoUserFinder entity.UserRepositoryFinder,
+	repoWalletCreator entity.UserWalletRepositoryCreator,
+	pre CreateWalletPresenter,
+) CreateWalletUseCase {
+	return createWalletInteractor{
+		repoUserFinder:    repoUserFinder,
+		repoWalletCreator: repoWalletCreator,
+		pre:               pre,
+	}
+}
+
+// Execute gives the user a new empty wallet of the currency for the purpose
+func (c createWalletInteractor) Execute(ctx context.Context, i CreateWalletInput) (CreateWalletOutput, error) {
+	ctx, span := tracer.Start(ctx, "CreateWalletInteractor.Execute", trace.WithAttributes(
+		attribute.String("user.id", i.UserID.Value()),
+		attribute.String("wallet.currency", i.Currency.String()),
+		attribute.String("wallet.purpose", i.Purpose.String()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user, err := c.repoUserFinder.FindByID(ctx, i.UserID)
+	if err != nil {
+		recordError(span, err)
+		return c.pre.Output(vo.Uuid{}, vo.Wallet{}), err
+	}
+
+	wallet := vo.NewWalletWithPurpose(vo.NewMoney(i.Currency, vo.Amount{}), walletPurpose(i.Purpose))
+	if err := user.AddWallet(wallet); err != nil {
+		recordError(span, err)
+		return c.pre.Output(vo.Uuid{}, vo.Wallet{}), err
+	}
+
+	// the repository refuses the wallet a concurrent request already created
+	if err := c.repoWalletCreator.CreateWallet(ctx, user.ID(), wallet); err != nil {
+		recordError(span, err)
+		return c.pre.Output(vo.Uuid{}, vo.Wallet{}), err
+	}
+
+	return c.pre.Output(user.ID(), *wallet), nil
+}
@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ApproveReview ReviewAction = "approve"
+	RejectReview  ReviewAction = "reject"
+)
+
+type (
+	// ReviewAction define the decisions of a reviewer
+	ReviewAction string
+
+	// Input port
+	DecideReviewUseCase interface {
+		Execute(context.Context, DecideReviewInput) (ReviewOutput, error)
+	}
+
+	// Input data
+	DecideReviewInput struct {
+		ID       vo.Uuid
+		Action   ReviewAction
+		Reviewer string
+		Note     string
+		At       time.Time
+	}
+
+	// Output port
+	DecideReviewPresenter interface {
+		Output(entity.Review) ReviewOutput
+	}
+
+	decideReviewInteractor struct {
+		reviewSettler
+		pre DecideReviewPresenter
+	}
+
+	// reviewSettler settles the held transfers as their review is decided,
+	// shared by the use cases deciding the reviews
+	reviewSettler struct {
+		transferExecutor
+		repoTransferUpdater entity.TransferRepositoryUpdater
+		repoReviewFinder    entity.ReviewRepositoryFinder
+		repoReviewUpdater   entity.ReviewRepositoryUpdater
+		repoAuditCreator    entity.AuditRepositoryCreator
+		notifier            Notifier
+	}
+)
+
+// NewDecideReviewInteractor create new decideReviewInteractor with its dependencies
+func NewDecideReviewInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoTransferUpdater entity.TransferRepositoryUpdater,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoReviewFinder entity.ReviewRepositoryFinder,
+	repoReviewUpdater entity.ReviewRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre DecideReviewPresenter,
+	notifier Notifier,
+	pricing entity.Pricing,
+) DecideReviewUseCase {
+	return decideReviewInteractor{
+		reviewSettler: reviewSettler{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoTransferFinder:  repoTransferFinder,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+				pricing:             pricing,
+			},
+			repoTransferUpdater: repoTransferUpdater,
+			repoReviewFinder:    repoReviewFinder,
+			repoReviewUpdater:   repoReviewUpdater,
+			repoAuditCreator:    repoAuditCreator,
+			notifier:            notifier,
+		},
+		pre: pre,
+	}
+}
+
+// Execute approves or rejects the pending review. The payees of an approved
+// transfer are credited, the payer of a rejected one is refunded.
+func (d decideReviewInteractor) Execute(ctx context.Context, i DecideReviewInput) (ReviewOutput, error) {
+	ctx, span := tracer.Start(ctx, "DecideReviewInteractor.Execute", trace.WithAttributes(
+		attribute.String("review.id", i.ID.Value()),
+		attribute.String("review.action", string(i.Action)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var status vo.ReviewStatus
+	switch i.Action {
+	case ApproveReview:
+		status = vo.ReviewApproved
+	case RejectReview:
+		status = vo.ReviewRejected
+	default:
+		recordError(span, entity.ErrReviewStatusTransition)
+		return d.pre.Output(entity.Review{}), entity.ErrReviewStatusTransition
+	}
+
+	review, err := d.settle(ctx, span, i.ID, status, i.Reviewer, i.Note, i.At)
+	if err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Review{}), err
+	}
+
+	return d.pre.Output(review), nil
+}
+
+// settle decides the review and settles its transfer in a single transaction,
+// then notifies the approved transfer
+func (s reviewSettler) settle(
+	ctx context.Context,
+	span trace.Span,
+	ID vo.Uuid,
+	status vo.ReviewStatus,
+	reviewer string,
+	note string,
+	at time.Time,
+) (entity.Review, error) {
+	var (
+		review   entity.Review
+		transfer entity.Transfer
+	)
+	err := s.retry(ctx, span, func() error {
+		return s.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			review, transfer, err = s.decide(sessCtx, ID, status, reviewer, note, at)
+			return err
+		})
+	})
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	if transfer.Status() == vo.TransferCompleted {
+		s.notifier.Notify(ctx, transfer)
+	}
+
+	return review, nil
+}
+
+// decide decides the review, settles its transfer and appends the decision
+// to the audit log, with the refund of the payer of a rejected transfer. It
+// must run in a transaction.
+func (s reviewSettler) decide(
+	ctx context.Context,
+	ID vo.Uuid,
+	status vo.ReviewStatus,
+	reviewer string,
+	note string,
+	at time.Time,
+) (entity.Review, entity.Transfer, error) {
+	review, err := s.repoReviewFinder.FindByID(ctx, ID)
+	if err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	if err := review.Decide(status, reviewer, note, at); err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	transfer, err := s.repoTransferFinder.FindByID(ctx, review.TransferID())
+	if err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	transferStatus, action := vo.TransferCompleted, vo.ReviewApprovedAction
+	if status == vo.ReviewRejected {
+		transferStatus, action = vo.TransferRejected, vo.ReviewRejectedAction
+	}
+
+	if err := s.release(ctx, transfer, status); err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	if err := s.repoTransferUpdater.UpdateStatus(ctx, transfer.ID(), transferStatus); err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	if err := s.repoReviewUpdater.Update(ctx, review); err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	_, err = appendAudit(ctx, s.repoAuditCreator, auditRecord{
+		action:    action,
+		actor:     reviewer,
+		target:    vo.ReviewTarget,
+		subjectID: review.ID(),
+		detail:    note,
+		at:        at,
+	})
+	if err != nil {
+		return entity.Review{}, entity.Transfer{}, err
+	}
+
+	decided := transfer.WithStatus(transferStatus)
+	if status == vo.ReviewRejected {
+		_, err = appendAudit(ctx, s.repoAuditCreator, auditRecord{
+			action:    vo.TransferRefundedAction,
+			actor:     reviewer,
+			target:    vo.TransferTarget,
+			subjectID: transfer.ID(),
+			detail:    note,
+			before:    newTransferSnapshot(transfer),
+			after:     newTransferSnapshot(decided),
+			at:        at,
+		})
+		if err != nil {
+			return entity.Review{}, entity.Transfer{}, err
+		}
+	}
+
+	return review, decided, nil
+}
+
+// release credits the payee of every leg of an approved transfer with its net
+// value and the fee account with the fees, the payer of a rejected one is
+// refunded with the value debited when it was held
+func (s reviewSettler) release(ctx context.Context, transfer entity.Transfer, status vo.ReviewStatus) error {
+	// a user credited several times, by several legs or with the fees, is found
+	// once and each wallet credited is updated once
+	var (
+		users   []entity.User
+		indexes = map[string]int{}
+		wallets []userWallet
+		moved   = map[userWallet]bool{}
+	)
+	credit := func(id vo.Uuid, money vo.Money, purpose vo.WalletPurpose) error {
+		i, ok := indexes[id.Value()]
+		if !ok {
+			user, err := s.repoUserFinder.FindByID(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			i = len(users)
+			indexes[id.Value()] = i
+			users = append(users, user)
+		}
+
+		if err := users[i].Deposit(money, purpose); err != nil {
+			return err
+		}
+
+		if w := (userWallet{user: i, purpose: purpose}); !moved[w] {
+			moved[w] = true
+			wallets = append(wallets, w)
+		}
+		return nil
+	}
+
+	if status == vo.ReviewRejected {
+		if err := credit(transfer.Payer(), transfer.Value(), transfer.Source()); err != nil {
+			return err
+		}
+	} else {
+		for _, leg := range transfer.Legs() {
+			if err := credit(leg.Payee(), leg.Net(), transfer.Destination()); err != nil {
+				return err
+			}
+		}
+
+		if fee := transfer.Fee(); fee.Amount().Value() > 0 {
+			err := credit(feeAccount(transfer, s.pricing), fee, vo.MainWallet)
+			if errors.Is(err, entity.ErrNotFoundUser) || errors.Is(err, entity.ErrNotFoundWallet) {
+				return errors.Wrap(entity.ErrNotFoundFeeAccount, err.Error())
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, w := range wallets {
+		user := users[w.user]
+		wallet, err := user.FindWallet(transfer.Value().Currency(), w.purpose)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repoUserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestDecideReviewRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   usecase.ReviewAction
+		noFees   bool
+		err      error
+		balances [4]int64 // of the payer, the payees and the fee account
+		transfer vo.TransferStatus
+		review   vo.ReviewStatus
+		audit    []vo.AuditAction
+	}{
+		{
+			name:     "approved legs and fees credited",
+			action:   usecase.ApproveReview,
+			balances: [4]int64{70, 18, 8, 4},
+			transfer: vo.TransferCompleted,
+			review:   vo.ReviewApproved,
+			audit:    []vo.AuditAction{vo.TransferCreatedAction, vo.ReviewApprovedAction},
+		},
+		{
+			name:     "rejected payer refunded",
+			action:   usecase.RejectReview,
+			balances: [4]int64{100, 0, 0, 0},
+			transfer: vo.TransferRejected,
+			review:   vo.ReviewRejected,
+			audit:    []vo.AuditAction{vo.TransferCreatedAction, vo.ReviewRejectedAction, vo.TransferRefundedAction},
+		},
+		{
+			name:     "missing fee account rolls back",
+			action:   usecase.ApproveReview,
+			noFees:   true,
+			err:      entity.ErrNotFoundFeeAccount,
+			balances: [4]int64{70, 0, 0, 0},
+			transfer: vo.TransferHeld,
+			review:   vo.ReviewPending,
+			audit:    []vo.AuditAction{vo.TransferCreatedAction},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			transfers := database.NewTransferInMen(db)
+			reviews := database.NewReviewInMen(db)
+			audit := database.NewAuditInMen(db)
+
+			payer := newUser(ctx, t, users, 100)
+			first := newUser(ctx, t, users, 0)
+			second := newUser(ctx, t, users, 0)
+			fees := newUser(ctx, t, users, 0)
+
+			account := fees.ID()
+			if tt.noFees {
+				account = newUuid(t)
+			}
+			pricing := newFlatPricing(t, account, 2)
+
+			// a transfer split in two legs, each charged a fee, held for review
+			review := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return false, entity.ErrReviewRequired })
+			split := usecase.NewCreateSplitTransferInteractor(
+				transfers,
+				transfers,
+				users,
+				users,
+				reviews,
+				audit,
+				presenter.NewCreateSplitTransferPresenter(),
+				review,
+				nopNotifier{},
+				pricing,
+				entity.LimitPolicy{},
+				entity.ReviewPolicy{},
+			)
+			transferID := newUuid(t)
+			if _, err := split.Execute(ctx, usecase.CreateSplitTransferInput{
+				ID:      transferID,
+				PayerID: payer.ID(),
+				Value:   vo.NewMoneyBRL(vo.NewAmountTest(30)),
+				Legs: []usecase.CreateSplitTransferLegInput{
+					{PayeeID: first.ID(), Share: vo.NewAmountShare(vo.NewAmountTest(20))},
+					{PayeeID: second.ID(), Share: vo.NewAmountShare(vo.NewAmountTest(10))},
+				},
+				CreateAt: time.Now(),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			pending, err := reviews.FindByStatus(ctx, vo.ReviewPending, 10)
+			if err != nil || len(pending) != 1 {
+				t.Fatalf("pending reviews = %d, %v, want 1", len(pending), err)
+			}
+
+			decide := usecase.NewDecideReviewInteractor(
+				transfers,
+				transfers,
+				transfers,
+				users,
+				users,
+				reviews,
+				reviews,
+				audit,
+				presenter.NewDecideReviewPresenter(),
+				nopNotifier{},
+				pricing,
+			)
+			_, err = decide.Execute(ctx, usecase.DecideReviewInput{
+				ID:       pending[0].ID(),
+				Action:   tt.action,
+				Reviewer: "operator",
+				Note:     "checked",
+				At:       time.Now(),
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+
+			for i, u := range []entity.User{payer, first, second, fees} {
+				got, err := users.FindByID(ctx, u.ID())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Wallet().Money().Amount().Value() != tt.balances[i] {
+					t.Errorf("balance %d = %d, want %d", i, got.Wallet().Money().Amount().Value(), tt.balances[i])
+				}
+			}
+
+			transfer, err := transfers.FindByID(ctx, transferID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transfer.Status() != tt.transfer {
+				t.Errorf("transfer status = %s, want %s", transfer.Status(), tt.transfer)
+			}
+
+			decided, err := reviews.FindByID(ctx, pending[0].ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decided.Status() != tt.review {
+				t.Errorf("review status = %s, want %s", decided.Status(), tt.review)
+			}
+
+			entries, err := audit.List(ctx, entity.AuditFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actions []vo.AuditAction
+			for _, e := range entries {
+				actions = append(actions, e.Action())
+			}
+			sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+			sort.Slice(tt.audit, func(i, j int) bool { return tt.audit[i] < tt.audit[j] })
+			if !reflect.DeepEqual(actions, tt.audit) {
+				t.Errorf("audit = %v, want %v", actions, tt.audit)
+			}
+			for _, e := range entries {
+				if e.Action() != vo.TransferCreatedAction && (e.Actor() != "operator" || e.Detail() != "checked") {
+					t.Errorf("audit %s by %q with %q, want by the reviewer with the note", e.Action(), e.Actor(), e.Detail())
+				}
+			}
+		})
+	}
+}
+
+// newFlatPricing returns the pricing charging a flat fee on every transfer leg, credited to the account
+func newFlatPricing(t *testing.T, account vo.Uuid, fee int64) entity.Pricing {
+	t.Helper()
+
+	tier, err := entity.NewFeeTier(vo.Amount{}, vo.NewAmountTest(fee), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := entity.NewFeeRule("flat", 1, "", "", "", []entity.FeeTier{tier}, vo.Amount{}, vo.Amount{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pricing, err := entity.NewPricing(account, []entity.FeeRule{rule})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pricing
+}
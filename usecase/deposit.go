@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrProviderUnavailable is returned by the payment providers failing to answer
+var ErrProviderUnavailable = errors.New("payment provider unavailable")
+
+type (
+	// PaymentProvider port, the service moving the money of the deposits into
+	// the system and the money of the withdrawals out of it
+	PaymentProvider interface {
+		Deposit(context.Context, entity.Funding) (ProviderResult, error)
+		Withdraw(context.Context, entity.Funding) (ProviderResult, error)
+	}
+
+	// ProviderResult is the answer of the payment provider to a funding. A
+	// pending funding is settled or failed later through SettleFundingUseCase.
+	ProviderResult struct {
+		Reference string
+		Status    vo.FundingStatus
+		// Reason is why the provider failed the funding
+		Reason string
+	}
+
+	// Input port
+	DepositUseCase interface {
+		Execute(context.Context, DepositInput) (FundingOutput, error)
+	}
+
+	// Input data
+	DepositInput struct {
+		ID       vo.Uuid
+		UserID   vo.Uuid
+		Value    vo.Money
+		Method   vo.FundingMethod
+		Wallet   vo.WalletPurpose // vo.MainWallet when empty
+		CreateAt time.Time
+	}
+
+	// Output port
+	DepositPresenter interface {
+		Output(entity.Funding) FundingOutput
+	}
+
+	// Output data
+	FundingOutput struct {
+		ID        string `json:"id"`
+		UserID    string `json:"user_id"`
+		Type      string `json:"type"`
+		Method    string `json:"method"`
+		Status    string `json:"status"`
+		Currency  string `json:"currency"`
+		Wallet    string `json:"wallet"`
+		Value     int64  `json:"value"`
+		Reference string `json:"reference,omitempty"`
+		Reason    string `json:"reason,omitempty"`
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+	}
+
+	depositInteractor struct {
+		fundingSettler
+		repoFundingCreator entity.FundingRepositoryCreator
+		pre                DepositPresenter
+		provider           PaymentProvider
+	}
+)
+
+// NewDepositInteractor create new depositInteractor with its dependencies
+func NewDepositInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoFundingCreator entity.FundingRepositoryCreator,
+	repoFundingFinder entity.FundingRepositoryFinder,
+	repoFundingUpdater entity.FundingRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre DepositPresenter,
+	provider PaymentProvider,
+) DepositUseCase {
+	return depositInteractor{
+		fundingSettler: fundingSettler{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+				repoAuditCreator:    repoAuditCreator,
+			},
+			repoFundingFinder:  repoFundingFinder,
+			repoFundingUpdater: repoFundingUpdater,
+		},
+		repoFundingCreator: repoFundingCreator,
+		pre:                pre,
+		provider:           provider,
+	}
+}
+
+// Execute records the deposit as pending and asks the payment provider to
+// collect it. The wallet is credited once the provider settles the deposit,
+// which stays pending when the provider fails to answer.
+func (d depositInteractor) Execute(ctx context.Context, i DepositInput) (FundingOutput, error) {
+	ctx, span := tracer.Start(ctx, "DepositInteractor.Execute", trace.WithAttributes(
+		attribute.String("funding.id", i.ID.Value()),
+		attribute.String("funding.user_id", i.UserID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if i.Value.Amount().Value() <= 0 {
+		recordError(span, entity.ErrInvalidFundingValue)
+		return d.pre.Output(entity.Funding{}), entity.ErrInvalidFundingValue
+	}
+
+	funding := entity.NewFunding(i.ID, i.UserID, vo.Deposit, i.Method, i.Value, walletPurpose(i.Wallet), i.CreateAt)
+
+	// the wallet must exist to be credited once the deposit is settled
+	user, err := d.repoUserFinder.FindByID(ctx, funding.User())
+	if err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	if _, err := user.FindWallet(funding.Value().Currency(), funding.Wallet()); err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	if funding, err = d.repoFundingCreator.Create(ctx, funding); err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	result, err := d.provider.Deposit(ctx, funding)
+	if err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	if funding, err = d.resolve(ctx, span, funding.ID(), result, time.Now()); err != nil {
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	if funding.Status() == vo.FundingFailed {
+		err := fundingError(funding)
+		recordError(span, err)
+		return d.pre.Output(entity.Funding{}), err
+	}
+
+	return d.pre.Output(funding), nil
+}
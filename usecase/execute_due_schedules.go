@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	ExecuteDueSchedulesUseCase interface {
+		Execute(context.Context, ExecuteDueSchedulesInput) (ExecuteDueSchedulesOutput, error)
+	}
+
+	// Input data
+	ExecuteDueSchedulesInput struct {
+		At    time.Time
+		Limit int
+	}
+
+	// Output data
+	ExecuteDueSchedulesOutput struct {
+		// Executed occurrences created their transfer
+		Executed int
+		// Failed occurrences were skipped because the transfer was refused
+		Failed int
+		// Deferred occurrences are still due and will be retried
+		Deferred int
+	}
+
+	executeDueSchedulesInteractor struct {
+		repoScheduleFinder  entity.ScheduleRepositoryFinder
+		repoScheduleUpdater entity.ScheduleRepositoryUpdater
+		createTransfer      CreateTransferUseCase
+	}
+)
+
+// NewExecuteDueSchedulesInteractor create new executeDueSchedulesInteractor with its dependencies
+func NewExecuteDueSchedulesInteractor(
+	repoScheduleFinder entity.ScheduleRepositoryFinder,
+	repoScheduleUpdater entity.ScheduleRepositoryUpdater,
+	createTransfer CreateTransferUseCase,
+) ExecuteDueSchedulesUseCase {
+	return executeDueSchedulesInteractor{
+		repoScheduleFinder:  repoScheduleFinder,
+		repoScheduleUpdater: repoScheduleUpdater,
+		createTransfer:      createTransfer,
+	}
+}
+
+// Execute runs the current occurrence of the schedules due at i.At through
+// CreateTransferUseCase. The transfer ID is derived from the schedule and the
+// occurrence, so an occurrence executed twice only transfers once.
+func (e executeDueSchedulesInteractor) Execute(ctx context.Context, i ExecuteDueSchedulesInput) (ExecuteDueSchedulesOutput, error) {
+	ctx, span := tracer.Start(ctx, "ExecuteDueSchedulesInteractor.Execute")
+	defer span.End()
+
+	var output ExecuteDueSchedulesOutput
+
+	schedules, err := e.repoScheduleFinder.FindDue(ctx, i.At, i.Limit)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	var firstErr error
+	for _, schedule := range schedules {
+		_, err := e.createTransfer.Execute(ctx, CreateTransferInput{
+			ID:       occurrenceTransferID(schedule),
+			PayerID:  schedule.Payer(),
+			PayeeID:  schedule.Payee(),
+			Value:    schedule.Value(),
+			Type:     vo.ScheduledTransfer,
+			CreateAt: i.At,
+		})
+
+		switch {
+		case err == nil, errors.Is(err, entity.ErrTransferAlreadyExists):
+			schedule.Advance(nil, i.At)
+			output.Executed++
+		case isTransferRefusal(err):
+			schedule.Advance(err, i.At)
+			output.Failed++
+		default:
+			output.Deferred++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if err := e.repoScheduleUpdater.Update(ctx, schedule); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("schedules.executed", output.Executed),
+		attribute.Int("schedules.failed", output.Failed),
+		attribute.Int("schedules.deferred", output.Deferred),
+	)
+	if firstErr != nil {
+		span.AddEvent("schedule deferred", trace.WithAttributes(attribute.String("error", firstErr.Error())))
+	}
+
+	return output, firstErr
+}
+
+// occurrenceTransferID returns the ID of the transfer of the current occurrence of the schedule
+func occurrenceTransferID(s entity.Schedule) vo.Uuid {
+	namespace := uuid.MustParse(s.ID().Value())
+	id, _ := vo.NewUuid(uuid.NewSHA1(namespace, []byte(strconv.Itoa(s.Occurrences()))).String())
+
+	return id
+}
@@ -0,0 +1,112 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestExecuteDueSchedulesAuthorization(t *testing.T) {
+	deny := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return false, nil })
+	unavailable := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) {
+		return false, usecase.ErrAuthorizerUnavailable
+	})
+
+	tests := []struct {
+		name       string
+		authorizer usecase.Authorizer
+		err        error
+		output     usecase.ExecuteDueSchedulesOutput
+		status     vo.ScheduleStatus
+	}{
+		{
+			name:       "denied occurrence is advanced as refused",
+			authorizer: deny,
+			output:     usecase.ExecuteDueSchedulesOutput{Failed: 1},
+			status:     vo.ScheduleCompleted,
+		},
+		{
+			name:       "unavailable authorizer defers the occurrence",
+			authorizer: unavailable,
+			err:        usecase.ErrAuthorizerUnavailable,
+			output:     usecase.ExecuteDueSchedulesOutput{Deferred: 1},
+			status:     vo.ScheduleActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			schedules := database.NewScheduleInMen(db)
+
+			payer := newUser(ctx, t, users, 100)
+			payee := newUser(ctx, t, users, 0)
+
+			now := time.Now()
+			schedule, err := entity.NewSchedule(
+				newUuid(t),
+				payer.ID(),
+				payee.ID(),
+				vo.NewMoneyBRL(vo.NewAmountTest(10)),
+				vo.NewRecurrenceOnce(),
+				now.Add(-time.Minute),
+				now.Add(-2*time.Minute),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := schedules.Create(ctx, schedule); err != nil {
+				t.Fatal(err)
+			}
+
+			uc := usecase.NewExecuteDueSchedulesInteractor(schedules, schedules, newCreateTransfer(db, tt.authorizer))
+
+			output, err := uc.Execute(ctx, usecase.ExecuteDueSchedulesInput{At: now, Limit: 10})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+			if output != tt.output {
+				t.Errorf("Execute() = %+v, want %+v", output, tt.output)
+			}
+
+			got, err := schedules.FindByID(ctx, schedule.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status() != tt.status {
+				t.Errorf("schedule status = %s, want %s", got.Status(), tt.status)
+			}
+		})
+	}
+}
+
+// newCreateTransfer returns the transfers of the in-memory database, authorized by the authorizer
+func newCreateTransfer(db *database.InMemoryHandler, authorizer usecase.Authorizer) usecase.CreateTransferUseCase {
+	users := database.NewUserInMen(db)
+	transfers := database.NewTransferInMen(db)
+
+	return usecase.NewCreateTransferInteractor(
+		transfers,
+		transfers,
+		users,
+		users,
+		database.NewReviewInMen(db),
+		database.NewAuditInMen(db),
+		presenter.NewCreateTransferPresenter(),
+		authorizer,
+		nopNotifier{},
+		entity.Pricing{},
+		entity.LimitPolicy{},
+		entity.ReviewPolicy{},
+	)
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type (
+	// Input port
+	ExpirePaymentsUseCase interface {
+		Execute(context.Context, ExpirePaymentsInput) (ExpirePaymentsOutput, error)
+	}
+
+	// Input data
+	ExpirePaymentsInput struct {
+		At    time.Time
+		Limit int
+	}
+
+	// Output data
+	ExpirePaymentsOutput struct {
+		// Expired payments released their hold
+		Expired int
+		// Failed payments are still authorized and will be retried
+		Failed int
+	}
+
+	expirePaymentsInteractor struct {
+		paymentReleaser
+	}
+)
+
+// NewExpirePaymentsInteractor create new expirePaymentsInteractor with its dependencies
+func NewExpirePaymentsInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoPaymentFinder entity.PaymentRepositoryFinder,
+	repoPaymentUpdater entity.PaymentRepositoryUpdater,
+) ExpirePaymentsUseCase {
+	return expirePaymentsInteractor{
+		paymentReleaser: paymentReleaser{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+			},
+			repoPaymentFinder:  repoPaymentFinder,
+			repoPaymentUpdater: repoPaymentUpdater,
+		},
+	}
+}
+
+// Execute releases the holds of the payments expired at i.At. A payment
+// captured or voided meanwhile is skipped.
+func (e expirePaymentsInteractor) Execute(ctx context.Context, i ExpirePaymentsInput) (ExpirePaymentsOutput, error) {
+	ctx, span := tracer.Start(ctx, "ExpirePaymentsInteractor.Execute")
+	defer span.End()
+
+	var output ExpirePaymentsOutput
+
+	payments, err := e.repoPaymentFinder.FindExpired(ctx, i.At, i.Limit)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	var firstErr error
+	for _, payment := range payments {
+		_, err := e.release(ctx, span, payment.ID(), func(p *entity.Payment) error {
+			return p.Expire(i.At)
+		})
+		switch {
+		case err == nil:
+			output.Expired++
+		case errors.Is(err, entity.ErrPaymentStatusTransition):
+		default:
+			output.Failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("payment.expired", output.Expired),
+		attribute.Int("payment.failed", output.Failed),
+	)
+
+	if firstErr != nil {
+		recordError(span, firstErr)
+	}
+
+	return output, firstErr
+}
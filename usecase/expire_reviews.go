@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// timeoutReviewer is the reviewer of the reviews decided when they expire
+const timeoutReviewer = "system"
+
+type (
+	// Input port
+	ExpireReviewsUseCase interface {
+		Execute(context.Context, ExpireReviewsInput) (ExpireReviewsOutput, error)
+	}
+
+	// Input data
+	ExpireReviewsInput struct {
+		At    time.Time
+		Limit int
+	}
+
+	// Output data
+	ExpireReviewsOutput struct {
+		// Approved reviews credited their transfer to the payees
+		Approved int
+		// Rejected reviews refunded their transfer to the payer
+		Rejected int
+		// Failed reviews are still pending and will be retried
+		Failed int
+	}
+
+	expireReviewsInteractor struct {
+		reviewSettler
+		policy entity.ReviewPolicy
+	}
+)
+
+// NewExpireReviewsInteractor create new expireReviewsInteractor with its dependencies
+func NewExpireReviewsInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoTransferUpdater entity.TransferRepositoryUpdater,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoReviewFinder entity.ReviewRepositoryFinder,
+	repoReviewUpdater entity.ReviewRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	notifier Notifier,
+	pricing entity.Pricing,
+	policy entity.ReviewPolicy,
+) ExpireReviewsUseCase {
+	return expireReviewsInteractor{
+		reviewSettler: reviewSettler{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoTransferFinder:  repoTransferFinder,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+				pricing:             pricing,
+			},
+			repoTransferUpdater: repoTransferUpdater,
+			repoReviewFinder:    repoReviewFinder,
+			repoReviewUpdater:   repoReviewUpdater,
+			repoAuditCreator:    repoAuditCreator,
+			notifier:            notifier,
+		},
+		policy: policy,
+	}
+}
+
+// Execute decides the reviews expired at i.At as the policy says. A review
+// decided meanwhile by a reviewer is skipped.
+func (e expireReviewsInteractor) Execute(ctx context.Context, i ExpireReviewsInput) (ExpireReviewsOutput, error) {
+	ctx, span := tracer.Start(ctx, "ExpireReviewsInteractor.Execute", trace.WithAttributes(
+		attribute.String("review.on_timeout", e.policy.OnTimeout().String()),
+	))
+	defer span.End()
+
+	var output ExpireReviewsOutput
+
+	reviews, err := e.repoReviewFinder.FindExpired(ctx, i.At, i.Limit)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	var firstErr error
+	for _, review := range reviews {
+		_, err := e.settle(ctx, span, review.ID(), e.policy.OnTimeout(), timeoutReviewer, "review expired", i.At)
+		switch {
+		case err == nil && e.policy.OnTimeout() == vo.ReviewApproved:
+			output.Approved++
+		case err == nil:
+			output.Rejected++
+		case errors.Is(err, entity.ErrReviewStatusTransition):
+		default:
+			output.Failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("review.approved", output.Approved),
+		attribute.Int("review.rejected", output.Rejected),
+		attribute.Int("review.failed", output.Failed),
+	)
+
+	if firstErr != nil {
+		recordError(span, firstErr)
+	}
+
+	return output, firstErr
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	FindBatchTransferUseCase interface {
+		Execute(context.Context, FindBatchTransferInput) (BatchTransferOutput, error)
+	}
+
+	// Input data
+	FindBatchTransferInput struct {
+		ID vo.Uuid
+	}
+
+	// Output port
+	FindBatchTransferPresenter interface {
+		Output(entity.Batch) BatchTransferOutput
+	}
+
+	findBatchTransferInteractor struct {
+		repoBatchFinder entity.BatchRepositoryFinder
+		pre             FindBatchTransferPresenter
+	}
+)
+
+// NewFindBatchTransferInteractor create new findBatchTransferInteractor with its dependencies
+func NewFindBatchTransferInteractor(
+	repoBatchFinder entity.BatchRepositoryFinder,
+	pre FindBatchTransferPresenter,
+) FindBatchTransferUseCase {
+	return findBatchTransferInteractor{
+		repoBatchFinder: repoBatchFinder,
+		pre:             pre,
+	}
+}
+
+// Execute orchestrate the use case
+func (f findBatchTransferInteractor) Execute(ctx context.Context, i FindBatchTransferInput) (BatchTransferOutput, error) {
+	ctx, span := tracer.Start(ctx, "FindBatchTransferInteractor.Execute", trace.WithAttributes(
+		attribute.String("batch.id", i.ID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	batch, err := f.repoBatchFinder.FindByID(ctx, i.ID)
+	if err != nil {
+		recordError(span, err)
+		return f.pre.Output(entity.Batch{}), err
+	}
+
+	return f.pre.Output(batch), nil
+}
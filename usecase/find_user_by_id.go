@@ -6,6 +6,8 @@ import (
 
 	"github.com/dungnguyen/clean-architecture/domain/entity"
 	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
@@ -31,6 +33,7 @@ type (
 		Email     string                     `json:"email"`
 		Document  FindUserByIDDocumentOutput `json:"document"`
 		Wallet    FindUserByIDWalletOutput   `json:"wallet"`
+		Wallets   []FindUserByIDWalletOutput `json:"wallets"`
 		Roles     FindUserByIDRolesOutput    `json:"roles"`
 		Type      string                     `json:"string"`
 		CreatedAt string                     `json:"created_at"`
@@ -42,10 +45,14 @@ type (
 		Value string `json:"value"`
 	}
 
-	// Output data
+	// Output data. Amount is the ledger balance, Available the part of it
+	// which is not held by the authorized payments.
 	FindUserByIDWalletOutput struct {
-		Currency string `json:"currency"`
-		Amount   int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Purpose   string `json:"purpose"`
+		Amount    int64  `json:"amount"`
+		Available int64  `json:"available"`
+		Held      int64  `json:"held"`
 	}
 
 	// Output data
@@ -69,11 +76,17 @@ func NewFindUserByIDInteractor(repo entity.UserRepositoryFinder, pre FindUserByI
 
 // Execute orchestrate the use case
 func (f findUserByIDInteractor) Execute(ctx context.Context, i FindUserByIDInput) (FindUserByIDOutput, error) {
+	ctx, span := tracer.Start(ctx, "FindUserByIDInteractor.Execute", trace.WithAttributes(
+		attribute.String("user.id", i.ID.Value()),
+	))
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user, err := f.repo.FindByID(ctx, i.ID)
 	if err != nil {
+		recordError(span, err)
 		return f.pre.Output(entity.User{}), err
 	}
 
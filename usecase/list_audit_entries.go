@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultAuditEntriesLimit is the number of audit entries listed when the input has no limit
+const defaultAuditEntriesLimit = 100
+
+type (
+	// Input port
+	ListAuditEntriesUseCase interface {
+		Execute(context.Context, ListAuditEntriesInput) ([]AuditEntryOutput, error)
+	}
+
+	// Input data
+	ListAuditEntriesInput struct {
+		Actor         string
+		Action        vo.AuditAction
+		Target        vo.AuditTarget
+		SubjectID     vo.Uuid
+		CorrelationID string
+		From          time.Time
+		To            time.Time
+		Offset        int
+		Limit         int
+	}
+
+	// Output port
+	ListAuditEntriesPresenter interface {
+		Output([]entity.AuditEntry) []AuditEntryOutput
+	}
+
+	// Output data. Before and After are the snapshots of the subject, absent
+	// when not recorded.
+	AuditEntryOutput struct {
+		ID            string          `json:"id"`
+		Sequence      int64           `json:"sequence,omitempty"`
+		Action        string          `json:"action"`
+		Actor         string          `json:"actor"`
+		Target        string          `json:"target,omitempty"`
+		SubjectID     string          `json:"subject_id"`
+		Detail        string          `json:"detail,omitempty"`
+		Before        json.RawMessage `json:"before,omitempty"`
+		After         json.RawMessage `json:"after,omitempty"`
+		CorrelationID string          `json:"correlation_id,omitempty"`
+		IP            string          `json:"ip,omitempty"`
+		UserAgent     string          `json:"user_agent,omitempty"`
+		CreatedAt     string          `json:"created_at"`
+		PrevHash      string          `json:"prev_hash,omitempty"`
+		Hash          string          `json:"hash,omitempty"`
+	}
+
+	listAuditEntriesInteractor struct {
+		repo entity.AuditRepositoryLister
+		pre  ListAuditEntriesPresenter
+	}
+)
+
+// NewListAuditEntriesInteractor create new listAuditEntriesInteractor with its dependencies
+func NewListAuditEntriesInteractor(repo entity.AuditRepositoryLister, pre ListAuditEntriesPresenter) ListAuditEntriesUseCase {
+	return listAuditEntriesInteractor{
+		repo: repo,
+		pre:  pre,
+	}
+}
+
+// Execute lists a page of the audit entries matching the input, in the order they were appended
+func (l listAuditEntriesInteractor) Execute(ctx context.Context, i ListAuditEntriesInput) ([]AuditEntryOutput, error) {
+	if i.Limit <= 0 {
+		i.Limit = defaultAuditEntriesLimit
+	}
+
+	ctx, span := tracer.Start(ctx, "ListAuditEntriesInteractor.Execute", trace.WithAttributes(
+		attribute.Int("audit.offset", i.Offset),
+		attribute.Int("audit.limit", i.Limit),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entries, err := l.repo.List(ctx, entity.AuditFilter{
+		Actor:         i.Actor,
+		Action:        i.Action,
+		Target:        i.Target,
+		SubjectID:     i.SubjectID,
+		CorrelationID: i.CorrelationID,
+		From:          i.From,
+		To:            i.To,
+		Offset:        i.Offset,
+		Limit:         i.Limit,
+	})
+	if err != nil {
+		recordError(span, err)
+		return l.pre.Output(nil), err
+	}
+
+	return l.pre.Output(entries), nil
+}
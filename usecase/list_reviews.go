@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultReviewsLimit is the number of reviews listed when the input has no limit
+const defaultReviewsLimit = 100
+
+type (
+	// Input port
+	ListReviewsUseCase interface {
+		Execute(context.Context, ListReviewsInput) ([]ReviewOutput, error)
+	}
+
+	// Input data
+	ListReviewsInput struct {
+		Status vo.ReviewStatus // vo.ReviewPending when empty
+		Limit  int
+	}
+
+	// Output port
+	ListReviewsPresenter interface {
+		Output([]entity.Review) []ReviewOutput
+	}
+
+	// Output data
+	ReviewOutput struct {
+		ID         string `json:"id"`
+		TransferID string `json:"transfer_id"`
+		PayerID    string `json:"payer"`
+		Value      int64  `json:"value"`
+		Reason     string `json:"reason"`
+		Status     string `json:"status"`
+		Reviewer   string `json:"reviewer,omitempty"`
+		Note       string `json:"note,omitempty"`
+		CreatedAt  string `json:"created_at"`
+		ExpiresAt  string `json:"expires_at"`
+		DecidedAt  string `json:"decided_at,omitempty"`
+	}
+
+	listReviewsInteractor struct {
+		repo entity.ReviewRepositoryFinder
+		pre  ListReviewsPresenter
+	}
+)
+
+// NewListReviewsInteractor create new listReviewsInteractor with its dependencies
+func NewListReviewsInteractor(repo entity.ReviewRepositoryFinder, pre ListReviewsPresenter) ListReviewsUseCase {
+	return listReviewsInteractor{
+		repo: repo,
+		pre:  pre,
+	}
+}
+
+// Execute orchestrate the use case
+func (l listReviewsInteractor) Execute(ctx context.Context, i ListReviewsInput) ([]ReviewOutput, error) {
+	if i.Status == "" {
+		i.Status = vo.ReviewPending
+	}
+	if i.Limit <= 0 {
+		i.Limit = defaultReviewsLimit
+	}
+
+	ctx, span := tracer.Start(ctx, "ListReviewsInteractor.Execute", trace.WithAttributes(
+		attribute.String("review.status", i.Status.String()),
+		attribute.Int("review.limit", i.Limit),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	reviews, err := l.repo.FindByStatus(ctx, i.Status, i.Limit)
+	if err != nil {
+		recordError(span, err)
+		return l.pre.Output(nil), err
+	}
+
+	return l.pre.Output(reviews), nil
+}
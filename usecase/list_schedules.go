@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	ListSchedulesUseCase interface {
+		Execute(context.Context, ListSchedulesInput) ([]ScheduleOutput, error)
+	}
+
+	// Input data
+	ListSchedulesInput struct {
+		PayerID vo.Uuid
+	}
+
+	// Output port
+	ListSchedulesPresenter interface {
+		Output([]entity.Schedule) []ScheduleOutput
+	}
+
+	listSchedulesInteractor struct {
+		repo entity.ScheduleRepositoryFinder
+		pre  ListSchedulesPresenter
+	}
+)
+
+// NewListSchedulesInteractor create new listSchedulesInteractor with its dependencies
+func NewListSchedulesInteractor(repo entity.ScheduleRepositoryFinder, pre ListSchedulesPresenter) ListSchedulesUseCase {
+	return listSchedulesInteractor{
+		repo: repo,
+		pre:  pre,
+	}
+}
+
+// Execute orchestrate the use case
+func (l listSchedulesInteractor) Execute(ctx context.Context, i ListSchedulesInput) ([]ScheduleOutput, error) {
+	ctx, span := tracer.Start(ctx, "ListSchedulesInteractor.Execute", trace.WithAttributes(
+		attribute.String("schedule.payer_id", i.PayerID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	schedules, err := l.repo.FindByPayer(ctx, i.PayerID)
+	if err != nil {
+		recordError(span, err)
+		return l.pre.Output(nil), err
+	}
+
+	return l.pre.Output(schedules), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultTransfersLimit is the number of transfers listed when the input has no limit
+const defaultTransfersLimit = 100
+
+type (
+	// Input port
+	ListTransfersUseCase interface {
+		Execute(context.Context, ListTransfersInput) ([]CreateTransferOutput, error)
+	}
+
+	// Input data. The transfers of every user are listed when UserID is
+	// empty, the ones created at any time when From and To are zero.
+	ListTransfersInput struct {
+		UserID vo.Uuid
+		From   time.Time
+		To     time.Time
+		Offset int
+		Limit  int
+	}
+
+	// Output port
+	ListTransfersPresenter interface {
+		Output([]entity.Transfer) []CreateTransferOutput
+	}
+
+	listTransfersInteractor struct {
+		repo entity.TransferRepositoryLister
+		pre  ListTransfersPresenter
+	}
+)
+
+// NewListTransfersInteractor create new listTransfersInteractor with its dependencies
+func NewListTransfersInteractor(repo entity.TransferRepositoryLister, pre ListTransfersPresenter) ListTransfersUseCase {
+	return listTransfersInteractor{
+		repo: repo,
+		pre:  pre,
+	}
+}
+
+// Execute lists a page of the transfers made or received by the user, the oldest first
+func (l listTransfersInteractor) Execute(ctx context.Context, i ListTransfersInput) ([]CreateTransferOutput, error) {
+	if i.Limit <= 0 {
+		i.Limit = defaultTransfersLimit
+	}
+
+	ctx, span := tracer.Start(ctx, "ListTransfersInteractor.Execute", trace.WithAttributes(
+		attribute.String("transfer.user_id", i.UserID.Value()),
+		attribute.Int("transfer.offset", i.Offset),
+		attribute.Int("transfer.limit", i.Limit),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	transfers, err := l.repo.List(ctx, entity.TransferFilter{
+		UserID: i.UserID,
+		From:   i.From,
+		To:     i.To,
+		Offset: i.Offset,
+		Limit:  i.Limit,
+	})
+	if err != nil {
+		recordError(span, err)
+		return l.pre.Output(nil), err
+	}
+
+	return l.pre.Output(transfers), nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultUsersLimit is the number of users listed when the input has no limit
+const defaultUsersLimit = 100
+
+type (
+	// Input port
+	ListUsersUseCase interface {
+		Execute(context.Context, ListUsersInput) ([]FindUserByIDOutput, error)
+	}
+
+	// Input data
+	ListUsersInput struct {
+		Type   vo.TypeUser
+		From   time.Time
+		To     time.Time
+		Offset int
+		Limit  int
+	}
+
+	// Output port
+	ListUsersPresenter interface {
+		Output([]entity.User) []FindUserByIDOutput
+	}
+
+	listUsersInteractor struct {
+		repo entity.UserRepositoryLister
+		pre  ListUsersPresenter
+	}
+)
+
+// NewListUsersInteractor create new listUsersInteractor with its dependencies
+func NewListUsersInteractor(repo entity.UserRepositoryLister, pre ListUsersPresenter) ListUsersUseCase {
+	return listUsersInteractor{
+		repo: repo,
+		pre:  pre,
+	}
+}
+
+// Execute lists a page of the users of the type created in [From, To), the oldest first
+func (l listUsersInteractor) Execute(ctx context.Context, i ListUsersInput) ([]FindUserByIDOutput, error) {
+	if i.Limit <= 0 {
+		i.Limit = defaultUsersLimit
+	}
+
+	ctx, span := tracer.Start(ctx, "ListUsersInteractor.Execute", trace.WithAttributes(
+		attribute.Int("user.offset", i.Offset),
+		attribute.Int("user.limit", i.Limit),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	users, err := l.repo.List(ctx, entity.UserFilter{
+		Type:   i.Type,
+		From:   i.From,
+		To:     i.To,
+		Offset: i.Offset,
+		Limit:  i.Limit,
+	})
+	if err != nil {
+		recordError(span, err)
+		return l.pre.Output(nil), err
+	}
+
+	return l.pre.Output(users), nil
+}
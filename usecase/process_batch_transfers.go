@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// atomicBatchTimeout bounds the transaction executing every transfer of an atomic batch
+const atomicBatchTimeout = time.Minute
+
+type (
+	// Input port
+	ProcessBatchTransfersUseCase interface {
+		Execute(context.Context, ProcessBatchTransfersInput) (ProcessBatchTransfersOutput, error)
+	}
+
+	// Input data
+	ProcessBatchTransfersInput struct {
+		Limit int
+	}
+
+	// Output data
+	ProcessBatchTransfersOutput struct {
+		// Finished batches have the result of every transfer
+		Finished int
+		// Deferred batches were interrupted and will be resumed
+		Deferred int
+	}
+
+	processBatchTransfersInteractor struct {
+		transferExecutor
+		repoBatchFinder  entity.BatchRepositoryFinder
+		repoBatchUpdater entity.BatchRepositoryUpdater
+		notifier         Notifier
+	}
+)
+
+// NewProcessBatchTransfersInteractor create new processBatchTransfersInteractor with its dependencies
+func NewProcessBatchTransfersInteractor(
+	repoBatchFinder entity.BatchRepositoryFinder,
+	repoBatchUpdater entity.BatchRepositoryUpdater,
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	authorizer Authorizer,
+	notifier Notifier,
+	pricing entity.Pricing,
+	limits entity.LimitPolicy,
+) ProcessBatchTransfersUseCase {
+	return processBatchTransfersInteractor{
+		transferExecutor: transferExecutor{
+			repoTransferCreator: repoTransferCreator,
+			repoTransferFinder:  repoTransferFinder,
+			repoUserUpdater:     repoUserUpdater,
+			repoUserFinder:      repoUserFinder,
+			repoAuditCreator:    repoAuditCreator,
+			authorizer:          authorizer,
+			pricing:             pricing,
+			limits:              limits,
+		},
+		repoBatchFinder:  repoBatchFinder,
+		repoBatchUpdater: repoBatchUpdater,
+		notifier:         notifier,
+	}
+}
+
+// Execute processes the unfinished batches, oldest first. The transfer IDs are
+// fixed when the batch is created, so a batch resumed after an interruption
+// never transfers twice.
+func (p processBatchTransfersInteractor) Execute(ctx context.Context, i ProcessBatchTransfersInput) (ProcessBatchTransfersOutput, error) {
+	ctx, span := tracer.Start(ctx, "ProcessBatchTransfersInteractor.Execute")
+	defer span.End()
+
+	var output ProcessBatchTransfersOutput
+
+	batches, err := p.repoBatchFinder.FindUnfinished(ctx, i.Limit)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	var firstErr error
+	for _, batch := range batches {
+		if err := p.process(ctx, span, batch); err != nil {
+			output.Deferred++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		output.Finished++
+	}
+
+	span.SetAttributes(
+		attribute.Int("batches.finished", output.Finished),
+		attribute.Int("batches.deferred", output.Deferred),
+	)
+	if firstErr != nil {
+		span.AddEvent("batch deferred", trace.WithAttributes(attribute.String("error", firstErr.Error())))
+	}
+
+	return output, firstErr
+}
+
+func (p processBatchTransfersInteractor) process(ctx context.Context, span trace.Span, batch entity.Batch) error {
+	if err := batch.Start(time.Now()); err != nil {
+		return err
+	}
+
+	if err := p.repoBatchUpdater.Update(ctx, batch); err != nil {
+		return err
+	}
+
+	if batch.Mode() == vo.BatchAtomic {
+		return p.processAtomic(ctx, span, batch)
+	}
+
+	return p.processBestEffort(ctx, span, batch)
+}
+
+// processAtomic executes every transfer in a single transaction, which also
+// saves the result of the batch. The transfers are authorized before it, the
+// first refused transfer rolls back the others.
+func (p processBatchTransfersInteractor) processAtomic(ctx context.Context, span trace.Span, batch entity.Batch) error {
+	ctx, cancel := context.WithTimeout(ctx, atomicBatchTimeout)
+	defer cancel()
+
+	var transfers []entity.Transfer
+
+	pending, failed, err := p.authorizeAll(ctx, batch)
+	if err == nil {
+		err = p.retry(ctx, span, func() error {
+			transfers, failed = nil, -1
+
+			return p.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+				for idx, t := range pending {
+					transfer, err := p.move(sessCtx, t)
+					if err != nil {
+						failed = idx
+						return err
+					}
+					transfers = append(transfers, transfer)
+				}
+
+				now := time.Now()
+				for idx := range pending {
+					batch.Succeed(idx, now)
+				}
+				batch.Finish(now)
+
+				return p.repoBatchUpdater.Update(sessCtx, batch)
+			})
+		})
+	}
+
+	switch {
+	case err == nil:
+		for _, transfer := range transfers {
+			p.notifier.Notify(ctx, transfer)
+		}
+		return nil
+	case isTransferRefusal(err), errors.Is(err, entity.ErrTransferAlreadyExists):
+		batch.Rollback(failed, err, time.Now())
+		return p.repoBatchUpdater.Update(ctx, batch)
+	default:
+		return err
+	}
+}
+
+// authorizeAll returns the transfers of the items once all of them are
+// authorized, the index of the first one that is not otherwise
+func (p processBatchTransfersInteractor) authorizeAll(ctx context.Context, batch entity.Batch) ([]entity.Transfer, int, error) {
+	items := batch.Items()
+	transfers := make([]entity.Transfer, 0, len(items))
+
+	for idx, item := range items {
+		t := batchTransfer(batch, item)
+		if err := p.authorize(ctx, t); err != nil {
+			return nil, idx, err
+		}
+		transfers = append(transfers, t)
+	}
+
+	return transfers, -1, nil
+}
+
+// processBestEffort executes the pending transfers one by one and saves the
+// result of each. A transfer failing for another reason than a refusal stops
+// the processing, it is retried when the batch is resumed.
+func (p processBatchTransfersInteractor) processBestEffort(ctx context.Context, span trace.Span, batch entity.Batch) error {
+	for idx, item := range batch.Items() {
+		if item.Status() != vo.BatchItemPending {
+			continue
+		}
+
+		transfer, err := p.transferItem(ctx, span, batchTransfer(batch, item))
+		switch {
+		case err == nil:
+			batch.Succeed(idx, time.Now())
+			p.notifier.Notify(ctx, transfer)
+		case errors.Is(err, entity.ErrTransferAlreadyExists):
+			batch.Succeed(idx, time.Now())
+		case isTransferRefusal(err):
+			batch.Fail(idx, err, time.Now())
+		default:
+			return err
+		}
+
+		if err := p.repoBatchUpdater.UpdateItem(ctx, batch, idx); err != nil {
+			return err
+		}
+	}
+
+	batch.Finish(time.Now())
+
+	return p.repoBatchUpdater.Update(ctx, batch)
+}
+
+// transferItem executes a transfer of a best-effort batch as CreateTransferUseCase does
+func (p processBatchTransfersInteractor) transferItem(ctx context.Context, span trace.Span, t entity.Transfer) (entity.Transfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := p.authorize(ctx, t); err != nil {
+		return entity.Transfer{}, err
+	}
+
+	var transfer entity.Transfer
+	err := p.retry(ctx, span, func() error {
+		var err error
+		transfer, err = p.transfer(ctx, t)
+		return err
+	})
+
+	return transfer, err
+}
+
+func batchTransfer(batch entity.Batch, item entity.BatchItem) entity.Transfer {
+	return entity.NewTransfer(item.TransferID(), batch.Payer(), item.Payee(), item.Value(), time.Now()).
+		WithType(vo.BatchTransfer)
+}
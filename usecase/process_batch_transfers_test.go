@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestProcessBatchTransfersDenied(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    vo.BatchMode
+		status  vo.BatchStatus
+		items   []vo.BatchItemStatus
+		balance int64
+	}{
+		{
+			name:    "best effort batch goes on after the denied item",
+			mode:    vo.BatchBestEffort,
+			status:  vo.BatchPartiallyCompleted,
+			items:   []vo.BatchItemStatus{vo.BatchItemSucceeded, vo.BatchItemFailed},
+			balance: 90,
+		},
+		{
+			name:    "atomic batch is rejected for the denied item",
+			mode:    vo.BatchAtomic,
+			status:  vo.BatchFailed,
+			items:   []vo.BatchItemStatus{vo.BatchItemSkipped, vo.BatchItemFailed},
+			balance: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			transfers := database.NewTransferInMen(db)
+			batches := database.NewBatchInMen(db)
+
+			payer := newUser(ctx, t, users, 100)
+			allowed := newUser(ctx, t, users, 0)
+			denied := newUser(ctx, t, users, 0)
+
+			batch, err := entity.NewBatch(newUuid(t), payer.ID(), tt.mode, []entity.BatchItem{
+				entity.NewBatchItem(newUuid(t), allowed.ID(), vo.NewMoneyBRL(vo.NewAmountTest(10))),
+				entity.NewBatchItem(newUuid(t), denied.ID(), vo.NewMoneyBRL(vo.NewAmountTest(20))),
+			}, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := batches.Create(ctx, batch); err != nil {
+				t.Fatal(err)
+			}
+
+			// the authorizer denies the transfers to one of the payees
+			authorizer := authorizerFunc(func(_ context.Context, transfer entity.Transfer) (bool, error) {
+				return !transfer.Payee().Equals(denied.ID()), nil
+			})
+
+			uc := usecase.NewProcessBatchTransfersInteractor(
+				batches,
+				batches,
+				transfers,
+				transfers,
+				users,
+				users,
+				database.NewAuditInMen(db),
+				authorizer,
+				nopNotifier{},
+				entity.Pricing{},
+				entity.LimitPolicy{},
+			)
+
+			output, err := uc.Execute(ctx, usecase.ProcessBatchTransfersInput{Limit: 10})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if want := (usecase.ProcessBatchTransfersOutput{Finished: 1}); output != want {
+				t.Errorf("Execute() = %+v, want %+v", output, want)
+			}
+
+			got, err := batches.FindByID(ctx, batch.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status() != tt.status {
+				t.Errorf("batch status = %s, want %s", got.Status(), tt.status)
+			}
+			for i, item := range got.Items() {
+				if item.Status() != tt.items[i] {
+					t.Errorf("item %d status = %s, want %s", i, item.Status(), tt.items[i])
+				}
+			}
+
+			stored, err := users.FindByID(ctx, payer.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if balance := stored.Wallet().Money().Amount().Value(); balance != tt.balance {
+				t.Errorf("payer balance = %d, want %d", balance, tt.balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,910 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// reconcilePageSize is the number of entities read at once while reconciling
+	reconcilePageSize = 100
+	// reconcileLockTTL is how long a run holds the lock of the reconciliation,
+	// the run being cancelled when it lasts longer
+	reconcileLockTTL = time.Hour
+)
+
+// ErrDayReconciled is returned when the day was already reconciled up to its checkpoint
+var ErrDayReconciled = errors.New("day already reconciled")
+
+const (
+	// BalanceMismatch is a wallet whose stored balance is not the sum of its movements
+	BalanceMismatch ReconciliationIssue = "BALANCE_MISMATCH"
+	// OrphanTransfer is a transfer whose payer or payee does not exist
+	OrphanTransfer ReconciliationIssue = "ORPHAN_TRANSFER"
+	// OrphanFunding is a funding whose user does not exist
+	OrphanFunding ReconciliationIssue = "ORPHAN_FUNDING"
+	// MissingCounterpart is a wallet moved which does not exist, or a held
+	// transfer without the pending review crediting or refunding it
+	MissingCounterpart ReconciliationIssue = "MISSING_COUNTERPART"
+)
+
+// ReconciliationIssues are the issues found by the reconciliation
+var ReconciliationIssues = []ReconciliationIssue{BalanceMismatch, OrphanTransfer, OrphanFunding, MissingCounterpart}
+
+type (
+	// ReconciliationIssue define the discrepancies found by the reconciliation
+	ReconciliationIssue string
+
+	// Input port
+	ReconcileUseCase interface {
+		Execute(context.Context, ReconcileInput) (ReconcileOutput, error)
+	}
+
+	// Input data
+	ReconcileInput struct {
+		// Day is the UTC day reconciled
+		Day time.Time
+		At  time.Time
+		// Checkpoint saves the balances of the wallets at the end of the day,
+		// the next reconciliations starting from them
+		Checkpoint bool
+	}
+
+	// Output data
+	ReconcileOutput struct {
+		Day          string    `json:"day"`
+		ReconciledAt time.Time `json:"reconciled_at"`
+		// Wallets is the number of wallets whose balance was compared
+		Wallets int `json:"wallets"`
+		// Moving wallets were moved while they were reconciled, their balance is not compared
+		Moving int `json:"moving"`
+		// Baselined wallets had their opening balance snapshotted from their
+		// stored one, their user being created before the audit log
+		Baselined int `json:"baselined"`
+		Transfers int `json:"transfers"`
+		Fundings  int `json:"fundings"`
+		// Balances are the wallets moved during the day or mismatching
+		Balances      []WalletBalanceOutput `json:"balances"`
+		Discrepancies []DiscrepancyOutput   `json:"discrepancies"`
+	}
+
+	// WalletBalanceOutput is the balance of a wallet as its movements compute
+	// it: Opening before the day, Closing at its end and Expected now, to be
+	// equal to the Actual balance stored
+	WalletBalanceOutput struct {
+		UserID   string `json:"user_id"`
+		Currency string `json:"currency"`
+		Purpose  string `json:"purpose"`
+		Opening  int64  `json:"opening"`
+		Credits  int64  `json:"credits"`
+		Debits   int64  `json:"debits"`
+		Closing  int64  `json:"closing"`
+		Expected int64  `json:"expected"`
+		Actual   int64  `json:"actual"`
+	}
+
+	// DiscrepancyOutput is an issue found by the reconciliation. Difference
+	// is the stored balance less the expected one.
+	DiscrepancyOutput struct {
+		Issue      ReconciliationIssue `json:"issue"`
+		UserID     string              `json:"user_id,omitempty"`
+		Currency   string              `json:"currency,omitempty"`
+		Purpose    string              `json:"purpose,omitempty"`
+		TransferID string              `json:"transfer_id,omitempty"`
+		FundingID  string              `json:"funding_id,omitempty"`
+		Difference int64               `json:"difference,omitempty"`
+		Detail     string              `json:"detail"`
+	}
+
+	reconcileInteractor struct {
+		repoUserLister     entity.UserRepositoryLister
+		repoTransferFinder entity.TransferRepositoryFinder
+		repoTransferLister entity.TransferRepositoryLister
+		repoFundingLister  entity.FundingRepositoryLister
+		repoAuditLister    entity.AuditRepositoryLister
+		repoReviewFinder   entity.ReviewRepositoryFinder
+		repoReconciliation entity.ReconciliationRepository
+		pricing            entity.Pricing
+	}
+
+	// transferDecision is the decision of the review of a held transfer, read from the audit log
+	transferDecision struct {
+		transfer entity.Transfer
+		approved bool
+		at       time.Time
+	}
+
+	// walletKey identifies a wallet among the ones of every user
+	walletKey struct {
+		user     string
+		currency string
+		purpose  string
+	}
+
+	// ledger sums the movements of the wallets: before, during and after the
+	// day. The movements of a wallet made before its baseline, or before the
+	// checkpoint for a wallet without one, are left out.
+	ledger struct {
+		from       time.Time
+		to         time.Time
+		checkpoint time.Time
+		wallets    map[walletKey]*walletMovements
+		since      map[walletKey]time.Time
+		// known are the users with a baseline or created in the audit log
+		known map[string]bool
+	}
+
+	walletMovements struct {
+		opening int64
+		credits int64
+		debits  int64
+		later   int64
+	}
+)
+
+// NewReconcileInteractor create new reconcileInteractor with its dependencies
+func NewReconcileInteractor(
+	repoUserLister entity.UserRepositoryLister,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoTransferLister entity.TransferRepositoryLister,
+	repoFundingLister entity.FundingRepositoryLister,
+	repoAuditLister entity.AuditRepositoryLister,
+	repoReviewFinder entity.ReviewRepositoryFinder,
+	repoReconciliation entity.ReconciliationRepository,
+	pricing entity.Pricing,
+) ReconcileUseCase {
+	return reconcileInteractor{
+		repoUserLister:     repoUserLister,
+		repoTransferFinder: repoTransferFinder,
+		repoTransferLister: repoTransferLister,
+		repoFundingLister:  repoFundingLister,
+		repoAuditLister:    repoAuditLister,
+		repoReviewFinder:   repoReviewFinder,
+		repoReconciliation: repoReconciliation,
+		pricing:            pricing,
+	}
+}
+
+// Execute recomputes the balance of every wallet from its baseline, the
+// transfers, the fundings, the adjustments and the initial balances of the
+// users, and compares it to the stored one. Only the movements made since
+// the latest checkpoint before the day are read, the wallets starting from
+// their balance at it. The wallets of the users created before the audit log
+// are baselined at the start of the day the first time they are reconciled.
+// The transfers and the fundings of the day are checked for the users they
+// miss, the held transfers read for their review. The movements are dated
+// when their transfer or funding was created or resolved. A single run
+// reconciles at once, ErrReconciliationLocked being returned to the others.
+func (r reconcileInteractor) Execute(ctx context.Context, i ReconcileInput) (ReconcileOutput, error) {
+	from := i.Day.UTC().Truncate(24 * time.Hour)
+	to := from.Add(24 * time.Hour)
+
+	ctx, span := tracer.Start(ctx, "ReconcileInteractor.Execute", trace.WithAttributes(
+		attribute.String("reconciliation.day", from.Format("2006-01-02")),
+	))
+	defer span.End()
+
+	output := ReconcileOutput{
+		Day:           from.Format("2006-01-02"),
+		ReconciledAt:  i.At,
+		Balances:      []WalletBalanceOutput{},
+		Discrepancies: []DiscrepancyOutput{},
+	}
+
+	owner := uuid.New().String()
+	if err := r.repoReconciliation.Lock(ctx, owner, i.At, i.At.Add(reconcileLockTTL)); err != nil {
+		recordError(span, err)
+		return output, err
+	}
+	defer func() {
+		// the lock is released even when ctx ended, not to wait for it to expire
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = r.repoReconciliation.Unlock(ctx, owner)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileLockTTL)
+	defer cancel()
+
+	if i.Checkpoint {
+		reconciled, err := r.repoReconciliation.Checkpoint(ctx, to)
+		if err != nil {
+			recordError(span, err)
+			return output, err
+		}
+		if reconciled.Equal(to) {
+			return output, ErrDayReconciled
+		}
+	}
+
+	since, err := r.repoReconciliation.Checkpoint(ctx, from)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	baselines, err := r.repoReconciliation.Baselines(ctx, from)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	l := newLedger(from, to, since, baselines)
+
+	// the balances are read before and after the movements, a wallet moved
+	// meanwhile being skipped: its movement may be read without its balance
+	before, err := r.balances(ctx)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	// the decisions are read after the transfers, the ones of the transfers
+	// decided while they were listed being read
+	listed, err := r.transfers(ctx, since)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	decisions, err := r.decisions(ctx, since)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	transfers, held := r.settle(&l, listed, decisions)
+
+	fundings, err := r.fundings(ctx, &l, since)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	if err := r.adjustments(ctx, &l, since); err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	after, err := r.balances(ctx)
+	if err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	seeded := l.seed(before, after)
+	if err := r.repoReconciliation.SaveBaselines(ctx, seeded); err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	output.Baselined = len(seeded)
+	output.Transfers = len(transfers)
+	output.Fundings = len(fundings)
+	r.compare(&output, l, before, after)
+	r.checkTransfers(&output, transfers, after)
+	r.checkFundings(&output, fundings, after)
+
+	if err := r.checkHeld(ctx, &output, held); err != nil {
+		recordError(span, err)
+		return output, err
+	}
+
+	// a day not ended yet may still be moved
+	if i.Checkpoint && !to.After(i.At) {
+		if err := r.repoReconciliation.SaveCheckpoint(ctx, to, l.closings(after)); err != nil {
+			recordError(span, err)
+			return output, err
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("reconciliation.wallets", output.Wallets),
+		attribute.Int("reconciliation.moving", output.Moving),
+		attribute.Int("reconciliation.baselined", output.Baselined),
+		attribute.Int("reconciliation.discrepancies", len(output.Discrepancies)),
+	)
+
+	return output, nil
+}
+
+// balances returns the stored balance of every wallet, the users being the
+// ones without wallet included
+func (r reconcileInteractor) balances(ctx context.Context) (map[walletKey]int64, error) {
+	balances := map[walletKey]int64{}
+	for offset := 0; ; offset += reconcilePageSize {
+		users, err := r.repoUserLister.List(ctx, entity.UserFilter{Offset: offset, Limit: reconcilePageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			balances[walletKey{user: user.ID().Value()}] = 0
+			for _, w := range user.Wallets() {
+				balances[newWalletKey(user.ID(), w.Money().Currency(), w.Purpose())] = w.Money().Amount().Value()
+			}
+		}
+
+		if len(users) < reconcilePageSize {
+			return balances, nil
+		}
+	}
+}
+
+// transfers returns the transfers created since the checkpoint
+func (r reconcileInteractor) transfers(ctx context.Context, since time.Time) ([]entity.Transfer, error) {
+	var listed []entity.Transfer
+	for offset := 0; ; offset += reconcilePageSize {
+		transfers, err := r.repoTransferLister.List(ctx, entity.TransferFilter{
+			From:   since,
+			Offset: offset,
+			Limit:  reconcilePageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		listed = append(listed, transfers...)
+
+		if len(transfers) < reconcilePageSize {
+			return listed, nil
+		}
+	}
+}
+
+// decisions returns the held transfers whose review was decided since the
+// checkpoint, keyed by their id
+func (r reconcileInteractor) decisions(ctx context.Context, since time.Time) (map[string]transferDecision, error) {
+	decisions := map[string]transferDecision{}
+	for _, action := range []vo.AuditAction{vo.ReviewApprovedAction, vo.ReviewRejectedAction} {
+		for offset := 0; ; offset += reconcilePageSize {
+			entries, err := r.repoAuditLister.List(ctx, entity.AuditFilter{
+				Action: action,
+				From:   since,
+				Offset: offset,
+				Limit:  reconcilePageSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				review, err := r.repoReviewFinder.FindByID(ctx, entry.SubjectID())
+				if err != nil {
+					return nil, err
+				}
+
+				transfer, err := r.repoTransferFinder.FindByID(ctx, review.TransferID())
+				if err != nil {
+					return nil, err
+				}
+
+				decisions[transfer.ID().Value()] = transferDecision{
+					transfer: transfer,
+					approved: action == vo.ReviewApprovedAction,
+					at:       entry.CreatedAt(),
+				}
+			}
+
+			if len(entries) < reconcilePageSize {
+				break
+			}
+		}
+	}
+
+	return decisions, nil
+}
+
+// settle adds the movements of the transfers to the ledger: the payer is
+// debited when a transfer is created, the payees and the fee account are
+// credited, or the payer refunded, when it is decided or, without a review,
+// when it is created. It returns the transfers of the day and the held ones.
+func (r reconcileInteractor) settle(l *ledger, transfers []entity.Transfer, decisions map[string]transferDecision) ([]entity.Transfer, []entity.Transfer) {
+	var day, held []entity.Transfer
+	for _, t := range transfers {
+		at := t.CreatedAt()
+		l.add(newWalletKey(t.Payer(), t.Value().Currency(), walletPurpose(t.Source())), -t.Value().Amount().Value(), at)
+
+		if l.during(at) {
+			day = append(day, t)
+		}
+
+		if _, decided := decisions[t.ID().Value()]; decided {
+			continue
+		}
+
+		switch t.Status() {
+		case vo.TransferCompleted:
+			r.release(l, t, true, at)
+		case vo.TransferRejected:
+			r.release(l, t, false, at)
+		case vo.TransferHeld:
+			held = append(held, t)
+		}
+	}
+
+	for _, d := range decisions {
+		r.release(l, d.transfer, d.approved, d.at)
+	}
+
+	return day, held
+}
+
+// release adds the credits of the payees and of the fee account recorded on
+// an approved transfer, or the refund of the payer of a rejected one
+func (r reconcileInteractor) release(l *ledger, t entity.Transfer, approved bool, at time.Time) {
+	currency := t.Value().Currency()
+	if !approved {
+		l.add(newWalletKey(t.Payer(), currency, walletPurpose(t.Source())), t.Value().Amount().Value(), at)
+		return
+	}
+
+	for _, leg := range t.Legs() {
+		l.add(newWalletKey(leg.Payee(), currency, walletPurpose(t.Destination())), leg.Net().Amount().Value(), at)
+	}
+	if fee := t.Fee().Amount().Value(); fee > 0 {
+		l.add(newWalletKey(feeAccount(t, r.pricing), currency, vo.MainWallet), fee, at)
+	}
+}
+
+// fundings adds the movements of the fundings updated since the checkpoint
+// to the ledger: a deposit credits its wallet when it is settled, a
+// withdrawal debits it when it is created and refunds it when it fails. It
+// returns the fundings of the day.
+func (r reconcileInteractor) fundings(ctx context.Context, l *ledger, since time.Time) ([]entity.Funding, error) {
+	var day []entity.Funding
+	for offset := 0; ; offset += reconcilePageSize {
+		fundings, err := r.repoFundingLister.List(ctx, entity.FundingFilter{
+			UpdatedFrom: since,
+			Offset:      offset,
+			Limit:       reconcilePageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range fundings {
+			wallet := newWalletKey(f.User(), f.Value().Currency(), walletPurpose(f.Wallet()))
+			value := f.Value().Amount().Value()
+
+			switch {
+			case f.Type() == vo.Deposit && f.Status() == vo.FundingSettled:
+				l.add(wallet, value, f.UpdatedAt())
+			case f.Type() == vo.Withdrawal:
+				l.add(wallet, -value, f.CreatedAt())
+				if f.Status() == vo.FundingFailed {
+					l.add(wallet, value, f.UpdatedAt())
+				}
+			}
+
+			if l.during(f.CreatedAt()) {
+				day = append(day, f)
+			}
+		}
+
+		if len(fundings) < reconcilePageSize {
+			return day, nil
+		}
+	}
+}
+
+// adjustments adds to the ledger the initial balances of the users and the
+// credits and debits of their wallets outside of any transfer, read from the
+// audit log since the checkpoint
+func (r reconcileInteractor) adjustments(ctx context.Context, l *ledger, since time.Time) error {
+	for _, action := range []vo.AuditAction{vo.UserCreatedAction, vo.WalletCreditedAction, vo.WalletDebitedAction} {
+		for offset := 0; ; offset += reconcilePageSize {
+			entries, err := r.repoAuditLister.List(ctx, entity.AuditFilter{
+				Action: action,
+				From:   since,
+				Offset: offset,
+				Limit:  reconcilePageSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				// an entry which cannot be read moves nothing, its wallet mismatching
+				if action == vo.UserCreatedAction {
+					var user userSnapshot
+					if json.Unmarshal([]byte(entry.After()), &user) != nil {
+						continue
+					}
+
+					l.known[user.ID] = true
+					for _, w := range user.Wallets {
+						l.add(walletKey{user: user.ID, currency: w.Currency, purpose: w.Purpose}, w.Amount, entry.CreatedAt())
+					}
+					continue
+				}
+
+				wallet, amount, ok := parseAdjustment(entry)
+				if !ok {
+					continue
+				}
+				l.add(wallet, amount, entry.CreatedAt())
+			}
+
+			if len(entries) < reconcilePageSize {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// compare compares the balance of every wallet stored or moved with the one of its movements
+func (r reconcileInteractor) compare(output *ReconcileOutput, l ledger, before, after map[walletKey]int64) {
+	keys := make(map[walletKey]bool, len(after)+len(l.wallets))
+	for k := range after {
+		if k.currency != "" {
+			keys[k] = true
+		}
+	}
+	for k := range l.wallets {
+		keys[k] = true
+	}
+
+	for _, k := range sortedWalletKeys(keys) {
+		m := l.wallets[k]
+		if m == nil {
+			m = &walletMovements{}
+		}
+
+		actual, stored := after[k]
+		if previous, ok := before[k]; stored && (!ok || previous != actual) {
+			output.Moving++
+			continue
+		}
+
+		expected := m.closing() + m.later
+		balance := WalletBalanceOutput{
+			UserID:   k.user,
+			Currency: k.currency,
+			Purpose:  k.purpose,
+			Opening:  m.opening,
+			Credits:  m.credits,
+			Debits:   m.debits,
+			Closing:  m.closing(),
+			Expected: expected,
+			Actual:   actual,
+		}
+
+		switch _, userExists := after[walletKey{user: k.user}]; {
+		case stored:
+			output.Wallets++
+		case !userExists:
+			// the transfers and the fundings of a user which does not exist are orphans
+			continue
+		default:
+			output.Discrepancies = append(output.Discrepancies, DiscrepancyOutput{
+				Issue:      MissingCounterpart,
+				UserID:     k.user,
+				Currency:   k.currency,
+				Purpose:    k.purpose,
+				Difference: -expected,
+				Detail:     "the wallet moved does not exist",
+			})
+			continue
+		}
+
+		if actual != expected {
+			output.Discrepancies = append(output.Discrepancies, DiscrepancyOutput{
+				Issue:      BalanceMismatch,
+				UserID:     k.user,
+				Currency:   k.currency,
+				Purpose:    k.purpose,
+				Difference: actual - expected,
+				Detail:     fmt.Sprintf("stored balance %d, expected %d", actual, expected),
+			})
+		}
+
+		if m.credits != 0 || m.debits != 0 || actual != expected {
+			output.Balances = append(output.Balances, balance)
+		}
+	}
+}
+
+// checkTransfers reports the transfers of the day whose payer or payee does not exist
+func (r reconcileInteractor) checkTransfers(output *ReconcileOutput, transfers []entity.Transfer, users map[walletKey]int64) {
+	for _, t := range transfers {
+		var missing []string
+		if _, ok := users[walletKey{user: t.Payer().Value()}]; !ok {
+			missing = append(missing, "payer "+t.Payer().Value())
+		}
+		for _, leg := range t.Legs() {
+			if _, ok := users[walletKey{user: leg.Payee().Value()}]; !ok {
+				missing = append(missing, "payee "+leg.Payee().Value())
+			}
+		}
+
+		if len(missing) > 0 {
+			output.Discrepancies = append(output.Discrepancies, DiscrepancyOutput{
+				Issue:      OrphanTransfer,
+				Currency:   t.Value().Currency().String(),
+				TransferID: t.ID().Value(),
+				Detail:     "unknown " + strings.Join(missing, ", "),
+			})
+		}
+	}
+}
+
+// checkFundings reports the fundings of the day whose user does not exist
+func (r reconcileInteractor) checkFundings(output *ReconcileOutput, fundings []entity.Funding, users map[walletKey]int64) {
+	for _, f := range fundings {
+		if _, ok := users[walletKey{user: f.User().Value()}]; !ok {
+			output.Discrepancies = append(output.Discrepancies, DiscrepancyOutput{
+				Issue:     OrphanFunding,
+				UserID:    f.User().Value(),
+				Currency:  f.Value().Currency().String(),
+				FundingID: f.ID().Value(),
+				Detail:    "unknown user of the " + strings.ToLower(f.Type().String()),
+			})
+		}
+	}
+}
+
+// checkHeld reports the held transfers read without a pending review. A transfer
+// decided since it was listed is read again, its review being decided with it.
+func (r reconcileInteractor) checkHeld(ctx context.Context, output *ReconcileOutput, held []entity.Transfer) error {
+	if len(held) == 0 {
+		return nil
+	}
+
+	pending, err := r.pendingReviews(ctx, len(held))
+	if err != nil {
+		return err
+	}
+
+	for _, t := range held {
+		if pending[t.ID().Value()] {
+			continue
+		}
+
+		current, err := r.repoTransferFinder.FindByID(ctx, t.ID())
+		if err != nil {
+			return err
+		}
+		if current.Status() != vo.TransferHeld {
+			continue
+		}
+
+		output.Discrepancies = append(output.Discrepancies, DiscrepancyOutput{
+			Issue:      MissingCounterpart,
+			UserID:     t.Payer().Value(),
+			Currency:   t.Value().Currency().String(),
+			TransferID: t.ID().Value(),
+			Detail:     "held transfer without a pending review",
+		})
+	}
+
+	return nil
+}
+
+// pendingReviews returns the transfers of the pending reviews, their number
+// being looked for from n on until fewer are found
+func (r reconcileInteractor) pendingReviews(ctx context.Context, n int) (map[string]bool, error) {
+	if n < reconcilePageSize {
+		n = reconcilePageSize
+	}
+
+	for ; ; n *= 2 {
+		reviews, err := r.repoReviewFinder.FindByStatus(ctx, vo.ReviewPending, n)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reviews) < n {
+			pending := make(map[string]bool, len(reviews))
+			for _, review := range reviews {
+				pending[review.TransferID().Value()] = true
+			}
+
+			return pending, nil
+		}
+	}
+}
+
+// newLedger returns the ledger of the day, the wallets starting from their baseline
+func newLedger(from, to, checkpoint time.Time, baselines []entity.WalletBaseline) ledger {
+	l := ledger{
+		from:       from,
+		to:         to,
+		checkpoint: checkpoint,
+		wallets:    map[walletKey]*walletMovements{},
+		since:      make(map[walletKey]time.Time, len(baselines)),
+		known:      map[string]bool{},
+	}
+
+	for _, b := range baselines {
+		wallet := newWalletKey(b.User(), b.Currency(), b.Purpose())
+		l.since[wallet] = b.At()
+		l.known[wallet.user] = true
+		l.add(wallet, b.Amount(), b.At())
+	}
+
+	return l
+}
+
+// add adds the credit, or the debit when negative, of the wallet made at the time
+func (l *ledger) add(wallet walletKey, amount int64, at time.Time) {
+	since, ok := l.since[wallet]
+	if !ok {
+		since = l.checkpoint
+	}
+	if at.Before(since) {
+		return
+	}
+
+	m := l.movements(wallet)
+	switch {
+	case at.Before(l.from):
+		m.opening += amount
+	case !l.during(at):
+		m.later += amount
+	case amount < 0:
+		m.debits -= amount
+	default:
+		m.credits += amount
+	}
+}
+
+// movements returns the movements of the wallet, added to the ledger when it has none
+func (l *ledger) movements(wallet walletKey) *walletMovements {
+	m, ok := l.wallets[wallet]
+	if !ok {
+		m = &walletMovements{}
+		l.wallets[wallet] = m
+	}
+
+	return m
+}
+
+// seed baselines at the start of the day the wallets stored without a
+// baseline whose user is unknown, created before the audit log: their opening
+// balance is the stored one less their movements since. The wallets moved
+// while they were reconciled are baselined by a later reconciliation.
+func (l *ledger) seed(before, after map[walletKey]int64) []entity.WalletBaseline {
+	var seeded []entity.WalletBaseline
+	for k, actual := range after {
+		if k.currency == "" || l.known[k.user] {
+			continue
+		}
+		if previous, ok := before[k]; !ok || previous != actual {
+			continue
+		}
+
+		m := l.movements(k)
+		m.opening = actual - m.credits + m.debits - m.later
+
+		baseline, err := newWalletBaseline(k, m.opening, l.from)
+		if err != nil {
+			continue
+		}
+		l.since[k] = l.from
+		seeded = append(seeded, baseline)
+	}
+
+	return seeded
+}
+
+// closings returns the balances at the end of the day of the wallets moved or
+// stored, the ones of the unknown users without a baseline being left to seed
+func (l *ledger) closings(after map[walletKey]int64) []entity.WalletBaseline {
+	keys := make(map[walletKey]bool, len(after)+len(l.wallets))
+	for k := range after {
+		if k.currency != "" {
+			keys[k] = true
+		}
+	}
+	for k := range l.wallets {
+		keys[k] = true
+	}
+
+	var closings []entity.WalletBaseline
+	for _, k := range sortedWalletKeys(keys) {
+		if _, ok := l.since[k]; !ok && !l.known[k.user] {
+			continue
+		}
+
+		var closing int64
+		if m, ok := l.wallets[k]; ok {
+			closing = m.closing()
+		}
+
+		baseline, err := newWalletBaseline(k, closing, l.to)
+		if err != nil {
+			continue
+		}
+		closings = append(closings, baseline)
+	}
+
+	return closings
+}
+
+// during reports whether the time is in the day reconciled
+func (l *ledger) during(at time.Time) bool {
+	return !at.Before(l.from) && at.Before(l.to)
+}
+
+// closing returns the balance at the end of the day
+func (m walletMovements) closing() int64 {
+	return m.opening + m.credits - m.debits
+}
+
+func newWalletKey(user vo.Uuid, currency vo.Currency, purpose vo.WalletPurpose) walletKey {
+	return walletKey{user: user.Value(), currency: currency.String(), purpose: purpose.String()}
+}
+
+func sortedWalletKeys(keys map[walletKey]bool) []walletKey {
+	sorted := make([]walletKey, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.user != b.user {
+			return a.user < b.user
+		}
+		if a.currency != b.currency {
+			return a.currency < b.currency
+		}
+		return a.purpose < b.purpose
+	})
+
+	return sorted
+}
+
+func newWalletBaseline(k walletKey, amount int64, at time.Time) (entity.WalletBaseline, error) {
+	user, err := vo.NewUuid(k.user)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	currency, err := vo.NewCurrency(k.currency)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	purpose, err := vo.NewWalletPurpose(k.purpose)
+	if err != nil {
+		return entity.WalletBaseline{}, err
+	}
+
+	return entity.NewWalletBaseline(user, currency, purpose, amount, at), nil
+}
+
+// parseAdjustment returns the wallet and the amount credited, negative when
+// debited, by the adjustment of the entry, read from the snapshots of the
+// wallet before and after it
+func parseAdjustment(entry entity.AuditEntry) (walletKey, int64, bool) {
+	var before, after walletSnapshot
+	if json.Unmarshal([]byte(entry.After()), &after) != nil {
+		return walletKey{}, 0, false
+	}
+	// the wallet created by a credit has no snapshot before it
+	if entry.Before() != "" && json.Unmarshal([]byte(entry.Before()), &before) != nil {
+		return walletKey{}, 0, false
+	}
+
+	return walletKey{
+		user:     entry.SubjectID().Value(),
+		currency: after.Currency,
+		purpose:  after.Purpose,
+	}, after.Amount - before.Amount, true
+}
@@ -0,0 +1,266 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestReconcileBaselines(t *testing.T) {
+	ctx := context.Background()
+
+	db := database.NewInMemoryHandler()
+	users := database.NewUserInMen(db)
+	transfers := database.NewTransferInMen(db)
+	audit := database.NewAuditInMen(db)
+
+	uc := usecase.NewReconcileInteractor(
+		users,
+		transfers,
+		transfers,
+		database.NewFundingInMen(db),
+		audit,
+		database.NewReviewInMen(db),
+		database.NewReconciliationInMen(db),
+		entity.Pricing{},
+	)
+	reconcile := func() usecase.ReconcileOutput {
+		t.Helper()
+
+		now := time.Now()
+		output, err := uc.Execute(ctx, usecase.ReconcileInput{Day: now.AddDate(0, 0, -1), At: now})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		return output
+	}
+
+	// a user created before the audit log has no entry explaining its balance
+	user := newUser(ctx, t, users, 100)
+
+	if output := reconcile(); output.Baselined != 1 || len(output.Discrepancies) != 0 {
+		t.Fatalf("first reconciliation baselined %d wallets with %v, want 1 without discrepancy", output.Baselined, output.Discrepancies)
+	}
+
+	adjust := usecase.NewAdjustWalletInteractor(transfers, users, users, audit, presenter.NewAdjustWalletPresenter())
+	if _, err := adjust.Execute(ctx, usecase.AdjustWalletInput{
+		UserID:     user.ID(),
+		Adjustment: usecase.DebitWallet,
+		Value:      vo.NewMoneyBRL(vo.NewAmountTest(30)),
+		Reason:     "refund 12 BRL main: duplicated",
+		Actor:      "operator",
+		At:         time.Now(),
+	}); err != nil {
+		t.Fatalf("AdjustWallet error = %v", err)
+	}
+
+	output := reconcile()
+	if output.Baselined != 0 || len(output.Discrepancies) != 0 {
+		t.Fatalf("reconciliation after the adjustment baselined %d wallets with %v, want none", output.Baselined, output.Discrepancies)
+	}
+
+	// a balance changed by no movement mismatches its baseline
+	stored, err := users.FindByID(ctx, user.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stored.Deposit(vo.NewMoneyBRL(vo.NewAmountTest(5)), vo.MainWallet); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.UpdateWallet(ctx, user.ID(), stored.Wallet()); err != nil {
+		t.Fatal(err)
+	}
+
+	output = reconcile()
+	if len(output.Discrepancies) != 1 {
+		t.Fatalf("discrepancies = %v, want the balance mismatch", output.Discrepancies)
+	}
+	if d := output.Discrepancies[0]; d.Issue != usecase.BalanceMismatch || d.UserID != user.ID().Value() || d.Difference != 5 {
+		t.Errorf("discrepancy = %+v, want a mismatch of 5 on the wallet of the user", d)
+	}
+}
+
+func TestReconcileCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	db := database.NewInMemoryHandler()
+	users := database.NewUserInMen(db)
+	transfers := database.NewTransferInMen(db)
+	audit := database.NewAuditInMen(db)
+	reviews := database.NewReviewInMen(db)
+	reconciliation := database.NewReconciliationInMen(db)
+	lister := &transferListerSpy{TransferRepositoryLister: transfers}
+
+	payer := newUser(ctx, t, users, 100)
+	payee := newUser(ctx, t, users, 0)
+	fees := newUser(ctx, t, users, 0)
+	// the account of the pricing changed since the transfer recorded its fee
+	pricing, err := entity.NewPricing(newUser(ctx, t, users, 0).ID(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := usecase.NewReconcileInteractor(users, transfers, lister, database.NewFundingInMen(db), audit, reviews, reconciliation, pricing)
+
+	// a transfer held yesterday, its payer debited
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	transfer := entity.NewTransfer(newUuid(t), payer.ID(), payee.ID(), vo.NewMoneyBRL(vo.NewAmountTest(30)), yesterday).
+		WithFee(0, entity.RestoreFee(vo.NewMoneyBRL(vo.NewAmountTest(5)), "common", 1, fees.ID())).
+		WithStatus(vo.TransferHeld)
+	if _, err := transfers.Create(ctx, transfer); err != nil {
+		t.Fatal(err)
+	}
+	review, err := reviews.Create(ctx, entity.NewReview(newUuid(t), transfer, "risk", yesterday, now.Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	moveWallet(ctx, t, users, payer.ID(), -30)
+
+	checkpoint := usecase.ReconcileInput{Day: yesterday, At: now, Checkpoint: true}
+	output, err := uc.Execute(ctx, checkpoint)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if output.Baselined != 4 || output.Transfers != 1 || len(output.Discrepancies) != 0 {
+		t.Fatalf("checkpointed reconciliation baselined %d wallets, read %d transfers with %v, want 4, 1 and none",
+			output.Baselined, output.Transfers, output.Discrepancies)
+	}
+
+	if _, err := uc.Execute(ctx, checkpoint); !errors.Is(err, usecase.ErrDayReconciled) {
+		t.Fatalf("Execute() checkpointed again error = %v, want %v", err, usecase.ErrDayReconciled)
+	}
+
+	// the transfer held before the checkpoint is approved after it
+	decide := usecase.NewDecideReviewInteractor(
+		transfers,
+		transfers,
+		transfers,
+		users,
+		users,
+		reviews,
+		reviews,
+		audit,
+		presenter.NewDecideReviewPresenter(),
+		nopNotifier{},
+		pricing,
+	)
+	if _, err := decide.Execute(ctx, usecase.DecideReviewInput{
+		ID:       review.ID(),
+		Action:   usecase.ApproveReview,
+		Reviewer: "operator",
+		At:       time.Now(),
+	}); err != nil {
+		t.Fatalf("DecideReview error = %v", err)
+	}
+
+	output, err = uc.Execute(ctx, usecase.ReconcileInput{Day: now, At: time.Now()})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(output.Discrepancies) != 0 {
+		t.Fatalf("reconciliation after the checkpoint found %v, want none", output.Discrepancies)
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	if from := lister.filters[len(lister.filters)-1].From; !from.Equal(today) {
+		t.Errorf("transfers listed from %s, want from the checkpoint %s", from, today)
+	}
+
+	var credited bool
+	for _, b := range output.Balances {
+		if b.UserID == fees.ID().Value() {
+			credited = b.Opening == 0 && b.Credits == 5 && b.Actual == 5
+		}
+	}
+	if !credited {
+		t.Errorf("balances = %+v, want the fee credited to the account recorded on the transfer", output.Balances)
+	}
+}
+
+func TestReconcileLocked(t *testing.T) {
+	ctx := context.Background()
+
+	db := database.NewInMemoryHandler()
+	transfers := database.NewTransferInMen(db)
+	reconciliation := database.NewReconciliationInMen(db)
+
+	uc := usecase.NewReconcileInteractor(
+		database.NewUserInMen(db),
+		transfers,
+		transfers,
+		database.NewFundingInMen(db),
+		database.NewAuditInMen(db),
+		database.NewReviewInMen(db),
+		reconciliation,
+		entity.Pricing{},
+	)
+
+	now := time.Now()
+	if err := reconciliation.Lock(ctx, "other", now, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	input := usecase.ReconcileInput{Day: now.AddDate(0, 0, -1), At: now}
+	if _, err := uc.Execute(ctx, input); !errors.Is(err, entity.ErrReconciliationLocked) {
+		t.Fatalf("Execute() locked error = %v, want %v", err, entity.ErrReconciliationLocked)
+	}
+
+	if err := reconciliation.Unlock(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Execute(ctx, input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// the run released the lock
+	if err := reconciliation.Lock(ctx, "other", now, now.Add(time.Hour)); err != nil {
+		t.Errorf("Lock() after the run error = %v", err)
+	}
+}
+
+// transferListerSpy records the filters the transfers are listed with
+type transferListerSpy struct {
+	entity.TransferRepositoryLister
+	filters []entity.TransferFilter
+}
+
+func (s *transferListerSpy) List(ctx context.Context, f entity.TransferFilter) ([]entity.Transfer, error) {
+	s.filters = append(s.filters, f)
+	return s.TransferRepositoryLister.List(ctx, f)
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(context.Context, entity.Transfer) {}
+
+// moveWallet credits the main wallet of the user with the amount, debits it when negative
+func moveWallet(ctx context.Context, t *testing.T, users *database.UserInMen, id vo.Uuid, amount int64) {
+	t.Helper()
+
+	user, err := users.FindByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if amount < 0 {
+		err = user.Withdraw(vo.NewMoneyBRL(vo.NewAmountTest(-amount)), vo.MainWallet)
+	} else {
+		err = user.Deposit(vo.NewMoneyBRL(vo.NewAmountTest(amount)), vo.MainWallet)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := users.UpdateWallet(ctx, id, user.Wallet()); err != nil {
+		t.Fatal(err)
+	}
+}
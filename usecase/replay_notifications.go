@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// replayPageSize is the number of transfers read at once while replaying
+const replayPageSize = 100
+
+type (
+	// Input port
+	ReplayNotificationsUseCase interface {
+		Execute(context.Context, ReplayNotificationsInput) (ReplayNotificationsOutput, error)
+	}
+
+	// Input data. The transfers of every user are replayed when UserID is
+	// empty, the ones created at any time when From and To are zero.
+	ReplayNotificationsInput struct {
+		UserID vo.Uuid
+		From   time.Time
+		To     time.Time
+	}
+
+	// Output port
+	ReplayNotificationsPresenter interface {
+		Output([]entity.Transfer) ReplayNotificationsOutput
+	}
+
+	// Output data
+	ReplayNotificationsOutput struct {
+		Notified  int      `json:"notified"`
+		Transfers []string `json:"transfers"`
+	}
+
+	replayNotificationsInteractor struct {
+		repo     entity.TransferRepositoryLister
+		pre      ReplayNotificationsPresenter
+		notifier Notifier
+	}
+)
+
+// NewReplayNotificationsInteractor create new replayNotificationsInteractor with its dependencies
+func NewReplayNotificationsInteractor(
+	repo entity.TransferRepositoryLister,
+	pre ReplayNotificationsPresenter,
+	notifier Notifier,
+) ReplayNotificationsUseCase {
+	return replayNotificationsInteractor{
+		repo:     repo,
+		pre:      pre,
+		notifier: notifier,
+	}
+}
+
+// Execute notifies again the completed transfers matching the input, the
+// oldest first. The notifications are not stored, so every completed transfer
+// is replayed whether its first notification failed or not.
+func (r replayNotificationsInteractor) Execute(ctx context.Context, i ReplayNotificationsInput) (ReplayNotificationsOutput, error) {
+	ctx, span := tracer.Start(ctx, "ReplayNotificationsInteractor.Execute", trace.WithAttributes(
+		attribute.String("transfer.user_id", i.UserID.Value()),
+	))
+	defer span.End()
+
+	var replayed []entity.Transfer
+	for offset := 0; ; offset += replayPageSize {
+		transfers, err := r.page(ctx, i, offset)
+		if err != nil {
+			recordError(span, err)
+			return r.pre.Output(replayed), err
+		}
+
+		for _, transfer := range transfers {
+			if transfer.Status() != vo.TransferCompleted {
+				continue
+			}
+
+			r.notifier.Notify(ctx, transfer)
+			replayed = append(replayed, transfer)
+		}
+
+		if len(transfers) < replayPageSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("transfer.notified", len(replayed)))
+
+	return r.pre.Output(replayed), nil
+}
+
+// page returns the transfers of the input after the offset, each page read
+// within its own timeout as the whole replay may take long
+func (r replayNotificationsInteractor) page(ctx context.Context, i ReplayNotificationsInput, offset int) ([]entity.Transfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.repo.List(ctx, entity.TransferFilter{
+		UserID: i.UserID,
+		From:   i.From,
+		To:     i.To,
+		Offset: offset,
+		Limit:  replayPageSize,
+	})
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type riskAuthorizer struct {
+	policy                entity.RiskPolicy
+	repoTransferFinder    entity.TransferRepositoryFinder
+	repoUserFinder        entity.UserRepositoryFinder
+	repoAssessmentCreator entity.RiskAssessmentRepositoryCreator
+}
+
+// NewRiskAuthorizer create new riskAuthorizer with its dependencies
+func NewRiskAuthorizer(
+	policy entity.RiskPolicy,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoAssessmentCreator entity.RiskAssessmentRepositoryCreator,
+) Authorizer {
+	return riskAuthorizer{
+		policy:                policy,
+		repoTransferFinder:    repoTransferFinder,
+		repoUserFinder:        repoUserFinder,
+		repoAssessmentCreator: repoAssessmentCreator,
+	}
+}
+
+// Authorized scores the transfer with the rules of the policy and saves the
+// assessment, approved or not, for review. The denied transfers are refused
+// with entity.ErrUnauthorizedTransfer, the ones to review are held with
+// entity.ErrReviewRequired.
+func (r riskAuthorizer) Authorized(ctx context.Context, transfer entity.Transfer) (bool, error) {
+	ctx, span := tracer.Start(ctx, "RiskAuthorizer.Authorized", trace.WithAttributes(
+		attribute.String("transfer.id", transfer.ID().Value()),
+		attribute.String("transfer.payer_id", transfer.Payer().Value()),
+	))
+	defer span.End()
+
+	now := time.Now()
+
+	facts, err := r.facts(ctx, transfer, now)
+	if err != nil {
+		recordError(span, err)
+		return false, err
+	}
+
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		recordError(span, err)
+		return false, err
+	}
+
+	assessment := r.policy.Assess(id, transfer, facts, now)
+	span.SetAttributes(
+		attribute.Int("risk.score", assessment.Score()),
+		attribute.String("risk.decision", assessment.Decision().String()),
+	)
+
+	if _, err := r.repoAssessmentCreator.Create(ctx, assessment); err != nil {
+		recordError(span, err)
+		return false, err
+	}
+
+	switch assessment.Decision() {
+	case vo.RiskDeny:
+		err := errors.Wrapf(entity.ErrUnauthorizedTransfer, "risk score %d", assessment.Score())
+		recordError(span, err)
+		return false, err
+	case vo.RiskReview:
+		return false, errors.Wrapf(entity.ErrReviewRequired, "risk score %d", assessment.Score())
+	}
+
+	return true, nil
+}
+
+// facts gathers what the rules of the policy need to know about the transfer,
+// each over the window of its rule ending at now
+func (r riskAuthorizer) facts(ctx context.Context, transfer entity.Transfer, now time.Time) (entity.RiskFacts, error) {
+	var facts entity.RiskFacts
+
+	since := func(window time.Duration) time.Time {
+		if window == 0 {
+			return time.Time{}
+		}
+		return now.Add(-window)
+	}
+
+	for _, rule := range r.policy.Rules() {
+		switch rule.Signal() {
+		case vo.NewPayeeSignal:
+			paid, err := r.payees(ctx, transfer.Payer(), since(rule.Window()), now)
+			if err != nil {
+				return entity.RiskFacts{}, err
+			}
+
+			for _, leg := range transfer.Legs() {
+				if !paid[leg.Payee().Value()] {
+					facts.NewPayees++
+				}
+			}
+		case vo.AmountAboveAverageSignal:
+			usage, err := r.repoTransferFinder.SumByPayer(ctx, transfer.Payer(), transfer.Value().Currency(), since(rule.Window()), now)
+			if err != nil {
+				return entity.RiskFacts{}, err
+			}
+
+			facts.History = usage.Count()
+			if usage.Count() > 0 {
+				if facts.Average, err = vo.NewAmount(usage.Value().Value() / int64(usage.Count())); err != nil {
+					return entity.RiskFacts{}, err
+				}
+			}
+		case vo.ManyPayeesSignal:
+			paid, err := r.payees(ctx, transfer.Payer(), since(rule.Window()), now)
+			if err != nil {
+				return entity.RiskFacts{}, err
+			}
+
+			for _, leg := range transfer.Legs() {
+				paid[leg.Payee().Value()] = true
+			}
+			facts.RecentPayees = len(paid)
+		case vo.EmailChangedSignal:
+			payer, err := r.repoUserFinder.FindByID(ctx, transfer.Payer())
+			if err != nil {
+				return entity.RiskFacts{}, err
+			}
+
+			facts.EmailChangedAt = payer.EmailChangedAt()
+		}
+	}
+
+	return facts, nil
+}
+
+// payees returns the set of the payees of the payer in [from, to)
+func (r riskAuthorizer) payees(ctx context.Context, payerID vo.Uuid, from, to time.Time) (map[string]bool, error) {
+	payees, err := r.repoTransferFinder.FindPayees(ctx, payerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(payees))
+	for _, p := range payees {
+		set[p.Value()] = true
+	}
+
+	return set, nil
+}
@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+func TestRiskAuthorizerSavesAssessment(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    int
+		err      error
+		decision vo.RiskDecision
+		balance  int64
+	}{
+		{name: "approved", score: 10, decision: vo.RiskApprove, balance: 90},
+		{name: "refused", score: 100, err: entity.ErrUnauthorizedTransfer, decision: vo.RiskDeny, balance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			risks := database.NewRiskInMen(db)
+
+			payer := newUser(ctx, t, users, 100)
+			payee := newUser(ctx, t, users, 0)
+
+			// the payee was never paid, adding the score of the rule
+			rule, err := entity.NewRiskRule(vo.NewPayeeSignal, tt.score, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			policy, err := entity.NewRiskPolicy([]entity.RiskRule{rule}, 50, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authorizer := usecase.NewRiskAuthorizer(policy, database.NewTransferInMen(db), users, risks)
+
+			id := newUuid(t)
+			_, err = newCreateTransfer(db, authorizer).Execute(ctx, usecase.CreateTransferInput{
+				ID:       id,
+				PayerID:  payer.ID(),
+				PayeeID:  payee.ID(),
+				Value:    vo.NewMoneyBRL(vo.NewAmountTest(10)),
+				CreateAt: time.Now(),
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+
+			assessment, err := risks.FindByTransferID(ctx, id)
+			if err != nil {
+				t.Fatalf("assessment not saved: %v", err)
+			}
+			if assessment.Decision() != tt.decision || assessment.Score() != tt.score {
+				t.Errorf("assessment = %s %d, want %s %d", assessment.Decision(), assessment.Score(), tt.decision, tt.score)
+			}
+
+			got, err := users.FindByID(ctx, payer.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Wallet().Money().Amount().Value() != tt.balance {
+				t.Errorf("payer balance = %d, want %d", got.Wallet().Money().Amount().Value(), tt.balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	ScheduleTransferUseCase interface {
+		Execute(context.Context, ScheduleTransferInput) (ScheduleOutput, error)
+	}
+
+	// Input data
+	ScheduleTransferInput struct {
+		ID         vo.Uuid
+		PayerID    vo.Uuid
+		PayeeID    vo.Uuid
+		Value      vo.Money
+		Recurrence vo.Recurrence
+		StartAt    time.Time
+		CreatedAt  time.Time
+	}
+
+	// Output port
+	ScheduleTransferPresenter interface {
+		Output(entity.Schedule) ScheduleOutput
+	}
+
+	// Output data
+	ScheduleOutput struct {
+		ID          string                   `json:"id"`
+		PayerID     string                   `json:"payer"`
+		PayeeID     string                   `json:"payee"`
+		Value       int64                    `json:"value"`
+		Recurrence  ScheduleRecurrenceOutput `json:"recurrence"`
+		Status      string                   `json:"status"`
+		NextRunAt   string                   `json:"next_run_at,omitempty"`
+		Occurrences int                      `json:"occurrences"`
+		LastError   string                   `json:"last_error,omitempty"`
+		CreatedAt   string                   `json:"created_at"`
+		UpdatedAt   string                   `json:"updated_at"`
+	}
+
+	// Output data
+	ScheduleRecurrenceOutput struct {
+		Frequency  string `json:"frequency"`
+		DayOfMonth int    `json:"day_of_month,omitempty"`
+		EndAt      string `json:"end_at,omitempty"`
+		Count      int    `json:"count,omitempty"`
+	}
+
+	scheduleTransferInteractor struct {
+		repoScheduleCreator entity.ScheduleRepositoryCreator
+		repoUserFinder      entity.UserRepositoryFinder
+		pre                 ScheduleTransferPresenter
+	}
+)
+
+// NewScheduleTransferInteractor create new scheduleTransferInteractor with its dependencies
+func NewScheduleTransferInteractor(
+	repoScheduleCreator entity.ScheduleRepositoryCreator,
+	repoUserFinder entity.UserRepositoryFinder,
+	pre ScheduleTransferPresenter,
+) ScheduleTransferUseCase {
+	return scheduleTransferInteractor{
+		repoScheduleCreator: repoScheduleCreator,
+		repoUserFinder:      repoUserFinder,
+		pre:                 pre,
+	}
+}
+
+// Execute orchestrate the use case
+func (s scheduleTransferInteractor) Execute(ctx context.Context, i ScheduleTransferInput) (ScheduleOutput, error) {
+	ctx, span := tracer.Start(ctx, "ScheduleTransferInteractor.Execute", trace.WithAttributes(
+		attribute.String("schedule.id", i.ID.Value()),
+		attribute.String("schedule.payer_id", i.PayerID.Value()),
+		attribute.String("schedule.payee_id", i.PayeeID.Value()),
+		attribute.String("schedule.frequency", i.Recurrence.Frequency().String()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	schedule, err := entity.NewSchedule(i.ID, i.PayerID, i.PayeeID, i.Value, i.Recurrence, i.StartAt, i.CreatedAt)
+	if err != nil {
+		recordError(span, err)
+		return s.pre.Output(entity.Schedule{}), err
+	}
+
+	payer, err := s.repoUserFinder.FindByID(ctx, i.PayerID)
+	if err != nil {
+		recordError(span, err)
+		return s.pre.Output(entity.Schedule{}), err
+	}
+
+	if err := payer.CanTransfer(); err != nil {
+		err = errors.Wrap(err, entity.ErrUnauthorizedTransfer.Error())
+		recordError(span, err)
+		return s.pre.Output(entity.Schedule{}), err
+	}
+
+	if _, err := s.repoUserFinder.FindByID(ctx, i.PayeeID); err != nil {
+		recordError(span, err)
+		return s.pre.Output(entity.Schedule{}), err
+	}
+
+	schedule, err = s.repoScheduleCreator.Create(ctx, schedule)
+	if err != nil {
+		recordError(span, err)
+		return s.pre.Output(entity.Schedule{}), err
+	}
+
+	return s.pre.Output(schedule), nil
+}
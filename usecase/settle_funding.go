@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// providerActor is the actor of the changes made as the payment provider answers the fundings
+const providerActor = "payment_provider"
+
+const (
+	SettleFunding FundingAction = "settle"
+	FailFunding   FundingAction = "fail"
+)
+
+type (
+	// FundingAction define the answers of the payment provider to a pending funding
+	FundingAction string
+
+	// Input port
+	SettleFundingUseCase interface {
+		Execute(context.Context, SettleFundingInput) (FundingOutput, error)
+	}
+
+	// Input data
+	SettleFundingInput struct {
+		ID     vo.Uuid
+		Action FundingAction
+		// Reference identifies the funding at the payment provider, the stored one is kept when empty
+		Reference string
+		Reason    string
+		At        time.Time
+	}
+
+	// Output port
+	SettleFundingPresenter interface {
+		Output(entity.Funding) FundingOutput
+	}
+
+	settleFundingInteractor struct {
+		fundingSettler
+		pre SettleFundingPresenter
+	}
+
+	// fundingSettler moves the money of the fundings as the payment provider
+	// answers them, shared by the use cases making and settling them
+	fundingSettler struct {
+		transferExecutor
+		repoFundingFinder  entity.FundingRepositoryFinder
+		repoFundingUpdater entity.FundingRepositoryUpdater
+	}
+)
+
+// NewSettleFundingInteractor create new settleFundingInteractor with its dependencies
+func NewSettleFundingInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoFundingFinder entity.FundingRepositoryFinder,
+	repoFundingUpdater entity.FundingRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	pre SettleFundingPresenter,
+) SettleFundingUseCase {
+	return settleFundingInteractor{
+		fundingSettler: fundingSettler{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+				repoAuditCreator:    repoAuditCreator,
+			},
+			repoFundingFinder:  repoFundingFinder,
+			repoFundingUpdater: repoFundingUpdater,
+		},
+		pre: pre,
+	}
+}
+
+// Execute settles or fails the pending funding as the payment provider
+// answered it. A settled deposit credits its wallet, a failed withdrawal is
+// refunded.
+func (s settleFundingInteractor) Execute(ctx context.Context, i SettleFundingInput) (FundingOutput, error) {
+	ctx, span := tracer.Start(ctx, "SettleFundingInteractor.Execute", trace.WithAttributes(
+		attribute.String("funding.id", i.ID.Value()),
+		attribute.String("funding.action", string(i.Action)),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := ProviderResult{Reference: i.Reference, Reason: i.Reason}
+	switch i.Action {
+	case SettleFunding:
+		result.Status = vo.FundingSettled
+	case FailFunding:
+		result.Status = vo.FundingFailed
+	default:
+		recordError(span, vo.ErrInvalidFundingStatus)
+		return s.pre.Output(entity.Funding{}), vo.ErrInvalidFundingStatus
+	}
+
+	funding, err := s.resolve(ctx, span, i.ID, result, i.At)
+	if err != nil {
+		recordError(span, err)
+		return s.pre.Output(entity.Funding{}), err
+	}
+
+	return s.pre.Output(funding), nil
+}
+
+// resolve applies the answer of the payment provider to the pending funding
+// in a single transaction, the funding staying pending unless the answer
+// settles or fails it
+func (f fundingSettler) resolve(
+	ctx context.Context,
+	span trace.Span,
+	ID vo.Uuid,
+	result ProviderResult,
+	at time.Time,
+) (entity.Funding, error) {
+	var funding entity.Funding
+	err := f.retry(ctx, span, func() error {
+		return f.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			if funding, err = f.repoFundingFinder.FindByID(sessCtx, ID); err != nil {
+				return err
+			}
+
+			if funding.Status() != vo.FundingPending {
+				return entity.ErrFundingStatusTransition
+			}
+
+			if result.Reference != "" {
+				funding = funding.WithReference(result.Reference)
+			}
+
+			switch result.Status {
+			case vo.FundingSettled:
+				err = funding.Settle(at)
+			case vo.FundingFailed:
+				err = funding.Fail(result.Reason, at)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := f.credit(sessCtx, funding); err != nil {
+				return err
+			}
+
+			return f.repoFundingUpdater.Update(sessCtx, funding)
+		})
+	})
+	if err != nil {
+		return entity.Funding{}, err
+	}
+
+	return funding, nil
+}
+
+// credit credits the wallet of a settled deposit, or refunds the wallet of a
+// failed withdrawal appending the refund to the audit log. It must run in a
+// transaction.
+func (f fundingSettler) credit(ctx context.Context, funding entity.Funding) error {
+	settledDeposit := funding.Type() == vo.Deposit && funding.Status() == vo.FundingSettled
+	failedWithdrawal := funding.Type() == vo.Withdrawal && funding.Status() == vo.FundingFailed
+	if !settledDeposit && !failedWithdrawal {
+		return nil
+	}
+
+	user, err := f.repoUserFinder.FindByID(ctx, funding.User())
+	if err != nil {
+		return err
+	}
+
+	if err := user.Deposit(funding.Value(), funding.Wallet()); err != nil {
+		return err
+	}
+
+	wallet, err := user.FindWallet(funding.Value().Currency(), funding.Wallet())
+	if err != nil {
+		return err
+	}
+
+	if err := f.repoUserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+		return err
+	}
+
+	if !failedWithdrawal {
+		return nil
+	}
+
+	_, err = appendAudit(ctx, f.repoAuditCreator, auditRecord{
+		action:    vo.WithdrawalRefundedAction,
+		actor:     providerActor,
+		target:    vo.FundingTarget,
+		subjectID: funding.ID(),
+		detail:    funding.Reason(),
+		after:     newFundingSnapshot(funding),
+		at:        funding.UpdatedAt(),
+	})
+
+	return err
+}
+
+// fundingError returns the error of the funding the payment provider failed
+func fundingError(funding entity.Funding) error {
+	if funding.Reason() == "" {
+		return entity.ErrFundingFailed
+	}
+
+	return errors.Wrap(entity.ErrFundingFailed, funding.Reason())
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// auditVerifyPage is the number of chained entries read at once by the verification
+const auditVerifyPage = 1000
+
+type (
+	// Input port
+	VerifyAuditUseCase interface {
+		Execute(context.Context, VerifyAuditInput) (VerifyAuditOutput, error)
+	}
+
+	// Input data
+	VerifyAuditInput struct {
+		// FromSequence is the first entry of the chain verified, the first one when zero
+		FromSequence int64
+	}
+
+	// Output data. BrokenAt is the sequence of the first entry not following
+	// the previous one or changed since it was chained.
+	VerifyAuditOutput struct {
+		Valid        bool  `json:"valid"`
+		Verified     int   `json:"verified"`
+		LastSequence int64 `json:"last_sequence"`
+		BrokenAt     int64 `json:"broken_at,omitempty"`
+	}
+
+	verifyAuditInteractor struct {
+		repo entity.AuditRepositoryLister
+	}
+)
+
+// NewVerifyAuditInteractor create new verifyAuditInteractor with its dependencies
+func NewVerifyAuditInteractor(repo entity.AuditRepositoryLister) VerifyAuditUseCase {
+	return verifyAuditInteractor{
+		repo: repo,
+	}
+}
+
+// Execute verifies the chain of the audit log from i.FromSequence to its last
+// chained entry. The pending entries and the ones appended before the log was
+// chained are not verified.
+func (v verifyAuditInteractor) Execute(ctx context.Context, i VerifyAuditInput) (VerifyAuditOutput, error) {
+	ctx, span := tracer.Start(ctx, "VerifyAuditInteractor.Execute", trace.WithAttributes(
+		attribute.Int64("audit.from_sequence", i.FromSequence),
+	))
+	defer span.End()
+
+	from := i.FromSequence
+	if from < 1 {
+		from = 1
+	}
+
+	// the entry before the first verified one is the one it is chained to
+	var prev entity.AuditEntry
+	if from > 1 {
+		entries, err := v.repo.List(ctx, entity.AuditFilter{FromSequence: from - 1, Limit: 1})
+		if err != nil {
+			recordError(span, err)
+			return VerifyAuditOutput{}, err
+		}
+		if len(entries) == 0 {
+			return VerifyAuditOutput{Valid: true}, nil
+		}
+		if entries[0].Sequence() != from-1 {
+			return VerifyAuditOutput{BrokenAt: from - 1}, nil
+		}
+		prev = entries[0]
+	}
+
+	output := VerifyAuditOutput{Valid: true, LastSequence: prev.Sequence()}
+	for {
+		entries, err := v.repo.List(ctx, entity.AuditFilter{FromSequence: prev.Sequence() + 1, Limit: auditVerifyPage})
+		if err != nil {
+			recordError(span, err)
+			return output, err
+		}
+
+		for _, entry := range entries {
+			if err := entry.VerifyChain(prev); err != nil {
+				if !errors.Is(err, entity.ErrAuditChainBroken) {
+					recordError(span, err)
+					return output, err
+				}
+
+				output.Valid = false
+				output.BrokenAt = prev.Sequence() + 1
+				span.SetAttributes(attribute.Int64("audit.broken_at", output.BrokenAt))
+				return output, nil
+			}
+
+			prev = entry
+			output.Verified++
+			output.LastSequence = entry.Sequence()
+		}
+
+		if len(entries) < auditVerifyPage {
+			span.SetAttributes(attribute.Int("audit.verified", output.Verified))
+			return output, nil
+		}
+	}
+}
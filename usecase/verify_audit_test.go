@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/usecase"
+)
+
+// auditLog lists its entries as the repositories filter them by sequence
+type auditLog []entity.AuditEntry
+
+func (l auditLog) List(_ context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	var entries []entity.AuditEntry
+	for _, entry := range l {
+		if entry.Sequence() >= f.FromSequence && (f.Limit == 0 || len(entries) < f.Limit) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func TestVerifyAudit(t *testing.T) {
+	var chain auditLog
+	for i := 0; i < 5; i++ {
+		var last entity.AuditEntry
+		if len(chain) > 0 {
+			last = chain[len(chain)-1]
+		}
+		entry := entity.NewAuditEntry(newUuid(t), vo.WalletCreditedAction, "admin", newUuid(t), "", time.Now())
+		chain = append(chain, entry.Chain(last.Sequence()+1, last.Hash()))
+	}
+
+	tampered := append(auditLog{}, chain...)
+	e := chain[2]
+	tampered[2] = entity.NewAuditEntry(e.ID(), e.Action(), "mallory", e.SubjectID(), e.Detail(), e.CreatedAt()).
+		WithChain(e.Sequence(), e.PrevHash(), e.Hash())
+
+	removed := append(append(auditLog{}, chain[:3]...), chain[4:]...)
+
+	tests := []struct {
+		name   string
+		log    auditLog
+		from   int64
+		output usecase.VerifyAuditOutput
+	}{
+		{name: "valid", log: chain, output: usecase.VerifyAuditOutput{Valid: true, Verified: 5, LastSequence: 5}},
+		{name: "from a sequence", log: chain, from: 3, output: usecase.VerifyAuditOutput{Valid: true, Verified: 3, LastSequence: 5}},
+		{name: "empty", output: usecase.VerifyAuditOutput{Valid: true}},
+		{name: "changed entry", log: tampered, output: usecase.VerifyAuditOutput{Verified: 2, LastSequence: 2, BrokenAt: 3}},
+		{name: "removed entry", log: removed, output: usecase.VerifyAuditOutput{Verified: 3, LastSequence: 3, BrokenAt: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := usecase.NewVerifyAuditInteractor(tt.log).Execute(context.Background(), usecase.VerifyAuditInput{FromSequence: tt.from})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if output != tt.output {
+				t.Errorf("Execute() = %+v, want %+v", output, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	VoidPaymentUseCase interface {
+		Execute(context.Context, VoidPaymentInput) (PaymentOutput, error)
+	}
+
+	// Input data
+	VoidPaymentInput struct {
+		ID vo.Uuid
+		At time.Time
+	}
+
+	// Output port
+	VoidPaymentPresenter interface {
+		Output(entity.Payment) PaymentOutput
+	}
+
+	voidPaymentInteractor struct {
+		paymentReleaser
+		pre VoidPaymentPresenter
+	}
+
+	// paymentReleaser releases the holds of the payments ending without a
+	// capture, shared by the use cases voiding and expiring them
+	paymentReleaser struct {
+		transferExecutor
+		repoPaymentFinder  entity.PaymentRepositoryFinder
+		repoPaymentUpdater entity.PaymentRepositoryUpdater
+	}
+)
+
+// NewVoidPaymentInteractor create new voidPaymentInteractor with its dependencies
+func NewVoidPaymentInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoPaymentFinder entity.PaymentRepositoryFinder,
+	repoPaymentUpdater entity.PaymentRepositoryUpdater,
+	pre VoidPaymentPresenter,
+) VoidPaymentUseCase {
+	return voidPaymentInteractor{
+		paymentReleaser: paymentReleaser{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+			},
+			repoPaymentFinder:  repoPaymentFinder,
+			repoPaymentUpdater: repoPaymentUpdater,
+		},
+		pre: pre,
+	}
+}
+
+// Execute voids the authorized payment, releasing its hold
+func (v voidPaymentInteractor) Execute(ctx context.Context, i VoidPaymentInput) (PaymentOutput, error) {
+	ctx, span := tracer.Start(ctx, "VoidPaymentInteractor.Execute", trace.WithAttributes(
+		attribute.String("payment.id", i.ID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	payment, err := v.release(ctx, span, i.ID, func(p *entity.Payment) error {
+		return p.Void(i.At)
+	})
+	if err != nil {
+		recordError(span, err)
+		return v.pre.Output(entity.Payment{}), err
+	}
+
+	return v.pre.Output(payment), nil
+}
+
+// release ends the payment with end and gives its hold back to the payer in a single transaction
+func (r paymentReleaser) release(
+	ctx context.Context,
+	span trace.Span,
+	ID vo.Uuid,
+	end func(*entity.Payment) error,
+) (entity.Payment, error) {
+	var payment entity.Payment
+	err := r.retry(ctx, span, func() error {
+		return r.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			if payment, err = r.repoPaymentFinder.FindByID(sessCtx, ID); err != nil {
+				return err
+			}
+
+			if err := end(&payment); err != nil {
+				return err
+			}
+
+			payer, err := r.repoUserFinder.FindByID(sessCtx, payment.Payer())
+			if err != nil {
+				return err
+			}
+
+			if err := payer.Release(payment.Value()); err != nil {
+				return err
+			}
+
+			wallet, err := payer.FindWallet(payment.Value().Currency(), vo.MainWallet)
+			if err != nil {
+				return err
+			}
+
+			if err := r.repoUserUpdater.UpdateWallet(sessCtx, payer.ID(), wallet); err != nil {
+				return err
+			}
+
+			return r.repoPaymentUpdater.Update(sessCtx, payment)
+		})
+	})
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	return payment, nil
+}
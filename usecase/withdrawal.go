@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Input port
+	WithdrawalUseCase interface {
+		Execute(context.Context, WithdrawalInput) (FundingOutput, error)
+	}
+
+	// Input data
+	WithdrawalInput struct {
+		ID       vo.Uuid
+		UserID   vo.Uuid
+		Value    vo.Money
+		Method   vo.FundingMethod
+		Wallet   vo.WalletPurpose // vo.MainWallet when empty
+		CreateAt time.Time
+	}
+
+	// Output port
+	WithdrawalPresenter interface {
+		Output(entity.Funding) FundingOutput
+	}
+
+	withdrawalInteractor struct {
+		fundingSettler
+		repoFundingCreator entity.FundingRepositoryCreator
+		pre                WithdrawalPresenter
+		provider           PaymentProvider
+	}
+)
+
+// NewWithdrawalInteractor create new withdrawalInteractor with its dependencies
+func NewWithdrawalInteractor(
+	repoTransferCreator entity.TransferRepositoryCreator,
+	repoTransferFinder entity.TransferRepositoryFinder,
+	repoUserUpdater entity.UserRepositoryUpdater,
+	repoUserFinder entity.UserRepositoryFinder,
+	repoFundingCreator entity.FundingRepositoryCreator,
+	repoFundingFinder entity.FundingRepositoryFinder,
+	repoFundingUpdater entity.FundingRepositoryUpdater,
+	repoAuditCreator entity.AuditRepositoryCreator,
+	authorizer Authorizer,
+	limits entity.LimitPolicy,
+	pre WithdrawalPresenter,
+	provider PaymentProvider,
+) WithdrawalUseCase {
+	return withdrawalInteractor{
+		fundingSettler: fundingSettler{
+			transferExecutor: transferExecutor{
+				repoTransferCreator: repoTransferCreator,
+				repoTransferFinder:  repoTransferFinder,
+				repoUserUpdater:     repoUserUpdater,
+				repoUserFinder:      repoUserFinder,
+				repoAuditCreator:    repoAuditCreator,
+				authorizer:          authorizer,
+				limits:              limits,
+			},
+			repoFundingFinder:  repoFundingFinder,
+			repoFundingUpdater: repoFundingUpdater,
+		},
+		repoFundingCreator: repoFundingCreator,
+		pre:                pre,
+		provider:           provider,
+	}
+}
+
+// Execute authorizes the withdrawal like a transfer of the user to itself, then
+// debits the wallet within the limits, records the withdrawal as pending and asks
+// the payment provider to pay it out. The wallet is refunded when the provider
+// fails the withdrawal, which stays pending when the provider fails to answer.
+// A withdrawal cannot be held, the one the authorizer requires a review of is refused.
+func (w withdrawalInteractor) Execute(ctx context.Context, i WithdrawalInput) (FundingOutput, error) {
+	ctx, span := tracer.Start(ctx, "WithdrawalInteractor.Execute", trace.WithAttributes(
+		attribute.String("funding.id", i.ID.Value()),
+		attribute.String("funding.user_id", i.UserID.Value()),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if i.Value.Amount().Value() <= 0 {
+		recordError(span, entity.ErrInvalidFundingValue)
+		return w.pre.Output(entity.Funding{}), entity.ErrInvalidFundingValue
+	}
+
+	funding := entity.NewFunding(i.ID, i.UserID, vo.Withdrawal, i.Method, i.Value, walletPurpose(i.Wallet), i.CreateAt)
+
+	if err := w.authorize(ctx, withdrawalTransfer(funding)); err != nil {
+		recordError(span, err)
+		return w.pre.Output(entity.Funding{}), err
+	}
+
+	err := w.retry(ctx, span, func() error {
+		return w.repoTransferCreator.WithTransaction(ctx, func(sessCtx context.Context) error {
+			return w.debit(sessCtx, funding)
+		})
+	})
+	if err != nil {
+		recordError(span, err)
+		return w.pre.Output(entity.Funding{}), err
+	}
+
+	result, err := w.provider.Withdraw(ctx, funding)
+	if err != nil {
+		recordError(span, err)
+		return w.pre.Output(entity.Funding{}), err
+	}
+
+	if funding, err = w.resolve(ctx, span, funding.ID(), result, time.Now()); err != nil {
+		recordError(span, err)
+		return w.pre.Output(entity.Funding{}), err
+	}
+
+	if funding.Status() == vo.FundingFailed {
+		err := fundingError(funding)
+		recordError(span, err)
+		return w.pre.Output(entity.Funding{}), err
+	}
+
+	return w.pre.Output(funding), nil
+}
+
+// debit checks the user may withdraw within its limits, debits its wallet and
+// records the withdrawal. It must run in a transaction.
+func (w withdrawalInteractor) debit(ctx context.Context, funding entity.Funding) error {
+	user, err := w.repoUserFinder.FindByID(ctx, funding.User())
+	if err != nil {
+		return err
+	}
+
+	if err := w.check(ctx, user, funding.Value(), funding.CreatedAt()); err != nil {
+		return err
+	}
+
+	if err := user.Withdraw(funding.Value(), funding.Wallet()); err != nil {
+		return err
+	}
+
+	wallet, err := user.FindWallet(funding.Value().Currency(), funding.Wallet())
+	if err != nil {
+		return err
+	}
+
+	if err := w.repoUserUpdater.UpdateWallet(ctx, user.ID(), wallet); err != nil {
+		return err
+	}
+
+	_, err = w.repoFundingCreator.Create(ctx, funding)
+	return err
+}
+
+// withdrawalTransfer returns the withdrawal as the transfer of the user to itself the authorizer decides on
+func withdrawalTransfer(funding entity.Funding) entity.Transfer {
+	return entity.NewTransfer(funding.ID(), funding.User(), funding.User(), funding.Value(), funding.CreatedAt()).
+		WithType(vo.WithdrawalTransfer).
+		WithWallets(funding.Wallet(), funding.Wallet())
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dungnguyen/clean-architecture/adapter/presenter"
+	"github.com/dungnguyen/clean-architecture/adapter/provider"
+	"github.com/dungnguyen/clean-architecture/domain/entity"
+	"github.com/dungnguyen/clean-architecture/domain/vo"
+	"github.com/dungnguyen/clean-architecture/infrastructure/database"
+	"github.com/dungnguyen/clean-architecture/usecase"
+	"github.com/google/uuid"
+)
+
+type authorizerFunc func(context.Context, entity.Transfer) (bool, error)
+
+func (f authorizerFunc) Authorized(ctx context.Context, t entity.Transfer) (bool, error) {
+	return f(ctx, t)
+}
+
+func TestWithdrawal(t *testing.T) {
+	allow := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return true, nil })
+	deny := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return false, nil })
+	review := authorizerFunc(func(context.Context, entity.Transfer) (bool, error) { return false, entity.ErrReviewRequired })
+
+	tests := []struct {
+		name       string
+		authorizer usecase.Authorizer
+		values     []int64
+		err        error
+		balance    int64
+	}{
+		{name: "within the limits", authorizer: allow, values: []int64{30, 20}, balance: 50},
+		{name: "over the daily limit with the previous withdrawal", authorizer: allow, values: []int64{30, 30}, err: entity.ErrLimitExceeded, balance: 70},
+		{name: "over the transaction limit", authorizer: allow, values: []int64{60}, err: entity.ErrLimitExceeded, balance: 100},
+		{name: "refused by the authorizer", authorizer: deny, values: []int64{10}, err: entity.ErrUnauthorizedTransfer, balance: 100},
+		{name: "review required", authorizer: review, values: []int64{10}, err: entity.ErrReviewRequired, balance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := database.NewInMemoryHandler()
+			users := database.NewUserInMen(db)
+			transfers := database.NewTransferInMen(db)
+			fundings := database.NewFundingInMen(db)
+
+			user := newUser(ctx, t, users, 100)
+			limits := entity.NewLimitPolicy(time.UTC, map[vo.TypeUser]entity.Limits{
+				vo.COMMON: entity.NewLimits(vo.NewAmountTest(50), vo.NewAmountTest(50), vo.Amount{}, entity.Velocity{}, entity.Night{}),
+			}, nil)
+
+			uc := usecase.NewWithdrawalInteractor(
+				transfers,
+				transfers,
+				users,
+				users,
+				fundings,
+				fundings,
+				fundings,
+				database.NewAuditInMen(db),
+				tt.authorizer,
+				limits,
+				presenter.NewWithdrawalPresenter(),
+				provider.NewSimulatedProvider(vo.FundingPending),
+			)
+
+			var err error
+			for _, value := range tt.values {
+				_, err = uc.Execute(ctx, usecase.WithdrawalInput{
+					ID:       newUuid(t),
+					UserID:   user.ID(),
+					Value:    vo.NewMoneyBRL(vo.NewAmountTest(value)),
+					Method:   vo.BankTransferMethod,
+					CreateAt: time.Now(),
+				})
+				if err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+
+			got, err := users.FindByID(ctx, user.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if balance := got.Wallet().Money().Amount().Value(); balance != tt.balance {
+				t.Errorf("balance = %d, want %d", balance, tt.balance)
+			}
+		})
+	}
+}
+
+func newUser(ctx context.Context, t *testing.T, users entity.UserRepositoryCreator, balance int64) entity.User {
+	t.Helper()
+
+	return newUserOfType(ctx, t, users, balance, vo.COMMON)
+}
+
+func newUserOfType(ctx context.Context, t *testing.T, users entity.UserRepositoryCreator, balance int64, typeUser vo.TypeUser) entity.User {
+	t.Helper()
+
+	id := newUuid(t)
+	u, err := entity.NewUser(
+		id,
+		vo.NewFullName("Test "+id.Value()[:8]),
+		vo.NewEmailTest(id.Value()[:8]+"@usecase.test"),
+		vo.NewPassword("secret"),
+		vo.NewDocumentTest(vo.CPF, "070.910.549-45"),
+		vo.NewWallet(vo.NewMoneyBRL(vo.NewAmountTest(balance))),
+		typeUser,
+		time.Now(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := users.Create(ctx, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+func newUuid(t *testing.T) vo.Uuid {
+	t.Helper()
+
+	id, err := vo.NewUuid(uuid.New().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}